@@ -33,7 +33,8 @@ func (es ${structName}) MoveAndAppendTo(dest ${structName}) {
 }
 
 // RemoveIf calls f sequentially for each element present in the slice.
-// If f returns true, the element is removed from the slice.
+// If f returns true, the element is removed from the slice. The elements are removed
+// in place, without reallocating the slice, and the remaining ones keep their order.
 func (es ${structName}) RemoveIf(f func(${elementName}) bool) {
 	newLen := 0
 	for i := 0; i < len(*es.orig); i++ {
@@ -191,6 +191,16 @@ type CustomUnmarshable interface {
 	Unmarshal(componentSection *Parser) error
 }
 
+// UnsetEnvValidatable defines an optional interface for the configurations that
+// must reject some values referencing unset environment variables, which are
+// expanded to empty strings when the configuration is loaded.
+type UnsetEnvValidatable interface {
+	// ValidateUnsetEnv is called once the configuration is loaded, with the names
+	// of the unset environment variables referenced by the string values, by key
+	// of the value, e.g. "endpoint". It is not called if all the variables are set.
+	ValidateUnsetEnv(unset map[string][]string) error
+}
+
 // DataType is the data type that is supported for collection. We currently support
 // collecting metrics, traces and logs, this can expand in the future.
 type DataType string
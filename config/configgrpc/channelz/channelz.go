@@ -0,0 +1,32 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package channelz links the gRPC channelz service into the collector, to support
+// the enable_channelz settings of configgrpc. It is imported for its side effects:
+//
+//	import _ "go.opentelemetry.io/collector/config/configgrpc/channelz"
+//
+// Importing it turns on the channelz data collection of gRPC for all the
+// connections of the process, whether enable_channelz is set or not.
+package channelz
+
+import (
+	channelzsvc "google.golang.org/grpc/channelz/service"
+
+	"go.opentelemetry.io/collector/config/configgrpc"
+)
+
+func init() {
+	configgrpc.RegisterChannelzService(channelzsvc.RegisterChannelzServiceToServer)
+}
@@ -0,0 +1,98 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package channelz
+
+import (
+	"context"
+	"testing"
+	_ "unsafe" // for go:linkname
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	channelzpb "google.golang.org/grpc/channelz/grpc_channelz_v1"
+
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/config"
+	"go.opentelemetry.io/collector/config/configgrpc"
+	"go.opentelemetry.io/collector/config/confignet"
+	"go.opentelemetry.io/collector/config/configtls"
+	"go.opentelemetry.io/collector/testutil"
+)
+
+// channelzState is the state of the channelz data collection of gRPC, 1 when it is
+// turned on, read from gRPC since it is not exported.
+//
+//go:linkname channelzState google.golang.org/grpc/internal/channelz.curState
+var channelzState int32
+
+func TestRegisterServices(t *testing.T) {
+	const channelzService = "grpc.channelz.v1.Channelz"
+
+	srv := grpc.NewServer()
+	gss := &configgrpc.GRPCServerSettings{}
+	gss.RegisterServices(srv)
+	assert.NotContains(t, srv.GetServiceInfo(), channelzService)
+
+	srv = grpc.NewServer()
+	gss.EnableChannelz = true
+	_, err := gss.ToServerOption(map[config.ComponentID]component.Extension{})
+	require.NoError(t, err)
+	gss.RegisterServices(srv)
+	assert.Contains(t, srv.GetServiceInfo(), channelzService)
+	assert.EqualValues(t, 1, channelzState)
+}
+
+func TestClientEnableChannelz(t *testing.T) {
+	gss := &configgrpc.GRPCServerSettings{
+		NetAddr: confignet.NetAddr{
+			Endpoint:  testutil.GetAvailableLocalAddress(t),
+			Transport: "tcp",
+		},
+		EnableChannelz: true,
+	}
+	ln, err := gss.ToListener()
+	require.NoError(t, err)
+	srv := grpc.NewServer()
+	gss.RegisterServices(srv)
+	go func() {
+		_ = srv.Serve(ln)
+	}()
+	defer srv.Stop()
+
+	// The connection of the client is listed by the channelz service of the server.
+	const target = "passthrough:///channelz.invalid:4317"
+	gcs := &configgrpc.GRPCClientSettings{
+		Endpoint:       target,
+		TLSSetting:     configtls.TLSClientSetting{Insecure: true},
+		EnableChannelz: true,
+	}
+	opts, err := gcs.ToDialOptions(map[config.ComponentID]component.Extension{})
+	require.NoError(t, err)
+	conn, err := grpc.Dial(gcs.Endpoint, opts...)
+	require.NoError(t, err)
+	defer conn.Close()
+
+	czConn, err := grpc.Dial(ln.Addr().String(), grpc.WithInsecure())
+	require.NoError(t, err)
+	defer czConn.Close()
+	resp, err := channelzpb.NewChannelzClient(czConn).GetTopChannels(context.Background(), &channelzpb.GetTopChannelsRequest{})
+	require.NoError(t, err)
+	var targets []string
+	for _, ch := range resp.Channel {
+		targets = append(targets, ch.Data.Target)
+	}
+	assert.Contains(t, targets, target)
+}
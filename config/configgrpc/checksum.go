@@ -0,0 +1,74 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package configgrpc
+
+import (
+	"context"
+	"fmt"
+	"hash/crc32"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/encoding"
+	"google.golang.org/grpc/encoding/proto"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// ChecksumMetadataKey is the gRPC metadata key carrying the CRC32C checksum of the
+// request message when VerifyChecksum is enabled.
+const ChecksumMetadataKey = "otel-checksum-crc32c"
+
+var crc32cTable = crc32.MakeTable(crc32.Castagnoli)
+
+// messageChecksum returns the hex encoded CRC32C checksum of the protobuf encoding
+// of msg, using the same codec as the one gRPC uses to send the message.
+func messageChecksum(msg interface{}) (string, error) {
+	buf, err := encoding.GetCodec(proto.Name).Marshal(msg)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%08x", crc32.Checksum(buf, crc32cTable)), nil
+}
+
+// checksumUnaryClientInterceptor attaches the checksum of the request message to
+// the outgoing metadata of every unary RPC.
+func checksumUnaryClientInterceptor(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+	checksum, err := messageChecksum(req)
+	if err != nil {
+		return fmt.Errorf("failed to compute the request checksum: %w", err)
+	}
+	ctx = metadata.AppendToOutgoingContext(ctx, ChecksumMetadataKey, checksum)
+	return invoker(ctx, method, req, reply, cc, opts...)
+}
+
+// checksumUnaryServerInterceptor verifies the checksum of the request message of
+// every unary RPC and rejects the mismatches with the DataLoss code. The requests
+// without checksum are accepted, so that clients not computing it keep working.
+func checksumUnaryServerInterceptor(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	md, _ := metadata.FromIncomingContext(ctx)
+	want := md.Get(ChecksumMetadataKey)
+	if len(want) == 0 {
+		return handler(ctx, req)
+	}
+	got, err := messageChecksum(req)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to compute the request checksum: %v", err)
+	}
+	if got != want[0] {
+		return nil, status.Errorf(codes.DataLoss, "request checksum mismatch: got %s, want %s", got, want[0])
+	}
+	return handler(ctx, req)
+}
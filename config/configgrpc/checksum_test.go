@@ -0,0 +1,117 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package configgrpc
+
+import (
+	"context"
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	"go.opentelemetry.io/collector/config/configtls"
+)
+
+// startChecksumServer starts a gRPC server verifying the checksums and serving
+// the health service, and returns its address.
+func startChecksumServer(t *testing.T) string {
+	gss := &GRPCServerSettings{VerifyChecksum: true}
+	opts, err := gss.ToServerOption(nil)
+	require.NoError(t, err)
+	srv := grpc.NewServer(opts...)
+	healthpb.RegisterHealthServer(srv, health.NewServer())
+
+	ln, err := net.Listen("tcp", "localhost:0")
+	require.NoError(t, err)
+	go func() {
+		_ = srv.Serve(ln)
+	}()
+	t.Cleanup(srv.Stop)
+	return ln.Addr().String()
+}
+
+func dialChecksumServer(t *testing.T, addr string, extraOpts ...grpc.DialOption) healthpb.HealthClient {
+	gcs := &GRPCClientSettings{
+		TLSSetting:     configtls.TLSClientSetting{Insecure: true},
+		VerifyChecksum: true,
+	}
+	opts, err := gcs.ToDialOptions(nil)
+	require.NoError(t, err)
+	conn, err := grpc.Dial(addr, append(opts, extraOpts...)...)
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		assert.NoError(t, conn.Close())
+	})
+	return healthpb.NewHealthClient(conn)
+}
+
+func TestChecksum(t *testing.T) {
+	client := dialChecksumServer(t, startChecksumServer(t))
+	resp, err := client.Check(context.Background(), &healthpb.HealthCheckRequest{Service: "svc"})
+	// The health server only knows the empty service.
+	assert.Equal(t, codes.NotFound, status.Code(err))
+	assert.Nil(t, resp)
+
+	resp, err = client.Check(context.Background(), &healthpb.HealthCheckRequest{})
+	require.NoError(t, err)
+	assert.Equal(t, healthpb.HealthCheckResponse_SERVING, resp.Status)
+}
+
+func TestChecksumMismatch(t *testing.T) {
+	// Simulates a corruption by changing the request after its checksum was computed.
+	corrupt := func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		req.(*healthpb.HealthCheckRequest).Service = "corrupted"
+		return invoker(ctx, method, req, reply, cc, opts...)
+	}
+	client := dialChecksumServer(t, startChecksumServer(t), grpc.WithChainUnaryInterceptor(corrupt))
+	_, err := client.Check(context.Background(), &healthpb.HealthCheckRequest{Service: "svc"})
+	assert.Equal(t, codes.DataLoss, status.Code(err))
+}
+
+func TestChecksumServerInterceptor(t *testing.T) {
+	req := &healthpb.HealthCheckRequest{Service: "svc"}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return "handled", nil
+	}
+	checksum, err := messageChecksum(req)
+	require.NoError(t, err)
+
+	tests := []struct {
+		name     string
+		md       metadata.MD
+		wantCode codes.Code
+	}{
+		{name: "no_checksum", md: metadata.MD{}, wantCode: codes.OK},
+		{name: "valid_checksum", md: metadata.Pairs(ChecksumMetadataKey, checksum), wantCode: codes.OK},
+		{name: "invalid_checksum", md: metadata.Pairs(ChecksumMetadataKey, "00000000"), wantCode: codes.DataLoss},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := metadata.NewIncomingContext(context.Background(), tt.md)
+			resp, err := checksumUnaryServerInterceptor(ctx, req, &grpc.UnaryServerInfo{}, handler)
+			assert.Equal(t, tt.wantCode, status.Code(err))
+			if tt.wantCode == codes.OK {
+				assert.Equal(t, "handled", resp)
+			}
+		})
+	}
+}
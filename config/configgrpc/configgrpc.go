@@ -16,6 +16,8 @@
 package configgrpc
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"net"
 	"strings"
@@ -42,6 +44,11 @@ const (
 	PerRPCAuthTypeBearer = "bearer"
 )
 
+// MsgSizeWarningThresholdMiB is the message size limit above which components are
+// expected to log a warning, since such large limits are most likely a mistake, e.g.
+// a size in bytes instead of MiB, and allow single messages to exhaust the memory.
+const MsgSizeWarningThresholdMiB = 256
+
 var (
 	// Map of opentelemetry compression types to grpc registered compression types
 	grpcCompressionKeyMap = map[string]string{
@@ -100,6 +107,43 @@ type GRPCClientSettings struct {
 
 	// Auth configuration for outgoing RPCs.
 	Auth *configauth.Authentication `mapstructure:"auth,omitempty"`
+
+	// MaxRecvMsgSizeMiB sets the maximum size (in MiB) of the responses accepted by the
+	// client, see grpc.MaxCallRecvMsgSize. The default value 0 keeps the gRPC default of 4 MiB.
+	MaxRecvMsgSizeMiB int `mapstructure:"max_recv_msg_size_mib"`
+
+	// MaxSendMsgSizeMiB sets the maximum size (in MiB) of the requests sent by the client,
+	// see grpc.MaxCallSendMsgSize. The default value 0 keeps the gRPC default of no limit.
+	MaxSendMsgSizeMiB int `mapstructure:"max_send_msg_size_mib"`
+
+	// TCPKeepAlive sets the interval of the TCP keep-alive probes of the connections, see
+	// net.Dialer.KeepAlive. Unlike the gRPC Keepalive, which sends HTTP/2 pings, the probes
+	// are sent by the OS and detect the dead peers even when the pings are not forwarded,
+	// e.g. by a load balancer. The default value 0 keeps the OS default and a negative value
+	// disables the TCP keep-alive. When set, the proxy environment variables are ignored.
+	TCPKeepAlive time.Duration `mapstructure:"tcp_keepalive"`
+
+	// VerifyChecksum attaches the CRC32C checksum of every unary request to its metadata,
+	// to be verified by servers also enabling VerifyChecksum. This catches corruptions
+	// not detected by the transport, at the cost of encoding every request twice.
+	VerifyChecksum bool `mapstructure:"verify_checksum"`
+
+	// UserAgent is sent in the user-agent header of the requests, e.g. to identify the
+	// version and the deployment of the collector. gRPC appends its own user agent to it,
+	// e.g. "my-collector/1.0 grpc-go/1.37.1". Empty (default) sends only the gRPC user agent.
+	UserAgent string `mapstructure:"user_agent"`
+
+	// DialTimeout bounds, when dialing with DialContext, how long the initial connection,
+	// including the TLS handshake, may take before the dial fails, e.g. for a black-holed
+	// endpoint. Zero (default) establishes the connection in the background without
+	// blocking the dial.
+	DialTimeout time.Duration `mapstructure:"dial_timeout"`
+
+	// EnableChannelz tracks the connection of the client in channelz, to inspect its
+	// state through the channelz service of a server enabling it, e.g. an OTLP receiver
+	// of the same collector. See https://github.com/grpc/proposal/blob/master/A14-channelz.md.
+	// It requires the collector to be built with the configgrpc/channelz package.
+	EnableChannelz bool `mapstructure:"enable_channelz"`
 }
 
 // KeepaliveServerConfig is the configuration for keepalive.
@@ -156,6 +200,47 @@ type GRPCServerSettings struct {
 
 	// Auth for this receiver
 	Auth *configauth.Authentication `mapstructure:"auth,omitempty"`
+
+	// EnableChannelz registers the gRPC channelz service on the server, which
+	// allows inspecting the state of all the gRPC connections and streams of the
+	// process, including the ones of the gRPC clients, e.g. with grpcdebug.
+	// See https://github.com/grpc/proposal/blob/master/A14-channelz.md.
+	// It requires the collector to be built with the configgrpc/channelz package.
+	EnableChannelz bool `mapstructure:"enable_channelz"`
+
+	// VerifyChecksum verifies the CRC32C checksum attached by the clients to the unary
+	// requests, see GRPCClientSettings.VerifyChecksum, and rejects the mismatches. This
+	// re-encodes every request carrying a checksum.
+	VerifyChecksum bool `mapstructure:"verify_checksum"`
+}
+
+// Validate checks if the client settings are valid.
+func (gcs *GRPCClientSettings) Validate() error {
+	if gcs.MaxRecvMsgSizeMiB < 0 {
+		return errors.New("max_recv_msg_size_mib must be non-negative")
+	}
+	if gcs.MaxSendMsgSizeMiB < 0 {
+		return errors.New("max_send_msg_size_mib must be non-negative")
+	}
+	if gcs.DialTimeout < 0 {
+		return errors.New("dial_timeout must be non-negative")
+	}
+	return nil
+}
+
+// DialContext creates a client connection to target with the given dial options, usually
+// returned by ToDialOptions. If DialTimeout is set, it blocks until the connection is
+// established, including the TLS handshake, and fails with the last connection error if
+// it is not within DialTimeout.
+func (gcs *GRPCClientSettings) DialContext(ctx context.Context, target string, opts ...grpc.DialOption) (*grpc.ClientConn, error) {
+	if gcs.DialTimeout <= 0 {
+		return grpc.DialContext(ctx, target, opts...)
+	}
+	ctx, cancel := context.WithTimeout(ctx, gcs.DialTimeout)
+	defer cancel()
+	// Cap opts so that the options appended below do not overwrite the ones of the caller.
+	opts = append(opts[:len(opts):len(opts)], grpc.WithBlock(), grpc.WithReturnConnectionError())
+	return grpc.DialContext(ctx, target, opts...)
 }
 
 // ToDialOptions maps configgrpc.GRPCClientSettings to a slice of dial options for gRPC
@@ -179,6 +264,14 @@ func (gcs *GRPCClientSettings) ToDialOptions(ext map[config.ComponentID]componen
 	}
 	opts = append(opts, tlsDialOption)
 
+	if gcs.MaxRecvMsgSizeMiB > 0 {
+		opts = append(opts, grpc.WithDefaultCallOptions(grpc.MaxCallRecvMsgSize(gcs.MaxRecvMsgSizeMiB*1024*1024)))
+	}
+
+	if gcs.MaxSendMsgSizeMiB > 0 {
+		opts = append(opts, grpc.WithDefaultCallOptions(grpc.MaxCallSendMsgSize(gcs.MaxSendMsgSizeMiB*1024*1024)))
+	}
+
 	if gcs.ReadBufferSize > 0 {
 		opts = append(opts, grpc.WithReadBufferSize(gcs.ReadBufferSize))
 	}
@@ -196,6 +289,10 @@ func (gcs *GRPCClientSettings) ToDialOptions(ext map[config.ComponentID]componen
 		opts = append(opts, keepAliveOption)
 	}
 
+	if gcs.TCPKeepAlive != 0 {
+		opts = append(opts, grpc.WithContextDialer(newTCPKeepAliveDialer(gcs.TCPKeepAlive)))
+	}
+
 	if gcs.Auth != nil {
 		if ext == nil {
 			return nil, fmt.Errorf("no extensions configuration available")
@@ -226,9 +323,33 @@ func (gcs *GRPCClientSettings) ToDialOptions(ext map[config.ComponentID]componen
 		opts = append(opts, grpc.WithDefaultServiceConfig(fmt.Sprintf(`{"loadBalancingPolicy":"%s"}`, gcs.BalancerName)))
 	}
 
+	if gcs.VerifyChecksum {
+		opts = append(opts, grpc.WithChainUnaryInterceptor(checksumUnaryClientInterceptor))
+	}
+
+	if gcs.UserAgent != "" {
+		opts = append(opts, grpc.WithUserAgent(gcs.UserAgent))
+	}
+
+	if gcs.EnableChannelz && channelzService == nil {
+		return nil, errChannelzNotLinked
+	}
+
 	return opts, nil
 }
 
+// newTCPKeepAliveDialer returns a gRPC dialer for the "unix://" and TCP addresses with the
+// given TCP keep-alive interval.
+func newTCPKeepAliveDialer(keepAlive time.Duration) func(context.Context, string) (net.Conn, error) {
+	dialer := &net.Dialer{KeepAlive: keepAlive}
+	return func(ctx context.Context, addr string) (net.Conn, error) {
+		if strings.HasPrefix(addr, "unix://") {
+			return dialer.DialContext(ctx, "unix", strings.TrimPrefix(addr, "unix://"))
+		}
+		return dialer.DialContext(ctx, "tcp", addr)
+	}
+}
+
 func validateBalancerName(balancerName string) bool {
 	for _, item := range allowedBalancerNames {
 		if item == balancerName {
@@ -243,10 +364,37 @@ func (gss *GRPCServerSettings) ToListener() (net.Listener, error) {
 	return gss.NetAddr.Listen()
 }
 
+// RegisterServices registers on the server the additional gRPC services enabled
+// in the settings. It must be called before the server starts serving, after
+// ToServerOption checked that the services are available.
+func (gss *GRPCServerSettings) RegisterServices(srv grpc.ServiceRegistrar) {
+	if gss.EnableChannelz && channelzService != nil {
+		channelzService(srv)
+	}
+}
+
+// channelzService registers the gRPC channelz service on a server, it is only set
+// when the configgrpc/channelz package is linked.
+var channelzService func(grpc.ServiceRegistrar)
+
+var errChannelzNotLinked = errors.New("enable_channelz requires the collector to be built with the go.opentelemetry.io/collector/config/configgrpc/channelz package")
+
+// RegisterChannelzService sets the function registering the gRPC channelz service
+// on the servers enabling it. It is called by the configgrpc/channelz package when
+// it is imported, since importing the gRPC channelz service turns on the channelz
+// data collection for all the connections of the process.
+func RegisterChannelzService(register func(grpc.ServiceRegistrar)) {
+	channelzService = register
+}
+
 // ToServerOption maps configgrpc.GRPCServerSettings to a slice of server options for gRPC
 func (gss *GRPCServerSettings) ToServerOption(ext map[config.ComponentID]component.Extension) ([]grpc.ServerOption, error) {
 	var opts []grpc.ServerOption
 
+	if gss.EnableChannelz && channelzService == nil {
+		return nil, errChannelzNotLinked
+	}
+
 	if gss.TLSSetting != nil {
 		tlsCfg, err := gss.TLSSetting.LoadTLSConfig()
 		if err != nil {
@@ -316,6 +464,10 @@ func (gss *GRPCServerSettings) ToServerOption(ext map[config.ComponentID]compone
 		)
 	}
 
+	if gss.VerifyChecksum {
+		opts = append(opts, grpc.ChainUnaryInterceptor(checksumUnaryServerInterceptor))
+	}
+
 	return opts, nil
 }
 
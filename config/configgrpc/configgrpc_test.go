@@ -16,14 +16,21 @@ package configgrpc
 
 import (
 	"context"
+	"net"
 	"path"
 	"runtime"
+	"strings"
 	"testing"
 	"time"
+	_ "unsafe" // for go:linkname
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/connectivity"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/metadata"
 
 	"go.opentelemetry.io/collector/component"
 	"go.opentelemetry.io/collector/config"
@@ -60,11 +67,16 @@ func TestAllGrpcClientSettings(t *testing.T) {
 			Timeout:             time.Second,
 			PermitWithoutStream: true,
 		},
-		ReadBufferSize:  1024,
-		WriteBufferSize: 1024,
-		WaitForReady:    true,
-		BalancerName:    "round_robin",
-		Auth:            &configauth.Authentication{AuthenticatorName: "testauth"},
+		ReadBufferSize:    1024,
+		WriteBufferSize:   1024,
+		WaitForReady:      true,
+		BalancerName:      "round_robin",
+		Auth:              &configauth.Authentication{AuthenticatorName: "testauth"},
+		MaxRecvMsgSizeMiB: 16,
+		MaxSendMsgSizeMiB: 8,
+		TCPKeepAlive:      time.Minute,
+		VerifyChecksum:    true,
+		UserAgent:         "collector/1.0",
 	}
 
 	ext := map[config.ComponentID]component.Extension{
@@ -73,7 +85,139 @@ func TestAllGrpcClientSettings(t *testing.T) {
 
 	opts, err := gcs.ToDialOptions(ext)
 	assert.NoError(t, err)
-	assert.Len(t, opts, 7)
+	assert.Len(t, opts, 12)
+}
+
+func TestGRPCClientSettings_Validate(t *testing.T) {
+	gcs := &GRPCClientSettings{MaxRecvMsgSizeMiB: 16, MaxSendMsgSizeMiB: 8}
+	assert.NoError(t, gcs.Validate())
+
+	gcs.MaxRecvMsgSizeMiB = -1
+	assert.EqualError(t, gcs.Validate(), "max_recv_msg_size_mib must be non-negative")
+
+	gcs = &GRPCClientSettings{MaxSendMsgSizeMiB: -1}
+	assert.EqualError(t, gcs.Validate(), "max_send_msg_size_mib must be non-negative")
+
+	gcs = &GRPCClientSettings{DialTimeout: -time.Second}
+	assert.EqualError(t, gcs.Validate(), "dial_timeout must be non-negative")
+}
+
+func TestGRPCClientSettings_DialTimeout(t *testing.T) {
+	// The listener accepts the connections but never completes the TLS handshake.
+	ln, err := net.Listen("tcp", "localhost:0")
+	require.NoError(t, err)
+	defer ln.Close()
+	go func() {
+		var conns []net.Conn
+		defer func() {
+			for _, conn := range conns {
+				conn.Close()
+			}
+		}()
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			conns = append(conns, conn)
+		}
+	}()
+
+	gcs := &GRPCClientSettings{Endpoint: ln.Addr().String(), DialTimeout: 100 * time.Millisecond}
+	opts, err := gcs.ToDialOptions(nil)
+	require.NoError(t, err)
+	start := time.Now()
+	_, err = gcs.DialContext(context.Background(), gcs.Endpoint, opts...)
+	require.Error(t, err)
+	assert.Less(t, int64(time.Since(start)), int64(5*time.Second))
+
+	// Without a dial timeout the connection is established in the background.
+	gcs.DialTimeout = 0
+	conn, err := gcs.DialContext(context.Background(), gcs.Endpoint, opts...)
+	require.NoError(t, err)
+	assert.NotEqual(t, connectivity.Ready, conn.GetState())
+	assert.NoError(t, conn.Close())
+}
+
+func TestGRPCClientSettings_DialTimeoutReady(t *testing.T) {
+	srv := grpc.NewServer()
+	healthpb.RegisterHealthServer(srv, health.NewServer())
+	ln, err := net.Listen("tcp", "localhost:0")
+	require.NoError(t, err)
+	go func() {
+		_ = srv.Serve(ln)
+	}()
+	defer srv.Stop()
+
+	gcs := &GRPCClientSettings{
+		Endpoint:    ln.Addr().String(),
+		TLSSetting:  configtls.TLSClientSetting{Insecure: true},
+		DialTimeout: 5 * time.Second,
+	}
+	opts, err := gcs.ToDialOptions(nil)
+	require.NoError(t, err)
+	conn, err := gcs.DialContext(context.Background(), gcs.Endpoint, opts...)
+	require.NoError(t, err)
+	defer conn.Close()
+	assert.Equal(t, connectivity.Ready, conn.GetState())
+}
+
+func TestGRPCClientSettings_UserAgent(t *testing.T) {
+	userAgents := make(chan string, 1)
+	srv := grpc.NewServer(grpc.UnaryInterceptor(func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		md, _ := metadata.FromIncomingContext(ctx)
+		userAgents <- strings.Join(md.Get("user-agent"), ",")
+		return handler(ctx, req)
+	}))
+	healthpb.RegisterHealthServer(srv, health.NewServer())
+	ln, err := net.Listen("tcp", "localhost:0")
+	require.NoError(t, err)
+	go func() {
+		_ = srv.Serve(ln)
+	}()
+	defer srv.Stop()
+
+	check := func(gcs *GRPCClientSettings) string {
+		opts, err := gcs.ToDialOptions(nil)
+		require.NoError(t, err)
+		conn, err := grpc.Dial(ln.Addr().String(), opts...)
+		require.NoError(t, err)
+		defer conn.Close()
+		_, err = healthpb.NewHealthClient(conn).Check(context.Background(), &healthpb.HealthCheckRequest{})
+		require.NoError(t, err)
+		return <-userAgents
+	}
+
+	gcs := &GRPCClientSettings{TLSSetting: configtls.TLSClientSetting{Insecure: true}}
+	assert.True(t, strings.HasPrefix(check(gcs), "grpc-go/"))
+
+	gcs.UserAgent = "collector/1.0 (prod)"
+	assert.True(t, strings.HasPrefix(check(gcs), "collector/1.0 (prod) grpc-go/"))
+}
+
+func TestTCPKeepAliveDialer(t *testing.T) {
+	ln, err := net.Listen("tcp", "localhost:0")
+	require.NoError(t, err)
+	defer ln.Close()
+
+	dial := newTCPKeepAliveDialer(time.Minute)
+	conn, err := dial(context.Background(), ln.Addr().String())
+	require.NoError(t, err)
+	assert.Equal(t, "tcp", conn.RemoteAddr().Network())
+	assert.NoError(t, conn.Close())
+
+	if runtime.GOOS == "windows" {
+		return
+	}
+	socketName := testutil.TempSocketName(t)
+	uln, err := net.Listen("unix", socketName)
+	require.NoError(t, err)
+	defer uln.Close()
+
+	conn, err = dial(context.Background(), "unix://"+socketName)
+	require.NoError(t, err)
+	assert.Equal(t, "unix", conn.RemoteAddr().Network())
+	assert.NoError(t, conn.Close())
 }
 
 func TestDefaultGrpcServerSettings(t *testing.T) {
@@ -110,10 +254,11 @@ func TestAllGrpcServerSettingsExceptAuth(t *testing.T) {
 				PermitWithoutStream: true,
 			},
 		},
+		VerifyChecksum: true,
 	}
 	opts, err := gss.ToServerOption(map[config.ComponentID]component.Extension{})
 	assert.NoError(t, err)
-	assert.Len(t, opts, 7)
+	assert.Len(t, opts, 8)
 }
 
 func TestGrpcServerAuthSettings(t *testing.T) {
@@ -362,6 +507,47 @@ func TestGRPCServerSettingsError(t *testing.T) {
 	}
 }
 
+// channelzState is the state of the channelz data collection of gRPC, 1 when it is
+// turned on, read from gRPC since it is not exported.
+//
+//go:linkname channelzState google.golang.org/grpc/internal/channelz.curState
+var channelzState int32
+
+func TestChannelzOffByDefault(t *testing.T) {
+	gcs := &GRPCClientSettings{
+		Endpoint:   "localhost:4317",
+		TLSSetting: configtls.TLSClientSetting{Insecure: true},
+	}
+	opts, err := gcs.ToDialOptions(map[config.ComponentID]component.Extension{})
+	require.NoError(t, err)
+	conn, err := grpc.Dial(gcs.Endpoint, opts...)
+	require.NoError(t, err)
+	defer conn.Close()
+
+	gss := &GRPCServerSettings{}
+	_, err = gss.ToServerOption(map[config.ComponentID]component.Extension{})
+	require.NoError(t, err)
+	srv := grpc.NewServer()
+	gss.RegisterServices(srv)
+	assert.NotContains(t, srv.GetServiceInfo(), "grpc.channelz.v1.Channelz")
+
+	assert.EqualValues(t, 0, channelzState)
+}
+
+func TestEnableChannelzNotLinked(t *testing.T) {
+	gcs := &GRPCClientSettings{
+		Endpoint:       "localhost:4317",
+		TLSSetting:     configtls.TLSClientSetting{Insecure: true},
+		EnableChannelz: true,
+	}
+	_, err := gcs.ToDialOptions(map[config.ComponentID]component.Extension{})
+	assert.Equal(t, errChannelzNotLinked, err)
+
+	gss := &GRPCServerSettings{EnableChannelz: true}
+	_, err = gss.ToServerOption(map[config.ComponentID]component.Extension{})
+	assert.Equal(t, errChannelzNotLinked, err)
+}
+
 func TestGRPCServerSettings_ToListener_Error(t *testing.T) {
 	settings := GRPCServerSettings{
 		NetAddr: confignet.NetAddr{
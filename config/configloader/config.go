@@ -182,6 +182,7 @@ func loadExtensions(exts map[string]interface{}, factories map[config.Type]compo
 	// Iterate over extensions and create a config for each.
 	for key, value := range exts {
 		componentConfig := config.NewParserFromStringMap(cast.ToStringMap(value))
+		unset := unsetEnvVars(componentConfig)
 		expandEnvConfig(componentConfig)
 
 		// Decode the key into type and fullName components.
@@ -207,6 +208,9 @@ func loadExtensions(exts map[string]interface{}, factories map[config.Type]compo
 		if err := unm(componentConfig, extensionCfg); err != nil {
 			return nil, errorUnmarshalError(extensionsKeyName, id, err)
 		}
+		if err := validateUnsetEnv(extensionCfg, unset); err != nil {
+			return nil, errorUnmarshalError(extensionsKeyName, id, err)
+		}
 
 		if extensions[id] != nil {
 			return nil, errorDuplicateName(extensionsKeyName, id)
@@ -261,6 +265,7 @@ func loadReceivers(recvs map[string]interface{}, factories map[config.Type]compo
 	// Iterate over input map and create a config for each.
 	for key, value := range recvs {
 		componentConfig := config.NewParserFromStringMap(cast.ToStringMap(value))
+		unset := unsetEnvVars(componentConfig)
 		expandEnvConfig(componentConfig)
 
 		// Decode the key into type and fullName components.
@@ -281,6 +286,9 @@ func loadReceivers(recvs map[string]interface{}, factories map[config.Type]compo
 			// LoadReceiver already wraps the error.
 			return nil, err
 		}
+		if err = validateUnsetEnv(receiverCfg, unset); err != nil {
+			return nil, errorUnmarshalError(receiversKeyName, id, err)
+		}
 
 		if receivers[id] != nil {
 			return nil, errorDuplicateName(receiversKeyName, id)
@@ -298,6 +306,7 @@ func loadExporters(exps map[string]interface{}, factories map[config.Type]compon
 	// Iterate over Exporters and create a config for each.
 	for key, value := range exps {
 		componentConfig := config.NewParserFromStringMap(cast.ToStringMap(value))
+		unset := unsetEnvVars(componentConfig)
 		expandEnvConfig(componentConfig)
 
 		// Decode the key into type and fullName components.
@@ -323,6 +332,9 @@ func loadExporters(exps map[string]interface{}, factories map[config.Type]compon
 		if err := unm(componentConfig, exporterCfg); err != nil {
 			return nil, errorUnmarshalError(exportersKeyName, id, err)
 		}
+		if err := validateUnsetEnv(exporterCfg, unset); err != nil {
+			return nil, errorUnmarshalError(exportersKeyName, id, err)
+		}
 
 		if exporters[id] != nil {
 			return nil, errorDuplicateName(exportersKeyName, id)
@@ -341,6 +353,7 @@ func loadProcessors(procs map[string]interface{}, factories map[config.Type]comp
 	// Iterate over processors and create a config for each.
 	for key, value := range procs {
 		componentConfig := config.NewParserFromStringMap(cast.ToStringMap(value))
+		unset := unsetEnvVars(componentConfig)
 		expandEnvConfig(componentConfig)
 
 		// Decode the key into type and fullName components.
@@ -366,6 +379,9 @@ func loadProcessors(procs map[string]interface{}, factories map[config.Type]comp
 		if err := unm(componentConfig, processorCfg); err != nil {
 			return nil, errorUnmarshalError(processorsKeyName, id, err)
 		}
+		if err := validateUnsetEnv(processorCfg, unset); err != nil {
+			return nil, errorUnmarshalError(processorsKeyName, id, err)
+		}
 
 		if processors[id] != nil {
 			return nil, errorDuplicateName(processorsKeyName, id)
@@ -465,6 +481,34 @@ func expandStringValues(value interface{}) interface{} {
 	}
 }
 
+// unsetEnvVars returns the names of the unset environment variables referenced by
+// the string values of v, by key. It must be called before expandEnvConfig.
+func unsetEnvVars(v *config.Parser) map[string][]string {
+	unset := make(map[string][]string)
+	for _, k := range v.AllKeys() {
+		s, ok := v.Get(k).(string)
+		if !ok {
+			continue
+		}
+		os.Expand(s, func(name string) string {
+			if _, ok := os.LookupEnv(name); !ok && name != "$" {
+				unset[k] = append(unset[k], name)
+			}
+			return ""
+		})
+	}
+	return unset
+}
+
+// validateUnsetEnv calls the ValidateUnsetEnv of cfg, if implemented, when some
+// of the variables referenced by its values are unset.
+func validateUnsetEnv(cfg interface{}, unset map[string][]string) error {
+	if v, ok := cfg.(config.UnsetEnvValidatable); ok && len(unset) > 0 {
+		return v.ValidateUnsetEnv(unset)
+	}
+	return nil
+}
+
 // expandEnvLoadedConfig is a utility function that goes recursively through a config object
 // and tries to expand environment variables in its string fields.
 func expandEnvLoadedConfig(s interface{}) {
@@ -15,6 +15,7 @@
 package configloader
 
 import (
+	"errors"
 	"os"
 	"path"
 	"testing"
@@ -665,3 +666,45 @@ func TestExpandEnvLoadedConfigUnexportedField(t *testing.T) {
 		ExportedStringValue:   "replaced_value",
 	}, cfg)
 }
+
+func TestUnsetEnvVars(t *testing.T) {
+	assert.NoError(t, os.Setenv("VALUE", "replaced_value"))
+
+	defer func() {
+		assert.NoError(t, os.Unsetenv("VALUE"))
+	}()
+
+	p := config.NewParserFromStringMap(map[string]interface{}{
+		"set":     "$VALUE",
+		"unset":   "${UNSET_HOST}:${UNSET_PORT}",
+		"escaped": "$$UNSET_HOST",
+		"nested":  map[string]interface{}{"unset": "$UNSET_HOST"},
+		"int":     1,
+	})
+	assert.Equal(t, map[string][]string{
+		"unset":         {"UNSET_HOST", "UNSET_PORT"},
+		"nested::unset": {"UNSET_HOST"},
+	}, unsetEnvVars(p))
+}
+
+type testUnsetEnvConfig struct {
+	config.ExporterSettings
+	unset map[string][]string
+}
+
+func (cfg *testUnsetEnvConfig) ValidateUnsetEnv(unset map[string][]string) error {
+	cfg.unset = unset
+	return errors.New("unset variables")
+}
+
+func TestValidateUnsetEnv(t *testing.T) {
+	cfg := &testUnsetEnvConfig{}
+	assert.NoError(t, validateUnsetEnv(cfg, map[string][]string{}))
+	assert.Nil(t, cfg.unset)
+
+	unset := map[string][]string{"endpoint": {"UNSET_HOST"}}
+	assert.EqualError(t, validateUnsetEnv(cfg, unset), "unset variables")
+	assert.Equal(t, unset, cfg.unset)
+
+	assert.NoError(t, validateUnsetEnv(&testConfig{}, unset))
+}
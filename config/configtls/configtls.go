@@ -17,11 +17,27 @@ package configtls
 import (
 	"crypto/tls"
 	"crypto/x509"
+	"errors"
 	"fmt"
 	"io/ioutil"
+	"os"
 	"path/filepath"
+	"strings"
+
+	"go.uber.org/zap"
+)
+
+// Supported values of TLSClientSetting.Renegotiation.
+const (
+	renegotiateNever  = "never"
+	renegotiateOnce   = "once"
+	renegotiateFreely = "freely"
 )
 
+// tlsPlaintextMismatchMsg is the error message returned by crypto/tls when a TLS
+// client receives a non TLS response, see tls.RecordHeaderError.
+const tlsPlaintextMismatchMsg = "first record does not look like a TLS handshake"
+
 // TLSSetting exposes the common client and server TLS configurations.
 // Note: Since there isn't anything specific to a server connection. Components
 // with server connections should use TLSSetting.
@@ -30,10 +46,18 @@ type TLSSetting struct {
 	// For a server this verifies client certificates. If empty uses system root CA.
 	// (optional)
 	CAFile string `mapstructure:"ca_file"`
+	// Path to a directory of CA certs, every PEM file of the directory is loaded in
+	// addition to the CAFile, like the system trust stores. The files which do not
+	// contain any PEM certificate are skipped. (optional)
+	CAPath string `mapstructure:"ca_path"`
 	// Path to the TLS cert to use for TLS required connections. (optional)
 	CertFile string `mapstructure:"cert_file"`
 	// Path to the TLS key to use for TLS required connections. (optional)
 	KeyFile string `mapstructure:"key_file"`
+	// DisableSessionTickets disables the session ticket based resumption: a server
+	// does not issue tickets and a client does not use them. This sets the
+	// SessionTicketsDisabled in the TLSConfig. (optional, default false)
+	DisableSessionTickets bool `mapstructure:"disable_session_tickets"`
 }
 
 // TLSClientSetting contains TLS configurations that are specific to client
@@ -57,6 +81,16 @@ type TLSClientSetting struct {
 	// This sets the ServerName in the TLSConfig. Please refer to
 	// https://godoc.org/crypto/tls#Config for more information. (optional)
 	ServerName string `mapstructure:"server_name_override"`
+	// Renegotiation controls whether the server may request TLS renegotiation,
+	// one of "never", "once" or "freely". This sets the Renegotiation in the
+	// TLSConfig. Please refer to https://godoc.org/crypto/tls#RenegotiationSupport
+	// for more information. (optional, default "never")
+	Renegotiation string `mapstructure:"renegotiation"`
+	// SessionCacheSize is the number of TLS sessions cached by the client to resume
+	// them on the next connections to the same server, saving full handshakes when
+	// connections are frequently re-established. Zero disables the resumption. This
+	// sets the ClientSessionCache in the TLSConfig. (optional, default 0)
+	SessionCacheSize int `mapstructure:"session_cache_size"`
 }
 
 // TLSServerSetting contains TLS configurations that are specific to server
@@ -87,6 +121,14 @@ func (c TLSSetting) loadTLSConfig() (*tls.Config, error) {
 			return nil, fmt.Errorf("failed to load CA CertPool: %w", err)
 		}
 	}
+	if len(c.CAPath) != 0 {
+		if certPool == nil {
+			certPool = x509.NewCertPool()
+		}
+		if err = c.loadCertDir(certPool, c.CAPath); err != nil {
+			return nil, fmt.Errorf("failed to load CA CertPool: %w", err)
+		}
+	}
 
 	if (c.CertFile == "" && c.KeyFile != "") || (c.CertFile != "" && c.KeyFile == "") {
 		return nil, fmt.Errorf("for auth via TLS, either both certificate and key must be supplied, or neither")
@@ -102,8 +144,9 @@ func (c TLSSetting) loadTLSConfig() (*tls.Config, error) {
 	}
 
 	return &tls.Config{
-		RootCAs:      certPool,
-		Certificates: certificates,
+		RootCAs:                certPool,
+		Certificates:           certificates,
+		SessionTicketsDisabled: c.DisableSessionTickets,
 	}, nil
 }
 
@@ -120,21 +163,84 @@ func (c TLSSetting) loadCert(caPath string) (*x509.CertPool, error) {
 	return certPool, nil
 }
 
+// loadCertDir appends to certPool the certificates of all the PEM files of the
+// directory, the sub-directories and the files without certificates are skipped.
+// The skipped files are logged at debug level with the global logger, since the
+// settings are loaded without a component logger.
+func (c TLSSetting) loadCertDir(certPool *x509.CertPool, caDir string) error {
+	logger := zap.L()
+	caDir = filepath.Clean(caDir)
+	entries, err := ioutil.ReadDir(caDir)
+	if err != nil {
+		return fmt.Errorf("failed to load CA directory %s: %w", caDir, err)
+	}
+	loaded := 0
+	for _, entry := range entries {
+		path := filepath.Join(caDir, entry.Name())
+		// Stat follows the symbolic links, which are common in the system trust stores.
+		info, err := os.Stat(path)
+		if err != nil {
+			logger.Debug("Skipping the CA file", zap.String("path", path), zap.Error(err))
+			continue
+		}
+		if info.IsDir() {
+			continue
+		}
+		caPEM, err := ioutil.ReadFile(path)
+		if err != nil {
+			return fmt.Errorf("failed to load CA %s: %w", path, err)
+		}
+		if !certPool.AppendCertsFromPEM(caPEM) {
+			logger.Debug("Skipping the CA file without PEM certificate", zap.String("path", path))
+			continue
+		}
+		loaded++
+	}
+	if loaded == 0 {
+		return fmt.Errorf("failed to parse CA directory %s: no PEM certificate found", caDir)
+	}
+	return nil
+}
+
 // LoadTLSConfig loads the tls configuration.
 func (c TLSClientSetting) LoadTLSConfig() (*tls.Config, error) {
-	if c.Insecure && c.CAFile == "" {
+	if c.Insecure && c.CAFile == "" && c.CAPath == "" {
 		return nil, nil
 	}
 
+	renegotiation, err := c.renegotiationSupport()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load TLS config: %w", err)
+	}
+	if c.SessionCacheSize < 0 {
+		return nil, errors.New("failed to load TLS config: session_cache_size must be non-negative")
+	}
 	tlsCfg, err := c.TLSSetting.loadTLSConfig()
 	if err != nil {
 		return nil, fmt.Errorf("failed to load TLS config: %w", err)
 	}
 	tlsCfg.ServerName = c.ServerName
 	tlsCfg.InsecureSkipVerify = c.InsecureSkipVerify
+	tlsCfg.Renegotiation = renegotiation
+	if c.SessionCacheSize > 0 {
+		tlsCfg.ClientSessionCache = tls.NewLRUClientSessionCache(c.SessionCacheSize)
+	}
 	return tlsCfg, nil
 }
 
+func (c TLSClientSetting) renegotiationSupport() (tls.RenegotiationSupport, error) {
+	switch c.Renegotiation {
+	case "", renegotiateNever:
+		return tls.RenegotiateNever, nil
+	case renegotiateOnce:
+		return tls.RenegotiateOnceAsClient, nil
+	case renegotiateFreely:
+		return tls.RenegotiateFreelyAsClient, nil
+	}
+	return tls.RenegotiateNever, fmt.Errorf("renegotiation must be %q, %q or %q, got %q",
+		renegotiateNever, renegotiateOnce, renegotiateFreely, c.Renegotiation)
+}
+
 // LoadTLSConfig loads the tls configuration.
 func (c TLSServerSetting) LoadTLSConfig() (*tls.Config, error) {
 	tlsCfg, err := c.loadTLSConfig()
@@ -151,3 +257,21 @@ func (c TLSServerSetting) LoadTLSConfig() (*tls.Config, error) {
 	}
 	return tlsCfg, nil
 }
+
+// WrapHandshakeError adds actionable guidance to err if it is caused by a TLS client
+// connecting to a server that does not use TLS, which usually means that the
+// `insecure` setting does not match the server configuration. Otherwise err is
+// returned unchanged.
+//
+// The error may be received as a message from libraries like gRPC that do not wrap
+// the original error, so the message is checked in addition to the error type.
+func WrapHandshakeError(err error) error {
+	if err == nil {
+		return nil
+	}
+	var recordHeaderErr tls.RecordHeaderError
+	if !errors.As(err, &recordHeaderErr) && !strings.Contains(err.Error(), tlsPlaintextMismatchMsg) {
+		return err
+	}
+	return fmt.Errorf("%w (the server does not seem to use TLS, set `insecure: true` if the server expects plaintext connections)", err)
+}
@@ -15,10 +15,19 @@
 package configtls
 
 import (
+	"crypto/tls"
+	"errors"
+	"io/ioutil"
+	"net"
+	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
 )
 
 func TestOptionsToConfig(t *testing.T) {
@@ -45,6 +54,19 @@ func TestOptionsToConfig(t *testing.T) {
 			options:     TLSSetting{CAFile: "testdata/testCA-bad.txt"},
 			expectError: "failed to parse CA",
 		},
+		{
+			name:    "should load custom CA directory",
+			options: TLSSetting{CAPath: "testdata/ca-dir"},
+		},
+		{
+			name:    "should load custom CA file and directory",
+			options: TLSSetting{CAFile: "testdata/testCA.pem", CAPath: "testdata/ca-dir"},
+		},
+		{
+			name:        "should fail with invalid CA directory path",
+			options:     TLSSetting{CAPath: "testdata/not/valid"},
+			expectError: "failed to load CA directory",
+		},
 		{
 			name: "should load valid TLS  settings",
 			options: TLSSetting{
@@ -123,6 +145,27 @@ func TestOptionsToConfig(t *testing.T) {
 	}
 }
 
+func TestLoadCertDir(t *testing.T) {
+	core, logs := observer.New(zapcore.DebugLevel)
+	defer zap.ReplaceGlobals(zap.New(core))()
+
+	cfg, err := TLSSetting{CAPath: "testdata/ca-dir"}.loadTLSConfig()
+	require.NoError(t, err)
+	// The README.txt of the directory is skipped.
+	assert.Len(t, cfg.RootCAs.Subjects(), 1)
+	entries := logs.TakeAll()
+	require.Len(t, entries, 1)
+	assert.Equal(t, "Skipping the CA file without PEM certificate", entries[0].Message)
+	assert.Equal(t, filepath.Join("testdata", "ca-dir", "README.txt"), entries[0].ContextMap()["path"])
+
+	dir := t.TempDir()
+	require.NoError(t, os.Mkdir(filepath.Join(dir, "subdir"), 0700))
+	require.NoError(t, ioutil.WriteFile(filepath.Join(dir, "notes.txt"), []byte("not a certificate"), 0600))
+	_, err = TLSSetting{CAPath: dir}.loadTLSConfig()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "no PEM certificate found")
+}
+
 func TestLoadTLSClientConfigError(t *testing.T) {
 	tlsSetting := TLSClientSetting{
 		TLSSetting: TLSSetting{
@@ -156,6 +199,115 @@ func TestLoadTLSClientConfig(t *testing.T) {
 	assert.True(t, tlsCfg.InsecureSkipVerify)
 }
 
+func TestLoadTLSClientConfigRenegotiation(t *testing.T) {
+	tests := []struct {
+		renegotiation string
+		expected      tls.RenegotiationSupport
+	}{
+		{renegotiation: "", expected: tls.RenegotiateNever},
+		{renegotiation: "never", expected: tls.RenegotiateNever},
+		{renegotiation: "once", expected: tls.RenegotiateOnceAsClient},
+		{renegotiation: "freely", expected: tls.RenegotiateFreelyAsClient},
+	}
+	for _, test := range tests {
+		t.Run(test.renegotiation, func(t *testing.T) {
+			tlsCfg, err := TLSClientSetting{Renegotiation: test.renegotiation}.LoadTLSConfig()
+			require.NoError(t, err)
+			assert.Equal(t, test.expected, tlsCfg.Renegotiation)
+		})
+	}
+
+	_, err := TLSClientSetting{Renegotiation: "always"}.LoadTLSConfig()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `renegotiation must be "never", "once" or "freely", got "always"`)
+}
+
+func TestLoadTLSClientConfigSessionResumption(t *testing.T) {
+	tlsCfg, err := TLSClientSetting{}.LoadTLSConfig()
+	require.NoError(t, err)
+	assert.False(t, tlsCfg.SessionTicketsDisabled)
+	assert.Nil(t, tlsCfg.ClientSessionCache)
+
+	tlsCfg, err = TLSClientSetting{
+		TLSSetting:       TLSSetting{DisableSessionTickets: true},
+		SessionCacheSize: 10,
+	}.LoadTLSConfig()
+	require.NoError(t, err)
+	assert.True(t, tlsCfg.SessionTicketsDisabled)
+	assert.NotNil(t, tlsCfg.ClientSessionCache)
+
+	_, err = TLSClientSetting{SessionCacheSize: -1}.LoadTLSConfig()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "session_cache_size must be non-negative")
+}
+
+func TestSessionResumption(t *testing.T) {
+	serverCfg, err := TLSServerSetting{
+		TLSSetting: TLSSetting{
+			CertFile: filepath.Join("testdata", "test-cert.pem"),
+			KeyFile:  filepath.Join("testdata", "test-key.pem"),
+		},
+	}.LoadTLSConfig()
+	require.NoError(t, err)
+	ln, err := tls.Listen("tcp", "localhost:0", serverCfg)
+	require.NoError(t, err)
+	defer ln.Close()
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			// The session ticket is sent before the data, so the client receives it
+			// when reading the data.
+			_, _ = conn.Write([]byte("x"))
+			conn.Close()
+		}
+	}()
+
+	// connect returns whether the handshake of a new connection resumed a session.
+	connect := func(tlsCfg *tls.Config) bool {
+		conn, err := tls.Dial("tcp", ln.Addr().String(), tlsCfg)
+		require.NoError(t, err)
+		defer conn.Close()
+		_, err = conn.Read(make([]byte, 1))
+		require.NoError(t, err)
+		return conn.ConnectionState().DidResume
+	}
+
+	tests := []struct {
+		name    string
+		setting TLSClientSetting
+		resume  bool
+	}{
+		{
+			name:    "default",
+			setting: TLSClientSetting{InsecureSkipVerify: true},
+		},
+		{
+			name:    "session_cache",
+			setting: TLSClientSetting{InsecureSkipVerify: true, SessionCacheSize: 1},
+			resume:  true,
+		},
+		{
+			name: "session_tickets_disabled",
+			setting: TLSClientSetting{
+				TLSSetting:         TLSSetting{DisableSessionTickets: true},
+				InsecureSkipVerify: true,
+				SessionCacheSize:   1,
+			},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			tlsCfg, err := test.setting.LoadTLSConfig()
+			require.NoError(t, err)
+			assert.False(t, connect(tlsCfg))
+			assert.Equal(t, test.resume, connect(tlsCfg))
+		})
+	}
+}
+
 func TestLoadTLSServerConfigError(t *testing.T) {
 	tlsSetting := TLSServerSetting{
 		TLSSetting: TLSSetting{
@@ -179,3 +331,40 @@ func TestLoadTLSServerConfig(t *testing.T) {
 	assert.NoError(t, err)
 	assert.NotNil(t, tlsCfg)
 }
+
+func TestWrapHandshakeError(t *testing.T) {
+	assert.NoError(t, WrapHandshakeError(nil))
+
+	otherErr := errors.New("other error")
+	assert.Equal(t, otherErr, WrapHandshakeError(otherErr))
+
+	// Errors received as a message, e.g. from gRPC.
+	msgErr := errors.New("connection error: desc = \"transport: authentication handshake failed: tls: first record does not look like a TLS handshake\"")
+	err := WrapHandshakeError(msgErr)
+	assert.True(t, errors.Is(err, msgErr))
+	assert.Contains(t, err.Error(), "insecure: true")
+}
+
+func TestWrapHandshakeErrorPlaintextServer(t *testing.T) {
+	ln, err := net.Listen("tcp", "localhost:0")
+	require.NoError(t, err)
+	defer ln.Close()
+	go func() {
+		conn, err := ln.Accept()
+		if err != nil {
+			return
+		}
+		_, _ = conn.Write([]byte("HTTP/1.1 400 Bad Request\r\n\r\n"))
+		_ = conn.Close()
+	}()
+
+	tlsCfg, err := TLSClientSetting{InsecureSkipVerify: true}.LoadTLSConfig()
+	require.NoError(t, err)
+	_, err = tls.Dial("tcp", ln.Addr().String(), tlsCfg)
+	require.Error(t, err)
+
+	err = WrapHandshakeError(err)
+	var recordHeaderErr tls.RecordHeaderError
+	assert.True(t, errors.As(err, &recordHeaderErr))
+	assert.Contains(t, err.Error(), "insecure: true")
+}
@@ -0,0 +1,115 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package consumerhelper
+
+import (
+	"context"
+	"sync/atomic"
+	"time"
+
+	"go.opentelemetry.io/collector/consumer"
+	"go.opentelemetry.io/collector/consumer/pdata"
+)
+
+// LivenessReporter is implemented by components that keep track of the time
+// they last received data, e.g. to allow a health check to detect stalled pipelines.
+type LivenessReporter interface {
+	// LastReceived returns the time when the last batch was received,
+	// or the zero time if no data was received yet.
+	LastReceived() time.Time
+}
+
+// LivenessTracker records the time when the last batch was received.
+// The zero value is ready to use and it is safe for concurrent use.
+type LivenessTracker struct {
+	// lastReceived is the UnixNano of the last received batch, accessed atomically.
+	lastReceived int64
+}
+
+var _ LivenessReporter = (*LivenessTracker)(nil)
+
+// Record marks that a batch was received now.
+func (lt *LivenessTracker) Record() {
+	atomic.StoreInt64(&lt.lastReceived, time.Now().UnixNano())
+}
+
+// LastReceived implements the LivenessReporter interface.
+func (lt *LivenessTracker) LastReceived() time.Time {
+	nanos := atomic.LoadInt64(&lt.lastReceived)
+	if nanos == 0 {
+		return time.Time{}
+	}
+	return time.Unix(0, nanos)
+}
+
+type livenessTraces struct {
+	*LivenessTracker
+	next consumer.Traces
+}
+
+// NewLivenessTraces returns a consumer.Traces that records in lt the time of
+// every received batch and then forwards the batch to next.
+func NewLivenessTraces(next consumer.Traces, lt *LivenessTracker) consumer.Traces {
+	return &livenessTraces{LivenessTracker: lt, next: next}
+}
+
+func (lc *livenessTraces) Capabilities() consumer.Capabilities {
+	return lc.next.Capabilities()
+}
+
+func (lc *livenessTraces) ConsumeTraces(ctx context.Context, td pdata.Traces) error {
+	lc.Record()
+	return lc.next.ConsumeTraces(ctx, td)
+}
+
+type livenessMetrics struct {
+	*LivenessTracker
+	next consumer.Metrics
+}
+
+// NewLivenessMetrics returns a consumer.Metrics that records in lt the time of
+// every received batch and then forwards the batch to next.
+func NewLivenessMetrics(next consumer.Metrics, lt *LivenessTracker) consumer.Metrics {
+	return &livenessMetrics{LivenessTracker: lt, next: next}
+}
+
+func (lc *livenessMetrics) Capabilities() consumer.Capabilities {
+	return lc.next.Capabilities()
+}
+
+func (lc *livenessMetrics) ConsumeMetrics(ctx context.Context, md pdata.Metrics) error {
+	lc.Record()
+	return lc.next.ConsumeMetrics(ctx, md)
+}
+
+type livenessLogs struct {
+	*LivenessTracker
+	next consumer.Logs
+}
+
+// NewLivenessLogs returns a consumer.Logs that records in lt the time of
+// every received batch and then forwards the batch to next.
+func NewLivenessLogs(next consumer.Logs, lt *LivenessTracker) consumer.Logs {
+	return &livenessLogs{LivenessTracker: lt, next: next}
+}
+
+func (lc *livenessLogs) Capabilities() consumer.Capabilities {
+	return lc.next.Capabilities()
+}
+
+func (lc *livenessLogs) ConsumeLogs(ctx context.Context, ld pdata.Logs) error {
+	lc.Record()
+	return lc.next.ConsumeLogs(ctx, ld)
+}
@@ -0,0 +1,73 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package consumerhelper
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"go.opentelemetry.io/collector/consumer"
+	"go.opentelemetry.io/collector/consumer/consumertest"
+	"go.opentelemetry.io/collector/consumer/pdata"
+)
+
+func TestLivenessTracker(t *testing.T) {
+	lt := &LivenessTracker{}
+	assert.True(t, lt.LastReceived().IsZero())
+
+	before := time.Now()
+	lt.Record()
+	assert.False(t, lt.LastReceived().Before(before))
+	assert.False(t, lt.LastReceived().After(time.Now()))
+}
+
+func TestLivenessTraces(t *testing.T) {
+	sink := new(consumertest.TracesSink)
+	lt := &LivenessTracker{}
+	lc := NewLivenessTraces(sink, lt)
+	assert.Equal(t, sink.Capabilities(), lc.Capabilities())
+	assert.True(t, lc.(LivenessReporter).LastReceived().IsZero())
+
+	assert.NoError(t, lc.ConsumeTraces(context.Background(), pdata.NewTraces()))
+	assert.Len(t, sink.AllTraces(), 1)
+	assert.False(t, lt.LastReceived().IsZero())
+	assert.Equal(t, lt.LastReceived(), lc.(LivenessReporter).LastReceived())
+}
+
+func TestLivenessMetrics(t *testing.T) {
+	sink := new(consumertest.MetricsSink)
+	lt := &LivenessTracker{}
+	lc := NewLivenessMetrics(sink, lt)
+	assert.Equal(t, sink.Capabilities(), lc.Capabilities())
+
+	assert.NoError(t, lc.ConsumeMetrics(context.Background(), pdata.NewMetrics()))
+	assert.Len(t, sink.AllMetrics(), 1)
+	assert.False(t, lt.LastReceived().IsZero())
+}
+
+func TestLivenessLogs(t *testing.T) {
+	want := errors.New("my_error")
+	lt := &LivenessTracker{}
+	lc := NewLivenessLogs(consumertest.NewErr(want), lt)
+	assert.Equal(t, consumer.Capabilities{MutatesData: false}, lc.Capabilities())
+
+	// The batch is recorded even if the next consumer fails.
+	assert.Equal(t, want, lc.ConsumeLogs(context.Background(), pdata.NewLogs()))
+	assert.False(t, lt.LastReceived().IsZero())
+}
@@ -0,0 +1,115 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package consumerhelper
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+
+	"go.opentelemetry.io/collector/consumer"
+	"go.opentelemetry.io/collector/consumer/pdata"
+)
+
+// ErrDataMutated is reported by the mutation check consumers when a consumer
+// declaring MutatesData false modified the data it received.
+var ErrDataMutated = errors.New("data modified by a consumer declaring MutatesData false")
+
+// otlpMarshaler is implemented by pdata.Traces, pdata.Metrics and pdata.Logs.
+type otlpMarshaler interface {
+	ToOtlpProtoBytes() ([]byte, error)
+}
+
+// checkMutation calls consumeFunc and calls onViolation if next modified data.
+// Data that cannot be marshaled is not checked.
+func checkMutation(next interface{}, data otlpMarshaler, consumeFunc func() error, onViolation func(error)) error {
+	before, err := data.ToOtlpProtoBytes()
+	if err != nil {
+		return consumeFunc()
+	}
+	err = consumeFunc()
+	if after, merr := data.ToOtlpProtoBytes(); merr == nil && !bytes.Equal(before, after) {
+		onViolation(fmt.Errorf("%T: %w", next, ErrDataMutated))
+	}
+	return err
+}
+
+type mutationCheckTraces struct {
+	next        consumer.Traces
+	onViolation func(error)
+}
+
+// NewMutationCheckTraces returns a consumer.Traces that forwards every batch to
+// next and calls onViolation with an error wrapping ErrDataMutated if next
+// modified the batch although it declares MutatesData false, e.g. to log the
+// error or to panic. The batch is marshaled before and after calling next, so
+// this is meant as a development aid: if enabled is false, or if next declares
+// MutatesData true, next is returned as is and the check costs nothing.
+func NewMutationCheckTraces(next consumer.Traces, enabled bool, onViolation func(error)) consumer.Traces {
+	if !enabled || next.Capabilities().MutatesData {
+		return next
+	}
+	return &mutationCheckTraces{next: next, onViolation: onViolation}
+}
+
+func (mc *mutationCheckTraces) Capabilities() consumer.Capabilities {
+	return mc.next.Capabilities()
+}
+
+func (mc *mutationCheckTraces) ConsumeTraces(ctx context.Context, td pdata.Traces) error {
+	return checkMutation(mc.next, td, func() error { return mc.next.ConsumeTraces(ctx, td) }, mc.onViolation)
+}
+
+type mutationCheckMetrics struct {
+	next        consumer.Metrics
+	onViolation func(error)
+}
+
+// NewMutationCheckMetrics is the consumer.Metrics equivalent of NewMutationCheckTraces.
+func NewMutationCheckMetrics(next consumer.Metrics, enabled bool, onViolation func(error)) consumer.Metrics {
+	if !enabled || next.Capabilities().MutatesData {
+		return next
+	}
+	return &mutationCheckMetrics{next: next, onViolation: onViolation}
+}
+
+func (mc *mutationCheckMetrics) Capabilities() consumer.Capabilities {
+	return mc.next.Capabilities()
+}
+
+func (mc *mutationCheckMetrics) ConsumeMetrics(ctx context.Context, md pdata.Metrics) error {
+	return checkMutation(mc.next, md, func() error { return mc.next.ConsumeMetrics(ctx, md) }, mc.onViolation)
+}
+
+type mutationCheckLogs struct {
+	next        consumer.Logs
+	onViolation func(error)
+}
+
+// NewMutationCheckLogs is the consumer.Logs equivalent of NewMutationCheckTraces.
+func NewMutationCheckLogs(next consumer.Logs, enabled bool, onViolation func(error)) consumer.Logs {
+	if !enabled || next.Capabilities().MutatesData {
+		return next
+	}
+	return &mutationCheckLogs{next: next, onViolation: onViolation}
+}
+
+func (mc *mutationCheckLogs) Capabilities() consumer.Capabilities {
+	return mc.next.Capabilities()
+}
+
+func (mc *mutationCheckLogs) ConsumeLogs(ctx context.Context, ld pdata.Logs) error {
+	return checkMutation(mc.next, ld, func() error { return mc.next.ConsumeLogs(ctx, ld) }, mc.onViolation)
+}
@@ -0,0 +1,100 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package consumerhelper
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go.opentelemetry.io/collector/consumer"
+	"go.opentelemetry.io/collector/consumer/consumertest"
+	"go.opentelemetry.io/collector/consumer/pdata"
+	"go.opentelemetry.io/collector/internal/testdata"
+)
+
+func TestMutationCheckTraces(t *testing.T) {
+	var violations []error
+	onViolation := func(err error) { violations = append(violations, err) }
+
+	sink := new(consumertest.TracesSink)
+	mc := NewMutationCheckTraces(sink, true, onViolation)
+	assert.Equal(t, sink.Capabilities(), mc.Capabilities())
+	require.NoError(t, mc.ConsumeTraces(context.Background(), testdata.GenerateTracesTwoSpansSameResource()))
+	assert.Len(t, sink.AllTraces(), 1)
+	assert.Empty(t, violations)
+
+	mutating, err := NewTraces(func(context.Context, pdata.Traces) error {
+		return nil
+	}, WithCapabilities(consumer.Capabilities{MutatesData: true}))
+	require.NoError(t, err)
+	assert.Same(t, mutating, NewMutationCheckTraces(mutating, true, onViolation))
+	assert.Same(t, consumer.Traces(sink), NewMutationCheckTraces(sink, false, onViolation))
+
+	errConsume := errors.New("consume error")
+	liar, err := NewTraces(func(_ context.Context, td pdata.Traces) error {
+		td.ResourceSpans().At(0).Resource().Attributes().UpsertString("mutated", "true")
+		return errConsume
+	})
+	require.NoError(t, err)
+	mc = NewMutationCheckTraces(liar, true, onViolation)
+	assert.Equal(t, errConsume, mc.ConsumeTraces(context.Background(), testdata.GenerateTracesTwoSpansSameResource()))
+	require.Len(t, violations, 1)
+	assert.True(t, errors.Is(violations[0], ErrDataMutated))
+}
+
+func TestMutationCheckMetrics(t *testing.T) {
+	var violations []error
+	onViolation := func(err error) { violations = append(violations, err) }
+
+	sink := new(consumertest.MetricsSink)
+	mc := NewMutationCheckMetrics(sink, true, onViolation)
+	require.NoError(t, mc.ConsumeMetrics(context.Background(), testdata.GeneratMetricsAllTypesWithSampleDatapoints()))
+	assert.Len(t, sink.AllMetrics(), 1)
+	assert.Empty(t, violations)
+
+	liar, err := NewMetrics(func(_ context.Context, md pdata.Metrics) error {
+		md.ResourceMetrics().At(0).Resource().Attributes().UpsertString("mutated", "true")
+		return nil
+	})
+	require.NoError(t, err)
+	mc = NewMutationCheckMetrics(liar, true, onViolation)
+	require.NoError(t, mc.ConsumeMetrics(context.Background(), testdata.GeneratMetricsAllTypesWithSampleDatapoints()))
+	require.Len(t, violations, 1)
+	assert.True(t, errors.Is(violations[0], ErrDataMutated))
+}
+
+func TestMutationCheckLogs(t *testing.T) {
+	var violations []error
+	onViolation := func(err error) { violations = append(violations, err) }
+
+	sink := new(consumertest.LogsSink)
+	mc := NewMutationCheckLogs(sink, true, onViolation)
+	require.NoError(t, mc.ConsumeLogs(context.Background(), testdata.GenerateLogsOneLogRecord()))
+	assert.Len(t, sink.AllLogs(), 1)
+	assert.Empty(t, violations)
+
+	liar, err := NewLogs(func(_ context.Context, ld pdata.Logs) error {
+		ld.ResourceLogs().At(0).Resource().Attributes().UpsertString("mutated", "true")
+		return nil
+	})
+	require.NoError(t, err)
+	mc = NewMutationCheckLogs(liar, true, onViolation)
+	require.NoError(t, mc.ConsumeLogs(context.Background(), testdata.GenerateLogsOneLogRecord()))
+	require.Len(t, violations, 1)
+	assert.True(t, errors.Is(violations[0], ErrDataMutated))
+}
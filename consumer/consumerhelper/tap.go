@@ -0,0 +1,102 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package consumerhelper
+
+import (
+	"context"
+	"io"
+	"sync"
+
+	"go.opentelemetry.io/collector/consumer"
+	"go.opentelemetry.io/collector/consumer/pdata"
+	"go.opentelemetry.io/collector/internal/otlptext"
+)
+
+// tapWriter serializes the writes of the rendered batches to the underlying writer.
+type tapWriter struct {
+	mu sync.Mutex
+	w  io.Writer
+}
+
+// write writes the text ignoring any error, since the tap is only a debugging aid
+// and must not prevent the data from being forwarded.
+func (tw *tapWriter) write(text string) {
+	tw.mu.Lock()
+	defer tw.mu.Unlock()
+	_, _ = io.WriteString(tw.w, text)
+}
+
+type tapTraces struct {
+	*tapWriter
+	next consumer.Traces
+}
+
+// NewTapTraces returns a consumer.Traces that renders every received batch as text
+// to w, in the same format as the logging exporter, and then forwards the unmodified
+// batch to next. This allows to inspect the data anywhere in a pipeline.
+func NewTapTraces(next consumer.Traces, w io.Writer) consumer.Traces {
+	return &tapTraces{tapWriter: &tapWriter{w: w}, next: next}
+}
+
+func (tc *tapTraces) Capabilities() consumer.Capabilities {
+	return tc.next.Capabilities()
+}
+
+func (tc *tapTraces) ConsumeTraces(ctx context.Context, td pdata.Traces) error {
+	tc.write(otlptext.Traces(td))
+	return tc.next.ConsumeTraces(ctx, td)
+}
+
+type tapMetrics struct {
+	*tapWriter
+	next consumer.Metrics
+}
+
+// NewTapMetrics returns a consumer.Metrics that renders every received batch as text
+// to w, in the same format as the logging exporter, and then forwards the unmodified
+// batch to next. This allows to inspect the data anywhere in a pipeline.
+func NewTapMetrics(next consumer.Metrics, w io.Writer) consumer.Metrics {
+	return &tapMetrics{tapWriter: &tapWriter{w: w}, next: next}
+}
+
+func (tc *tapMetrics) Capabilities() consumer.Capabilities {
+	return tc.next.Capabilities()
+}
+
+func (tc *tapMetrics) ConsumeMetrics(ctx context.Context, md pdata.Metrics) error {
+	tc.write(otlptext.Metrics(md))
+	return tc.next.ConsumeMetrics(ctx, md)
+}
+
+type tapLogs struct {
+	*tapWriter
+	next consumer.Logs
+}
+
+// NewTapLogs returns a consumer.Logs that renders every received batch as text
+// to w, in the same format as the logging exporter, and then forwards the unmodified
+// batch to next. This allows to inspect the data anywhere in a pipeline.
+func NewTapLogs(next consumer.Logs, w io.Writer) consumer.Logs {
+	return &tapLogs{tapWriter: &tapWriter{w: w}, next: next}
+}
+
+func (tc *tapLogs) Capabilities() consumer.Capabilities {
+	return tc.next.Capabilities()
+}
+
+func (tc *tapLogs) ConsumeLogs(ctx context.Context, ld pdata.Logs) error {
+	tc.write(otlptext.Logs(ld))
+	return tc.next.ConsumeLogs(ctx, ld)
+}
@@ -0,0 +1,83 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package consumerhelper
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go.opentelemetry.io/collector/consumer"
+	"go.opentelemetry.io/collector/consumer/consumertest"
+	"go.opentelemetry.io/collector/internal/testdata"
+)
+
+func TestTapTraces(t *testing.T) {
+	td := testdata.GenerateTracesTwoSpansSameResource()
+	want := td.Clone()
+	sink := new(consumertest.TracesSink)
+	var buf bytes.Buffer
+	tc := NewTapTraces(sink, &buf)
+	assert.Equal(t, sink.Capabilities(), tc.Capabilities())
+
+	require.NoError(t, tc.ConsumeTraces(context.Background(), td))
+	assert.Contains(t, buf.String(), "ResourceSpans #0")
+	assert.Contains(t, buf.String(), "Span #1")
+	require.Len(t, sink.AllTraces(), 1)
+	assert.Equal(t, want, sink.AllTraces()[0])
+}
+
+func TestTapMetrics(t *testing.T) {
+	md := testdata.GeneratMetricsAllTypesWithSampleDatapoints()
+	want := md.Clone()
+	sink := new(consumertest.MetricsSink)
+	var buf bytes.Buffer
+	tc := NewTapMetrics(sink, &buf)
+	assert.Equal(t, sink.Capabilities(), tc.Capabilities())
+
+	require.NoError(t, tc.ConsumeMetrics(context.Background(), md))
+	assert.Contains(t, buf.String(), "ResourceMetrics #0")
+	require.Len(t, sink.AllMetrics(), 1)
+	assert.Equal(t, want, sink.AllMetrics()[0])
+}
+
+func TestTapLogs(t *testing.T) {
+	ld := testdata.GenerateLogsOneLogRecord()
+	want := errors.New("my_error")
+	var buf bytes.Buffer
+	tc := NewTapLogs(consumertest.NewErr(want), &buf)
+	assert.Equal(t, consumer.Capabilities{MutatesData: false}, tc.Capabilities())
+
+	// The batch is rendered even if the next consumer fails.
+	assert.Equal(t, want, tc.ConsumeLogs(context.Background(), ld))
+	assert.Contains(t, buf.String(), "LogRecord #0")
+}
+
+type errWriter struct{}
+
+func (errWriter) Write([]byte) (int, error) {
+	return 0, errors.New("write error")
+}
+
+func TestTapWriteError(t *testing.T) {
+	sink := new(consumertest.TracesSink)
+	tc := NewTapTraces(sink, errWriter{})
+	require.NoError(t, tc.ConsumeTraces(context.Background(), testdata.GenerateTracesOneSpan()))
+	assert.Equal(t, 1, sink.SpansCount())
+}
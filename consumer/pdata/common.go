@@ -18,7 +18,10 @@ package pdata
 // such as timestamps, attributes, etc.
 
 import (
+	"encoding/json"
+	"fmt"
 	"sort"
+	"strconv"
 
 	otlpcommon "go.opentelemetry.io/collector/internal/data/protogen/common/v1"
 )
@@ -258,6 +261,58 @@ func (a AttributeValue) CopyTo(dest AttributeValue) {
 	a.copyTo(dest.orig)
 }
 
+// AsString returns the value converted to a display string, whatever its type: the
+// string values as is, the numbers and bools formatted with strconv, the map and
+// array values as JSON, and an empty string for the null values.
+func (a AttributeValue) AsString() string {
+	switch a.Type() {
+	case AttributeValueTypeNull:
+		return ""
+	case AttributeValueTypeString:
+		return a.StringVal()
+	case AttributeValueTypeBool:
+		return strconv.FormatBool(a.BoolVal())
+	case AttributeValueTypeDouble:
+		return strconv.FormatFloat(a.DoubleVal(), 'f', -1, 64)
+	case AttributeValueTypeInt:
+		return strconv.FormatInt(a.IntVal(), 10)
+	case AttributeValueTypeMap, AttributeValueTypeArray:
+		jsonStr, _ := json.Marshal(a.asRaw())
+		return string(jsonStr)
+	}
+	return fmt.Sprintf("<Unknown OpenTelemetry attribute value type %q>", a.Type())
+}
+
+// asRaw returns the value as a string, int64, float64, bool, map[string]interface{},
+// []interface{}, or nil for the null values.
+func (a AttributeValue) asRaw() interface{} {
+	switch a.Type() {
+	case AttributeValueTypeString:
+		return a.StringVal()
+	case AttributeValueTypeInt:
+		return a.IntVal()
+	case AttributeValueTypeDouble:
+		return a.DoubleVal()
+	case AttributeValueTypeBool:
+		return a.BoolVal()
+	case AttributeValueTypeMap:
+		raw := make(map[string]interface{}, a.MapVal().Len())
+		a.MapVal().Range(func(k string, v AttributeValue) bool {
+			raw[k] = v.asRaw()
+			return true
+		})
+		return raw
+	case AttributeValueTypeArray:
+		arr := a.ArrayVal()
+		raw := make([]interface{}, 0, arr.Len())
+		for i := 0; i < arr.Len(); i++ {
+			raw = append(raw, arr.At(i).asRaw())
+		}
+		return raw
+	}
+	return nil
+}
+
 // Equal checks for equality, it returns true if the objects are equal otherwise false.
 func (a AttributeValue) Equal(av AttributeValue) bool {
 	if a.orig == av.orig {
@@ -343,6 +398,25 @@ func newAttributeKeyValue(k string, av AttributeValue) otlpcommon.KeyValue {
 	return orig
 }
 
+// The scopes of the attributes visited by the RangeAttributes functions, telling
+// where each attribute is located.
+const (
+	AttributeScopeResource  = "resource"
+	AttributeScopeSpan      = "span"
+	AttributeScopeSpanEvent = "span_event"
+	AttributeScopeSpanLink  = "span_link"
+	AttributeScopeDataPoint = "data_point"
+	AttributeScopeLogRecord = "log_record"
+)
+
+// rangeAttributes calls f for all the attributes of am, with the given scope.
+func rangeAttributes(scope string, am AttributeMap, f func(scope, key string, val AttributeValue)) {
+	am.Range(func(k string, v AttributeValue) bool {
+		f(scope, k, v)
+		return true
+	})
+}
+
 // AttributeMap stores a map of attribute keys to values.
 type AttributeMap struct {
 	orig *[]otlpcommon.KeyValue
@@ -412,6 +486,42 @@ func (am AttributeMap) Get(key string) (AttributeValue, bool) {
 	return AttributeValue{nil}, false
 }
 
+// GetString returns the value of the string attribute with the given key. If the key
+// does not exist, or its value is not a string, it returns an empty string and false.
+func (am AttributeMap) GetString(key string) (string, bool) {
+	if av, ok := am.Get(key); ok && av.Type() == AttributeValueTypeString {
+		return av.StringVal(), true
+	}
+	return "", false
+}
+
+// GetInt returns the value of the int attribute with the given key. If the key does
+// not exist, or its value is not an int, it returns 0 and false.
+func (am AttributeMap) GetInt(key string) (int64, bool) {
+	if av, ok := am.Get(key); ok && av.Type() == AttributeValueTypeInt {
+		return av.IntVal(), true
+	}
+	return 0, false
+}
+
+// GetDouble returns the value of the double attribute with the given key. If the key
+// does not exist, or its value is not a double, it returns 0 and false.
+func (am AttributeMap) GetDouble(key string) (float64, bool) {
+	if av, ok := am.Get(key); ok && av.Type() == AttributeValueTypeDouble {
+		return av.DoubleVal(), true
+	}
+	return 0, false
+}
+
+// GetBool returns the value of the bool attribute with the given key. If the key does
+// not exist, or its value is not a bool, it returns false and false.
+func (am AttributeMap) GetBool(key string) (bool, bool) {
+	if av, ok := am.Get(key); ok && av.Type() == AttributeValueTypeBool {
+		return av.BoolVal(), true
+	}
+	return false, false
+}
+
 // Delete deletes the entry associated with the key and returns true if the key
 // was present in the map, otherwise returns false.
 func (am AttributeMap) Delete(key string) bool {
@@ -645,6 +755,55 @@ func (am AttributeMap) CopyTo(dest AttributeMap) {
 	*dest.orig = origs
 }
 
+// Flatten returns the entries of the map as a flat map with dotted keys, where
+// map values are recursively expanded into "key.nested" entries and array values
+// into "key.0", "key.1", etc. entries. If the prefix is not empty it is prepended,
+// followed by a dot, to all the keys.
+//
+// The values in the returned map are string, int64, float64, bool, or nil for
+// null values. Empty map and array values have no entries in the returned map.
+func (am AttributeMap) Flatten(prefix string) map[string]interface{} {
+	dest := make(map[string]interface{}, am.Len())
+	flattenAttributeMap(dest, prefix, am)
+	return dest
+}
+
+func flattenAttributeMap(dest map[string]interface{}, prefix string, am AttributeMap) {
+	am.Range(func(k string, v AttributeValue) bool {
+		flattenAttributeValue(dest, flattenKey(prefix, k), v)
+		return true
+	})
+}
+
+func flattenAttributeValue(dest map[string]interface{}, key string, v AttributeValue) {
+	switch v.Type() {
+	case AttributeValueTypeString:
+		dest[key] = v.StringVal()
+	case AttributeValueTypeInt:
+		dest[key] = v.IntVal()
+	case AttributeValueTypeDouble:
+		dest[key] = v.DoubleVal()
+	case AttributeValueTypeBool:
+		dest[key] = v.BoolVal()
+	case AttributeValueTypeMap:
+		flattenAttributeMap(dest, key, v.MapVal())
+	case AttributeValueTypeArray:
+		arr := v.ArrayVal()
+		for i := 0; i < arr.Len(); i++ {
+			flattenAttributeValue(dest, flattenKey(key, strconv.Itoa(i)), arr.At(i))
+		}
+	default:
+		dest[key] = nil
+	}
+}
+
+func flattenKey(prefix string, key string) string {
+	if prefix == "" {
+		return key
+	}
+	return prefix + "." + key
+}
+
 // StringMap stores a map of attribute keys to values.
 type StringMap struct {
 	orig *[]otlpcommon.StringKeyValue
@@ -633,6 +633,119 @@ func TestAttributeMap_CopyTo(t *testing.T) {
 	assert.EqualValues(t, generateTestAttributeMap(), dest)
 }
 
+func TestAttributeMap_Flatten(t *testing.T) {
+	nested := NewAttributeValueMap()
+	nested.MapVal().InsertString("c", "d")
+	nested.MapVal().InsertDouble("e", 1.5)
+	nested.MapVal().Insert("empty", NewAttributeValueMap())
+
+	arr := NewAttributeValueArray()
+	arr.ArrayVal().AppendEmpty().SetIntVal(1)
+	elem := NewAttributeValueMap()
+	elem.MapVal().InsertBool("f", true)
+	elem.CopyTo(arr.ArrayVal().AppendEmpty())
+	inner := NewAttributeValueArray()
+	inner.ArrayVal().AppendEmpty().SetStringVal("g")
+	inner.CopyTo(arr.ArrayVal().AppendEmpty())
+
+	am := NewAttributeMap()
+	am.InsertString("a", "b")
+	am.InsertNull("null")
+	am.Insert("nested", nested)
+	am.Insert("array", arr)
+	am.Insert("empty", NewAttributeValueArray())
+
+	expected := map[string]interface{}{
+		"a":         "b",
+		"null":      nil,
+		"nested.c":  "d",
+		"nested.e":  1.5,
+		"array.0":   int64(1),
+		"array.1.f": true,
+		"array.2.0": "g",
+	}
+	assert.Equal(t, expected, am.Flatten(""))
+
+	prefixed := am.Flatten("attrs")
+	assert.Len(t, prefixed, len(expected))
+	for k, v := range expected {
+		assert.Equal(t, v, prefixed["attrs."+k])
+	}
+
+	assert.Empty(t, NewAttributeMap().Flatten("attrs"))
+}
+
+func TestAttributeMap_TypedGetters(t *testing.T) {
+	am := NewAttributeMap()
+	am.InsertString("str", "value")
+	am.InsertInt("int", 42)
+	am.InsertDouble("double", 1.5)
+	am.InsertBool("bool", true)
+	am.InsertNull("null")
+
+	s, ok := am.GetString("str")
+	assert.True(t, ok)
+	assert.Equal(t, "value", s)
+	i, ok := am.GetInt("int")
+	assert.True(t, ok)
+	assert.EqualValues(t, 42, i)
+	d, ok := am.GetDouble("double")
+	assert.True(t, ok)
+	assert.Equal(t, 1.5, d)
+	b, ok := am.GetBool("bool")
+	assert.True(t, ok)
+	assert.True(t, b)
+
+	// The type mismatches and the missing keys are reported as not found.
+	for _, key := range []string{"str", "int", "double", "null", "missing"} {
+		b, ok = am.GetBool(key)
+		assert.False(t, ok, key)
+		assert.False(t, b, key)
+	}
+	for _, key := range []string{"int", "double", "bool", "null", "missing"} {
+		s, ok = am.GetString(key)
+		assert.False(t, ok, key)
+		assert.Empty(t, s, key)
+	}
+	for _, key := range []string{"str", "double", "bool", "null", "missing"} {
+		i, ok = am.GetInt(key)
+		assert.False(t, ok, key)
+		assert.Zero(t, i, key)
+	}
+	for _, key := range []string{"str", "int", "bool", "null", "missing"} {
+		d, ok = am.GetDouble(key)
+		assert.False(t, ok, key)
+		assert.Zero(t, d, key)
+	}
+}
+
+func TestAttributeValue_AsString(t *testing.T) {
+	nested := NewAttributeValueMap()
+	nested.MapVal().InsertString("c", "d")
+	arr := NewAttributeValueArray()
+	arr.ArrayVal().AppendEmpty().SetIntVal(1)
+	nested.CopyTo(arr.ArrayVal().AppendEmpty())
+	arr.ArrayVal().AppendEmpty()
+
+	tests := []struct {
+		value    AttributeValue
+		expected string
+	}{
+		{NewAttributeValueNull(), ""},
+		{NewAttributeValueString("value"), "value"},
+		{NewAttributeValueInt(-42), "-42"},
+		{NewAttributeValueDouble(1.25), "1.25"},
+		{NewAttributeValueDouble(1e21), "1000000000000000000000"},
+		{NewAttributeValueBool(true), "true"},
+		{nested, `{"c":"d"}`},
+		{arr, `[1,{"c":"d"},null]`},
+		{NewAttributeValueArray(), `[]`},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.expected, tt.value.AsString())
+	}
+}
+
 func TestAttributeValue_copyTo(t *testing.T) {
 	av := NewAttributeValueNull()
 	destVal := otlpcommon.AnyValue{Value: &otlpcommon.AnyValue_IntValue{}}
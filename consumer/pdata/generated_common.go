@@ -187,7 +187,8 @@ func (es AnyValueArray) MoveAndAppendTo(dest AnyValueArray) {
 }
 
 // RemoveIf calls f sequentially for each element present in the slice.
-// If f returns true, the element is removed from the slice.
+// If f returns true, the element is removed from the slice. The elements are removed
+// in place, without reallocating the slice, and the remaining ones keep their order.
 func (es AnyValueArray) RemoveIf(f func(AttributeValue) bool) {
 	newLen := 0
 	for i := 0; i < len(*es.orig); i++ {
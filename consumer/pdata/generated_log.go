@@ -144,7 +144,8 @@ func (es ResourceLogsSlice) MoveAndAppendTo(dest ResourceLogsSlice) {
 }
 
 // RemoveIf calls f sequentially for each element present in the slice.
-// If f returns true, the element is removed from the slice.
+// If f returns true, the element is removed from the slice. The elements are removed
+// in place, without reallocating the slice, and the remaining ones keep their order.
 func (es ResourceLogsSlice) RemoveIf(f func(ResourceLogs) bool) {
 	newLen := 0
 	for i := 0; i < len(*es.orig); i++ {
@@ -324,7 +325,8 @@ func (es InstrumentationLibraryLogsSlice) MoveAndAppendTo(dest InstrumentationLi
 }
 
 // RemoveIf calls f sequentially for each element present in the slice.
-// If f returns true, the element is removed from the slice.
+// If f returns true, the element is removed from the slice. The elements are removed
+// in place, without reallocating the slice, and the remaining ones keep their order.
 func (es InstrumentationLibraryLogsSlice) RemoveIf(f func(InstrumentationLibraryLogs) bool) {
 	newLen := 0
 	for i := 0; i < len(*es.orig); i++ {
@@ -504,7 +506,8 @@ func (es LogSlice) MoveAndAppendTo(dest LogSlice) {
 }
 
 // RemoveIf calls f sequentially for each element present in the slice.
-// If f returns true, the element is removed from the slice.
+// If f returns true, the element is removed from the slice. The elements are removed
+// in place, without reallocating the slice, and the remaining ones keep their order.
 func (es LogSlice) RemoveIf(f func(LogRecord) bool) {
 	newLen := 0
 	for i := 0; i < len(*es.orig); i++ {
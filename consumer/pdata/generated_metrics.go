@@ -144,7 +144,8 @@ func (es ResourceMetricsSlice) MoveAndAppendTo(dest ResourceMetricsSlice) {
 }
 
 // RemoveIf calls f sequentially for each element present in the slice.
-// If f returns true, the element is removed from the slice.
+// If f returns true, the element is removed from the slice. The elements are removed
+// in place, without reallocating the slice, and the remaining ones keep their order.
 func (es ResourceMetricsSlice) RemoveIf(f func(ResourceMetrics) bool) {
 	newLen := 0
 	for i := 0; i < len(*es.orig); i++ {
@@ -324,7 +325,8 @@ func (es InstrumentationLibraryMetricsSlice) MoveAndAppendTo(dest Instrumentatio
 }
 
 // RemoveIf calls f sequentially for each element present in the slice.
-// If f returns true, the element is removed from the slice.
+// If f returns true, the element is removed from the slice. The elements are removed
+// in place, without reallocating the slice, and the remaining ones keep their order.
 func (es InstrumentationLibraryMetricsSlice) RemoveIf(f func(InstrumentationLibraryMetrics) bool) {
 	newLen := 0
 	for i := 0; i < len(*es.orig); i++ {
@@ -504,7 +506,8 @@ func (es MetricSlice) MoveAndAppendTo(dest MetricSlice) {
 }
 
 // RemoveIf calls f sequentially for each element present in the slice.
-// If f returns true, the element is removed from the slice.
+// If f returns true, the element is removed from the slice. The elements are removed
+// in place, without reallocating the slice, and the remaining ones keep their order.
 func (es MetricSlice) RemoveIf(f func(Metric) bool) {
 	newLen := 0
 	for i := 0; i < len(*es.orig); i++ {
@@ -997,7 +1000,8 @@ func (es IntDataPointSlice) MoveAndAppendTo(dest IntDataPointSlice) {
 }
 
 // RemoveIf calls f sequentially for each element present in the slice.
-// If f returns true, the element is removed from the slice.
+// If f returns true, the element is removed from the slice. The elements are removed
+// in place, without reallocating the slice, and the remaining ones keep their order.
 func (es IntDataPointSlice) RemoveIf(f func(IntDataPoint) bool) {
 	newLen := 0
 	for i := 0; i < len(*es.orig); i++ {
@@ -1210,7 +1214,8 @@ func (es DoubleDataPointSlice) MoveAndAppendTo(dest DoubleDataPointSlice) {
 }
 
 // RemoveIf calls f sequentially for each element present in the slice.
-// If f returns true, the element is removed from the slice.
+// If f returns true, the element is removed from the slice. The elements are removed
+// in place, without reallocating the slice, and the remaining ones keep their order.
 func (es DoubleDataPointSlice) RemoveIf(f func(DoubleDataPoint) bool) {
 	newLen := 0
 	for i := 0; i < len(*es.orig); i++ {
@@ -1423,7 +1428,8 @@ func (es IntHistogramDataPointSlice) MoveAndAppendTo(dest IntHistogramDataPointS
 }
 
 // RemoveIf calls f sequentially for each element present in the slice.
-// If f returns true, the element is removed from the slice.
+// If f returns true, the element is removed from the slice. The elements are removed
+// in place, without reallocating the slice, and the remaining ones keep their order.
 func (es IntHistogramDataPointSlice) RemoveIf(f func(IntHistogramDataPoint) bool) {
 	newLen := 0
 	for i := 0; i < len(*es.orig); i++ {
@@ -1669,7 +1675,8 @@ func (es HistogramDataPointSlice) MoveAndAppendTo(dest HistogramDataPointSlice)
 }
 
 // RemoveIf calls f sequentially for each element present in the slice.
-// If f returns true, the element is removed from the slice.
+// If f returns true, the element is removed from the slice. The elements are removed
+// in place, without reallocating the slice, and the remaining ones keep their order.
 func (es HistogramDataPointSlice) RemoveIf(f func(HistogramDataPoint) bool) {
 	newLen := 0
 	for i := 0; i < len(*es.orig); i++ {
@@ -1915,7 +1922,8 @@ func (es SummaryDataPointSlice) MoveAndAppendTo(dest SummaryDataPointSlice) {
 }
 
 // RemoveIf calls f sequentially for each element present in the slice.
-// If f returns true, the element is removed from the slice.
+// If f returns true, the element is removed from the slice. The elements are removed
+// in place, without reallocating the slice, and the remaining ones keep their order.
 func (es SummaryDataPointSlice) RemoveIf(f func(SummaryDataPoint) bool) {
 	newLen := 0
 	for i := 0; i < len(*es.orig); i++ {
@@ -2139,7 +2147,8 @@ func (es ValueAtQuantileSlice) MoveAndAppendTo(dest ValueAtQuantileSlice) {
 }
 
 // RemoveIf calls f sequentially for each element present in the slice.
-// If f returns true, the element is removed from the slice.
+// If f returns true, the element is removed from the slice. The elements are removed
+// in place, without reallocating the slice, and the remaining ones keep their order.
 func (es ValueAtQuantileSlice) RemoveIf(f func(ValueAtQuantile) bool) {
 	newLen := 0
 	for i := 0; i < len(*es.orig); i++ {
@@ -2324,7 +2333,8 @@ func (es IntExemplarSlice) MoveAndAppendTo(dest IntExemplarSlice) {
 }
 
 // RemoveIf calls f sequentially for each element present in the slice.
-// If f returns true, the element is removed from the slice.
+// If f returns true, the element is removed from the slice. The elements are removed
+// in place, without reallocating the slice, and the remaining ones keep their order.
 func (es IntExemplarSlice) RemoveIf(f func(IntExemplar) bool) {
 	newLen := 0
 	for i := 0; i < len(*es.orig); i++ {
@@ -2518,7 +2528,8 @@ func (es ExemplarSlice) MoveAndAppendTo(dest ExemplarSlice) {
 }
 
 // RemoveIf calls f sequentially for each element present in the slice.
-// If f returns true, the element is removed from the slice.
+// If f returns true, the element is removed from the slice. The elements are removed
+// in place, without reallocating the slice, and the remaining ones keep their order.
 func (es ExemplarSlice) RemoveIf(f func(Exemplar) bool) {
 	newLen := 0
 	for i := 0; i < len(*es.orig); i++ {
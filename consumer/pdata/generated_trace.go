@@ -144,7 +144,8 @@ func (es ResourceSpansSlice) MoveAndAppendTo(dest ResourceSpansSlice) {
 }
 
 // RemoveIf calls f sequentially for each element present in the slice.
-// If f returns true, the element is removed from the slice.
+// If f returns true, the element is removed from the slice. The elements are removed
+// in place, without reallocating the slice, and the remaining ones keep their order.
 func (es ResourceSpansSlice) RemoveIf(f func(ResourceSpans) bool) {
 	newLen := 0
 	for i := 0; i < len(*es.orig); i++ {
@@ -324,7 +325,8 @@ func (es InstrumentationLibrarySpansSlice) MoveAndAppendTo(dest InstrumentationL
 }
 
 // RemoveIf calls f sequentially for each element present in the slice.
-// If f returns true, the element is removed from the slice.
+// If f returns true, the element is removed from the slice. The elements are removed
+// in place, without reallocating the slice, and the remaining ones keep their order.
 func (es InstrumentationLibrarySpansSlice) RemoveIf(f func(InstrumentationLibrarySpans) bool) {
 	newLen := 0
 	for i := 0; i < len(*es.orig); i++ {
@@ -504,7 +506,8 @@ func (es SpanSlice) MoveAndAppendTo(dest SpanSlice) {
 }
 
 // RemoveIf calls f sequentially for each element present in the slice.
-// If f returns true, the element is removed from the slice.
+// If f returns true, the element is removed from the slice. The elements are removed
+// in place, without reallocating the slice, and the remaining ones keep their order.
 func (es SpanSlice) RemoveIf(f func(Span) bool) {
 	newLen := 0
 	for i := 0; i < len(*es.orig); i++ {
@@ -818,7 +821,8 @@ func (es SpanEventSlice) MoveAndAppendTo(dest SpanEventSlice) {
 }
 
 // RemoveIf calls f sequentially for each element present in the slice.
-// If f returns true, the element is removed from the slice.
+// If f returns true, the element is removed from the slice. The elements are removed
+// in place, without reallocating the slice, and the remaining ones keep their order.
 func (es SpanEventSlice) RemoveIf(f func(SpanEvent) bool) {
 	newLen := 0
 	for i := 0; i < len(*es.orig); i++ {
@@ -1026,7 +1030,8 @@ func (es SpanLinkSlice) MoveAndAppendTo(dest SpanLinkSlice) {
 }
 
 // RemoveIf calls f sequentially for each element present in the slice.
-// If f returns true, the element is removed from the slice.
+// If f returns true, the element is removed from the slice. The elements are removed
+// in place, without reallocating the slice, and the remaining ones keep their order.
 func (es SpanLinkSlice) RemoveIf(f func(SpanLink) bool) {
 	newLen := 0
 	for i := 0; i < len(*es.orig); i++ {
@@ -0,0 +1,81 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pdata
+
+import (
+	"bytes"
+
+	"github.com/gogo/protobuf/jsonpb"
+	"github.com/gogo/protobuf/proto"
+)
+
+// otlpJSONMarshaler implements the OTLP/JSON encoding: lowerCamelCase field names,
+// enums as integers, and trace/span ids as hex strings (via the data.TraceID and
+// data.SpanID JSON methods). Gogoproto-compatible jsonpb is required for the ids.
+var otlpJSONMarshaler = &jsonpb.Marshaler{EnumsAsInts: true}
+
+// protoNamesJSONMarshaler is the otlpJSONMarshaler using the proto field names.
+var protoNamesJSONMarshaler = &jsonpb.Marshaler{EnumsAsInts: true, OrigName: true}
+
+// otlpJSONUnmarshaler ignores unknown fields, as required by the OTLP/JSON encoding.
+// It accepts both the lowerCamelCase and the proto field names.
+var otlpJSONUnmarshaler = &jsonpb.Unmarshaler{AllowUnknownFields: true}
+
+// JSONFieldNaming defines the naming of the fields in the JSON encoding.
+type JSONFieldNaming int
+
+const (
+	// JSONFieldNamingCamelCase uses the lowerCamelCase field names, e.g. traceId, as
+	// required by the OTLP/JSON encoding.
+	JSONFieldNamingCamelCase JSONFieldNaming = iota
+	// JSONFieldNamingProto uses the field names of the proto definitions, e.g.
+	// trace_id, expected by some tools consuming the JSON.
+	JSONFieldNamingProto
+)
+
+// JSONOption customizes the JSON encoding of the ToOtlpJSONBytes functions.
+type JSONOption func(*jsonOptions)
+
+type jsonOptions struct {
+	fieldNaming JSONFieldNaming
+}
+
+// WithJSONFieldNaming defines the naming of the fields, JSONFieldNamingCamelCase by
+// default. The FromOtlpJSONBytes functions accept both namings.
+func WithJSONFieldNaming(naming JSONFieldNaming) JSONOption {
+	return func(o *jsonOptions) {
+		o.fieldNaming = naming
+	}
+}
+
+func marshalOtlpJSON(pb proto.Message, opts []JSONOption) ([]byte, error) {
+	o := jsonOptions{}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	marshaler := otlpJSONMarshaler
+	if o.fieldNaming == JSONFieldNamingProto {
+		marshaler = protoNamesJSONMarshaler
+	}
+	buf := bytes.Buffer{}
+	if err := marshaler.Marshal(&buf, pb); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func unmarshalOtlpJSON(data []byte, pb proto.Message) error {
+	return otlpJSONUnmarshaler.Unmarshal(bytes.NewReader(data), pb)
+}
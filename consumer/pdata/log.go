@@ -56,6 +56,18 @@ func LogsFromOtlpProtoBytes(data []byte) (Logs, error) {
 	return Logs{orig: &req}, nil
 }
 
+// LogsFromOtlpJSONBytes converts OTLP Collector ExportLogsServiceRequest
+// OTLP/JSON bytes to the internal Logs.
+//
+// Returns an invalid Logs instance if error is not nil.
+func LogsFromOtlpJSONBytes(data []byte) (Logs, error) {
+	req := otlpcollectorlog.ExportLogsServiceRequest{}
+	if err := unmarshalOtlpJSON(data, &req); err != nil {
+		return Logs{}, err
+	}
+	return Logs{orig: &req}, nil
+}
+
 // InternalRep returns internal representation of the logs. Should not be used outside
 // this module. This is intended to be used only by OTLP exporter and File exporter,
 // which legitimately need to work with OTLP Protobuf structs.
@@ -71,6 +83,14 @@ func (ld Logs) ToOtlpProtoBytes() ([]byte, error) {
 	return ld.orig.Marshal()
 }
 
+// ToOtlpJSONBytes converts this Logs to the OTLP Collector ExportLogsServiceRequest
+// OTLP/JSON bytes, customized by the options.
+//
+// Returns an nil byte-array if error is not nil.
+func (ld Logs) ToOtlpJSONBytes(opts ...JSONOption) ([]byte, error) {
+	return marshalOtlpJSON(ld.orig, opts)
+}
+
 // Clone returns a copy of Logs.
 func (ld Logs) Clone() Logs {
 	cloneLd := NewLogs()
@@ -93,6 +113,23 @@ func (ld Logs) LogRecordCount() int {
 	return logCount
 }
 
+// RangeAttributes calls f for all the attributes of ld, with the scope of the
+// attribute: the resource and log record attributes.
+func (ld Logs) RangeAttributes(f func(scope, key string, val AttributeValue)) {
+	rls := ld.ResourceLogs()
+	for i := 0; i < rls.Len(); i++ {
+		rl := rls.At(i)
+		rangeAttributes(AttributeScopeResource, rl.Resource().Attributes(), f)
+		ills := rl.InstrumentationLibraryLogs()
+		for j := 0; j < ills.Len(); j++ {
+			logs := ills.At(j).Logs()
+			for k := 0; k < logs.Len(); k++ {
+				rangeAttributes(AttributeScopeLogRecord, logs.At(k).Attributes(), f)
+			}
+		}
+	}
+}
+
 // OtlpProtoSize returns the size in bytes of this Logs encoded as OTLP Collector
 // ExportLogsServiceRequest ProtoBuf bytes.
 func (ld Logs) OtlpProtoSize() int {
@@ -94,6 +94,24 @@ func TestLogsFromInvalidOtlpProtoBytes(t *testing.T) {
 	assert.EqualError(t, err, "unexpected EOF")
 }
 
+func TestLogsToFromOtlpJSONBytes(t *testing.T) {
+	send := NewLogs()
+	fillTestResourceLogsSlice(send.ResourceLogs())
+	for _, naming := range []JSONFieldNaming{JSONFieldNamingCamelCase, JSONFieldNamingProto} {
+		bytes, err := send.ToOtlpJSONBytes(WithJSONFieldNaming(naming))
+		assert.NoError(t, err)
+
+		recv, err := LogsFromOtlpJSONBytes(bytes)
+		assert.NoError(t, err)
+		assert.EqualValues(t, send, recv)
+	}
+}
+
+func TestLogsFromInvalidOtlpJSONBytes(t *testing.T) {
+	_, err := LogsFromOtlpJSONBytes([]byte("{"))
+	assert.Error(t, err)
+}
+
 func TestLogsClone(t *testing.T) {
 	logs := NewLogs()
 	fillTestResourceLogsSlice(logs.ResourceLogs())
@@ -137,3 +155,36 @@ func BenchmarkLogsFromOtlp(b *testing.B) {
 		assert.Equal(b, baseLogs.ResourceLogs().Len(), logs.ResourceLogs().Len())
 	}
 }
+
+func TestLogSliceRemoveIfKeepsOrder(t *testing.T) {
+	logs := NewLogSlice()
+	for _, name := range []string{"a", "b", "c", "d", "e"} {
+		logs.AppendEmpty().SetName(name)
+	}
+	logs.RemoveIf(func(lr LogRecord) bool {
+		return lr.Name() == "b" || lr.Name() == "c"
+	})
+	require.Equal(t, 3, logs.Len())
+	for i, name := range []string{"a", "d", "e"} {
+		assert.Equal(t, name, logs.At(i).Name())
+	}
+}
+
+func TestLogsRangeAttributes(t *testing.T) {
+	ld := NewLogs()
+	rl := ld.ResourceLogs().AppendEmpty()
+	rl.Resource().Attributes().InsertString("service.name", "svc")
+	logs := rl.InstrumentationLibraryLogs().AppendEmpty().Logs()
+	logs.AppendEmpty().Attributes().InsertString("http.method", "GET")
+	logs.AppendEmpty().Attributes().InsertInt("http.status_code", 200)
+
+	var got []string
+	ld.RangeAttributes(func(scope, key string, val AttributeValue) {
+		got = append(got, scope+":"+key+"="+val.Type().String())
+	})
+	assert.Equal(t, []string{
+		"resource:service.name=STRING",
+		"log_record:http.method=STRING",
+		"log_record:http.status_code=INT",
+	}, got)
+}
@@ -70,6 +70,18 @@ func MetricsFromOtlpProtoBytes(data []byte) (Metrics, error) {
 	return Metrics{orig: &req}, nil
 }
 
+// MetricsFromOtlpJSONBytes converts the OTLP Collector ExportMetricsServiceRequest
+// OTLP/JSON bytes to Metrics.
+//
+// Returns an invalid Metrics instance if error is not nil.
+func MetricsFromOtlpJSONBytes(data []byte) (Metrics, error) {
+	req := otlpcollectormetrics.ExportMetricsServiceRequest{}
+	if err := unmarshalOtlpJSON(data, &req); err != nil {
+		return Metrics{}, err
+	}
+	return Metrics{orig: &req}, nil
+}
+
 // InternalRep returns internal representation of the Metrics.
 // Should not be used outside this module.
 func (md Metrics) InternalRep() internal.MetricsWrapper {
@@ -84,6 +96,14 @@ func (md Metrics) ToOtlpProtoBytes() ([]byte, error) {
 	return md.orig.Marshal()
 }
 
+// ToOtlpJSONBytes converts this Metrics to the OTLP Collector ExportMetricsServiceRequest
+// OTLP/JSON bytes, customized by the options.
+//
+// Returns an nil byte-array if error is not nil.
+func (md Metrics) ToOtlpJSONBytes(opts ...JSONOption) ([]byte, error) {
+	return marshalOtlpJSON(md.orig, opts)
+}
+
 // Clone returns a copy of MetricData.
 func (md Metrics) Clone() Metrics {
 	cloneMd := NewMetrics()
@@ -152,6 +172,79 @@ func (md Metrics) MetricAndDataPointCount() (metricCount int, dataPointCount int
 	return
 }
 
+// RangeAttributes calls f for all the attributes of md, with the scope of the
+// attribute: the resource attributes and the data point labels, as string values.
+func (md Metrics) RangeAttributes(f func(scope, key string, val AttributeValue)) {
+	rms := md.ResourceMetrics()
+	for i := 0; i < rms.Len(); i++ {
+		rm := rms.At(i)
+		rangeAttributes(AttributeScopeResource, rm.Resource().Attributes(), f)
+		ilms := rm.InstrumentationLibraryMetrics()
+		for j := 0; j < ilms.Len(); j++ {
+			ms := ilms.At(j).Metrics()
+			for k := 0; k < ms.Len(); k++ {
+				for _, labels := range ms.At(k).DataPointLabels() {
+					labels.Range(func(key string, val string) bool {
+						f(AttributeScopeDataPoint, key, NewAttributeValueString(val))
+						return true
+					})
+				}
+			}
+		}
+	}
+}
+
+// DataPointLabels returns the labels of all the data points of the metric, in order,
+// e.g. to identify the series of the metric.
+func (ms Metric) DataPointLabels() []StringMap {
+	var labels []StringMap
+	switch ms.DataType() {
+	case MetricDataTypeIntGauge:
+		dps := ms.IntGauge().DataPoints()
+		for i := 0; i < dps.Len(); i++ {
+			labels = append(labels, dps.At(i).LabelsMap())
+		}
+	case MetricDataTypeDoubleGauge:
+		dps := ms.DoubleGauge().DataPoints()
+		for i := 0; i < dps.Len(); i++ {
+			labels = append(labels, dps.At(i).LabelsMap())
+		}
+	case MetricDataTypeIntSum:
+		dps := ms.IntSum().DataPoints()
+		for i := 0; i < dps.Len(); i++ {
+			labels = append(labels, dps.At(i).LabelsMap())
+		}
+	case MetricDataTypeDoubleSum:
+		dps := ms.DoubleSum().DataPoints()
+		for i := 0; i < dps.Len(); i++ {
+			labels = append(labels, dps.At(i).LabelsMap())
+		}
+	case MetricDataTypeIntHistogram:
+		dps := ms.IntHistogram().DataPoints()
+		for i := 0; i < dps.Len(); i++ {
+			labels = append(labels, dps.At(i).LabelsMap())
+		}
+	case MetricDataTypeHistogram:
+		dps := ms.Histogram().DataPoints()
+		for i := 0; i < dps.Len(); i++ {
+			labels = append(labels, dps.At(i).LabelsMap())
+		}
+	case MetricDataTypeSummary:
+		dps := ms.Summary().DataPoints()
+		for i := 0; i < dps.Len(); i++ {
+			labels = append(labels, dps.At(i).LabelsMap())
+		}
+	}
+	return labels
+}
+
+// DataPointCount calculates the total number of data points, which is more
+// representative of the load than the number of metrics.
+func (md Metrics) DataPointCount() int {
+	_, dataPointCount := md.MetricAndDataPointCount()
+	return dataPointCount
+}
+
 // MetricDataType specifies the type of data in a Metric.
 type MetricDataType int32
 
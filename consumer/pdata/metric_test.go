@@ -15,6 +15,7 @@
 package pdata
 
 import (
+	"strconv"
 	"testing"
 
 	gogoproto "github.com/gogo/protobuf/proto"
@@ -295,6 +296,23 @@ func TestMetricAndDataPointCountWithNilDataPoints(t *testing.T) {
 	assert.EqualValues(t, 0, dps)
 }
 
+func TestDataPointCount(t *testing.T) {
+	assert.EqualValues(t, 0, NewMetrics().DataPointCount())
+	assert.EqualValues(t, 0, generateMetricsEmptyMetrics().DataPointCount())
+	assert.EqualValues(t, 1, generateMetricsEmptyDataPoints().DataPointCount())
+
+	md := NewMetrics()
+	ms := md.ResourceMetrics().AppendEmpty().InstrumentationLibraryMetrics().AppendEmpty().Metrics()
+	intSum := ms.AppendEmpty()
+	intSum.SetDataType(MetricDataTypeIntSum)
+	intSum.IntSum().DataPoints().Resize(1000)
+	histogram := ms.AppendEmpty()
+	histogram.SetDataType(MetricDataTypeHistogram)
+	histogram.Histogram().DataPoints().Resize(3)
+	assert.EqualValues(t, 2, md.MetricCount())
+	assert.EqualValues(t, 1003, md.DataPointCount())
+}
+
 func TestOtlpToInternalReadOnly(t *testing.T) {
 	metricData := MetricsFromInternalRep(internal.MetricsFromOtlp(&otlpcollectormetrics.ExportMetricsServiceRequest{
 		ResourceMetrics: []*otlpmetrics.ResourceMetrics{
@@ -672,6 +690,24 @@ func TestMetricsFromInvalidOtlpProtoBytes(t *testing.T) {
 	assert.EqualError(t, err, "unexpected EOF")
 }
 
+func TestMetricsToFromOtlpJSONBytes(t *testing.T) {
+	send := NewMetrics()
+	fillTestResourceMetricsSlice(send.ResourceMetrics())
+	for _, naming := range []JSONFieldNaming{JSONFieldNamingCamelCase, JSONFieldNamingProto} {
+		bytes, err := send.ToOtlpJSONBytes(WithJSONFieldNaming(naming))
+		assert.NoError(t, err)
+
+		recv, err := MetricsFromOtlpJSONBytes(bytes)
+		assert.NoError(t, err)
+		assert.EqualValues(t, send, recv)
+	}
+}
+
+func TestMetricsFromInvalidOtlpJSONBytes(t *testing.T) {
+	_, err := MetricsFromOtlpJSONBytes([]byte("{"))
+	assert.Error(t, err)
+}
+
 func TestMetricsClone(t *testing.T) {
 	metrics := NewMetrics()
 	fillTestResourceMetricsSlice(metrics.ResourceMetrics())
@@ -1023,3 +1059,113 @@ func generateMetricsEmptyDataPoints() Metrics {
 		},
 	}))
 }
+
+func TestMetricSliceRemoveIfKeepsOrder(t *testing.T) {
+	metrics := NewMetricSlice()
+	for _, name := range []string{"a", "b", "c", "d", "e"} {
+		metrics.AppendEmpty().SetName(name)
+	}
+	metrics.RemoveIf(func(m Metric) bool {
+		return m.Name() == "e"
+	})
+	require.Equal(t, 4, metrics.Len())
+	for i, name := range []string{"a", "b", "c", "d"} {
+		assert.Equal(t, name, metrics.At(i).Name())
+	}
+}
+
+func TestMetricDataPointLabels(t *testing.T) {
+	metrics := NewMetricSlice()
+	for _, dataType := range []MetricDataType{
+		MetricDataTypeIntGauge,
+		MetricDataTypeDoubleGauge,
+		MetricDataTypeIntSum,
+		MetricDataTypeDoubleSum,
+		MetricDataTypeIntHistogram,
+		MetricDataTypeHistogram,
+		MetricDataTypeSummary,
+	} {
+		m := metrics.AppendEmpty()
+		m.SetDataType(dataType)
+		switch dataType {
+		case MetricDataTypeIntGauge:
+			m.IntGauge().DataPoints().AppendEmpty().LabelsMap().Insert("k", "0")
+			m.IntGauge().DataPoints().AppendEmpty().LabelsMap().Insert("k", "1")
+		case MetricDataTypeDoubleGauge:
+			m.DoubleGauge().DataPoints().AppendEmpty().LabelsMap().Insert("k", "0")
+			m.DoubleGauge().DataPoints().AppendEmpty().LabelsMap().Insert("k", "1")
+		case MetricDataTypeIntSum:
+			m.IntSum().DataPoints().AppendEmpty().LabelsMap().Insert("k", "0")
+			m.IntSum().DataPoints().AppendEmpty().LabelsMap().Insert("k", "1")
+		case MetricDataTypeDoubleSum:
+			m.DoubleSum().DataPoints().AppendEmpty().LabelsMap().Insert("k", "0")
+			m.DoubleSum().DataPoints().AppendEmpty().LabelsMap().Insert("k", "1")
+		case MetricDataTypeIntHistogram:
+			m.IntHistogram().DataPoints().AppendEmpty().LabelsMap().Insert("k", "0")
+			m.IntHistogram().DataPoints().AppendEmpty().LabelsMap().Insert("k", "1")
+		case MetricDataTypeHistogram:
+			m.Histogram().DataPoints().AppendEmpty().LabelsMap().Insert("k", "0")
+			m.Histogram().DataPoints().AppendEmpty().LabelsMap().Insert("k", "1")
+		case MetricDataTypeSummary:
+			m.Summary().DataPoints().AppendEmpty().LabelsMap().Insert("k", "0")
+			m.Summary().DataPoints().AppendEmpty().LabelsMap().Insert("k", "1")
+		}
+		labels := m.DataPointLabels()
+		require.Len(t, labels, 2, dataType.String())
+		for i, l := range labels {
+			v, _ := l.Get("k")
+			assert.Equal(t, strconv.Itoa(i), v, dataType.String())
+		}
+	}
+	assert.Empty(t, NewMetric().DataPointLabels())
+}
+
+func TestMetricsRangeAttributes(t *testing.T) {
+	md := NewMetrics()
+	rm := md.ResourceMetrics().AppendEmpty()
+	rm.Resource().Attributes().InsertInt("host.cpus", 4)
+	ms := rm.InstrumentationLibraryMetrics().AppendEmpty().Metrics()
+	for _, dataType := range []MetricDataType{
+		MetricDataTypeIntGauge,
+		MetricDataTypeDoubleGauge,
+		MetricDataTypeIntSum,
+		MetricDataTypeDoubleSum,
+		MetricDataTypeIntHistogram,
+		MetricDataTypeHistogram,
+		MetricDataTypeSummary,
+	} {
+		m := ms.AppendEmpty()
+		m.SetDataType(dataType)
+		switch dataType {
+		case MetricDataTypeIntGauge:
+			m.IntGauge().DataPoints().AppendEmpty().LabelsMap().Insert("type", dataType.String())
+		case MetricDataTypeDoubleGauge:
+			m.DoubleGauge().DataPoints().AppendEmpty().LabelsMap().Insert("type", dataType.String())
+		case MetricDataTypeIntSum:
+			m.IntSum().DataPoints().AppendEmpty().LabelsMap().Insert("type", dataType.String())
+		case MetricDataTypeDoubleSum:
+			m.DoubleSum().DataPoints().AppendEmpty().LabelsMap().Insert("type", dataType.String())
+		case MetricDataTypeIntHistogram:
+			m.IntHistogram().DataPoints().AppendEmpty().LabelsMap().Insert("type", dataType.String())
+		case MetricDataTypeHistogram:
+			m.Histogram().DataPoints().AppendEmpty().LabelsMap().Insert("type", dataType.String())
+		case MetricDataTypeSummary:
+			m.Summary().DataPoints().AppendEmpty().LabelsMap().Insert("type", dataType.String())
+		}
+	}
+
+	var got []string
+	md.RangeAttributes(func(scope, key string, val AttributeValue) {
+		got = append(got, scope+":"+key+"="+val.StringVal())
+	})
+	assert.Equal(t, []string{
+		"resource:host.cpus=",
+		"data_point:type=IntGauge",
+		"data_point:type=DoubleGauge",
+		"data_point:type=IntSum",
+		"data_point:type=DoubleSum",
+		"data_point:type=IntHistogram",
+		"data_point:type=Histogram",
+		"data_point:type=Summary",
+	}, got)
+}
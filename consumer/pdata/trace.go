@@ -51,6 +51,19 @@ func TracesFromOtlpProtoBytes(data []byte) (Traces, error) {
 	return Traces{orig: &req}, nil
 }
 
+// TracesFromOtlpJSONBytes converts OTLP Collector ExportTraceServiceRequest
+// OTLP/JSON bytes to the internal Traces.
+//
+// Returns an invalid Traces instance if error is not nil.
+func TracesFromOtlpJSONBytes(data []byte) (Traces, error) {
+	req := otlpcollectortrace.ExportTraceServiceRequest{}
+	if err := unmarshalOtlpJSON(data, &req); err != nil {
+		return Traces{}, err
+	}
+	internal.TracesCompatibilityChanges(&req)
+	return Traces{orig: &req}, nil
+}
+
 // InternalRep returns internal representation of the Traces.
 // Should not be used outside this module.
 func (td Traces) InternalRep() internal.TracesWrapper {
@@ -65,6 +78,14 @@ func (td Traces) ToOtlpProtoBytes() ([]byte, error) {
 	return td.orig.Marshal()
 }
 
+// ToOtlpJSONBytes converts this Traces to the OTLP Collector ExportTraceServiceRequest
+// OTLP/JSON bytes, customized by the options.
+//
+// Returns an nil byte-array if error is not nil.
+func (td Traces) ToOtlpJSONBytes(opts ...JSONOption) ([]byte, error) {
+	return marshalOtlpJSON(td.orig, opts)
+}
+
 // Clone returns a copy of Traces.
 func (td Traces) Clone() Traces {
 	cloneTd := NewTraces()
@@ -86,6 +107,57 @@ func (td Traces) SpanCount() int {
 	return spanCount
 }
 
+// attributeServiceName is the conventions.AttributeServiceName resource attribute,
+// defined here since pdata does not depend on the translator packages.
+const attributeServiceName = "service.name"
+
+// ServiceNames returns the distinct values of the service.name attribute of the resources
+// of td, in the order of their first resource, e.g. to summarize the services of a batch.
+// The resources without the attribute contribute an empty string.
+func (td Traces) ServiceNames() []string {
+	var names []string
+	seen := make(map[string]struct{})
+	rss := td.ResourceSpans()
+	for i := 0; i < rss.Len(); i++ {
+		name := ""
+		if v, ok := rss.At(i).Resource().Attributes().Get(attributeServiceName); ok {
+			name = v.AsString()
+		}
+		if _, ok := seen[name]; ok {
+			continue
+		}
+		seen[name] = struct{}{}
+		names = append(names, name)
+	}
+	return names
+}
+
+// RangeAttributes calls f for all the attributes of td, with the scope of the
+// attribute: the resource, span, span event and span link attributes.
+func (td Traces) RangeAttributes(f func(scope, key string, val AttributeValue)) {
+	rss := td.ResourceSpans()
+	for i := 0; i < rss.Len(); i++ {
+		rs := rss.At(i)
+		rangeAttributes(AttributeScopeResource, rs.Resource().Attributes(), f)
+		ilss := rs.InstrumentationLibrarySpans()
+		for j := 0; j < ilss.Len(); j++ {
+			spans := ilss.At(j).Spans()
+			for k := 0; k < spans.Len(); k++ {
+				span := spans.At(k)
+				rangeAttributes(AttributeScopeSpan, span.Attributes(), f)
+				events := span.Events()
+				for l := 0; l < events.Len(); l++ {
+					rangeAttributes(AttributeScopeSpanEvent, events.At(l).Attributes(), f)
+				}
+				links := span.Links()
+				for l := 0; l < links.Len(); l++ {
+					rangeAttributes(AttributeScopeSpanLink, links.At(l).Attributes(), f)
+				}
+			}
+		}
+	}
+}
+
 // OtlpProtoSize returns the size in bytes of this Traces encoded as OTLP Collector
 // ExportTraceServiceRequest ProtoBuf bytes.
 func (td Traces) OtlpProtoSize() int {
@@ -160,3 +232,58 @@ func (ms SpanStatus) SetCode(v StatusCode) {
 		ms.orig.DeprecatedCode = otlptrace.Status_DEPRECATED_STATUS_CODE_UNKNOWN_ERROR
 	}
 }
+
+// DedupLinks removes the links of the span with the same trace ID, span ID and attributes
+// as a previous link, e.g. emitted twice by buggy instrumentation. The links keep the
+// order of their first occurrence. The attributes are compared regardless of their order,
+// and the trace state and dropped attributes count of the links are not compared.
+func (ms Span) DedupLinks() {
+	var kept []SpanLink
+	ms.Links().RemoveIf(func(link SpanLink) bool {
+		for _, k := range kept {
+			if k.TraceID() == link.TraceID() && k.SpanID() == link.SpanID() && attributeMapsEqual(k.Attributes(), link.Attributes()) {
+				return true
+			}
+		}
+		kept = append(kept, link)
+		return false
+	})
+}
+
+// attributeMapsEqual returns true if a and b have the same keys with equal values.
+func attributeMapsEqual(a, b AttributeMap) bool {
+	if a.Len() != b.Len() {
+		return false
+	}
+	equal := true
+	a.Range(func(k string, v AttributeValue) bool {
+		bv, ok := b.Get(k)
+		equal = ok && v.Equal(bv)
+		return equal
+	})
+	return equal
+}
+
+// GroupTracesByResourceAttribute partitions the resource spans of td by the value of the
+// given attribute of their resource, as returned by AttributeValue.AsString, e.g. to route
+// the spans of every tenant to its own backend. The resource spans whose resource does not
+// have the attribute are grouped under the empty string. The resource spans are copied, with
+// their resource and instrumentation libraries, so td is left unchanged.
+func GroupTracesByResourceAttribute(td Traces, key string) map[string]Traces {
+	groups := make(map[string]Traces)
+	rss := td.ResourceSpans()
+	for i := 0; i < rss.Len(); i++ {
+		rs := rss.At(i)
+		value := ""
+		if v, ok := rs.Resource().Attributes().Get(key); ok {
+			value = v.AsString()
+		}
+		group, ok := groups[value]
+		if !ok {
+			group = NewTraces()
+			groups[value] = group
+		}
+		rs.CopyTo(group.ResourceSpans().AppendEmpty())
+	}
+	return groups
+}
@@ -49,6 +49,28 @@ func TestSpanCount(t *testing.T) {
 	assert.EqualValues(t, 6, md.SpanCount())
 }
 
+func TestTracesServiceNames(t *testing.T) {
+	td := NewTraces()
+	assert.Empty(t, td.ServiceNames())
+
+	for _, name := range []string{"frontend", "backend", "", "frontend", "db", "backend"} {
+		rs := td.ResourceSpans().AppendEmpty()
+		if name != "" {
+			rs.Resource().Attributes().InsertString("service.name", name)
+		}
+		rs.InstrumentationLibrarySpans().AppendEmpty().Spans().AppendEmpty()
+	}
+	assert.Equal(t, []string{"frontend", "backend", "", "db"}, td.ServiceNames())
+
+	// A resource with an empty service.name is not distinguished from one without it.
+	td.ResourceSpans().AppendEmpty().Resource().Attributes().InsertString("service.name", "")
+	assert.Equal(t, []string{"frontend", "backend", "", "db"}, td.ServiceNames())
+
+	// Non-string values are converted.
+	td.ResourceSpans().AppendEmpty().Resource().Attributes().InsertInt("service.name", 42)
+	assert.Equal(t, []string{"frontend", "backend", "", "db", "42"}, td.ServiceNames())
+}
+
 func TestSize(t *testing.T) {
 	td := NewTraces()
 	assert.Equal(t, 0, td.OtlpProtoSize())
@@ -177,6 +199,45 @@ func TestTracesFromInvalidOtlpProtoBytes(t *testing.T) {
 	assert.EqualError(t, err, "unexpected EOF")
 }
 
+func TestTracesToFromOtlpJSONBytes(t *testing.T) {
+	send := NewTraces()
+	fillTestResourceSpansSlice(send.ResourceSpans())
+	for _, naming := range []JSONFieldNaming{JSONFieldNamingCamelCase, JSONFieldNamingProto} {
+		bytes, err := send.ToOtlpJSONBytes(WithJSONFieldNaming(naming))
+		assert.NoError(t, err)
+
+		recv, err := TracesFromOtlpJSONBytes(bytes)
+		assert.NoError(t, err)
+		assert.EqualValues(t, send, recv)
+	}
+}
+
+func TestTracesToOtlpJSONBytesEncoding(t *testing.T) {
+	td := NewTraces()
+	span := td.ResourceSpans().AppendEmpty().InstrumentationLibrarySpans().AppendEmpty().Spans().AppendEmpty()
+	span.SetTraceID(NewTraceID([16]byte{1, 2, 3, 4, 5, 6, 7, 8, 8, 7, 6, 5, 4, 3, 2, 1}))
+	span.SetSpanID(NewSpanID([8]byte{1, 2, 3, 4, 5, 6, 7, 8}))
+	span.SetKind(SpanKindServer)
+	span.SetStartTimestamp(Timestamp(1234))
+
+	bytes, err := td.ToOtlpJSONBytes()
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"resourceSpans":[{"resource":{},"instrumentationLibrarySpans":[{"instrumentationLibrary":{},"spans":[{`+
+		`"traceId":"01020304050607080807060504030201","spanId":"0102030405060708","parentSpanId":"",`+
+		`"kind":2,"startTimeUnixNano":"1234","status":{}}]}]}]}`, string(bytes))
+
+	bytes, err = td.ToOtlpJSONBytes(WithJSONFieldNaming(JSONFieldNamingProto))
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"resource_spans":[{"resource":{},"instrumentation_library_spans":[{"instrumentation_library":{},"spans":[{`+
+		`"trace_id":"01020304050607080807060504030201","span_id":"0102030405060708","parent_span_id":"",`+
+		`"kind":2,"start_time_unix_nano":"1234","status":{}}]}]}]}`, string(bytes))
+}
+
+func TestTracesFromInvalidOtlpJSONBytes(t *testing.T) {
+	_, err := TracesFromOtlpJSONBytes([]byte("{"))
+	assert.Error(t, err)
+}
+
 func TestTracesClone(t *testing.T) {
 	traces := NewTraces()
 	fillTestResourceSpansSlice(traces.ResourceSpans())
@@ -220,3 +281,143 @@ func BenchmarkTracesFromOtlp(b *testing.B) {
 		assert.Equal(b, baseTraces.ResourceSpans().Len(), traces.ResourceSpans().Len())
 	}
 }
+
+func TestResourceSpansRemoveIfKeepsOrder(t *testing.T) {
+	td := NewTraces()
+	for _, name := range []string{"a", "b", "c", "d", "e"} {
+		td.ResourceSpans().AppendEmpty().Resource().Attributes().InsertString("name", name)
+	}
+	td.ResourceSpans().RemoveIf(func(rs ResourceSpans) bool {
+		name, _ := rs.Resource().Attributes().Get("name")
+		return name.StringVal() == "b" || name.StringVal() == "d"
+	})
+	var names []string
+	for i := 0; i < td.ResourceSpans().Len(); i++ {
+		name, _ := td.ResourceSpans().At(i).Resource().Attributes().Get("name")
+		names = append(names, name.StringVal())
+	}
+	assert.Equal(t, []string{"a", "c", "e"}, names)
+}
+
+func TestSpanSliceRemoveIfKeepsOrder(t *testing.T) {
+	spans := NewSpanSlice()
+	for _, name := range []string{"a", "b", "c", "d", "e"} {
+		spans.AppendEmpty().SetName(name)
+	}
+	spans.RemoveIf(func(span Span) bool {
+		return span.Name() == "a" || span.Name() == "c"
+	})
+	require.Equal(t, 3, spans.Len())
+	for i, name := range []string{"b", "d", "e"} {
+		assert.Equal(t, name, spans.At(i).Name())
+	}
+
+	// Removing everything leaves an empty slice that can be appended to.
+	spans.RemoveIf(func(Span) bool { return true })
+	assert.Equal(t, 0, spans.Len())
+	spans.AppendEmpty().SetName("f")
+	assert.Equal(t, "f", spans.At(0).Name())
+}
+
+func TestSpanDedupLinks(t *testing.T) {
+	span := NewSpan()
+	traceID1 := NewTraceID([16]byte{1})
+	traceID2 := NewTraceID([16]byte{2})
+	spanID1 := NewSpanID([8]byte{1})
+	spanID2 := NewSpanID([8]byte{2})
+	addLink := func(traceState string, traceID TraceID, spanID SpanID, attrs map[string]AttributeValue) {
+		link := span.Links().AppendEmpty()
+		link.SetTraceState(TraceState(traceState))
+		link.SetTraceID(traceID)
+		link.SetSpanID(spanID)
+		link.Attributes().InitFromMap(attrs)
+	}
+	attrs := map[string]AttributeValue{"a": NewAttributeValueString("1"), "b": NewAttributeValueInt(2)}
+	addLink("first", traceID1, spanID1, attrs)
+	addLink("other-span", traceID1, spanID2, attrs)
+	addLink("duplicate", traceID1, spanID1, map[string]AttributeValue{"b": NewAttributeValueInt(2), "a": NewAttributeValueString("1")})
+	addLink("other-trace", traceID2, spanID1, attrs)
+	addLink("other-value", traceID1, spanID1, map[string]AttributeValue{"a": NewAttributeValueString("1"), "b": NewAttributeValueInt(3)})
+	addLink("fewer-attributes", traceID1, spanID1, map[string]AttributeValue{"a": NewAttributeValueString("1")})
+	addLink("duplicate", traceID2, spanID1, attrs)
+	addLink("duplicate", traceID1, spanID1, attrs)
+
+	span.DedupLinks()
+	var traceStates []string
+	for i := 0; i < span.Links().Len(); i++ {
+		traceStates = append(traceStates, string(span.Links().At(i).TraceState()))
+	}
+	assert.Equal(t, []string{"first", "other-span", "other-trace", "other-value", "fewer-attributes"}, traceStates)
+
+	// Removing the duplicates again is a no-op.
+	span.DedupLinks()
+	assert.Equal(t, 5, span.Links().Len())
+}
+
+func TestTracesRangeAttributes(t *testing.T) {
+	td := NewTraces()
+	rs := td.ResourceSpans().AppendEmpty()
+	rs.Resource().Attributes().InsertString("service.name", "svc")
+	span := rs.InstrumentationLibrarySpans().AppendEmpty().Spans().AppendEmpty()
+	span.Attributes().InsertInt("http.status_code", 200)
+	span.Events().AppendEmpty().Attributes().InsertString("exception.type", "Error")
+	span.Links().AppendEmpty().Attributes().InsertBool("sampled", true)
+	// Spans without attributes are skipped.
+	rs.InstrumentationLibrarySpans().AppendEmpty().Spans().AppendEmpty()
+
+	var got []string
+	td.RangeAttributes(func(scope, key string, val AttributeValue) {
+		got = append(got, scope+":"+key+"="+val.Type().String())
+	})
+	assert.Equal(t, []string{
+		"resource:service.name=STRING",
+		"span:http.status_code=INT",
+		"span_event:exception.type=STRING",
+		"span_link:sampled=BOOL",
+	}, got)
+}
+
+func TestGroupTracesByResourceAttribute(t *testing.T) {
+	td := NewTraces()
+	addResourceSpans := func(tenant AttributeValue, libraries ...string) {
+		rs := td.ResourceSpans().AppendEmpty()
+		if tenant.orig != nil {
+			rs.Resource().Attributes().Insert("tenant", tenant)
+		}
+		rs.Resource().Attributes().InsertInt("index", int64(td.ResourceSpans().Len()-1))
+		for _, library := range libraries {
+			ils := rs.InstrumentationLibrarySpans().AppendEmpty()
+			ils.InstrumentationLibrary().SetName(library)
+			ils.Spans().AppendEmpty().SetName(library + "-span-1")
+			ils.Spans().AppendEmpty().SetName(library + "-span-2")
+		}
+	}
+	addResourceSpans(NewAttributeValueString("acme"), "http", "db")
+	addResourceSpans(NewAttributeValueString("globex"), "http")
+	addResourceSpans(AttributeValue{}, "grpc")
+	addResourceSpans(NewAttributeValueString("acme"), "queue")
+	addResourceSpans(NewAttributeValueInt(42), "http")
+	orig := td.Clone()
+
+	groups := GroupTracesByResourceAttribute(td, "tenant")
+	require.Len(t, groups, 4)
+	assert.Equal(t, 6, groups["acme"].SpanCount())
+	assert.Equal(t, 2, groups["globex"].SpanCount())
+	assert.Equal(t, 2, groups[""].SpanCount())
+	assert.Equal(t, 2, groups["42"].SpanCount())
+
+	// The resource spans keep their resource and instrumentation libraries, in order.
+	acme := groups["acme"].ResourceSpans()
+	require.Equal(t, 2, acme.Len())
+	assert.EqualValues(t, orig.ResourceSpans().At(0), acme.At(0))
+	assert.EqualValues(t, orig.ResourceSpans().At(3), acme.At(1))
+	assert.EqualValues(t, orig.ResourceSpans().At(2), groups[""].ResourceSpans().At(0))
+	ilss := acme.At(0).InstrumentationLibrarySpans()
+	require.Equal(t, 2, ilss.Len())
+	assert.Equal(t, "http", ilss.At(0).InstrumentationLibrary().Name())
+	assert.Equal(t, "db-span-2", ilss.At(1).Spans().At(1).Name())
+
+	// The original traces are left unchanged.
+	assert.EqualValues(t, orig, td)
+	assert.Empty(t, GroupTracesByResourceAttribute(NewTraces(), "tenant"))
+}
@@ -0,0 +1,86 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pdatabuilder
+
+import (
+	"fmt"
+	"sort"
+
+	"go.opentelemetry.io/collector/consumer/pdata"
+)
+
+// Attrs is a set of attributes. The supported value types are string, bool,
+// int, int64, float64, nil, Attrs or map[string]interface{} for map values and
+// []interface{} for array values.
+type Attrs map[string]interface{}
+
+// insertAttrs inserts attrs into dest. The keys are inserted in lexicographical
+// order so that the built data is deterministic and can be compared.
+func insertAttrs(dest pdata.AttributeMap, attrs Attrs) {
+	keys := make([]string, 0, len(attrs))
+	for k := range attrs {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		dest.Insert(k, newAttributeValue(attrs[k]))
+	}
+}
+
+// newAttributeValue converts v to an AttributeValue, it panics if the type of v
+// is not supported since builders are meant to be used with literal values.
+func newAttributeValue(v interface{}) pdata.AttributeValue {
+	switch val := v.(type) {
+	case nil:
+		return pdata.NewAttributeValueNull()
+	case string:
+		return pdata.NewAttributeValueString(val)
+	case bool:
+		return pdata.NewAttributeValueBool(val)
+	case int:
+		return pdata.NewAttributeValueInt(int64(val))
+	case int64:
+		return pdata.NewAttributeValueInt(val)
+	case float64:
+		return pdata.NewAttributeValueDouble(val)
+	case Attrs:
+		av := pdata.NewAttributeValueMap()
+		insertAttrs(av.MapVal(), val)
+		return av
+	case map[string]interface{}:
+		return newAttributeValue(Attrs(val))
+	case []interface{}:
+		av := pdata.NewAttributeValueArray()
+		for _, elem := range val {
+			av.ArrayVal().Append(newAttributeValue(elem))
+		}
+		return av
+	default:
+		panic(fmt.Sprintf("pdatabuilder: unsupported attribute value type %T", v))
+	}
+}
+
+// insertLabels inserts labels into dest. The keys are inserted in lexicographical
+// order so that the built data is deterministic and can be compared.
+func insertLabels(dest pdata.StringMap, labels map[string]string) {
+	keys := make([]string, 0, len(labels))
+	for k := range labels {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		dest.Insert(k, labels[k])
+	}
+}
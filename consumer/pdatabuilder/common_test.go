@@ -0,0 +1,75 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pdatabuilder
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"go.opentelemetry.io/collector/consumer/pdata"
+)
+
+func TestInsertAttrs(t *testing.T) {
+	am := pdata.NewAttributeMap()
+	insertAttrs(am, Attrs{
+		"str":    "val",
+		"bool":   true,
+		"int":    1,
+		"int64":  int64(2),
+		"double": 3.5,
+		"null":   nil,
+		"map":    Attrs{"nested": "val"},
+		"gomap":  map[string]interface{}{"nested": 1},
+		"array":  []interface{}{"a", 1},
+	})
+
+	expected := pdata.NewAttributeMap()
+	av := pdata.NewAttributeValueArray()
+	av.ArrayVal().Append(pdata.NewAttributeValueString("a"))
+	av.ArrayVal().Append(pdata.NewAttributeValueInt(1))
+	expected.Insert("array", av)
+	expected.InsertBool("bool", true)
+	expected.InsertDouble("double", 3.5)
+	av = pdata.NewAttributeValueMap()
+	av.MapVal().InsertInt("nested", 1)
+	expected.Insert("gomap", av)
+	expected.InsertInt("int", 1)
+	expected.InsertInt("int64", 2)
+	av = pdata.NewAttributeValueMap()
+	av.MapVal().InsertString("nested", "val")
+	expected.Insert("map", av)
+	expected.InsertNull("null")
+	expected.InsertString("str", "val")
+	assert.Equal(t, expected, am)
+}
+
+func TestInsertAttrsUnsupportedType(t *testing.T) {
+	assert.PanicsWithValue(t, "pdatabuilder: unsupported attribute value type uint8", func() {
+		insertAttrs(pdata.NewAttributeMap(), Attrs{"k": uint8(1)})
+	})
+}
+
+func TestInsertLabels(t *testing.T) {
+	sm := pdata.NewStringMap()
+	insertLabels(sm, map[string]string{"b": "2", "a": "1"})
+	assert.Equal(t, 2, sm.Len())
+	var keys []string
+	sm.Range(func(k string, _ string) bool {
+		keys = append(keys, k)
+		return true
+	})
+	assert.Equal(t, []string{"a", "b"}, keys)
+}
@@ -0,0 +1,136 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pdatabuilder
+
+import (
+	"time"
+
+	"go.opentelemetry.io/collector/consumer/pdata"
+)
+
+// LogsBuilder builds a pdata.Logs using a fluent API, e.g.:
+//
+//	ld := NewLogs().
+//		Resource(Attrs{"service.name": "svc"}).
+//		Library("lib", "1.0").
+//		Log("message").WithSeverity(pdata.SeverityNumberINFO, "Info").
+//		Build()
+//
+// Library and Log add to the last added resource and library respectively,
+// creating empty ones if none was added. The With* methods apply to the last
+// added log record and panic if no log record was added.
+type LogsBuilder struct {
+	ld pdata.Logs
+}
+
+// NewLogs returns a new LogsBuilder.
+func NewLogs() *LogsBuilder {
+	return &LogsBuilder{ld: pdata.NewLogs()}
+}
+
+// Resource adds a resource with the given attributes.
+func (b *LogsBuilder) Resource(attrs Attrs) *LogsBuilder {
+	insertAttrs(b.ld.ResourceLogs().AppendEmpty().Resource().Attributes(), attrs)
+	return b
+}
+
+// Library adds an instrumentation library to the last resource.
+func (b *LogsBuilder) Library(name, version string) *LogsBuilder {
+	il := b.lastResource().InstrumentationLibraryLogs().AppendEmpty().InstrumentationLibrary()
+	il.SetName(name)
+	il.SetVersion(version)
+	return b
+}
+
+// Log adds a log record with the given string body to the last instrumentation library.
+func (b *LogsBuilder) Log(body string) *LogsBuilder {
+	b.lastLibrary().Logs().AppendEmpty().Body().SetStringVal(body)
+	return b
+}
+
+// WithAttr adds an attribute to the last log record.
+func (b *LogsBuilder) WithAttr(key string, value interface{}) *LogsBuilder {
+	b.lastLog().Attributes().Insert(key, newAttributeValue(value))
+	return b
+}
+
+// WithAttrs adds the attributes to the last log record.
+func (b *LogsBuilder) WithAttrs(attrs Attrs) *LogsBuilder {
+	insertAttrs(b.lastLog().Attributes(), attrs)
+	return b
+}
+
+// WithName sets the name of the last log record.
+func (b *LogsBuilder) WithName(name string) *LogsBuilder {
+	b.lastLog().SetName(name)
+	return b
+}
+
+// WithSeverity sets the severity number and text of the last log record.
+func (b *LogsBuilder) WithSeverity(number pdata.SeverityNumber, text string) *LogsBuilder {
+	lr := b.lastLog()
+	lr.SetSeverityNumber(number)
+	lr.SetSeverityText(text)
+	return b
+}
+
+// WithTimestamp sets the timestamp of the last log record.
+func (b *LogsBuilder) WithTimestamp(ts time.Time) *LogsBuilder {
+	b.lastLog().SetTimestamp(pdata.TimestampFromTime(ts))
+	return b
+}
+
+// WithIDs sets the trace ID and the span ID of the last log record.
+func (b *LogsBuilder) WithIDs(traceID [16]byte, spanID [8]byte) *LogsBuilder {
+	lr := b.lastLog()
+	lr.SetTraceID(pdata.NewTraceID(traceID))
+	lr.SetSpanID(pdata.NewSpanID(spanID))
+	return b
+}
+
+// Build returns the built pdata.Logs. The builder must not be used after Build.
+func (b *LogsBuilder) Build() pdata.Logs {
+	return b.ld
+}
+
+func (b *LogsBuilder) lastResource() pdata.ResourceLogs {
+	rls := b.ld.ResourceLogs()
+	if rls.Len() == 0 {
+		return rls.AppendEmpty()
+	}
+	return rls.At(rls.Len() - 1)
+}
+
+func (b *LogsBuilder) lastLibrary() pdata.InstrumentationLibraryLogs {
+	ills := b.lastResource().InstrumentationLibraryLogs()
+	if ills.Len() == 0 {
+		return ills.AppendEmpty()
+	}
+	return ills.At(ills.Len() - 1)
+}
+
+func (b *LogsBuilder) lastLog() pdata.LogRecord {
+	rls := b.ld.ResourceLogs()
+	if rls.Len() > 0 {
+		ills := rls.At(rls.Len() - 1).InstrumentationLibraryLogs()
+		if ills.Len() > 0 {
+			logs := ills.At(ills.Len() - 1).Logs()
+			if logs.Len() > 0 {
+				return logs.At(logs.Len() - 1)
+			}
+		}
+	}
+	panic("pdatabuilder: Log must be called before setting log record fields")
+}
@@ -0,0 +1,62 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pdatabuilder
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"go.opentelemetry.io/collector/consumer/pdata"
+)
+
+func TestLogsBuilder(t *testing.T) {
+	ts := time.Unix(1, 0)
+	ld := NewLogs().
+		Resource(Attrs{"service.name": "svc"}).
+		Library("lib", "1.0").
+		Log("first").WithName("name").WithSeverity(pdata.SeverityNumberINFO, "Info").WithTimestamp(ts).
+		Log("second").WithIDs([16]byte{1}, [8]byte{2}).WithAttr("k", "v").WithAttrs(Attrs{"n": 1}).
+		Build()
+
+	expected := pdata.NewLogs()
+	rl := expected.ResourceLogs().AppendEmpty()
+	rl.Resource().Attributes().InsertString("service.name", "svc")
+	ill := rl.InstrumentationLibraryLogs().AppendEmpty()
+	ill.InstrumentationLibrary().SetName("lib")
+	ill.InstrumentationLibrary().SetVersion("1.0")
+	lr := ill.Logs().AppendEmpty()
+	lr.Body().SetStringVal("first")
+	lr.SetName("name")
+	lr.SetSeverityNumber(pdata.SeverityNumberINFO)
+	lr.SetSeverityText("Info")
+	lr.SetTimestamp(pdata.TimestampFromTime(ts))
+	lr = ill.Logs().AppendEmpty()
+	lr.Body().SetStringVal("second")
+	lr.SetTraceID(pdata.NewTraceID([16]byte{1}))
+	lr.SetSpanID(pdata.NewSpanID([8]byte{2}))
+	lr.Attributes().InsertString("k", "v")
+	lr.Attributes().InsertInt("n", 1)
+
+	assert.Equal(t, expected, ld)
+	assert.Equal(t, 2, ld.LogRecordCount())
+}
+
+func TestLogsBuilderNoLog(t *testing.T) {
+	assert.Panics(t, func() {
+		NewLogs().Resource(nil).WithName("name")
+	})
+}
@@ -0,0 +1,171 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pdatabuilder
+
+import (
+	"go.opentelemetry.io/collector/consumer/pdata"
+)
+
+// MetricsBuilder builds a pdata.Metrics using a fluent API, e.g.:
+//
+//	md := NewMetrics().
+//		Resource(Attrs{"service.name": "svc"}).
+//		Library("lib", "1.0").
+//		IntSum("requests", true).IntDataPoint(10, map[string]string{"code": "200"}).
+//		DoubleGauge("cpu").WithUnit("1").DoubleDataPoint(0.5, nil).
+//		Build()
+//
+// Library and the metric methods add to the last added resource and library
+// respectively, creating empty ones if none was added. The With* and data point
+// methods apply to the last added metric and panic if no metric was added, or if
+// the data point does not match the type of the metric.
+type MetricsBuilder struct {
+	md pdata.Metrics
+}
+
+// NewMetrics returns a new MetricsBuilder.
+func NewMetrics() *MetricsBuilder {
+	return &MetricsBuilder{md: pdata.NewMetrics()}
+}
+
+// Resource adds a resource with the given attributes.
+func (b *MetricsBuilder) Resource(attrs Attrs) *MetricsBuilder {
+	insertAttrs(b.md.ResourceMetrics().AppendEmpty().Resource().Attributes(), attrs)
+	return b
+}
+
+// Library adds an instrumentation library to the last resource.
+func (b *MetricsBuilder) Library(name, version string) *MetricsBuilder {
+	il := b.lastResource().InstrumentationLibraryMetrics().AppendEmpty().InstrumentationLibrary()
+	il.SetName(name)
+	il.SetVersion(version)
+	return b
+}
+
+// IntGauge adds an int gauge metric to the last instrumentation library.
+func (b *MetricsBuilder) IntGauge(name string) *MetricsBuilder {
+	b.addMetric(name, pdata.MetricDataTypeIntGauge)
+	return b
+}
+
+// DoubleGauge adds a double gauge metric to the last instrumentation library.
+func (b *MetricsBuilder) DoubleGauge(name string) *MetricsBuilder {
+	b.addMetric(name, pdata.MetricDataTypeDoubleGauge)
+	return b
+}
+
+// IntSum adds a cumulative int sum metric to the last instrumentation library.
+func (b *MetricsBuilder) IntSum(name string, monotonic bool) *MetricsBuilder {
+	sum := b.addMetric(name, pdata.MetricDataTypeIntSum).IntSum()
+	sum.SetAggregationTemporality(pdata.AggregationTemporalityCumulative)
+	sum.SetIsMonotonic(monotonic)
+	return b
+}
+
+// DoubleSum adds a cumulative double sum metric to the last instrumentation library.
+func (b *MetricsBuilder) DoubleSum(name string, monotonic bool) *MetricsBuilder {
+	sum := b.addMetric(name, pdata.MetricDataTypeDoubleSum).DoubleSum()
+	sum.SetAggregationTemporality(pdata.AggregationTemporalityCumulative)
+	sum.SetIsMonotonic(monotonic)
+	return b
+}
+
+// WithDescription sets the description of the last metric.
+func (b *MetricsBuilder) WithDescription(description string) *MetricsBuilder {
+	b.lastMetric().SetDescription(description)
+	return b
+}
+
+// WithUnit sets the unit of the last metric.
+func (b *MetricsBuilder) WithUnit(unit string) *MetricsBuilder {
+	b.lastMetric().SetUnit(unit)
+	return b
+}
+
+// IntDataPoint adds a data point to the last metric, which must be an int gauge or sum.
+func (b *MetricsBuilder) IntDataPoint(value int64, labels map[string]string) *MetricsBuilder {
+	var dps pdata.IntDataPointSlice
+	switch m := b.lastMetric(); m.DataType() {
+	case pdata.MetricDataTypeIntGauge:
+		dps = m.IntGauge().DataPoints()
+	case pdata.MetricDataTypeIntSum:
+		dps = m.IntSum().DataPoints()
+	default:
+		panic("pdatabuilder: IntDataPoint called on a " + m.DataType().String() + " metric")
+	}
+	dp := dps.AppendEmpty()
+	dp.SetValue(value)
+	insertLabels(dp.LabelsMap(), labels)
+	return b
+}
+
+// DoubleDataPoint adds a data point to the last metric, which must be a double gauge or sum.
+func (b *MetricsBuilder) DoubleDataPoint(value float64, labels map[string]string) *MetricsBuilder {
+	var dps pdata.DoubleDataPointSlice
+	switch m := b.lastMetric(); m.DataType() {
+	case pdata.MetricDataTypeDoubleGauge:
+		dps = m.DoubleGauge().DataPoints()
+	case pdata.MetricDataTypeDoubleSum:
+		dps = m.DoubleSum().DataPoints()
+	default:
+		panic("pdatabuilder: DoubleDataPoint called on a " + m.DataType().String() + " metric")
+	}
+	dp := dps.AppendEmpty()
+	dp.SetValue(value)
+	insertLabels(dp.LabelsMap(), labels)
+	return b
+}
+
+// Build returns the built pdata.Metrics. The builder must not be used after Build.
+func (b *MetricsBuilder) Build() pdata.Metrics {
+	return b.md
+}
+
+func (b *MetricsBuilder) addMetric(name string, dataType pdata.MetricDataType) pdata.Metric {
+	m := b.lastLibrary().Metrics().AppendEmpty()
+	m.SetName(name)
+	m.SetDataType(dataType)
+	return m
+}
+
+func (b *MetricsBuilder) lastResource() pdata.ResourceMetrics {
+	rms := b.md.ResourceMetrics()
+	if rms.Len() == 0 {
+		return rms.AppendEmpty()
+	}
+	return rms.At(rms.Len() - 1)
+}
+
+func (b *MetricsBuilder) lastLibrary() pdata.InstrumentationLibraryMetrics {
+	ilms := b.lastResource().InstrumentationLibraryMetrics()
+	if ilms.Len() == 0 {
+		return ilms.AppendEmpty()
+	}
+	return ilms.At(ilms.Len() - 1)
+}
+
+func (b *MetricsBuilder) lastMetric() pdata.Metric {
+	rms := b.md.ResourceMetrics()
+	if rms.Len() > 0 {
+		ilms := rms.At(rms.Len() - 1).InstrumentationLibraryMetrics()
+		if ilms.Len() > 0 {
+			metrics := ilms.At(ilms.Len() - 1).Metrics()
+			if metrics.Len() > 0 {
+				return metrics.At(metrics.Len() - 1)
+			}
+		}
+	}
+	panic("pdatabuilder: a metric must be added before setting metric fields")
+}
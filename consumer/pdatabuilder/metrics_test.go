@@ -0,0 +1,87 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pdatabuilder
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"go.opentelemetry.io/collector/consumer/pdata"
+)
+
+func TestMetricsBuilder(t *testing.T) {
+	md := NewMetrics().
+		Resource(Attrs{"service.name": "svc"}).
+		Library("lib", "1.0").
+		IntGauge("int_gauge").WithDescription("desc").WithUnit("1").IntDataPoint(1, nil).IntDataPoint(2, map[string]string{"k": "v"}).
+		DoubleGauge("double_gauge").DoubleDataPoint(1.5, nil).
+		IntSum("int_sum", true).IntDataPoint(3, nil).
+		DoubleSum("double_sum", false).DoubleDataPoint(2.5, nil).
+		Build()
+
+	expected := pdata.NewMetrics()
+	rm := expected.ResourceMetrics().AppendEmpty()
+	rm.Resource().Attributes().InsertString("service.name", "svc")
+	ilm := rm.InstrumentationLibraryMetrics().AppendEmpty()
+	ilm.InstrumentationLibrary().SetName("lib")
+	ilm.InstrumentationLibrary().SetVersion("1.0")
+
+	m := ilm.Metrics().AppendEmpty()
+	m.SetName("int_gauge")
+	m.SetDescription("desc")
+	m.SetUnit("1")
+	m.SetDataType(pdata.MetricDataTypeIntGauge)
+	m.IntGauge().DataPoints().AppendEmpty().SetValue(1)
+	dp := m.IntGauge().DataPoints().AppendEmpty()
+	dp.SetValue(2)
+	dp.LabelsMap().Insert("k", "v")
+
+	m = ilm.Metrics().AppendEmpty()
+	m.SetName("double_gauge")
+	m.SetDataType(pdata.MetricDataTypeDoubleGauge)
+	m.DoubleGauge().DataPoints().AppendEmpty().SetValue(1.5)
+
+	m = ilm.Metrics().AppendEmpty()
+	m.SetName("int_sum")
+	m.SetDataType(pdata.MetricDataTypeIntSum)
+	m.IntSum().SetAggregationTemporality(pdata.AggregationTemporalityCumulative)
+	m.IntSum().SetIsMonotonic(true)
+	m.IntSum().DataPoints().AppendEmpty().SetValue(3)
+
+	m = ilm.Metrics().AppendEmpty()
+	m.SetName("double_sum")
+	m.SetDataType(pdata.MetricDataTypeDoubleSum)
+	m.DoubleSum().SetAggregationTemporality(pdata.AggregationTemporalityCumulative)
+	m.DoubleSum().DataPoints().AppendEmpty().SetValue(2.5)
+
+	assert.Equal(t, expected, md)
+	assert.Equal(t, 5, md.DataPointCount())
+}
+
+func TestMetricsBuilderMismatchedDataPoint(t *testing.T) {
+	assert.PanicsWithValue(t, "pdatabuilder: IntDataPoint called on a DoubleGauge metric", func() {
+		NewMetrics().DoubleGauge("m").IntDataPoint(1, nil)
+	})
+	assert.PanicsWithValue(t, "pdatabuilder: DoubleDataPoint called on a IntSum metric", func() {
+		NewMetrics().IntSum("m", true).DoubleDataPoint(1, nil)
+	})
+}
+
+func TestMetricsBuilderNoMetric(t *testing.T) {
+	assert.Panics(t, func() {
+		NewMetrics().Library("lib", "1.0").WithUnit("1")
+	})
+}
@@ -0,0 +1,145 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pdatabuilder
+
+import (
+	"time"
+
+	"go.opentelemetry.io/collector/consumer/pdata"
+)
+
+// TracesBuilder builds a pdata.Traces using a fluent API, e.g.:
+//
+//	td := NewTraces().
+//		Resource(Attrs{"service.name": "svc"}).
+//		Library("lib", "1.0").
+//		Span("op").WithAttr("k", "v").
+//		Span("other").WithKind(pdata.SpanKindServer).
+//		Build()
+//
+// Library and Span add to the last added resource and library respectively,
+// creating empty ones if none was added. The With* methods apply to the last
+// added span and panic if no span was added.
+type TracesBuilder struct {
+	td pdata.Traces
+}
+
+// NewTraces returns a new TracesBuilder.
+func NewTraces() *TracesBuilder {
+	return &TracesBuilder{td: pdata.NewTraces()}
+}
+
+// Resource adds a resource with the given attributes.
+func (b *TracesBuilder) Resource(attrs Attrs) *TracesBuilder {
+	insertAttrs(b.td.ResourceSpans().AppendEmpty().Resource().Attributes(), attrs)
+	return b
+}
+
+// Library adds an instrumentation library to the last resource.
+func (b *TracesBuilder) Library(name, version string) *TracesBuilder {
+	il := b.lastResource().InstrumentationLibrarySpans().AppendEmpty().InstrumentationLibrary()
+	il.SetName(name)
+	il.SetVersion(version)
+	return b
+}
+
+// Span adds a span with the given name to the last instrumentation library.
+func (b *TracesBuilder) Span(name string) *TracesBuilder {
+	b.lastLibrary().Spans().AppendEmpty().SetName(name)
+	return b
+}
+
+// WithAttr adds an attribute to the last span.
+func (b *TracesBuilder) WithAttr(key string, value interface{}) *TracesBuilder {
+	b.lastSpan().Attributes().Insert(key, newAttributeValue(value))
+	return b
+}
+
+// WithAttrs adds the attributes to the last span.
+func (b *TracesBuilder) WithAttrs(attrs Attrs) *TracesBuilder {
+	insertAttrs(b.lastSpan().Attributes(), attrs)
+	return b
+}
+
+// WithKind sets the kind of the last span.
+func (b *TracesBuilder) WithKind(kind pdata.SpanKind) *TracesBuilder {
+	b.lastSpan().SetKind(kind)
+	return b
+}
+
+// WithIDs sets the trace ID and the span ID of the last span.
+func (b *TracesBuilder) WithIDs(traceID [16]byte, spanID [8]byte) *TracesBuilder {
+	span := b.lastSpan()
+	span.SetTraceID(pdata.NewTraceID(traceID))
+	span.SetSpanID(pdata.NewSpanID(spanID))
+	return b
+}
+
+// WithParentSpanID sets the parent span ID of the last span.
+func (b *TracesBuilder) WithParentSpanID(parentSpanID [8]byte) *TracesBuilder {
+	b.lastSpan().SetParentSpanID(pdata.NewSpanID(parentSpanID))
+	return b
+}
+
+// WithTimestamps sets the start and end timestamps of the last span.
+func (b *TracesBuilder) WithTimestamps(start, end time.Time) *TracesBuilder {
+	span := b.lastSpan()
+	span.SetStartTimestamp(pdata.TimestampFromTime(start))
+	span.SetEndTimestamp(pdata.TimestampFromTime(end))
+	return b
+}
+
+// WithStatus sets the status of the last span.
+func (b *TracesBuilder) WithStatus(code pdata.StatusCode, message string) *TracesBuilder {
+	status := b.lastSpan().Status()
+	status.SetCode(code)
+	status.SetMessage(message)
+	return b
+}
+
+// Build returns the built pdata.Traces. The builder must not be used after Build.
+func (b *TracesBuilder) Build() pdata.Traces {
+	return b.td
+}
+
+func (b *TracesBuilder) lastResource() pdata.ResourceSpans {
+	rss := b.td.ResourceSpans()
+	if rss.Len() == 0 {
+		return rss.AppendEmpty()
+	}
+	return rss.At(rss.Len() - 1)
+}
+
+func (b *TracesBuilder) lastLibrary() pdata.InstrumentationLibrarySpans {
+	ilss := b.lastResource().InstrumentationLibrarySpans()
+	if ilss.Len() == 0 {
+		return ilss.AppendEmpty()
+	}
+	return ilss.At(ilss.Len() - 1)
+}
+
+func (b *TracesBuilder) lastSpan() pdata.Span {
+	rss := b.td.ResourceSpans()
+	if rss.Len() > 0 {
+		ilss := rss.At(rss.Len() - 1).InstrumentationLibrarySpans()
+		if ilss.Len() > 0 {
+			spans := ilss.At(ilss.Len() - 1).Spans()
+			if spans.Len() > 0 {
+				return spans.At(spans.Len() - 1)
+			}
+		}
+	}
+	panic("pdatabuilder: Span must be called before setting span fields")
+}
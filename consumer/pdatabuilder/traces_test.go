@@ -0,0 +1,82 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pdatabuilder
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"go.opentelemetry.io/collector/consumer/pdata"
+)
+
+func TestTracesBuilder(t *testing.T) {
+	start := time.Unix(1, 0)
+	end := time.Unix(2, 0)
+	td := NewTraces().
+		Resource(Attrs{"service.name": "svc"}).
+		Library("lib", "1.0").
+		Span("op").WithAttr("k", "v").WithKind(pdata.SpanKindServer).
+		Span("child").
+		WithIDs([16]byte{1}, [8]byte{2}).
+		WithParentSpanID([8]byte{3}).
+		WithTimestamps(start, end).
+		WithStatus(pdata.StatusCodeError, "err").
+		WithAttrs(Attrs{"b": 2, "a": 1}).
+		Library("other", "").
+		Resource(nil).
+		Span("orphan").
+		Build()
+
+	expected := pdata.NewTraces()
+	rs := expected.ResourceSpans().AppendEmpty()
+	rs.Resource().Attributes().InsertString("service.name", "svc")
+	ils := rs.InstrumentationLibrarySpans().AppendEmpty()
+	ils.InstrumentationLibrary().SetName("lib")
+	ils.InstrumentationLibrary().SetVersion("1.0")
+	span := ils.Spans().AppendEmpty()
+	span.SetName("op")
+	span.Attributes().InsertString("k", "v")
+	span.SetKind(pdata.SpanKindServer)
+	span = ils.Spans().AppendEmpty()
+	span.SetName("child")
+	span.SetTraceID(pdata.NewTraceID([16]byte{1}))
+	span.SetSpanID(pdata.NewSpanID([8]byte{2}))
+	span.SetParentSpanID(pdata.NewSpanID([8]byte{3}))
+	span.SetStartTimestamp(pdata.TimestampFromTime(start))
+	span.SetEndTimestamp(pdata.TimestampFromTime(end))
+	span.Status().SetCode(pdata.StatusCodeError)
+	span.Status().SetMessage("err")
+	span.Attributes().InsertInt("a", 1)
+	span.Attributes().InsertInt("b", 2)
+	rs.InstrumentationLibrarySpans().AppendEmpty().InstrumentationLibrary().SetName("other")
+	rs = expected.ResourceSpans().AppendEmpty()
+	rs.InstrumentationLibrarySpans().AppendEmpty().Spans().AppendEmpty().SetName("orphan")
+
+	assert.Equal(t, expected, td)
+	assert.Equal(t, 3, td.SpanCount())
+}
+
+func TestTracesBuilderEmpty(t *testing.T) {
+	assert.Equal(t, pdata.NewTraces(), NewTraces().Build())
+	assert.Equal(t, 1, NewTraces().Span("op").Build().SpanCount())
+}
+
+func TestTracesBuilderNoSpan(t *testing.T) {
+	assert.Panics(t, func() {
+		NewTraces().Library("lib", "1.0").WithAttr("k", "v")
+	})
+}
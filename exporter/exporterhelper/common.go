@@ -18,6 +18,7 @@ import (
 	"context"
 	"time"
 
+	"go.opencensus.io/metric/metricdata"
 	"go.uber.org/zap"
 
 	"go.opentelemetry.io/collector/component"
@@ -52,6 +53,8 @@ type request interface {
 	onError(error) request
 	// Returns the count of spans/metric points or log records.
 	count() int
+	// Returns the pdata.Traces, pdata.Metrics or pdata.Logs of the request.
+	data() interface{}
 }
 
 // requestSender is an abstraction of a sender for a request independent of the type of the data (traces, metrics, logs).
@@ -80,6 +83,19 @@ type baseSettings struct {
 	QueueSettings
 	RetrySettings
 	ResourceToTelemetrySettings
+	orderingKey    OrderingKeyFunc
+	maxConcurrency int
+	queueOverflow  interface{}
+	sampler        SamplerFunc
+	// decimationWindow is the window in which at most one data point of each metrics
+	// series is exported, no decimation if zero.
+	decimationWindow time.Duration
+	// metricsReportInterval is the interval at which the exporter metrics are updated,
+	// on every batch if zero.
+	metricsReportInterval time.Duration
+	// logSamplingInterval is the interval at which the identical failure messages are logged.
+	logSamplingInterval time.Duration
+	clock               Clock
 }
 
 // fromOptions returns the internal options starting from the default and applying all configured options.
@@ -92,6 +108,7 @@ func fromOptions(options ...Option) *baseSettings {
 		// TODO: Enable retry by default (call DefaultRetrySettings)
 		RetrySettings:               RetrySettings{Enabled: false},
 		ResourceToTelemetrySettings: defaultResourceToTelemetrySettings(),
+		clock:                       NewSystemClock(),
 	}
 
 	for _, op := range options {
@@ -161,20 +178,147 @@ func WithResourceToTelemetryConversion(resourceToTelemetrySettings ResourceToTel
 	}
 }
 
+// OrderingKeyFunc returns the ordering key of the data of a request, which is a
+// pdata.Traces, pdata.Metrics or pdata.Logs depending on the exporter type.
+type OrderingKeyFunc func(data interface{}) uint64
+
+// WithOrderedDelivery enables ordered delivery of the requests with the same key returned
+// by keyFn, e.g. a hash of the resource. Requests with the same key are always exported by
+// the same queue consumer, in the order they were received, while requests with different
+// keys are still exported in parallel.
+//
+// Ordered delivery reduces the throughput compared to the default unordered delivery,
+// since a request blocks all the following requests with the same key while it is being
+// exported or retried, and unevenly distributed keys leave some consumers idle.
+// Only applies if the sending queue is enabled, otherwise requests are exported synchronously.
+func WithOrderedDelivery(keyFn OrderingKeyFunc) Option {
+	return func(o *baseSettings) {
+		o.orderingKey = keyFn
+	}
+}
+
+// WithMaxConcurrency limits to n the number of simultaneous invocations of the push
+// function, across all the queue consumers or, if the sending queue is disabled, all
+// the callers of the exporter. Every attempt, including retries, waits for a free slot
+// before the timeout starts. This bounds the load on the backend independently of the
+// number of queue consumers. The default value 0 means no limit.
+func WithMaxConcurrency(n int) Option {
+	return func(o *baseSettings) {
+		o.maxConcurrency = n
+	}
+}
+
+// SamplerFunc returns whether the data of a request, which is a pdata.Traces, pdata.Metrics
+// or pdata.Logs depending on the exporter type, is sent or deliberately dropped.
+type SamplerFunc func(data interface{}) bool
+
+// WithSampler drops the requests for which sampler returns false before they are queued,
+// e.g. to accept a lossy export under extreme load instead of the backpressure. The dropped
+// requests are reported as successfully consumed and their items are counted by the
+// exporter/sampler_dropped_items metric, separately from the items dropped by failures.
+// See NewTraceIDRatioSampler for a sampler keeping the traces coherent.
+func WithSampler(sampler SamplerFunc) Option {
+	return func(o *baseSettings) {
+		o.sampler = sampler
+	}
+}
+
+// WithDecimation only exports the first data point of each metrics series in every time
+// window, a series being identified by its resource, instrumentation library, metric name
+// and labels, e.g. to reduce the cost of a backend billed per data point. The windows are
+// aligned on multiples of the window duration and the data points are placed by their
+// timestamp, so a series is exported at most once per window whatever its collection
+// interval. Only the gauges, summaries and cumulative sums and histograms are decimated,
+// since the points of a cumulative series still carry the totals and the rates computed
+// over the remaining points stay correct; the delta sums and histograms are always
+// exported. The dropped points are counted by the exporter/decimation_dropped_points
+// metric. The series without data points for two windows are forgotten. Only applies to
+// the metrics exporters, and a window <= 0 disables it.
+func WithDecimation(window time.Duration) Option {
+	return func(o *baseSettings) {
+		o.decimationWindow = window
+	}
+}
+
+// WithMetricsReportInterval sets the interval at which the cumulative metrics of the
+// exporter, e.g. exporter/queue_overflowed_items, are updated. By default they are
+// updated on every batch, which has a cost for the exporters sending many small
+// batches. With an interval the increments are summed and added to the metrics every
+// interval and on shutdown, so the metrics can lag behind by up to the interval.
+func WithMetricsReportInterval(interval time.Duration) Option {
+	return func(o *baseSettings) {
+		o.metricsReportInterval = interval
+	}
+}
+
+// WithLogSamplingInterval sets the interval at which the identical messages logged by the
+// exporter on failures, e.g. when the backend is down, are logged: the first one is logged,
+// then at most one every interval, along with the number of messages suppressed since the
+// previous one. By default the messages are sampled, 1 every 100 after the first one every
+// 10 seconds, unless debugging is enabled.
+func WithLogSamplingInterval(interval time.Duration) Option {
+	return func(o *baseSettings) {
+		o.logSamplingInterval = interval
+	}
+}
+
+// WithQueueOverflow sends to overflow the data that would otherwise be dropped because the
+// sending queue is full, e.g. to export it to a secondary destination while the backend is
+// unavailable. The overflow must be a consumer.Traces, consumer.Metrics or consumer.Logs
+// matching the type of the exporter, otherwise the creation of the exporter fails. The data
+// is dropped if the overflow returns an error. Only applies if the sending queue is enabled.
+func WithQueueOverflow(overflow interface{}) Option {
+	return func(o *baseSettings) {
+		o.queueOverflow = overflow
+	}
+}
+
+// WithClock sets the Clock used by the retry sender to measure the time elapsed since the
+// first attempt and to wait between the attempts, the system clock by default. This is
+// mostly useful in tests, to make the retries deterministic. The attempts themselves, and
+// the max_elapsed_time bound of their timeout, still use the system time.
+func WithClock(clock Clock) Option {
+	return func(o *baseSettings) {
+		o.clock = clock
+	}
+}
+
+// Flusher is implemented by the exporters created by this package, which can be
+// asserted to a Flusher to wait for the data in the sending queue to be sent.
+type Flusher interface {
+	// Flush blocks until all the data in the sending queue was sent, including the
+	// retries, or the context is done, in which case the context error is returned.
+	// Flush is a no-op if the sending queue is disabled, since the data is sent
+	// synchronously by the Consume functions.
+	Flush(ctx context.Context) error
+}
+
 // baseExporter contains common fields between different exporter types.
 type baseExporter struct {
 	component.Component
 	sender   requestSender
 	qrSender *queuedRetrySender
+	reporter *metricsReporter
 }
 
-func newBaseExporter(cfg config.Exporter, logger *zap.Logger, bs *baseSettings) *baseExporter {
+func newBaseExporter(cfg config.Exporter, dataType config.DataType, logger *zap.Logger, bs *baseSettings) *baseExporter {
 	be := &baseExporter{
 		Component: componenthelper.New(bs.componentOptions...),
+		reporter:  newMetricsReporter(bs.metricsReportInterval),
 	}
 
-	be.qrSender = newQueuedRetrySender(cfg.ID().String(), bs.QueueSettings, bs.RetrySettings, &timeoutSender{cfg: bs.TimeoutSettings}, logger)
+	var nextSender requestSender = &timeoutSender{cfg: bs.TimeoutSettings}
+	if bs.maxConcurrency > 0 {
+		nextSender = newConcurrencySender(bs.maxConcurrency, nextSender)
+	}
+	be.qrSender = newQueuedRetrySender(cfg.ID().String(), dataType, bs.QueueSettings, bs.RetrySettings, bs.orderingKey, nextSender,
+		bs.clock, createSampledLogger(logger, bs.logSamplingInterval))
+	be.qrSender.overflowedItems = be.reporter.counter(queueOverflowedItems, be.qrSender.labelValues...)
 	be.sender = be.qrSender
+	if bs.sampler != nil {
+		be.sender = newSamplingSender(cfg.ID().String(), bs.sampler, be.qrSender,
+			be.reporter.counter(samplerDroppedItems, metricdata.NewLabelValue(cfg.ID().String())))
+	}
 
 	return be
 }
@@ -193,13 +337,25 @@ func (be *baseExporter) Start(ctx context.Context, host component.Host) error {
 	}
 
 	// If no error then start the queuedRetrySender.
-	return be.qrSender.start()
+	if err := be.qrSender.start(); err != nil {
+		return err
+	}
+	be.reporter.start()
+	return nil
+}
+
+// Flush implements the Flusher interface.
+func (be *baseExporter) Flush(ctx context.Context) error {
+	return be.qrSender.flush(ctx)
 }
 
 // Shutdown all senders and exporter and is invoked during service shutdown.
 func (be *baseExporter) Shutdown(ctx context.Context) error {
-	// First shutdown the queued retry sender
-	be.qrSender.shutdown()
+	// First shutdown the queued retry sender, which drains the queue, so that the
+	// wrapped exporter is only shut down once there is nothing left to send.
+	be.qrSender.shutdown(ctx)
+	// Then report the metrics updated while draining the queue.
+	be.reporter.shutdown()
 	// Last shutdown the wrapped exporter itself.
 	return be.Component.Shutdown(ctx)
 }
@@ -221,3 +377,29 @@ func (ts *timeoutSender) send(req request) error {
 	}
 	return req.export(ctx)
 }
+
+// concurrencySender is a request sender that limits the number of requests
+// simultaneously sent to the next sender, using a semaphore with one slot
+// per allowed concurrent request.
+type concurrencySender struct {
+	sem        chan struct{}
+	nextSender requestSender
+}
+
+func newConcurrencySender(maxConcurrency int, nextSender requestSender) *concurrencySender {
+	return &concurrencySender{
+		sem:        make(chan struct{}, maxConcurrency),
+		nextSender: nextSender,
+	}
+}
+
+// send implements the requestSender interface
+func (cs *concurrencySender) send(req request) error {
+	select {
+	case cs.sem <- struct{}{}:
+	case <-req.context().Done():
+		return req.context().Err()
+	}
+	defer func() { <-cs.sem }()
+	return cs.nextSender.send(req)
+}
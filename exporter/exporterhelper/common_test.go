@@ -16,8 +16,12 @@ package exporterhelper
 import (
 	"context"
 	"errors"
+	"sync"
+	"sync/atomic"
 	"testing"
+	"time"
 
+	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"go.opencensus.io/tag"
 	"go.opencensus.io/trace"
@@ -26,6 +30,7 @@ import (
 	"go.opentelemetry.io/collector/component"
 	"go.opentelemetry.io/collector/component/componenttest"
 	"go.opentelemetry.io/collector/config"
+	"go.opentelemetry.io/collector/consumer/pdata"
 )
 
 var (
@@ -36,6 +41,11 @@ var (
 	defaultExporterTags = []tag.Tag{
 		{Key: exporterTag, Value: "test"},
 	}
+	dataTypeTag, _   = tag.NewKey("data_type")
+	defaultQueueTags = []tag.Tag{
+		{Key: exporterTag, Value: "test"},
+		{Key: dataTypeTag, Value: "traces"},
+	}
 )
 
 func TestErrorToStatus(t *testing.T) {
@@ -44,7 +54,7 @@ func TestErrorToStatus(t *testing.T) {
 }
 
 func TestBaseExporter(t *testing.T) {
-	be := newBaseExporter(&defaultExporterCfg, zap.NewNop(), fromOptions())
+	be := newBaseExporter(&defaultExporterCfg, config.TracesDataType, zap.NewNop(), fromOptions())
 	require.NoError(t, be.Start(context.Background(), componenttest.NewNopHost()))
 	require.NoError(t, be.Shutdown(context.Background()))
 }
@@ -53,6 +63,7 @@ func TestBaseExporterWithOptions(t *testing.T) {
 	want := errors.New("my error")
 	be := newBaseExporter(
 		&defaultExporterCfg,
+		config.TracesDataType,
 		zap.NewNop(),
 		fromOptions(
 			WithStart(func(ctx context.Context, host component.Host) error { return want }),
@@ -64,6 +75,97 @@ func TestBaseExporterWithOptions(t *testing.T) {
 	require.Equal(t, want, be.Shutdown(context.Background()))
 }
 
+func TestBaseExporterWithMaxConcurrency(t *testing.T) {
+	const maxConcurrency = 2
+	var inFlight, maxInFlight, pushed int64
+	release := make(chan struct{})
+	push := func(context.Context, pdata.Traces) error {
+		n := atomic.AddInt64(&inFlight, 1)
+		for {
+			max := atomic.LoadInt64(&maxInFlight)
+			if n <= max || atomic.CompareAndSwapInt64(&maxInFlight, max, n) {
+				break
+			}
+		}
+		<-release
+		atomic.AddInt64(&inFlight, -1)
+		atomic.AddInt64(&pushed, 1)
+		return nil
+	}
+
+	qCfg := DefaultQueueSettings()
+	qCfg.NumConsumers = 10
+	te, err := NewTracesExporter(&defaultExporterCfg, zap.NewNop(), push, WithQueue(qCfg), WithMaxConcurrency(maxConcurrency))
+	require.NoError(t, err)
+	require.NoError(t, te.Start(context.Background(), componenttest.NewNopHost()))
+
+	for i := 0; i < 10; i++ {
+		require.NoError(t, te.ConsumeTraces(context.Background(), pdata.NewTraces()))
+	}
+	assert.Eventually(t, func() bool {
+		return atomic.LoadInt64(&inFlight) == maxConcurrency
+	}, time.Second, time.Millisecond)
+	// Give the other queue consumers the chance to exceed the limit.
+	time.Sleep(50 * time.Millisecond)
+	assert.EqualValues(t, maxConcurrency, atomic.LoadInt64(&inFlight))
+
+	close(release)
+	assert.Eventually(t, func() bool {
+		return atomic.LoadInt64(&pushed) == 10
+	}, time.Second, time.Millisecond)
+	assert.EqualValues(t, maxConcurrency, atomic.LoadInt64(&maxInFlight))
+	require.NoError(t, te.Shutdown(context.Background()))
+}
+
+func TestConcurrencySenderCanceled(t *testing.T) {
+	var wg sync.WaitGroup
+	release := make(chan struct{})
+	cs := newConcurrencySender(1, &timeoutSender{})
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		assert.NoError(t, cs.send(newBlockingRequest(context.Background(), release)))
+	}()
+	assert.Eventually(t, func() bool {
+		return len(cs.sem) == 1
+	}, time.Second, time.Millisecond)
+
+	// The slot is taken, so a request with a canceled context is not sent.
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	assert.Equal(t, context.Canceled, cs.send(newBlockingRequest(ctx, release)))
+
+	close(release)
+	wg.Wait()
+	assert.Len(t, cs.sem, 0)
+}
+
+type blockingRequest struct {
+	baseRequest
+	release chan struct{}
+}
+
+func newBlockingRequest(ctx context.Context, release chan struct{}) request {
+	return &blockingRequest{baseRequest: baseRequest{ctx: ctx}, release: release}
+}
+
+func (r *blockingRequest) export(context.Context) error {
+	<-r.release
+	return nil
+}
+
+func (r *blockingRequest) onError(error) request {
+	return r
+}
+
+func (r *blockingRequest) count() int {
+	return 1
+}
+
+func (r *blockingRequest) data() interface{} {
+	return nil
+}
+
 func errToStatus(err error) trace.Status {
 	if err != nil {
 		return trace.Status{Code: trace.StatusCodeUnknown, Message: err.Error()}
@@ -29,4 +29,6 @@ var (
 	errNilPushMetricsData = errors.New("nil PushMetrics")
 	// errNilPushLogsData is returned when a nil PushLogs is given.
 	errNilPushLogsData = errors.New("nil PushLogs")
+	// errInvalidQueueOverflow is returned when the queue overflow does not match the type of the exporter.
+	errInvalidQueueOverflow = errors.New("queue overflow does not match the exporter type")
 )
@@ -0,0 +1,189 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package exporterhelper
+
+import (
+	"sync"
+	"time"
+
+	"go.opencensus.io/metric"
+	"go.opencensus.io/metric/metricdata"
+
+	"go.opentelemetry.io/collector/consumer/pdata"
+	"go.opentelemetry.io/collector/internal/metricskey"
+	"go.opentelemetry.io/collector/obsreport"
+)
+
+var decimationDroppedPoints, _ = r.AddInt64Cumulative(
+	obsreport.ExporterKey+"/decimation_dropped_points",
+	metric.WithDescription("Number of metric data points deliberately dropped by the decimation of the exporter"),
+	metric.WithLabelKeys(obsreport.ExporterKey),
+	metric.WithUnit(metricdata.UnitDimensionless))
+
+// decimator keeps at most 1 data point of each metrics series per time window, a
+// series being identified by its resource, instrumentation library, metric name and
+// labels. The windows are aligned on multiples of the window duration, the data
+// points being placed by their timestamp, or by the time they are received if they
+// have none. The data points of the delta sums and histograms are always kept,
+// since dropping them loses their counts.
+type decimator struct {
+	window        time.Duration
+	clock         Clock
+	droppedPoints *counter
+
+	mu sync.Mutex
+	// series holds the window of the last data point kept for each series.
+	series map[string]decimatedSeries
+	// lastSweep is the last time the series not seen anymore were removed.
+	lastSweep time.Time
+}
+
+type decimatedSeries struct {
+	window int64
+	// seen is the time a data point of the series was last received.
+	seen time.Time
+}
+
+// decimationStaleWindows is the number of windows without data points after which
+// a series is forgotten. Its next data point is then always kept, which it would
+// be anyway since it falls in a later window.
+const decimationStaleWindows = 2
+
+func newDecimator(window time.Duration, clock Clock, droppedPoints *counter) *decimator {
+	return &decimator{
+		window:        window,
+		clock:         clock,
+		droppedPoints: droppedPoints,
+		series:        make(map[string]decimatedSeries),
+		lastSweep:     clock.Now(),
+	}
+}
+
+// decimate returns a copy of md without the decimated data points and the metrics
+// left without data points.
+func (d *decimator) decimate(md pdata.Metrics) pdata.Metrics {
+	md = md.Clone()
+	dropped := 0
+
+	d.mu.Lock()
+	now := d.clock.Now()
+	d.removeStale(now)
+	rms := md.ResourceMetrics()
+	for i := 0; i < rms.Len(); i++ {
+		resKey := metricskey.Resource(rms.At(i).Resource())
+		ilms := rms.At(i).InstrumentationLibraryMetrics()
+		for j := 0; j < ilms.Len(); j++ {
+			il := ilms.At(j).InstrumentationLibrary()
+			ilms.At(j).Metrics().RemoveIf(func(m pdata.Metric) bool {
+				numPoints := metricDataPointCount(m)
+				if numPoints == 0 {
+					return false
+				}
+				metKey := metricskey.Metric(resKey, il, m)
+				drop := func(labels pdata.StringMap, ts pdata.Timestamp) bool {
+					if !d.keep(metricskey.Series(metKey, labels), ts, now) {
+						dropped++
+						numPoints--
+						return true
+					}
+					return false
+				}
+				switch m.DataType() {
+				case pdata.MetricDataTypeIntGauge:
+					m.IntGauge().DataPoints().RemoveIf(func(dp pdata.IntDataPoint) bool { return drop(dp.LabelsMap(), dp.Timestamp()) })
+				case pdata.MetricDataTypeDoubleGauge:
+					m.DoubleGauge().DataPoints().RemoveIf(func(dp pdata.DoubleDataPoint) bool { return drop(dp.LabelsMap(), dp.Timestamp()) })
+				case pdata.MetricDataTypeIntSum:
+					if m.IntSum().AggregationTemporality() == pdata.AggregationTemporalityCumulative {
+						m.IntSum().DataPoints().RemoveIf(func(dp pdata.IntDataPoint) bool { return drop(dp.LabelsMap(), dp.Timestamp()) })
+					}
+				case pdata.MetricDataTypeDoubleSum:
+					if m.DoubleSum().AggregationTemporality() == pdata.AggregationTemporalityCumulative {
+						m.DoubleSum().DataPoints().RemoveIf(func(dp pdata.DoubleDataPoint) bool { return drop(dp.LabelsMap(), dp.Timestamp()) })
+					}
+				case pdata.MetricDataTypeIntHistogram:
+					if m.IntHistogram().AggregationTemporality() == pdata.AggregationTemporalityCumulative {
+						m.IntHistogram().DataPoints().RemoveIf(func(dp pdata.IntHistogramDataPoint) bool { return drop(dp.LabelsMap(), dp.Timestamp()) })
+					}
+				case pdata.MetricDataTypeHistogram:
+					if m.Histogram().AggregationTemporality() == pdata.AggregationTemporalityCumulative {
+						m.Histogram().DataPoints().RemoveIf(func(dp pdata.HistogramDataPoint) bool { return drop(dp.LabelsMap(), dp.Timestamp()) })
+					}
+				case pdata.MetricDataTypeSummary:
+					m.Summary().DataPoints().RemoveIf(func(dp pdata.SummaryDataPoint) bool { return drop(dp.LabelsMap(), dp.Timestamp()) })
+				}
+				return numPoints == 0
+			})
+		}
+	}
+	d.mu.Unlock()
+
+	if dropped > 0 {
+		d.droppedPoints.add(int64(dropped))
+	}
+	return md
+}
+
+// keep returns whether the data point of the given series with the timestamp ts,
+// received at now, is kept: only the first data point of every window is kept.
+func (d *decimator) keep(series string, ts pdata.Timestamp, now time.Time) bool {
+	t := now
+	if ts != 0 {
+		t = ts.AsTime()
+	}
+	window := t.UnixNano() / int64(d.window)
+	last, found := d.series[series]
+	if found && window <= last.window {
+		last.seen = now
+		d.series[series] = last
+		return false
+	}
+	d.series[series] = decimatedSeries{window: window, seen: now}
+	return true
+}
+
+// removeStale forgets the series without data points for decimationStaleWindows
+// windows, checking at most once per window.
+func (d *decimator) removeStale(now time.Time) {
+	if now.Sub(d.lastSweep) < d.window {
+		return
+	}
+	d.lastSweep = now
+	for series, last := range d.series {
+		if now.Sub(last.seen) >= decimationStaleWindows*d.window {
+			delete(d.series, series)
+		}
+	}
+}
+
+// metricDataPointCount returns the number of data points of m.
+func metricDataPointCount(m pdata.Metric) int {
+	switch m.DataType() {
+	case pdata.MetricDataTypeIntGauge:
+		return m.IntGauge().DataPoints().Len()
+	case pdata.MetricDataTypeDoubleGauge:
+		return m.DoubleGauge().DataPoints().Len()
+	case pdata.MetricDataTypeIntSum:
+		return m.IntSum().DataPoints().Len()
+	case pdata.MetricDataTypeDoubleSum:
+		return m.DoubleSum().DataPoints().Len()
+	case pdata.MetricDataTypeIntHistogram:
+		return m.IntHistogram().DataPoints().Len()
+	case pdata.MetricDataTypeHistogram:
+		return m.Histogram().DataPoints().Len()
+	case pdata.MetricDataTypeSummary:
+		return m.Summary().DataPoints().Len()
+	}
+	return 0
+}
@@ -0,0 +1,186 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package exporterhelper
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opencensus.io/metric/metricdata"
+	"go.opencensus.io/tag"
+	"go.uber.org/zap"
+
+	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/config"
+	"go.opentelemetry.io/collector/consumer/consumertest"
+	"go.opentelemetry.io/collector/consumer/pdata"
+	"go.opentelemetry.io/collector/consumer/pdatabuilder"
+	"go.opentelemetry.io/collector/internal/metricskey"
+)
+
+// newDecimationMetrics returns a cumulative sum with two series, a gauge and a delta sum.
+func newDecimationMetrics() pdata.Metrics {
+	md := pdatabuilder.NewMetrics().
+		Resource(pdatabuilder.Attrs{"service.name": "svc"}).
+		IntSum("requests", true).
+		IntDataPoint(10, map[string]string{"code": "200"}).
+		IntDataPoint(1, map[string]string{"code": "500"}).
+		DoubleGauge("cpu").DoubleDataPoint(0.5, nil).
+		IntSum("delta", true).IntDataPoint(3, nil).
+		Build()
+	md.ResourceMetrics().At(0).InstrumentationLibraryMetrics().At(0).Metrics().At(2).IntSum().
+		SetAggregationTemporality(pdata.AggregationTemporalityDelta)
+	return md
+}
+
+// setDecimationTimestamps sets the timestamp of all the data points of md built by
+// newDecimationMetrics.
+func setDecimationTimestamps(md pdata.Metrics, t time.Time) pdata.Metrics {
+	ts := pdata.TimestampFromTime(t)
+	metrics := md.ResourceMetrics().At(0).InstrumentationLibraryMetrics().At(0).Metrics()
+	for i := 0; i < metrics.Len(); i++ {
+		switch m := metrics.At(i); m.DataType() {
+		case pdata.MetricDataTypeIntSum:
+			for j := 0; j < m.IntSum().DataPoints().Len(); j++ {
+				m.IntSum().DataPoints().At(j).SetTimestamp(ts)
+			}
+		case pdata.MetricDataTypeDoubleGauge:
+			for j := 0; j < m.DoubleGauge().DataPoints().Len(); j++ {
+				m.DoubleGauge().DataPoints().At(j).SetTimestamp(ts)
+			}
+		}
+	}
+	return md
+}
+
+func newTestDecimator(window time.Duration, clock Clock) *decimator {
+	return newDecimator(window, clock, newMetricsReporter(0).counter(decimationDroppedPoints, metricdata.NewLabelValue("test")))
+}
+
+func TestDecimator(t *testing.T) {
+	d := newTestDecimator(time.Minute, newFakeClock())
+	start := newFakeClock().Now()
+	var kept []int
+	for i := 0; i < 7; i++ {
+		md := setDecimationTimestamps(newDecimationMetrics(), start.Add(time.Duration(i)*20*time.Second))
+		out := d.decimate(md)
+		// The input is not modified.
+		assert.Equal(t, 4, md.DataPointCount())
+		kept = append(kept, out.DataPointCount())
+	}
+	// The 3 points of the decimated series are kept once per minute, the delta sum is always kept.
+	assert.Equal(t, []int{4, 1, 1, 4, 1, 1, 4}, kept)
+
+	out := newTestDecimator(time.Minute, newFakeClock()).decimate(setDecimationTimestamps(newDecimationMetrics(), start))
+	metrics := out.ResourceMetrics().At(0).InstrumentationLibraryMetrics().At(0).Metrics()
+	require.Equal(t, 3, metrics.Len())
+
+	// The metrics left without data points are removed.
+	out = d.decimate(setDecimationTimestamps(newDecimationMetrics(), start.Add(2*time.Minute+30*time.Second)))
+	metrics = out.ResourceMetrics().At(0).InstrumentationLibraryMetrics().At(0).Metrics()
+	require.Equal(t, 1, metrics.Len())
+	assert.Equal(t, "delta", metrics.At(0).Name())
+}
+
+func TestDecimator_WindowFollowsTime(t *testing.T) {
+	start := newFakeClock().Now()
+	// Whatever the collection interval, a series is exported once per window.
+	for _, interval := range []time.Duration{time.Second, 10 * time.Second, 30 * time.Second} {
+		d := newTestDecimator(time.Minute, newFakeClock())
+		kept := 0
+		for ts := start; ts.Before(start.Add(5 * time.Minute)); ts = ts.Add(interval) {
+			md := pdatabuilder.NewMetrics().DoubleGauge("cpu").DoubleDataPoint(0.5, nil).Build()
+			md.ResourceMetrics().At(0).InstrumentationLibraryMetrics().At(0).Metrics().At(0).
+				DoubleGauge().DataPoints().At(0).SetTimestamp(pdata.TimestampFromTime(ts))
+			kept += d.decimate(md).DataPointCount()
+		}
+		assert.Equal(t, 5, kept, interval.String())
+	}
+}
+
+func TestDecimator_SeriesAreIndependent(t *testing.T) {
+	d := newTestDecimator(time.Minute, newFakeClock())
+	newGauge := func(service string) pdata.Metrics {
+		return pdatabuilder.NewMetrics().
+			Resource(pdatabuilder.Attrs{"service.name": service}).
+			IntGauge("queue").IntDataPoint(1, map[string]string{"a": "1", "b": "2"}).
+			Build()
+	}
+	// The data points without timestamp are placed by the time they are received.
+	assert.Equal(t, 1, d.decimate(newGauge("svc1")).DataPointCount())
+	assert.Equal(t, 1, d.decimate(newGauge("svc2")).DataPointCount())
+	assert.Equal(t, 0, d.decimate(newGauge("svc1")).DataPointCount())
+
+	// The order of the labels does not change the series.
+	md := newGauge("svc2")
+	labels := md.ResourceMetrics().At(0).InstrumentationLibraryMetrics().At(0).Metrics().At(0).IntGauge().DataPoints().At(0).LabelsMap()
+	labels.InitFromMap(map[string]string{"b": "2", "a": "1"})
+	assert.Equal(t, 0, d.decimate(md).DataPointCount())
+}
+
+func TestDecimator_ForgetsStaleSeries(t *testing.T) {
+	clock := newFakeClock()
+	d := newTestDecimator(time.Minute, clock)
+	newGauge := func(name string) pdata.Metrics {
+		return pdatabuilder.NewMetrics().IntGauge(name).IntDataPoint(1, nil).Build()
+	}
+	d.decimate(newGauge("a"))
+	d.decimate(newGauge("b"))
+	assert.Len(t, d.series, 2)
+
+	// The "a" series keeps being reported, the "b" series stops.
+	for i := 0; i < 4; i++ {
+		clock.Advance(30 * time.Second)
+		d.decimate(newGauge("a"))
+	}
+	assert.Len(t, d.series, 1)
+	assert.Contains(t, d.series, metricskey.Series(metricskey.Metric(metricskey.Resource(pdata.NewResource()),
+		pdata.NewInstrumentationLibrary(), newGauge("a").ResourceMetrics().At(0).InstrumentationLibraryMetrics().At(0).Metrics().At(0)),
+		pdata.NewStringMap()))
+}
+
+func TestMetricsExporter_WithDecimation(t *testing.T) {
+	// Use a dedicated exporter name, since the other tests also decimate metrics.
+	cfg := config.NewExporterSettings(config.NewIDWithName(typeStr, "decimation"))
+	sink := new(consumertest.MetricsSink)
+	clock := newFakeClock()
+	me, err := NewMetricsExporter(&cfg, zap.NewNop(), sink.ConsumeMetrics, WithDecimation(time.Minute), WithClock(clock))
+	require.NoError(t, err)
+	require.NoError(t, me.Start(context.Background(), componenttest.NewNopHost()))
+	t.Cleanup(func() {
+		assert.NoError(t, me.Shutdown(context.Background()))
+	})
+
+	gauge := func() pdata.Metrics {
+		return pdatabuilder.NewMetrics().DoubleGauge("cpu").DoubleDataPoint(0.5, nil).Build()
+	}
+	for i := 0; i < 4; i++ {
+		assert.NoError(t, me.ConsumeMetrics(context.Background(), gauge()))
+		clock.Advance(30 * time.Second)
+	}
+	// The batches left without data points are not sent.
+	assert.Len(t, sink.AllMetrics(), 2)
+	checkValueForProducer(t, []tag.Tag{{Key: exporterTag, Value: "test/decimation"}}, int64(2), "exporter/decimation_dropped_points")
+
+	me, err = NewMetricsExporter(&cfg, zap.NewNop(), sink.ConsumeMetrics, WithDecimation(0))
+	require.NoError(t, err)
+	sink.Reset()
+	for i := 0; i < 4; i++ {
+		assert.NoError(t, me.ConsumeMetrics(context.Background(), gauge()))
+	}
+	assert.Len(t, sink.AllMetrics(), 4)
+}
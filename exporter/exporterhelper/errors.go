@@ -0,0 +1,91 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package exporterhelper
+
+import (
+	"fmt"
+	"time"
+
+	"go.opentelemetry.io/collector/consumer/consumererror"
+)
+
+// The errors below can be returned by the push functions, directly or wrapped, to
+// tell the exporterhelper how to handle a failed export. Callers can inspect them
+// with errors.As using a pointer to the error type, e.g.:
+//
+//   var throttleErr *ThrottleError
+//   if errors.As(err, &throttleErr) { ... }
+
+// PermanentError is an error that will always be returned if the same data is
+// exported again, so the data is dropped without retries. It is also detected by
+// consumererror.IsPermanent.
+type PermanentError struct {
+	Err error
+}
+
+func (e *PermanentError) Error() string {
+	return "Permanent error: " + e.Err.Error()
+}
+
+// Unwrap returns the error marked with consumererror.Permanent, so that the error
+// is detected as permanent by all the components and Err can still be found with
+// errors.Is and errors.As.
+func (e *PermanentError) Unwrap() error {
+	return consumererror.Permanent(e.Err)
+}
+
+// ThrottleError is a retryable error returned when the backend is throttling the
+// requests, with an optional delay before the next attempt.
+type ThrottleError struct {
+	Err error
+	// RetryAfter is the delay requested by the backend before the next attempt, e.g.
+	// from a Retry-After header. If set, it is used instead of the backoff delay.
+	RetryAfter time.Duration
+}
+
+func (e *ThrottleError) Error() string {
+	return e.Err.Error()
+}
+
+// Unwrap returns the wrapped error for functions Is and As in standard package errors.
+func (e *ThrottleError) Unwrap() error {
+	return e.Err
+}
+
+// NewThrottleRetry creates a new ThrottleError with the given delay.
+func NewThrottleRetry(err error, delay time.Duration) error {
+	return &ThrottleError{
+		Err:        err,
+		RetryAfter: delay,
+	}
+}
+
+// PartialError is returned when the backend accepted the request except for some
+// items it rejected, e.g. because they were invalid. The rejected items are
+// dropped without retries, since the backend would reject them again.
+type PartialError struct {
+	Err error
+	// Rejected is the number of rejected spans, metric points or log records.
+	Rejected int
+}
+
+func (e *PartialError) Error() string {
+	return fmt.Sprintf("%d items rejected: %v", e.Rejected, e.Err)
+}
+
+// Unwrap returns the wrapped error for functions Is and As in standard package errors.
+func (e *PartialError) Unwrap() error {
+	return e.Err
+}
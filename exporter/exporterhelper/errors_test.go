@@ -0,0 +1,63 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package exporterhelper
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go.opentelemetry.io/collector/consumer/consumererror"
+)
+
+func TestPermanentError(t *testing.T) {
+	cause := errors.New("bad data")
+	err := fmt.Errorf("export: %w", &PermanentError{Err: cause})
+	assert.Equal(t, "export: Permanent error: bad data", err.Error())
+	assert.True(t, consumererror.IsPermanent(err))
+	assert.True(t, errors.Is(err, cause))
+
+	var permErr *PermanentError
+	require.True(t, errors.As(err, &permErr))
+	assert.Equal(t, cause, permErr.Err)
+}
+
+func TestThrottleError(t *testing.T) {
+	cause := errors.New("quota exceeded")
+	err := fmt.Errorf("export: %w", NewThrottleRetry(cause, time.Minute))
+	assert.Equal(t, "export: quota exceeded", err.Error())
+	assert.False(t, consumererror.IsPermanent(err))
+	assert.True(t, errors.Is(err, cause))
+
+	var throttleErr *ThrottleError
+	require.True(t, errors.As(err, &throttleErr))
+	assert.Equal(t, time.Minute, throttleErr.RetryAfter)
+}
+
+func TestPartialError(t *testing.T) {
+	cause := errors.New("invalid spans")
+	err := fmt.Errorf("export: %w", &PartialError{Err: cause, Rejected: 3})
+	assert.Equal(t, "export: 3 items rejected: invalid spans", err.Error())
+	assert.False(t, consumererror.IsPermanent(err))
+	assert.True(t, errors.Is(err, cause))
+
+	var partialErr *PartialError
+	require.True(t, errors.As(err, &partialErr))
+	assert.Equal(t, 3, partialErr.Rejected)
+}
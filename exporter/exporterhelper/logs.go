@@ -59,6 +59,10 @@ func (req *logsRequest) count() int {
 	return req.ld.LogRecordCount()
 }
 
+func (req *logsRequest) data() interface{} {
+	return req.ld
+}
+
 type logsExporter struct {
 	*baseExporter
 	consumer.Logs
@@ -84,7 +88,19 @@ func NewLogsExporter(
 	}
 
 	bs := fromOptions(options...)
-	be := newBaseExporter(cfg, logger, bs)
+	if err := bs.RetrySettings.Validate(); err != nil {
+		return nil, err
+	}
+	be := newBaseExporter(cfg, config.LogsDataType, logger, bs)
+	if bs.queueOverflow != nil {
+		overflow, ok := bs.queueOverflow.(consumer.Logs)
+		if !ok {
+			return nil, errInvalidQueueOverflow
+		}
+		be.qrSender.overflow = func(req request) error {
+			return overflow.ConsumeLogs(req.context(), req.data().(pdata.Logs))
+		}
+	}
 	be.wrapConsumerSender(func(nextSender requestSender) requestSender {
 		return &logsExporterWithObservability{
 			obsrep: obsreport.NewExporter(obsreport.ExporterSettings{
@@ -29,6 +29,7 @@ import (
 	"go.opentelemetry.io/collector/consumer"
 	"go.opentelemetry.io/collector/consumer/consumererror"
 	"go.opentelemetry.io/collector/consumer/consumerhelper"
+	"go.opentelemetry.io/collector/consumer/consumertest"
 	"go.opentelemetry.io/collector/consumer/pdata"
 	"go.opentelemetry.io/collector/internal/testdata"
 	"go.opentelemetry.io/collector/obsreport"
@@ -159,6 +160,24 @@ func TestLogsExporter_WithShutdown_ReturnError(t *testing.T) {
 	assert.Equal(t, le.Shutdown(context.Background()), want)
 }
 
+func TestLogsExporter_WithQueueOverflow(t *testing.T) {
+	qCfg := DefaultQueueSettings()
+	qCfg.QueueSize = 0
+	sink := new(consumertest.LogsSink)
+	le, err := NewLogsExporter(&fakeLogsExporterConfig, zap.NewNop(), newPushLogsData(nil), WithQueue(qCfg), WithQueueOverflow(sink))
+	require.NoError(t, err)
+	require.NoError(t, le.Start(context.Background(), componenttest.NewNopHost()))
+
+	ld := testdata.GenerateLogsTwoLogRecordsSameResource()
+	assert.NoError(t, le.ConsumeLogs(context.Background(), ld))
+	assert.NoError(t, le.Shutdown(context.Background()))
+	assert.Equal(t, []pdata.Logs{ld}, sink.AllLogs())
+
+	le, err = NewLogsExporter(&fakeLogsExporterConfig, zap.NewNop(), newPushLogsData(nil), WithQueueOverflow(new(consumertest.MetricsSink)))
+	assert.Nil(t, le)
+	assert.Equal(t, errInvalidQueueOverflow, err)
+}
+
 func newPushLogsData(retError error) consumerhelper.ConsumeLogsFunc {
 	return func(ctx context.Context, td pdata.Logs) error {
 		return retError
@@ -17,6 +17,7 @@ package exporterhelper
 import (
 	"context"
 
+	"go.opencensus.io/metric/metricdata"
 	"go.uber.org/zap"
 
 	"go.opentelemetry.io/collector/component"
@@ -60,6 +61,10 @@ func (req *metricsRequest) count() int {
 	return numPoints
 }
 
+func (req *metricsRequest) data() interface{} {
+	return req.md
+}
+
 type metricsExporter struct {
 	*baseExporter
 	consumer.Metrics
@@ -85,7 +90,19 @@ func NewMetricsExporter(
 	}
 
 	bs := fromOptions(options...)
-	be := newBaseExporter(cfg, logger, bs)
+	if err := bs.RetrySettings.Validate(); err != nil {
+		return nil, err
+	}
+	be := newBaseExporter(cfg, config.MetricsDataType, logger, bs)
+	if bs.queueOverflow != nil {
+		overflow, ok := bs.queueOverflow.(consumer.Metrics)
+		if !ok {
+			return nil, errInvalidQueueOverflow
+		}
+		be.qrSender.overflow = func(req request) error {
+			return overflow.ConsumeMetrics(req.context(), req.data().(pdata.Metrics))
+		}
+	}
 	be.wrapConsumerSender(func(nextSender requestSender) requestSender {
 		return &metricsSenderWithObservability{
 			obsrep: obsreport.NewExporter(obsreport.ExporterSettings{
@@ -96,7 +113,17 @@ func NewMetricsExporter(
 		}
 	})
 
+	var dec *decimator
+	if bs.decimationWindow > 0 {
+		dec = newDecimator(bs.decimationWindow, bs.clock, be.reporter.counter(decimationDroppedPoints, metricdata.NewLabelValue(cfg.ID().String())))
+	}
+
 	mc, err := consumerhelper.NewMetrics(func(ctx context.Context, md pdata.Metrics) error {
+		if dec != nil {
+			if md = dec.decimate(md); md.DataPointCount() == 0 {
+				return nil
+			}
+		}
 		if bs.ResourceToTelemetrySettings.Enabled {
 			md = convertResourceToLabels(md)
 		}
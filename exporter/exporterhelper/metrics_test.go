@@ -29,6 +29,7 @@ import (
 	"go.opentelemetry.io/collector/consumer"
 	"go.opentelemetry.io/collector/consumer/consumererror"
 	"go.opentelemetry.io/collector/consumer/consumerhelper"
+	"go.opentelemetry.io/collector/consumer/consumertest"
 	"go.opentelemetry.io/collector/consumer/pdata"
 	"go.opentelemetry.io/collector/internal/testdata"
 	"go.opentelemetry.io/collector/obsreport"
@@ -182,6 +183,24 @@ func TestMetricsExporter_WithShutdown_ReturnError(t *testing.T) {
 	assert.Equal(t, want, me.Shutdown(context.Background()))
 }
 
+func TestMetricsExporter_WithQueueOverflow(t *testing.T) {
+	qCfg := DefaultQueueSettings()
+	qCfg.QueueSize = 0
+	sink := new(consumertest.MetricsSink)
+	me, err := NewMetricsExporter(&fakeMetricsExporterConfig, zap.NewNop(), newPushMetricsData(nil), WithQueue(qCfg), WithQueueOverflow(sink))
+	require.NoError(t, err)
+	require.NoError(t, me.Start(context.Background(), componenttest.NewNopHost()))
+
+	md := testdata.GenerateMetricsTwoMetrics()
+	assert.NoError(t, me.ConsumeMetrics(context.Background(), md))
+	assert.NoError(t, me.Shutdown(context.Background()))
+	assert.Equal(t, []pdata.Metrics{md}, sink.AllMetrics())
+
+	me, err = NewMetricsExporter(&fakeMetricsExporterConfig, zap.NewNop(), newPushMetricsData(nil), WithQueueOverflow(new(consumertest.TracesSink)))
+	assert.Nil(t, me)
+	assert.Equal(t, errInvalidQueueOverflow, err)
+}
+
 func newPushMetricsData(retError error) consumerhelper.ConsumeMetricsFunc {
 	return func(ctx context.Context, td pdata.Metrics) error {
 		return retError
@@ -18,6 +18,9 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"strconv"
+	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/cenkalti/backoff/v4"
@@ -28,18 +31,31 @@ import (
 	"go.opencensus.io/trace"
 	"go.uber.org/zap"
 	"go.uber.org/zap/zapcore"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 
+	"go.opentelemetry.io/collector/config"
 	"go.opentelemetry.io/collector/consumer/consumererror"
 	"go.opentelemetry.io/collector/obsreport"
 )
 
+// dataTypeKey is the label of the queue metrics identifying the signal of the exporter,
+// since the exporters of the different signals of a component have their own queue.
+const dataTypeKey = "data_type"
+
 var (
 	r = metric.NewRegistry()
 
 	queueSizeGauge, _ = r.AddInt64DerivedGauge(
 		obsreport.ExporterKey+"/queue_size",
 		metric.WithDescription("Current size of the retry queue (in batches)"),
-		metric.WithLabelKeys(obsreport.ExporterKey),
+		metric.WithLabelKeys(obsreport.ExporterKey, dataTypeKey),
+		metric.WithUnit(metricdata.UnitDimensionless))
+
+	queueOverflowedItems, _ = r.AddInt64Cumulative(
+		obsreport.ExporterKey+"/queue_overflowed_items",
+		metric.WithDescription("Number of items sent to the queue overflow consumer because the retry queue was full"),
+		metric.WithLabelKeys(obsreport.ExporterKey, dataTypeKey),
 		metric.WithUnit(metricdata.UnitDimensionless))
 )
 
@@ -81,8 +97,37 @@ type RetrySettings struct {
 	// consecutive retries will always be `MaxInterval`.
 	MaxInterval time.Duration `mapstructure:"max_interval"`
 	// MaxElapsedTime is the maximum amount of time (including retries) spent trying to send a request/batch.
-	// Once this value is reached, the data is discarded.
+	// Once this value is reached, the data is discarded. It bounds the attempts, whose timeout is
+	// shortened to the time left, as well as the delays between them. Zero means no limit.
 	MaxElapsedTime time.Duration `mapstructure:"max_elapsed_time"`
+	// RetryableStatusCodes are the names of the gRPC status codes of the failed exports
+	// that are retried, e.g. "UNAVAILABLE". The failed exports with any other gRPC status
+	// code are dropped. If not set, all the failed exports are retried. The errors
+	// without a gRPC status and the ThrottleError are always retried.
+	RetryableStatusCodes []string `mapstructure:"retryable_status_codes"`
+}
+
+// Validate checks if the retry settings are valid.
+func (rCfg *RetrySettings) Validate() error {
+	_, err := rCfg.retryableCodes()
+	return err
+}
+
+// retryableCodes returns the set of the retryable gRPC status codes, nil if all
+// the codes are retryable.
+func (rCfg *RetrySettings) retryableCodes() (map[codes.Code]bool, error) {
+	if rCfg.RetryableStatusCodes == nil {
+		return nil, nil
+	}
+	retryable := make(map[codes.Code]bool)
+	for _, name := range rCfg.RetryableStatusCodes {
+		var code codes.Code
+		if err := code.UnmarshalJSON([]byte(strconv.Quote(name))); err != nil {
+			return nil, fmt.Errorf("unknown retryable status code %q", name)
+		}
+		retryable[code] = true
+	}
+	return retryable, nil
 }
 
 // DefaultRetrySettings returns the default settings for RetrySettings.
@@ -95,17 +140,101 @@ func DefaultRetrySettings() RetrySettings {
 	}
 }
 
+// boundedQueue is the interface implemented by the queues used by the queuedRetrySender.
+type boundedQueue interface {
+	Produce(item interface{}) bool
+	StartConsumers(num int, callback func(item interface{}))
+	Stop()
+	Size() int
+}
+
+var _ boundedQueue = (*queue.BoundedQueue)(nil)
+
+// partitionedQueue is a boundedQueue that routes the requests to partitions based
+// on their ordering key. Every partition has a single consumer, which preserves the
+// order of the requests with the same key.
+type partitionedQueue struct {
+	partitions  []*queue.BoundedQueue
+	orderingKey OrderingKeyFunc
+}
+
+func newPartitionedQueue(numPartitions int, capacity int, orderingKey OrderingKeyFunc) *partitionedQueue {
+	if numPartitions < 1 {
+		numPartitions = 1
+	}
+	// Split the capacity between partitions, rounding up so no partition is empty.
+	partitionCapacity := (capacity + numPartitions - 1) / numPartitions
+	pq := &partitionedQueue{
+		partitions:  make([]*queue.BoundedQueue, numPartitions),
+		orderingKey: orderingKey,
+	}
+	for i := range pq.partitions {
+		pq.partitions[i] = queue.NewBoundedQueue(partitionCapacity, func(item interface{}) {})
+	}
+	return pq
+}
+
+// Produce adds the request to the partition corresponding to its ordering key.
+func (pq *partitionedQueue) Produce(item interface{}) bool {
+	key := pq.orderingKey(item.(request).data())
+	return pq.partitions[key%uint64(len(pq.partitions))].Produce(item)
+}
+
+// StartConsumers starts one consumer per partition, the number of consumers
+// is given by the number of partitions.
+func (pq *partitionedQueue) StartConsumers(_ int, callback func(item interface{})) {
+	for _, partition := range pq.partitions {
+		partition.StartConsumers(1, callback)
+	}
+}
+
+// Stop stops all the partitions.
+func (pq *partitionedQueue) Stop() {
+	for _, partition := range pq.partitions {
+		partition.Stop()
+	}
+}
+
+// Size returns the number of requests in all the partitions.
+func (pq *partitionedQueue) Size() int {
+	size := 0
+	for _, partition := range pq.partitions {
+		size += partition.Size()
+	}
+	return size
+}
+
+// flushCheckInterval is the interval at which flush checks whether all the requests were sent.
+const flushCheckInterval = 10 * time.Millisecond
+
 type queuedRetrySender struct {
-	fullName        string
+	// pending is the number of requests in the queue or being sent, accessed atomically.
+	// It is the first field to guarantee the 64-bit alignment required by atomic operations.
+	pending  int64
+	fullName string
+	// labelValues are the values of the labels of the queue metrics.
+	labelValues     []metricdata.LabelValue
 	cfg             QueueSettings
 	consumerSender  requestSender
-	queue           *queue.BoundedQueue
+	queue           boundedQueue
 	retryStopCh     chan struct{}
 	traceAttributes []trace.Attribute
 	logger          *zap.Logger
+	// overflow, if set, receives the requests that do not fit in the queue.
+	overflow func(req request) error
+	// overflowedItems counts the items sent to the overflow.
+	overflowedItems *counter
 }
 
-func createSampledLogger(logger *zap.Logger) *zap.Logger {
+// createSampledLogger returns the logger of the failures of an exporter. If the interval
+// is set the identical messages are logged at most once per interval, otherwise they are
+// sampled unless debugging is enabled.
+func createSampledLogger(logger *zap.Logger, interval time.Duration) *zap.Logger {
+	if interval > 0 {
+		return logger.WithOptions(zap.WrapCore(func(core zapcore.Core) zapcore.Core {
+			return &dedupCore{Core: core, interval: interval, state: &dedupState{entries: make(map[dedupKey]*dedupEntry)}}
+		}))
+	}
 	if logger.Core().Enabled(zapcore.DebugLevel) {
 		// Debugging is enabled. Don't do any sampling.
 		return logger
@@ -124,24 +253,94 @@ func createSampledLogger(logger *zap.Logger) *zap.Logger {
 	return logger.WithOptions(opts)
 }
 
-func newQueuedRetrySender(fullName string, qCfg QueueSettings, rCfg RetrySettings, nextSender requestSender, logger *zap.Logger) *queuedRetrySender {
+// dedupCore is a zapcore.Core logging the first entry with a given level and message,
+// then at most one every interval along with the number of entries suppressed since the
+// previous one, e.g. to keep readable the logs of a backend which is down.
+type dedupCore struct {
+	zapcore.Core
+	interval time.Duration
+	// state is shared by the cores derived with With.
+	state *dedupState
+}
+
+type dedupKey struct {
+	level   zapcore.Level
+	message string
+}
+
+type dedupEntry struct {
+	last       time.Time
+	suppressed int
+}
+
+type dedupState struct {
+	mu      sync.Mutex
+	entries map[dedupKey]*dedupEntry
+}
+
+func (c *dedupCore) With(fields []zapcore.Field) zapcore.Core {
+	return &dedupCore{Core: c.Core.With(fields), interval: c.interval, state: c.state}
+}
+
+func (c *dedupCore) Check(entry zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if !c.Enabled(entry.Level) {
+		return ce
+	}
+	suppressed, ok := c.state.admit(dedupKey{level: entry.Level, message: entry.Message}, entry.Time, c.interval)
+	if !ok {
+		return ce
+	}
+	if suppressed > 0 {
+		return c.Core.With([]zapcore.Field{zap.Int("suppressed", suppressed)}).Check(entry, ce)
+	}
+	return c.Core.Check(entry, ce)
+}
+
+// admit returns whether the entry with the given key logged at t is logged, and the number
+// of entries with the same key suppressed since the previous one.
+func (s *dedupState) admit(key dedupKey, t time.Time, interval time.Duration) (int, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	e, ok := s.entries[key]
+	if !ok {
+		s.entries[key] = &dedupEntry{last: t}
+		return 0, true
+	}
+	if t.Sub(e.last) < interval {
+		e.suppressed++
+		return 0, false
+	}
+	suppressed := e.suppressed
+	e.last, e.suppressed = t, 0
+	return suppressed, true
+}
+
+func newQueuedRetrySender(fullName string, dataType config.DataType, qCfg QueueSettings, rCfg RetrySettings, orderingKey OrderingKeyFunc, nextSender requestSender, clock Clock, logger *zap.Logger) *queuedRetrySender {
 	retryStopCh := make(chan struct{})
-	sampledLogger := createSampledLogger(logger)
 	traceAttr := trace.StringAttribute(obsreport.ExporterKey, fullName)
+	// The retry settings are validated when the exporter is created.
+	retryableCodes, _ := rCfg.retryableCodes()
+	var q boundedQueue = queue.NewBoundedQueue(qCfg.QueueSize, func(item interface{}) {})
+	if orderingKey != nil {
+		q = newPartitionedQueue(qCfg.NumConsumers, qCfg.QueueSize, orderingKey)
+	}
 	return &queuedRetrySender{
-		fullName: fullName,
-		cfg:      qCfg,
+		fullName:    fullName,
+		labelValues: []metricdata.LabelValue{metricdata.NewLabelValue(fullName), metricdata.NewLabelValue(string(dataType))},
+		cfg:         qCfg,
 		consumerSender: &retrySender{
 			traceAttribute: traceAttr,
 			cfg:            rCfg,
+			retryableCodes: retryableCodes,
 			nextSender:     nextSender,
 			stopCh:         retryStopCh,
-			logger:         sampledLogger,
+			clock:          clock,
+			logger:         logger,
 		},
-		queue:           queue.NewBoundedQueue(qCfg.QueueSize, func(item interface{}) {}),
+		queue:           q,
 		retryStopCh:     retryStopCh,
 		traceAttributes: []trace.Attribute{traceAttr},
-		logger:          sampledLogger,
+		logger:          logger,
 	}
 }
 
@@ -150,13 +349,14 @@ func (qrs *queuedRetrySender) start() error {
 	qrs.queue.StartConsumers(qrs.cfg.NumConsumers, func(item interface{}) {
 		req := item.(request)
 		_ = qrs.consumerSender.send(req)
+		atomic.AddInt64(&qrs.pending, -1)
 	})
 
 	// Start reporting queue length metric
 	if qrs.cfg.Enabled {
 		err := queueSizeGauge.UpsertEntry(func() int64 {
 			return int64(qrs.queue.Size())
-		}, metricdata.NewLabelValue(qrs.fullName))
+		}, qrs.labelValues...)
 		if err != nil {
 			return fmt.Errorf("failed to create retry queue size metric: %v", err)
 		}
@@ -183,7 +383,13 @@ func (qrs *queuedRetrySender) send(req request) error {
 	req.setContext(noCancellationContext{Context: req.context()})
 
 	span := trace.FromContext(req.context())
+	// The request is counted before being produced since it can be consumed right away.
+	atomic.AddInt64(&qrs.pending, 1)
 	if !qrs.queue.Produce(req) {
+		atomic.AddInt64(&qrs.pending, -1)
+		if qrs.overflow != nil {
+			return qrs.sendToOverflow(req, span)
+		}
 		qrs.logger.Error(
 			"Dropping data because sending_queue is full. Try increasing queue_size.",
 			zap.Int("dropped_items", req.count()),
@@ -196,42 +402,70 @@ func (qrs *queuedRetrySender) send(req request) error {
 	return nil
 }
 
-// shutdown is invoked during service shutdown.
-func (qrs *queuedRetrySender) shutdown() {
+// sendToOverflow sends to the overflow consumer a request that does not fit in the queue.
+func (qrs *queuedRetrySender) sendToOverflow(req request, span *trace.Span) error {
+	if err := qrs.overflow(req); err != nil {
+		qrs.logger.Error(
+			"Dropping data because sending_queue is full and the queue overflow failed.",
+			zap.Int("dropped_items", req.count()),
+			zap.Error(err),
+		)
+		span.Annotate(qrs.traceAttributes, "Dropped item, sending_queue is full and the queue overflow failed.")
+		return err
+	}
+	qrs.overflowedItems.add(int64(req.count()))
+	span.Annotate(qrs.traceAttributes, "Sent item to the queue overflow, sending_queue is full.")
+	return nil
+}
+
+// flush blocks until all the requests in the queue were sent, including the retries,
+// or the context is done. It returns immediately if the queue is disabled.
+func (qrs *queuedRetrySender) flush(ctx context.Context) error {
+	if !qrs.cfg.Enabled {
+		return nil
+	}
+	ticker := time.NewTicker(flushCheckInterval)
+	defer ticker.Stop()
+	for atomic.LoadInt64(&qrs.pending) > 0 {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+	return nil
+}
+
+// shutdown is invoked during service shutdown, it returns once the queued requests
+// were sent, each one attempted once, or the context is done.
+func (qrs *queuedRetrySender) shutdown(ctx context.Context) {
 	// Cleanup queue metrics reporting
 	if qrs.cfg.Enabled {
 		_ = queueSizeGauge.UpsertEntry(func() int64 {
 			return int64(0)
-		}, metricdata.NewLabelValue(qrs.fullName))
+		}, qrs.labelValues...)
 	}
 
 	// First stop the retry goroutines, so that unblocks the queue workers.
 	close(qrs.retryStopCh)
 
-	// Stop the queued sender, this will drain the queue and will call the retry (which is stopped) that will only
-	// try once every request.
-	qrs.queue.Stop()
-}
-
-// TODO: Clean this by forcing all exporters to return an internal error type that always include the information about retries.
-type throttleRetry struct {
-	error
-	delay time.Duration
-}
-
-// NewThrottleRetry creates a new throttle retry error.
-func NewThrottleRetry(err error, delay time.Duration) error {
-	return &throttleRetry{
-		error: err,
-		delay: delay,
+	// Wait for the queue workers to drain the queue, the retry (which is stopped) will only try once every
+	// request. The queue drops the requests left when it is stopped.
+	if qrs.cfg.NumConsumers > 0 {
+		_ = qrs.flush(ctx)
 	}
+
+	// Stop the queued sender.
+	qrs.queue.Stop()
 }
 
 type retrySender struct {
 	traceAttribute trace.Attribute
 	cfg            RetrySettings
+	retryableCodes map[codes.Code]bool
 	nextSender     requestSender
 	stopCh         chan struct{}
+	clock          Clock
 	logger         *zap.Logger
 }
 
@@ -257,9 +491,29 @@ func (rs *retrySender) send(req request) error {
 		MaxInterval:         rs.cfg.MaxInterval,
 		MaxElapsedTime:      rs.cfg.MaxElapsedTime,
 		Stop:                backoff.Stop,
-		Clock:               backoff.SystemClock,
+		Clock:               rs.clock,
 	}
 	expBackoff.Reset()
+	if rs.cfg.MaxElapsedTime > 0 {
+		// Bound the attempts and the delays between them by the max elapsed time, measured
+		// with the clock like the elapsed time of the backoff. The original context is
+		// restored since the caller keeps using the request.
+		orig, parent := req, req.context()
+		ctx, cancel := context.WithCancel(parent)
+		expired := rs.clock.After(rs.cfg.MaxElapsedTime)
+		go func() {
+			select {
+			case <-expired:
+				cancel()
+			case <-ctx.Done():
+			}
+		}()
+		req.setContext(ctx)
+		defer func() {
+			cancel()
+			orig.setContext(parent)
+		}()
+	}
 	span := trace.FromContext(req.context())
 	retryNum := int64(0)
 	for {
@@ -274,6 +528,17 @@ func (rs *retrySender) send(req request) error {
 			return nil
 		}
 
+		// Immediately drop the rejected items of a partially accepted request, the rest was exported.
+		var partialErr *PartialError
+		if errors.As(err, &partialErr) {
+			rs.logger.Error(
+				"Exporting partially failed. The rejected items are not retryable. Dropping them.",
+				zap.Error(err),
+				zap.Int("dropped_items", partialErr.Rejected),
+			)
+			return err
+		}
+
 		// Immediately drop data on permanent errors.
 		if consumererror.IsPermanent(err) {
 			rs.logger.Error(
@@ -284,11 +549,29 @@ func (rs *retrySender) send(req request) error {
 			return err
 		}
 
+		// Immediately drop data on the gRPC status codes that are not retryable.
+		if !rs.isRetryable(err) {
+			rs.logger.Error(
+				"Exporting failed. The status code is not retryable. Dropping data.",
+				zap.Error(err),
+				zap.Int("dropped_items", req.count()),
+			)
+			return err
+		}
+
 		// Give the request a chance to extract signal data to retry if only some data
 		// failed to process.
 		req = req.onError(err)
 
 		backoffDelay := expBackoff.NextBackOff()
+		// Honor the delay requested by the backend, as long as it fits in the max elapsed time.
+		var throttleErr *ThrottleError
+		if backoffDelay != backoff.Stop && errors.As(err, &throttleErr) && throttleErr.RetryAfter > 0 {
+			backoffDelay = throttleErr.RetryAfter
+			if rs.cfg.MaxElapsedTime > 0 && expBackoff.GetElapsedTime()+backoffDelay > rs.cfg.MaxElapsedTime {
+				backoffDelay = backoff.Stop
+			}
+		}
 		if backoffDelay == backoff.Stop {
 			// throw away the batch
 			err = fmt.Errorf("max elapsed time expired %w", err)
@@ -300,10 +583,6 @@ func (rs *retrySender) send(req request) error {
 			return err
 		}
 
-		if throttleErr, isThrottle := err.(*throttleRetry); isThrottle {
-			backoffDelay = max(backoffDelay, throttleErr.delay)
-		}
-
 		backoffDelayStr := backoffDelay.String()
 		span.Annotate(
 			[]trace.Attribute{
@@ -321,20 +600,37 @@ func (rs *retrySender) send(req request) error {
 		// back-off, but get interrupted when shutting down or request is cancelled or timed out.
 		select {
 		case <-req.context().Done():
+			if rs.cfg.MaxElapsedTime > 0 && expBackoff.GetElapsedTime() >= rs.cfg.MaxElapsedTime {
+				err = fmt.Errorf("max elapsed time expired %w", err)
+				rs.logger.Error(
+					"Exporting failed. No more retries left. Dropping data.",
+					zap.Error(err),
+					zap.Int("dropped_items", req.count()),
+				)
+				return err
+			}
 			return fmt.Errorf("request is cancelled or timed out %w", err)
 		case <-rs.stopCh:
 			return fmt.Errorf("interrupted due to shutdown %w", err)
-		case <-time.After(backoffDelay):
+		case <-rs.clock.After(backoffDelay):
 		}
 	}
 }
 
-// max returns the larger of x or y.
-func max(x, y time.Duration) time.Duration {
-	if x < y {
-		return y
+// isRetryable returns whether the failed export must be retried given its gRPC status code.
+func (rs *retrySender) isRetryable(err error) bool {
+	if rs.retryableCodes == nil {
+		return true
+	}
+	var throttleErr *ThrottleError
+	if errors.As(err, &throttleErr) {
+		return true
+	}
+	var statusErr interface{ GRPCStatus() *status.Status }
+	if !errors.As(err, &statusErr) {
+		return true
 	}
-	return x
+	return rs.retryableCodes[statusErr.GRPCStatus().Code()]
 }
 
 type noCancellationContext struct {
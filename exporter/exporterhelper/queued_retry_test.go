@@ -29,8 +29,13 @@ import (
 	"go.opencensus.io/metric/metricproducer"
 	"go.opencensus.io/tag"
 	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 
 	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/config"
 	"go.opentelemetry.io/collector/consumer/consumererror"
 	"go.opentelemetry.io/collector/internal/testdata"
 	"go.opentelemetry.io/collector/obsreport/obsreporttest"
@@ -39,7 +44,7 @@ import (
 func TestQueuedRetry_DropOnPermanentError(t *testing.T) {
 	qCfg := DefaultQueueSettings()
 	rCfg := DefaultRetrySettings()
-	be := newBaseExporter(&defaultExporterCfg, zap.NewNop(), fromOptions(WithRetry(rCfg), WithQueue(qCfg)))
+	be := newBaseExporter(&defaultExporterCfg, config.TracesDataType, zap.NewNop(), fromOptions(WithRetry(rCfg), WithQueue(qCfg)))
 	ocs := newObservabilityConsumerSender(be.qrSender.consumerSender)
 	be.qrSender.consumerSender = ocs
 	require.NoError(t, be.Start(context.Background(), componenttest.NewNopHost()))
@@ -59,11 +64,56 @@ func TestQueuedRetry_DropOnPermanentError(t *testing.T) {
 	ocs.checkDroppedItemsCount(t, 2)
 }
 
+func TestQueuedRetry_DropOnPermanentErrorType(t *testing.T) {
+	qCfg := DefaultQueueSettings()
+	rCfg := DefaultRetrySettings()
+	be := newBaseExporter(&defaultExporterCfg, config.TracesDataType, zap.NewNop(), fromOptions(WithRetry(rCfg), WithQueue(qCfg)))
+	ocs := newObservabilityConsumerSender(be.qrSender.consumerSender)
+	be.qrSender.consumerSender = ocs
+	require.NoError(t, be.Start(context.Background(), componenttest.NewNopHost()))
+	t.Cleanup(func() {
+		assert.NoError(t, be.Shutdown(context.Background()))
+	})
+
+	mockR := newMockRequest(context.Background(), 2, fmt.Errorf("export: %w", &PermanentError{Err: errors.New("bad data")}))
+	ocs.run(func() {
+		// This is asynchronous so it should just enqueue, no errors expected.
+		require.NoError(t, be.sender.send(mockR))
+	})
+	ocs.awaitAsyncProcessing()
+	mockR.checkNumRequests(t, 1)
+	ocs.checkSendItemsCount(t, 0)
+	ocs.checkDroppedItemsCount(t, 2)
+}
+
+func TestQueuedRetry_PartialError(t *testing.T) {
+	rCfg := DefaultRetrySettings()
+	rCfg.InitialInterval = 0
+	core, logs := observer.New(zapcore.ErrorLevel)
+	be := newBaseExporter(&defaultExporterCfg, config.TracesDataType, zap.New(core), fromOptions(WithRetry(rCfg)))
+	require.NoError(t, be.Start(context.Background(), componenttest.NewNopHost()))
+	t.Cleanup(func() {
+		assert.NoError(t, be.Shutdown(context.Background()))
+	})
+
+	partialErr := &PartialError{Err: errors.New("invalid spans"), Rejected: 3}
+	mockR := newMockRequest(context.Background(), 10, partialErr)
+	err := be.sender.send(mockR)
+	var gotErr *PartialError
+	require.True(t, errors.As(err, &gotErr))
+	assert.Equal(t, 3, gotErr.Rejected)
+	// The rejected items are not retried.
+	mockR.checkNumRequests(t, 1)
+	entries := logs.FilterMessageSnippet("partially failed").All()
+	require.Len(t, entries, 1)
+	assert.Equal(t, int64(3), entries[0].ContextMap()["dropped_items"])
+}
+
 func TestQueuedRetry_DropOnNoRetry(t *testing.T) {
 	qCfg := DefaultQueueSettings()
 	rCfg := DefaultRetrySettings()
 	rCfg.Enabled = false
-	be := newBaseExporter(&defaultExporterCfg, zap.NewNop(), fromOptions(WithRetry(rCfg), WithQueue(qCfg)))
+	be := newBaseExporter(&defaultExporterCfg, config.TracesDataType, zap.NewNop(), fromOptions(WithRetry(rCfg), WithQueue(qCfg)))
 	ocs := newObservabilityConsumerSender(be.qrSender.consumerSender)
 	be.qrSender.consumerSender = ocs
 	require.NoError(t, be.Start(context.Background(), componenttest.NewNopHost()))
@@ -88,7 +138,7 @@ func TestQueuedRetry_OnError(t *testing.T) {
 	qCfg.NumConsumers = 1
 	rCfg := DefaultRetrySettings()
 	rCfg.InitialInterval = 0
-	be := newBaseExporter(&defaultExporterCfg, zap.NewNop(), fromOptions(WithRetry(rCfg), WithQueue(qCfg)))
+	be := newBaseExporter(&defaultExporterCfg, config.TracesDataType, zap.NewNop(), fromOptions(WithRetry(rCfg), WithQueue(qCfg)))
 	ocs := newObservabilityConsumerSender(be.qrSender.consumerSender)
 	be.qrSender.consumerSender = ocs
 	require.NoError(t, be.Start(context.Background(), componenttest.NewNopHost()))
@@ -110,11 +160,64 @@ func TestQueuedRetry_OnError(t *testing.T) {
 	ocs.checkDroppedItemsCount(t, 0)
 }
 
+func TestQueuedRetry_OrderedDelivery(t *testing.T) {
+	qCfg := DefaultQueueSettings()
+	qCfg.NumConsumers = 3
+	rCfg := DefaultRetrySettings()
+	keyFn := func(data interface{}) uint64 {
+		return data.(uint64)
+	}
+	be := newBaseExporter(&defaultExporterCfg, config.TracesDataType, zap.NewNop(), fromOptions(WithRetry(rCfg), WithQueue(qCfg), WithOrderedDelivery(keyFn)))
+	require.NoError(t, be.Start(context.Background(), componenttest.NewNopHost()))
+	t.Cleanup(func() {
+		assert.NoError(t, be.Shutdown(context.Background()))
+	})
+
+	exported := &orderedExports{seqs: map[uint64][]int{}}
+	for seq := 0; seq < 50; seq++ {
+		for key := uint64(0); key < 5; key++ {
+			require.NoError(t, be.sender.send(&mockOrderedRequest{
+				baseRequest: baseRequest{ctx: context.Background()},
+				key:         key,
+				seq:         seq,
+				exported:    exported,
+			}))
+		}
+	}
+
+	assert.Eventually(t, func() bool {
+		return exported.len() == 250
+	}, time.Second, 1*time.Millisecond)
+	for key := uint64(0); key < 5; key++ {
+		seqs := exported.get(key)
+		require.Len(t, seqs, 50)
+		for i, seq := range seqs {
+			assert.Equal(t, i, seq)
+		}
+	}
+}
+
+func TestPartitionedQueue(t *testing.T) {
+	pq := newPartitionedQueue(3, 10, func(data interface{}) uint64 {
+		return data.(uint64)
+	})
+	require.Len(t, pq.partitions, 3)
+	// The capacity of 10 is split in 4 per partition.
+	for i := 0; i < 4; i++ {
+		assert.True(t, pq.Produce(&mockOrderedRequest{key: 1}))
+	}
+	assert.False(t, pq.Produce(&mockOrderedRequest{key: 1}))
+	assert.False(t, pq.Produce(&mockOrderedRequest{key: 4}))
+	assert.True(t, pq.Produce(&mockOrderedRequest{key: 2}))
+	assert.Equal(t, 5, pq.Size())
+	pq.Stop()
+}
+
 func TestQueuedRetry_StopWhileWaiting(t *testing.T) {
 	qCfg := DefaultQueueSettings()
 	qCfg.NumConsumers = 1
 	rCfg := DefaultRetrySettings()
-	be := newBaseExporter(&defaultExporterCfg, zap.NewNop(), fromOptions(WithRetry(rCfg), WithQueue(qCfg)))
+	be := newBaseExporter(&defaultExporterCfg, config.TracesDataType, zap.NewNop(), fromOptions(WithRetry(rCfg), WithQueue(qCfg)))
 	ocs := newObservabilityConsumerSender(be.qrSender.consumerSender)
 	be.qrSender.consumerSender = ocs
 	require.NoError(t, be.Start(context.Background(), componenttest.NewNopHost()))
@@ -147,7 +250,7 @@ func TestQueuedRetry_DoNotPreserveCancellation(t *testing.T) {
 	qCfg := DefaultQueueSettings()
 	qCfg.NumConsumers = 1
 	rCfg := DefaultRetrySettings()
-	be := newBaseExporter(&defaultExporterCfg, zap.NewNop(), fromOptions(WithRetry(rCfg), WithQueue(qCfg)))
+	be := newBaseExporter(&defaultExporterCfg, config.TracesDataType, zap.NewNop(), fromOptions(WithRetry(rCfg), WithQueue(qCfg)))
 	ocs := newObservabilityConsumerSender(be.qrSender.consumerSender)
 	be.qrSender.consumerSender = ocs
 	require.NoError(t, be.Start(context.Background(), componenttest.NewNopHost()))
@@ -176,7 +279,7 @@ func TestQueuedRetry_MaxElapsedTime(t *testing.T) {
 	rCfg := DefaultRetrySettings()
 	rCfg.InitialInterval = time.Millisecond
 	rCfg.MaxElapsedTime = 100 * time.Millisecond
-	be := newBaseExporter(&defaultExporterCfg, zap.NewNop(), fromOptions(WithRetry(rCfg), WithQueue(qCfg)))
+	be := newBaseExporter(&defaultExporterCfg, config.TracesDataType, zap.NewNop(), fromOptions(WithRetry(rCfg), WithQueue(qCfg)))
 	ocs := newObservabilityConsumerSender(be.qrSender.consumerSender)
 	be.qrSender.consumerSender = ocs
 	require.NoError(t, be.Start(context.Background(), componenttest.NewNopHost()))
@@ -209,12 +312,93 @@ func TestQueuedRetry_MaxElapsedTime(t *testing.T) {
 	require.Zero(t, be.qrSender.queue.Size())
 }
 
+func TestQueuedRetry_MaxElapsedTimeBoundsAttempts(t *testing.T) {
+	rCfg := DefaultRetrySettings()
+	rCfg.InitialInterval = 10 * time.Millisecond
+	rCfg.MaxElapsedTime = 100 * time.Millisecond
+	be := newBaseExporter(&defaultExporterCfg, config.TracesDataType, zap.NewNop(),
+		fromOptions(WithRetry(rCfg), WithTimeout(TimeoutSettings{Timeout: 10 * time.Second})))
+	require.NoError(t, be.Start(context.Background(), componenttest.NewNopHost()))
+	t.Cleanup(func() {
+		assert.NoError(t, be.Shutdown(context.Background()))
+	})
+
+	// Every attempt would take the whole timeout, it is shortened to the time left.
+	slowR := &slowRequest{mockRequest{baseRequest: baseRequest{ctx: context.Background()}, cnt: 2, requestCount: new(int64)}}
+	start := time.Now()
+	err := be.sender.send(slowR)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "max elapsed time expired")
+	assert.Less(t, int64(time.Since(start)), int64(time.Second))
+	assert.NoError(t, slowR.context().Err())
+}
+
+func TestQueuedRetry_MaxElapsedTimeThrottleError(t *testing.T) {
+	rCfg := DefaultRetrySettings()
+	rCfg.MaxElapsedTime = 100 * time.Millisecond
+	be := newBaseExporter(&defaultExporterCfg, config.TracesDataType, zap.NewNop(), fromOptions(WithRetry(rCfg)))
+	require.NoError(t, be.Start(context.Background(), componenttest.NewNopHost()))
+	t.Cleanup(func() {
+		assert.NoError(t, be.Shutdown(context.Background()))
+	})
+
+	// The delay requested by the backend does not fit in the max elapsed time.
+	throttleErr := &ThrottleError{Err: errors.New("throttled"), RetryAfter: time.Hour}
+	mockR := newMockRequest(context.Background(), 2, throttleErr)
+	start := time.Now()
+	err := be.sender.send(mockR)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "max elapsed time expired")
+	assert.Less(t, int64(time.Since(start)), int64(time.Second))
+	mockR.checkNumRequests(t, 1)
+}
+
+func TestCreateSampledLogger_Interval(t *testing.T) {
+	core, logs := observer.New(zapcore.InfoLevel)
+	logger := createSampledLogger(zap.New(core), 50*time.Millisecond)
+
+	for i := 0; i < 5; i++ {
+		logger.Error("Exporting failed.", zap.Int("dropped_items", i))
+	}
+	logger.With(zap.String("kind", "other")).Warn("Exporting failed.")
+	logger.Info("Another message.")
+	entries := logs.TakeAll()
+	require.Len(t, entries, 3)
+	assert.Equal(t, map[string]interface{}{"dropped_items": int64(0)}, entries[0].ContextMap())
+	// The messages are identified by their level and message, regardless of their fields.
+	assert.Equal(t, zapcore.WarnLevel, entries[1].Level)
+	assert.Equal(t, "Another message.", entries[2].Message)
+
+	time.Sleep(60 * time.Millisecond)
+	logger.Error("Exporting failed.", zap.Int("dropped_items", 5))
+	logger.Error("Exporting failed.", zap.Int("dropped_items", 6))
+	entries = logs.TakeAll()
+	require.Len(t, entries, 1)
+	assert.Equal(t, map[string]interface{}{"suppressed": int64(4), "dropped_items": int64(5)}, entries[0].ContextMap())
+}
+
+func TestTracesExporter_WithLogSamplingInterval(t *testing.T) {
+	core, logs := observer.New(zapcore.InfoLevel)
+	te, err := NewTracesExporter(&defaultExporterCfg, zap.New(core), newTraceDataPusher(errors.New("backend is down")),
+		WithLogSamplingInterval(time.Hour))
+	require.NoError(t, err)
+	require.NoError(t, te.Start(context.Background(), componenttest.NewNopHost()))
+	t.Cleanup(func() {
+		assert.NoError(t, te.Shutdown(context.Background()))
+	})
+
+	for i := 0; i < 10; i++ {
+		assert.Error(t, te.ConsumeTraces(context.Background(), testdata.GenerateTracesOneSpan()))
+	}
+	assert.Equal(t, 1, logs.FilterMessage("Exporting failed. Try enabling retry_on_failure config option.").Len())
+}
+
 func TestQueuedRetry_ThrottleError(t *testing.T) {
 	qCfg := DefaultQueueSettings()
 	qCfg.NumConsumers = 1
 	rCfg := DefaultRetrySettings()
 	rCfg.InitialInterval = 10 * time.Millisecond
-	be := newBaseExporter(&defaultExporterCfg, zap.NewNop(), fromOptions(WithRetry(rCfg), WithQueue(qCfg)))
+	be := newBaseExporter(&defaultExporterCfg, config.TracesDataType, zap.NewNop(), fromOptions(WithRetry(rCfg), WithQueue(qCfg)))
 	ocs := newObservabilityConsumerSender(be.qrSender.consumerSender)
 	be.qrSender.consumerSender = ocs
 	require.NoError(t, be.Start(context.Background(), componenttest.NewNopHost()))
@@ -239,13 +423,112 @@ func TestQueuedRetry_ThrottleError(t *testing.T) {
 	require.Zero(t, be.qrSender.queue.Size())
 }
 
+func TestQueuedRetry_ThrottleErrorRetryAfter(t *testing.T) {
+	rCfg := DefaultRetrySettings()
+	// The backoff delay is ignored in favor of the delay requested by the backend.
+	rCfg.InitialInterval = 10 * time.Second
+	clock := newFakeClock()
+	be := newBaseExporter(&defaultExporterCfg, config.TracesDataType, zap.NewNop(), fromOptions(WithRetry(rCfg), WithClock(clock)))
+	require.NoError(t, be.Start(context.Background(), componenttest.NewNopHost()))
+	t.Cleanup(func() {
+		assert.NoError(t, be.Shutdown(context.Background()))
+	})
+
+	throttleErr := &ThrottleError{Err: errors.New("throttled"), RetryAfter: time.Minute}
+	mockR := newMockRequest(context.Background(), 2, fmt.Errorf("export: %w", throttleErr))
+	done := make(chan error, 1)
+	go func() {
+		done <- be.sender.send(mockR)
+	}()
+	// Wait for the backoff, in addition to the wait for the max elapsed time.
+	require.Eventually(t, func() bool { return clock.numWaiters() == 2 }, time.Second, time.Millisecond)
+
+	// The request is not retried before the delay requested by the backend.
+	clock.Advance(time.Minute - time.Second)
+	mockR.checkNumRequests(t, 1)
+	clock.Advance(time.Second)
+	require.NoError(t, <-done)
+	mockR.checkNumRequests(t, 2)
+}
+
+func TestQueuedRetry_ClockMaxElapsedTime(t *testing.T) {
+	rCfg := DefaultRetrySettings()
+	rCfg.InitialInterval = time.Minute
+	rCfg.MaxInterval = time.Minute
+	rCfg.MaxElapsedTime = 10 * time.Minute
+	clock := newFakeClock()
+	be := newBaseExporter(&defaultExporterCfg, config.TracesDataType, zap.NewNop(), fromOptions(WithRetry(rCfg), WithClock(clock)))
+	require.NoError(t, be.Start(context.Background(), componenttest.NewNopHost()))
+	t.Cleanup(func() {
+		assert.NoError(t, be.Shutdown(context.Background()))
+	})
+
+	done := make(chan error, 1)
+	go func() {
+		done <- be.sender.send(newErrorRequest(context.Background()))
+	}()
+
+	// Every delay is at most 1.5 minutes with the randomization of the backoff, so
+	// advancing the clock by 2 minutes always fires the pending wait. The wait for
+	// the max elapsed time is pending during the whole send.
+	advances := 0
+	for {
+		select {
+		case err := <-done:
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), "max elapsed time expired")
+			assert.GreaterOrEqual(t, advances, 1)
+			assert.LessOrEqual(t, advances, 5)
+			return
+		default:
+		}
+		if clock.numWaiters() > 1 {
+			clock.Advance(2 * time.Minute)
+			advances++
+		} else {
+			time.Sleep(time.Millisecond)
+		}
+	}
+}
+
+func TestQueuedRetry_ClockMaxElapsedTimeBoundsAttempts(t *testing.T) {
+	rCfg := DefaultRetrySettings()
+	rCfg.MaxElapsedTime = 10 * time.Minute
+	clock := newFakeClock()
+	be := newBaseExporter(&defaultExporterCfg, config.TracesDataType, zap.NewNop(), fromOptions(WithRetry(rCfg), WithClock(clock)))
+	require.NoError(t, be.Start(context.Background(), componenttest.NewNopHost()))
+	t.Cleanup(func() {
+		assert.NoError(t, be.Shutdown(context.Background()))
+	})
+
+	// The attempt only returns once the max elapsed time expires on the clock.
+	slowR := &slowRequest{mockRequest{baseRequest: baseRequest{ctx: context.Background()}, cnt: 2, requestCount: new(int64)}}
+	done := make(chan error, 1)
+	go func() {
+		done <- be.sender.send(slowR)
+	}()
+	require.Eventually(t, func() bool {
+		return atomic.LoadInt64(slowR.requestCount) == 1
+	}, time.Second, time.Millisecond)
+	clock.Advance(10 * time.Minute)
+
+	select {
+	case err := <-done:
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "max elapsed time expired")
+	case <-time.After(10 * time.Second):
+		t.Fatal("the send was not interrupted when the max elapsed time expired")
+	}
+	slowR.checkNumRequests(t, 1)
+}
+
 func TestQueuedRetry_RetryOnError(t *testing.T) {
 	qCfg := DefaultQueueSettings()
 	qCfg.NumConsumers = 1
 	qCfg.QueueSize = 1
 	rCfg := DefaultRetrySettings()
 	rCfg.InitialInterval = 0
-	be := newBaseExporter(&defaultExporterCfg, zap.NewNop(), fromOptions(WithRetry(rCfg), WithQueue(qCfg)))
+	be := newBaseExporter(&defaultExporterCfg, config.TracesDataType, zap.NewNop(), fromOptions(WithRetry(rCfg), WithQueue(qCfg)))
 	ocs := newObservabilityConsumerSender(be.qrSender.consumerSender)
 	be.qrSender.consumerSender = ocs
 	require.NoError(t, be.Start(context.Background(), componenttest.NewNopHost()))
@@ -267,11 +550,100 @@ func TestQueuedRetry_RetryOnError(t *testing.T) {
 	require.Zero(t, be.qrSender.queue.Size())
 }
 
+func TestQueuedRetry_RetryableStatusCodes(t *testing.T) {
+	tests := []struct {
+		name           string
+		retryableCodes []string
+		err            error
+		wantRetry      bool
+	}{
+		{
+			name:      "not set",
+			err:       status.Error(codes.InvalidArgument, "bad data"),
+			wantRetry: true,
+		},
+		{
+			name:           "wrapped non retryable code",
+			retryableCodes: []string{"UNAVAILABLE"},
+			err:            fmt.Errorf("export: %w", status.Error(codes.InvalidArgument, "bad data")),
+			wantRetry:      false,
+		},
+		{
+			name:           "no status",
+			retryableCodes: []string{"UNAVAILABLE"},
+			err:            errors.New("transient error"),
+			wantRetry:      true,
+		},
+		{
+			name:           "configured retryable code",
+			retryableCodes: []string{"INVALID_ARGUMENT"},
+			err:            status.Error(codes.InvalidArgument, "bad data"),
+			wantRetry:      true,
+		},
+		{
+			name:           "configured non retryable code",
+			retryableCodes: []string{"INVALID_ARGUMENT"},
+			err:            status.Error(codes.Unavailable, "unavailable"),
+			wantRetry:      false,
+		},
+		{
+			name:           "throttled",
+			retryableCodes: []string{},
+			err:            NewThrottleRetry(status.Error(codes.ResourceExhausted, "throttled"), time.Millisecond),
+			wantRetry:      true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			qCfg := DefaultQueueSettings()
+			qCfg.NumConsumers = 1
+			rCfg := DefaultRetrySettings()
+			rCfg.InitialInterval = 0
+			rCfg.RetryableStatusCodes = tt.retryableCodes
+			be := newBaseExporter(&defaultExporterCfg, config.TracesDataType, zap.NewNop(), fromOptions(WithRetry(rCfg), WithQueue(qCfg)))
+			ocs := newObservabilityConsumerSender(be.qrSender.consumerSender)
+			be.qrSender.consumerSender = ocs
+			require.NoError(t, be.Start(context.Background(), componenttest.NewNopHost()))
+			t.Cleanup(func() {
+				assert.NoError(t, be.Shutdown(context.Background()))
+			})
+
+			mockR := newMockRequest(context.Background(), 2, tt.err)
+			ocs.run(func() {
+				// This is asynchronous so it should just enqueue, no errors expected.
+				require.NoError(t, be.sender.send(mockR))
+			})
+			ocs.awaitAsyncProcessing()
+
+			if tt.wantRetry {
+				mockR.checkNumRequests(t, 2)
+				ocs.checkSendItemsCount(t, 2)
+				ocs.checkDroppedItemsCount(t, 0)
+			} else {
+				mockR.checkNumRequests(t, 1)
+				ocs.checkSendItemsCount(t, 0)
+				ocs.checkDroppedItemsCount(t, 2)
+			}
+		})
+	}
+}
+
+func TestRetrySettings_Validate(t *testing.T) {
+	rCfg := DefaultRetrySettings()
+	assert.NoError(t, rCfg.Validate())
+
+	rCfg.RetryableStatusCodes = []string{"UNAVAILABLE", "INVALID_ARGUMENT"}
+	assert.NoError(t, rCfg.Validate())
+
+	rCfg.RetryableStatusCodes = []string{"UNAVAILABLE", "unavailable"}
+	assert.EqualError(t, rCfg.Validate(), `unknown retryable status code "unavailable"`)
+}
+
 func TestQueuedRetry_DropOnFull(t *testing.T) {
 	qCfg := DefaultQueueSettings()
 	qCfg.QueueSize = 0
 	rCfg := DefaultRetrySettings()
-	be := newBaseExporter(&defaultExporterCfg, zap.NewNop(), fromOptions(WithRetry(rCfg), WithQueue(qCfg)))
+	be := newBaseExporter(&defaultExporterCfg, config.TracesDataType, zap.NewNop(), fromOptions(WithRetry(rCfg), WithQueue(qCfg)))
 	ocs := newObservabilityConsumerSender(be.qrSender.consumerSender)
 	be.qrSender.consumerSender = ocs
 	require.NoError(t, be.Start(context.Background(), componenttest.NewNopHost()))
@@ -282,6 +654,93 @@ func TestQueuedRetry_DropOnFull(t *testing.T) {
 	require.Error(t, err)
 }
 
+func TestQueuedRetry_QueueOverflow(t *testing.T) {
+	qCfg := DefaultQueueSettings()
+	qCfg.QueueSize = 0
+	rCfg := DefaultRetrySettings()
+	be := newBaseExporter(&defaultExporterCfg, config.TracesDataType, zap.NewNop(), fromOptions(WithRetry(rCfg), WithQueue(qCfg)))
+	var overflowed []request
+	overflowErr := errors.New("overflow error")
+	be.qrSender.overflow = func(req request) error {
+		if req.count() == 3 {
+			return overflowErr
+		}
+		overflowed = append(overflowed, req)
+		return nil
+	}
+	require.NoError(t, be.Start(context.Background(), componenttest.NewNopHost()))
+	t.Cleanup(func() {
+		assert.NoError(t, be.Shutdown(context.Background()))
+	})
+
+	req := newMockRequest(context.Background(), 2, nil)
+	require.NoError(t, be.sender.send(req))
+	require.NoError(t, be.sender.send(newMockRequest(context.Background(), 5, nil)))
+	assert.Equal(t, overflowErr, be.sender.send(newMockRequest(context.Background(), 3, nil)))
+
+	require.Len(t, overflowed, 2)
+	assert.Same(t, req, overflowed[0])
+	req.checkNumRequests(t, 0)
+	checkValueForProducer(t, defaultQueueTags, int64(7), "exporter/queue_overflowed_items")
+}
+
+func TestQueuedRetry_Flush(t *testing.T) {
+	qCfg := DefaultQueueSettings()
+	rCfg := DefaultRetrySettings()
+	rCfg.InitialInterval = 10 * time.Millisecond
+	be := newBaseExporter(&defaultExporterCfg, config.TracesDataType, zap.NewNop(), fromOptions(WithRetry(rCfg), WithQueue(qCfg)))
+	require.NoError(t, be.Start(context.Background(), componenttest.NewNopHost()))
+	t.Cleanup(func() {
+		assert.NoError(t, be.Shutdown(context.Background()))
+	})
+
+	// Flush returns immediately if the queue is empty.
+	require.NoError(t, be.Flush(context.Background()))
+
+	req := newMockRequest(context.Background(), 2, errors.New("transient error"))
+	require.NoError(t, be.sender.send(req))
+
+	// Flush waits for the retry of the request.
+	require.NoError(t, be.Flush(context.Background()))
+	assert.EqualValues(t, 2, atomic.LoadInt64(req.requestCount))
+	assert.Zero(t, be.qrSender.queue.Size())
+}
+
+func TestQueuedRetry_FlushContextDone(t *testing.T) {
+	qCfg := DefaultQueueSettings()
+	rCfg := DefaultRetrySettings()
+	be := newBaseExporter(&defaultExporterCfg, config.TracesDataType, zap.NewNop(), fromOptions(WithRetry(rCfg), WithQueue(qCfg)))
+	require.NoError(t, be.Start(context.Background(), componenttest.NewNopHost()))
+	t.Cleanup(func() {
+		assert.NoError(t, be.Shutdown(context.Background()))
+	})
+
+	// The request keeps failing, so it is still being retried when the context is done.
+	require.NoError(t, be.sender.send(newErrorRequest(context.Background())))
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	assert.Equal(t, context.DeadlineExceeded, be.Flush(ctx))
+}
+
+func TestQueuedRetry_ShutdownDrainsQueue(t *testing.T) {
+	qCfg := DefaultQueueSettings()
+	qCfg.NumConsumers = 1
+	rCfg := DefaultRetrySettings()
+	be := newBaseExporter(&defaultExporterCfg, config.TracesDataType, zap.NewNop(), fromOptions(WithRetry(rCfg), WithQueue(qCfg)))
+	require.NoError(t, be.Start(context.Background(), componenttest.NewNopHost()))
+
+	reqs := make([]*mockRequest, 100)
+	for i := range reqs {
+		reqs[i] = newMockRequest(context.Background(), 1, nil)
+		require.NoError(t, be.sender.send(reqs[i]))
+	}
+	// The queued requests are all sent before Shutdown returns.
+	require.NoError(t, be.Shutdown(context.Background()))
+	for _, req := range reqs {
+		assert.EqualValues(t, 1, atomic.LoadInt64(req.requestCount))
+	}
+}
+
 func TestQueuedRetryHappyPath(t *testing.T) {
 	doneFn, err := obsreporttest.SetupRecordedMetricsTest()
 	require.NoError(t, err)
@@ -289,7 +748,7 @@ func TestQueuedRetryHappyPath(t *testing.T) {
 
 	qCfg := DefaultQueueSettings()
 	rCfg := DefaultRetrySettings()
-	be := newBaseExporter(&defaultExporterCfg, zap.NewNop(), fromOptions(WithRetry(rCfg), WithQueue(qCfg)))
+	be := newBaseExporter(&defaultExporterCfg, config.TracesDataType, zap.NewNop(), fromOptions(WithRetry(rCfg), WithQueue(qCfg)))
 	ocs := newObservabilityConsumerSender(be.qrSender.consumerSender)
 	be.qrSender.consumerSender = ocs
 	require.NoError(t, be.Start(context.Background(), componenttest.NewNopHost()))
@@ -323,16 +782,54 @@ func TestQueuedRetry_QueueMetricsReported(t *testing.T) {
 	qCfg := DefaultQueueSettings()
 	qCfg.NumConsumers = 0 // to make every request go straight to the queue
 	rCfg := DefaultRetrySettings()
-	be := newBaseExporter(&defaultExporterCfg, zap.NewNop(), fromOptions(WithRetry(rCfg), WithQueue(qCfg)))
+	be := newBaseExporter(&defaultExporterCfg, config.TracesDataType, zap.NewNop(), fromOptions(WithRetry(rCfg), WithQueue(qCfg)))
 	require.NoError(t, be.Start(context.Background(), componenttest.NewNopHost()))
 
 	for i := 0; i < 7; i++ {
 		require.NoError(t, be.sender.send(newErrorRequest(context.Background())))
 	}
-	checkValueForProducer(t, defaultExporterTags, int64(7), "exporter/queue_size")
+	checkValueForProducer(t, defaultQueueTags, int64(7), "exporter/queue_size")
 
 	assert.NoError(t, be.Shutdown(context.Background()))
-	checkValueForProducer(t, defaultExporterTags, int64(0), "exporter/queue_size")
+	checkValueForProducer(t, defaultQueueTags, int64(0), "exporter/queue_size")
+}
+
+func TestQueuedRetry_PerSignalQueueSettings(t *testing.T) {
+	tracesCfg := DefaultQueueSettings()
+	tracesCfg.NumConsumers = 0 // to make every request go straight to the queue
+	tracesCfg.QueueSize = 2
+	metricsCfg := DefaultQueueSettings()
+	metricsCfg.NumConsumers = 0
+	metricsCfg.QueueSize = 10
+	rCfg := DefaultRetrySettings()
+
+	tracesBe := newBaseExporter(&defaultExporterCfg, config.TracesDataType, zap.NewNop(), fromOptions(WithRetry(rCfg), WithQueue(tracesCfg)))
+	metricsBe := newBaseExporter(&defaultExporterCfg, config.MetricsDataType, zap.NewNop(), fromOptions(WithRetry(rCfg), WithQueue(metricsCfg)))
+	require.NoError(t, tracesBe.Start(context.Background(), componenttest.NewNopHost()))
+	require.NoError(t, metricsBe.Start(context.Background(), componenttest.NewNopHost()))
+	t.Cleanup(func() {
+		assert.NoError(t, tracesBe.Shutdown(context.Background()))
+		assert.NoError(t, metricsBe.Shutdown(context.Background()))
+	})
+
+	for i := 0; i < 3; i++ {
+		err := tracesBe.sender.send(newErrorRequest(context.Background()))
+		if i < tracesCfg.QueueSize {
+			require.NoError(t, err)
+		} else {
+			require.Error(t, err)
+		}
+	}
+	for i := 0; i < 5; i++ {
+		require.NoError(t, metricsBe.sender.send(newErrorRequest(context.Background())))
+	}
+
+	// The queue metrics of the signals of the same exporter do not collide.
+	checkValueForProducer(t, defaultQueueTags, int64(2), "exporter/queue_size")
+	checkValueForProducer(t, []tag.Tag{
+		{Key: exporterTag, Value: "test"},
+		{Key: dataTypeTag, Value: "metrics"},
+	}, int64(5), "exporter/queue_size")
 }
 
 func TestNoCancellationContext(t *testing.T) {
@@ -367,6 +864,10 @@ func (mer *mockErrorRequest) count() int {
 	return 7
 }
 
+func (mer *mockErrorRequest) data() interface{} {
+	return nil
+}
+
 func newErrorRequest(ctx context.Context) request {
 	return &mockErrorRequest{
 		baseRequest: baseRequest{ctx: ctx},
@@ -413,6 +914,25 @@ func (m *mockRequest) count() int {
 	return m.cnt
 }
 
+func (m *mockRequest) data() interface{} {
+	return nil
+}
+
+// slowRequest is a request whose export only returns once its context is done.
+type slowRequest struct {
+	mockRequest
+}
+
+func (r *slowRequest) export(ctx context.Context) error {
+	atomic.AddInt64(r.requestCount, 1)
+	<-ctx.Done()
+	return ctx.Err()
+}
+
+func (r *slowRequest) onError(error) request {
+	return r
+}
+
 func newMockRequest(ctx context.Context, cnt int, consumeError error) *mockRequest {
 	return &mockRequest{
 		baseRequest:  baseRequest{ctx: ctx},
@@ -422,6 +942,57 @@ func newMockRequest(ctx context.Context, cnt int, consumeError error) *mockReque
 	}
 }
 
+type orderedExports struct {
+	mu   sync.Mutex
+	seqs map[uint64][]int
+}
+
+func (oe *orderedExports) add(key uint64, seq int) {
+	oe.mu.Lock()
+	defer oe.mu.Unlock()
+	oe.seqs[key] = append(oe.seqs[key], seq)
+}
+
+func (oe *orderedExports) get(key uint64) []int {
+	oe.mu.Lock()
+	defer oe.mu.Unlock()
+	return oe.seqs[key]
+}
+
+func (oe *orderedExports) len() int {
+	oe.mu.Lock()
+	defer oe.mu.Unlock()
+	total := 0
+	for _, seqs := range oe.seqs {
+		total += len(seqs)
+	}
+	return total
+}
+
+type mockOrderedRequest struct {
+	baseRequest
+	key      uint64
+	seq      int
+	exported *orderedExports
+}
+
+func (m *mockOrderedRequest) export(context.Context) error {
+	m.exported.add(m.key, m.seq)
+	return nil
+}
+
+func (m *mockOrderedRequest) onError(error) request {
+	return m
+}
+
+func (m *mockOrderedRequest) count() int {
+	return 1
+}
+
+func (m *mockOrderedRequest) data() interface{} {
+	return m.key
+}
+
 type observabilityConsumerSender struct {
 	waitGroup         *sync.WaitGroup
 	sentItemsCount    int64
@@ -467,13 +1038,14 @@ func checkValueForProducer(t *testing.T, wantTags []tag.Tag, value int64, vName
 	producers := metricproducer.GlobalManager().GetAll()
 	for _, producer := range producers {
 		for _, metric := range producer.Read() {
-			if metric.Descriptor.Name == vName && len(metric.TimeSeries) > 0 {
-				lastValue := metric.TimeSeries[len(metric.TimeSeries)-1]
-				if tagsMatchLabelKeys(wantTags, metric.Descriptor.LabelKeys, lastValue.LabelValues) {
-					require.Equal(t, value, lastValue.Points[len(lastValue.Points)-1].Value.(int64))
+			if metric.Descriptor.Name != vName {
+				continue
+			}
+			for _, ts := range metric.TimeSeries {
+				if tagsMatchLabelKeys(wantTags, metric.Descriptor.LabelKeys, ts.LabelValues) {
+					require.Equal(t, value, ts.Points[len(ts.Points)-1].Value.(int64))
 					return
 				}
-
 			}
 		}
 	}
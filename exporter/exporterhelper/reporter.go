@@ -0,0 +1,117 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package exporterhelper
+
+import (
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"go.opencensus.io/metric"
+	"go.opencensus.io/metric/metricdata"
+)
+
+// metricsReporter updates the cumulative metrics of an exporter, e.g. the number of
+// items dropped by its sampler. If the report interval is set, the increments of every
+// batch are only summed, and added to the metrics every interval and on shutdown.
+type metricsReporter struct {
+	interval time.Duration
+	counters []*counter
+	stopCh   chan struct{}
+	stopOnce sync.Once
+	stopWg   sync.WaitGroup
+}
+
+func newMetricsReporter(interval time.Duration) *metricsReporter {
+	return &metricsReporter{
+		interval: interval,
+		stopCh:   make(chan struct{}),
+	}
+}
+
+// counter returns the counter of the entry of m with the given label values. It must be
+// called before start.
+func (mr *metricsReporter) counter(m *metric.Int64Cumulative, labelValues ...metricdata.LabelValue) *counter {
+	c := &counter{metric: m, labelValues: labelValues, buffered: mr.interval > 0}
+	mr.counters = append(mr.counters, c)
+	return c
+}
+
+// start reports the counters every interval until shutdown, if the interval is set.
+func (mr *metricsReporter) start() {
+	if mr.interval <= 0 {
+		return
+	}
+	mr.stopWg.Add(1)
+	go func() {
+		defer mr.stopWg.Done()
+		ticker := time.NewTicker(mr.interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				mr.report()
+			case <-mr.stopCh:
+				return
+			}
+		}
+	}()
+}
+
+// shutdown stops the periodic reporting and reports the increments left. It can be
+// called more than once.
+func (mr *metricsReporter) shutdown() {
+	mr.stopOnce.Do(func() { close(mr.stopCh) })
+	mr.stopWg.Wait()
+	mr.report()
+}
+
+func (mr *metricsReporter) report() {
+	for _, c := range mr.counters {
+		c.report()
+	}
+}
+
+// counter increments an entry of a cumulative metric, right away or on the next report
+// if it is buffered. The entry is only created by the first increment, so no series is
+// reported for the exporters which never increment it.
+type counter struct {
+	// pending is the sum of the increments not reported yet, accessed atomically. It is
+	// the first field to be 64-bit aligned on 32-bit platforms.
+	pending     int64
+	metric      *metric.Int64Cumulative
+	labelValues []metricdata.LabelValue
+	buffered    bool
+}
+
+func (c *counter) add(n int64) {
+	if !c.buffered {
+		c.inc(n)
+		return
+	}
+	atomic.AddInt64(&c.pending, n)
+}
+
+func (c *counter) report() {
+	if n := atomic.SwapInt64(&c.pending, 0); n > 0 {
+		c.inc(n)
+	}
+}
+
+func (c *counter) inc(n int64) {
+	if entry, err := c.metric.GetEntry(c.labelValues...); err == nil {
+		entry.Inc(n)
+	}
+}
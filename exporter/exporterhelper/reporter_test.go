@@ -0,0 +1,110 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package exporterhelper
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opencensus.io/metric"
+	"go.opencensus.io/metric/metricdata"
+	"go.opencensus.io/tag"
+	"go.uber.org/zap"
+
+	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/config"
+	"go.opentelemetry.io/collector/consumer/consumertest"
+	"go.opentelemetry.io/collector/internal/testdata"
+)
+
+// readCounter returns the value of the only series of the metric of the registry, and
+// whether there is one.
+func readCounter(t *testing.T, registry *metric.Registry) (int64, bool) {
+	metrics := registry.Read()
+	require.Len(t, metrics, 1)
+	if len(metrics[0].TimeSeries) == 0 {
+		return 0, false
+	}
+	points := metrics[0].TimeSeries[0].Points
+	return points[len(points)-1].Value.(int64), true
+}
+
+func TestMetricsReporter_Unbuffered(t *testing.T) {
+	registry := metric.NewRegistry()
+	m, err := registry.AddInt64Cumulative("test/items", metric.WithLabelKeys("exporter"))
+	require.NoError(t, err)
+
+	mr := newMetricsReporter(0)
+	c := mr.counter(m, metricdata.NewLabelValue("test"))
+	mr.start()
+	_, ok := readCounter(t, registry)
+	assert.False(t, ok)
+
+	c.add(3)
+	c.add(4)
+	value, ok := readCounter(t, registry)
+	assert.True(t, ok)
+	assert.EqualValues(t, 7, value)
+	mr.shutdown()
+}
+
+func TestMetricsReporter_Buffered(t *testing.T) {
+	registry := metric.NewRegistry()
+	m, err := registry.AddInt64Cumulative("test/items", metric.WithLabelKeys("exporter"))
+	require.NoError(t, err)
+
+	mr := newMetricsReporter(10 * time.Millisecond)
+	c := mr.counter(m, metricdata.NewLabelValue("test"))
+	mr.start()
+	c.add(3)
+	c.add(4)
+	assert.Eventually(t, func() bool {
+		value, ok := readCounter(t, registry)
+		return ok && value == 7
+	}, time.Second, 5*time.Millisecond)
+
+	c.add(5)
+	mr.shutdown()
+	value, _ := readCounter(t, registry)
+	assert.EqualValues(t, 12, value)
+
+	// A second shutdown does not report the increments again.
+	mr.shutdown()
+	value, _ = readCounter(t, registry)
+	assert.EqualValues(t, 12, value)
+}
+
+func TestTracesExporter_WithMetricsReportInterval(t *testing.T) {
+	// Use a dedicated exporter name, since the other tests also sample traces.
+	cfg := config.NewExporterSettings(config.NewIDWithName(typeStr, "report_interval"))
+	sink := new(consumertest.TracesSink)
+	te, err := NewTracesExporter(&cfg, zap.NewNop(), sink.ConsumeTraces,
+		WithSampler(func(interface{}) bool { return false }),
+		WithMetricsReportInterval(time.Hour))
+	require.NoError(t, err)
+	require.NoError(t, te.Start(context.Background(), componenttest.NewNopHost()))
+
+	for i := 0; i < 3; i++ {
+		assert.NoError(t, te.ConsumeTraces(context.Background(), testdata.GenerateTracesTwoSpansSameResource()))
+	}
+	assert.Zero(t, sink.SpansCount())
+
+	// The dropped items are only reported on shutdown, before the interval.
+	require.NoError(t, te.Shutdown(context.Background()))
+	checkValueForProducer(t, []tag.Tag{{Key: exporterTag, Value: "test/report_interval"}}, int64(6), "exporter/sampler_dropped_items")
+}
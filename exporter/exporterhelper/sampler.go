@@ -0,0 +1,98 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package exporterhelper
+
+import (
+	"hash/fnv"
+
+	"go.opencensus.io/metric"
+	"go.opencensus.io/metric/metricdata"
+	"go.opencensus.io/trace"
+
+	"go.opentelemetry.io/collector/consumer/pdata"
+	"go.opentelemetry.io/collector/obsreport"
+)
+
+var samplerDroppedItems, _ = r.AddInt64Cumulative(
+	obsreport.ExporterKey+"/sampler_dropped_items",
+	metric.WithDescription("Number of items deliberately dropped by the sampler of the exporter"),
+	metric.WithLabelKeys(obsreport.ExporterKey),
+	metric.WithUnit(metricdata.UnitDimensionless))
+
+// samplingSender is a request sender that drops the requests not selected by the
+// sampler, and sends the others to the next sender.
+type samplingSender struct {
+	sampler         SamplerFunc
+	nextSender      requestSender
+	droppedItems    *counter
+	traceAttributes []trace.Attribute
+}
+
+func newSamplingSender(fullName string, sampler SamplerFunc, nextSender requestSender, droppedItems *counter) *samplingSender {
+	return &samplingSender{
+		sampler:         sampler,
+		nextSender:      nextSender,
+		droppedItems:    droppedItems,
+		traceAttributes: []trace.Attribute{trace.StringAttribute(obsreport.ExporterKey, fullName)},
+	}
+}
+
+// send implements the requestSender interface
+func (ss *samplingSender) send(req request) error {
+	if ss.sampler(req.data()) {
+		return ss.nextSender.send(req)
+	}
+	ss.droppedItems.add(int64(req.count()))
+	trace.FromContext(req.context()).Annotate(ss.traceAttributes, "Dropped item by the sampler.")
+	return nil
+}
+
+// NewTraceIDRatioSampler returns a SamplerFunc sending the given ratio, between 0 and 1,
+// of the traces requests. The decision is based on a hash of the trace ID of the first
+// span of the request, so the requests starting with the same trace are either all sent
+// or all dropped, on every collector instance. The traces are only kept coherent if the
+// requests contain whole traces, e.g. when they are grouped by trace before the exporter.
+// The requests without spans and the metrics and logs requests are always sent.
+func NewTraceIDRatioSampler(ratio float64) SamplerFunc {
+	threshold := ratio * (1 << 32)
+	return func(data interface{}) bool {
+		td, ok := data.(pdata.Traces)
+		if !ok {
+			return true
+		}
+		traceID, ok := firstTraceID(td)
+		if !ok {
+			return true
+		}
+		id := traceID.Bytes()
+		hash := fnv.New32a()
+		_, _ = hash.Write(id[:])
+		return float64(hash.Sum32()) < threshold
+	}
+}
+
+// firstTraceID returns the trace ID of the first span of td, if any.
+func firstTraceID(td pdata.Traces) (pdata.TraceID, bool) {
+	rss := td.ResourceSpans()
+	for i := 0; i < rss.Len(); i++ {
+		ilss := rss.At(i).InstrumentationLibrarySpans()
+		for j := 0; j < ilss.Len(); j++ {
+			if spans := ilss.At(j).Spans(); spans.Len() > 0 {
+				return spans.At(0).TraceID(), true
+			}
+		}
+	}
+	return pdata.TraceID{}, false
+}
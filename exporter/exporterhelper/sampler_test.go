@@ -0,0 +1,92 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package exporterhelper
+
+import (
+	"context"
+	"encoding/binary"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/consumer/consumertest"
+	"go.opentelemetry.io/collector/consumer/pdata"
+	"go.opentelemetry.io/collector/internal/testdata"
+)
+
+// newTracesWithTraceID returns a pdata.Traces with a single span of the given trace.
+func newTracesWithTraceID(n uint64) pdata.Traces {
+	var id [16]byte
+	binary.BigEndian.PutUint64(id[8:], n)
+	td := testdata.GenerateTracesOneSpan()
+	td.ResourceSpans().At(0).InstrumentationLibrarySpans().At(0).Spans().At(0).SetTraceID(pdata.NewTraceID(id))
+	return td
+}
+
+func TestTraceIDRatioSampler(t *testing.T) {
+	const numTraces = 10000
+	sampler := NewTraceIDRatioSampler(0.25)
+	smallerSampler := NewTraceIDRatioSampler(0.1)
+	sampled := 0
+	for i := uint64(0); i < numTraces; i++ {
+		td := newTracesWithTraceID(i)
+		decision := sampler(td)
+		if decision {
+			sampled++
+		}
+		// The decision only depends on the trace ID.
+		assert.Equal(t, decision, NewTraceIDRatioSampler(0.25)(newTracesWithTraceID(i)))
+		// The traces sampled with a smaller ratio are also sampled with a bigger one.
+		if smallerSampler(td) {
+			assert.True(t, decision)
+		}
+	}
+	assert.InDelta(t, 0.25, float64(sampled)/numTraces, 0.02)
+}
+
+func TestTraceIDRatioSampler_Bounds(t *testing.T) {
+	td := newTracesWithTraceID(42)
+	assert.True(t, NewTraceIDRatioSampler(1)(td))
+	assert.False(t, NewTraceIDRatioSampler(0)(td))
+
+	// The requests without spans and the other signals are always sent.
+	sampler := NewTraceIDRatioSampler(0)
+	assert.True(t, sampler(pdata.NewTraces()))
+	assert.True(t, sampler(testdata.GenerateTracesOneEmptyInstrumentationLibrary()))
+	assert.True(t, sampler(testdata.GenerateMetricsOneMetric()))
+	assert.True(t, sampler(testdata.GenerateLogsOneLogRecord()))
+}
+
+func TestTracesExporter_WithSampler(t *testing.T) {
+	sink := new(consumertest.TracesSink)
+	te, err := NewTracesExporter(&defaultExporterCfg, zap.NewNop(), sink.ConsumeTraces,
+		WithSampler(func(data interface{}) bool {
+			return data.(pdata.Traces).SpanCount() < 3
+		}))
+	require.NoError(t, err)
+	require.NoError(t, te.Start(context.Background(), componenttest.NewNopHost()))
+	t.Cleanup(func() {
+		assert.NoError(t, te.Shutdown(context.Background()))
+	})
+
+	assert.NoError(t, te.ConsumeTraces(context.Background(), testdata.GenerateTracesTwoSpansSameResource()))
+	// The dropped requests are not reported as failures.
+	assert.NoError(t, te.ConsumeTraces(context.Background(), testdata.GenerateTracesManySpansSameResource(5)))
+	assert.Equal(t, 2, sink.SpansCount())
+	checkValueForProducer(t, defaultExporterTags, int64(5), "exporter/sampler_dropped_items")
+}
@@ -59,6 +59,10 @@ func (req *tracesRequest) count() int {
 	return req.td.SpanCount()
 }
 
+func (req *tracesRequest) data() interface{} {
+	return req.td
+}
+
 type traceExporter struct {
 	*baseExporter
 	consumer.Traces
@@ -85,7 +89,19 @@ func NewTracesExporter(
 	}
 
 	bs := fromOptions(options...)
-	be := newBaseExporter(cfg, logger, bs)
+	if err := bs.RetrySettings.Validate(); err != nil {
+		return nil, err
+	}
+	be := newBaseExporter(cfg, config.TracesDataType, logger, bs)
+	if bs.queueOverflow != nil {
+		overflow, ok := bs.queueOverflow.(consumer.Traces)
+		if !ok {
+			return nil, errInvalidQueueOverflow
+		}
+		be.qrSender.overflow = func(req request) error {
+			return overflow.ConsumeTraces(req.context(), req.data().(pdata.Traces))
+		}
+	}
 	be.wrapConsumerSender(func(nextSender requestSender) requestSender {
 		return &tracesExporterWithObservability{
 			obsrep: obsreport.NewExporter(
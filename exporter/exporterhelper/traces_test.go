@@ -21,6 +21,7 @@ import (
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"go.opencensus.io/stats/view"
 	"go.opencensus.io/trace"
 	"go.uber.org/zap"
 
@@ -30,6 +31,7 @@ import (
 	"go.opentelemetry.io/collector/consumer"
 	"go.opentelemetry.io/collector/consumer/consumererror"
 	"go.opentelemetry.io/collector/consumer/consumerhelper"
+	"go.opentelemetry.io/collector/consumer/consumertest"
 	"go.opentelemetry.io/collector/consumer/pdata"
 	"go.opentelemetry.io/collector/internal/testdata"
 	"go.opentelemetry.io/collector/obsreport"
@@ -82,6 +84,14 @@ func TestTracesExporter_NilPushTraceData(t *testing.T) {
 	require.Equal(t, errNilPushTraceData, err)
 }
 
+func TestTracesExporter_InvalidRetrySettings(t *testing.T) {
+	rCfg := DefaultRetrySettings()
+	rCfg.RetryableStatusCodes = []string{"UNKNOWN_CODE"}
+	te, err := NewTracesExporter(&fakeTracesExporterConfig, zap.NewNop(), newTraceDataPusher(nil), WithRetry(rCfg))
+	require.Nil(t, te)
+	require.EqualError(t, err, `unknown retryable status code "UNKNOWN_CODE"`)
+}
+
 func TestTracesExporter_Default(t *testing.T) {
 	td := pdata.NewTraces()
 	te, err := NewTracesExporter(&fakeTracesExporterConfig, zap.NewNop(), newTraceDataPusher(nil))
@@ -94,6 +104,25 @@ func TestTracesExporter_Default(t *testing.T) {
 	assert.NoError(t, te.Shutdown(context.Background()))
 }
 
+func TestTracesExporter_Flush(t *testing.T) {
+	td := testdata.GenerateTracesTwoSpansSameResource()
+	sink := new(consumertest.TracesSink)
+	te, err := NewTracesExporter(&fakeTracesExporterConfig, zap.NewNop(), sink.ConsumeTraces, WithQueue(DefaultQueueSettings()))
+	require.NoError(t, err)
+	require.Implements(t, (*Flusher)(nil), te)
+
+	require.NoError(t, te.Start(context.Background(), componenttest.NewNopHost()))
+	assert.NoError(t, te.ConsumeTraces(context.Background(), td))
+	assert.NoError(t, te.(Flusher).Flush(context.Background()))
+	assert.Equal(t, 2, sink.SpansCount())
+	assert.NoError(t, te.Shutdown(context.Background()))
+
+	// Flush is a no-op when the queue is disabled.
+	te, err = NewTracesExporter(&fakeTracesExporterConfig, zap.NewNop(), newTraceDataPusher(nil))
+	require.NoError(t, err)
+	assert.NoError(t, te.(Flusher).Flush(context.Background()))
+}
+
 func TestTracesExporter_WithCapabilities(t *testing.T) {
 	capabilities := consumer.Capabilities{MutatesData: true}
 	te, err := NewTracesExporter(&fakeTracesExporterConfig, zap.NewNop(), newTraceDataPusher(nil), WithCapabilities(capabilities))
@@ -173,6 +202,47 @@ func TestTracesExporter_WithShutdown_ReturnError(t *testing.T) {
 	assert.Equal(t, te.Shutdown(context.Background()), want)
 }
 
+func TestTracesExporter_WithQueueOverflow(t *testing.T) {
+	qCfg := DefaultQueueSettings()
+	qCfg.QueueSize = 0
+	sink := new(consumertest.TracesSink)
+	te, err := NewTracesExporter(&fakeTracesExporterConfig, zap.NewNop(), newTraceDataPusher(nil), WithQueue(qCfg), WithQueueOverflow(sink))
+	require.NoError(t, err)
+	require.NoError(t, te.Start(context.Background(), componenttest.NewNopHost()))
+
+	td := testdata.GenerateTracesTwoSpansSameResource()
+	assert.NoError(t, te.ConsumeTraces(context.Background(), td))
+	assert.NoError(t, te.Shutdown(context.Background()))
+	assert.Equal(t, []pdata.Traces{td}, sink.AllTraces())
+
+	te, err = NewTracesExporter(&fakeTracesExporterConfig, zap.NewNop(), newTraceDataPusher(nil), WithQueueOverflow(new(consumertest.LogsSink)))
+	assert.Nil(t, te)
+	assert.Equal(t, errInvalidQueueOverflow, err)
+}
+
+func TestTracesExporter_RecordsOnlyTracesMetrics(t *testing.T) {
+	doneFn, err := obsreporttest.SetupRecordedMetricsTest()
+	require.NoError(t, err)
+	defer doneFn()
+
+	te, err := NewTracesExporter(&fakeTracesExporterConfig, zap.NewNop(), newTraceDataPusher(nil))
+	require.NoError(t, err)
+	require.NoError(t, te.ConsumeTraces(context.Background(), testdata.GenerateTracesTwoSpansSameResource()))
+
+	// The metrics of the other signals are not recorded, so no zero-valued series are exported.
+	for _, name := range []string{
+		"exporter/" + obsreport.SentMetricPointsKey,
+		"exporter/" + obsreport.FailedToSendMetricPointsKey,
+		"exporter/" + obsreport.SentLogRecordsKey,
+		"exporter/" + obsreport.FailedToSendLogRecordsKey,
+	} {
+		rows, err := view.RetrieveData(name)
+		require.NoError(t, err)
+		assert.Empty(t, rows, name)
+	}
+	obsreporttest.CheckExporterTraces(t, fakeTracesExporterName, 2, 0)
+}
+
 func newTraceDataPusher(retError error) consumerhelper.ConsumeTracesFunc {
 	return func(ctx context.Context, td pdata.Traces) error {
 		return retError
@@ -16,6 +16,8 @@ package fileexporter
 
 import (
 	"errors"
+	"fmt"
+	"time"
 
 	"go.opentelemetry.io/collector/config"
 )
@@ -26,8 +28,52 @@ type Config struct {
 
 	// Path of the file to write to. Path is relative to current directory.
 	Path string `mapstructure:"path"`
+
+	// Format of the written data, either "json" (default) to write one line of
+	// Protobuf-JSON per batch, or "protobuf" to write every batch as an OTLP
+	// Protobuf message prefixed by its varint encoded length.
+	Format string `mapstructure:"format"`
+
+	// Compression of the written file, either "none" (default) or "gzip". The gzip
+	// stream is only complete once the exporter is shut down.
+	Compression string `mapstructure:"compression"`
+
+	// FrameCompression of every frame written in the protobuf format, either "none"
+	// (default) or "zstd". Unlike Compression, the compressed frames can be read
+	// back before the exporter is shut down.
+	FrameCompression string `mapstructure:"frame_compression"`
+
+	// FlushEvery defines when the written data is flushed to the file. By default
+	// every batch is written to the file directly.
+	FlushEvery FlushSettings `mapstructure:"flush_every"`
+}
+
+// FlushSettings defines when the buffered data is flushed to the file, trading the
+// latency of the data in the file against the number of writes. The data is
+// flushed when any of the thresholds is reached, and when the exporter shuts down.
+// If no threshold is set the data is not buffered.
+type FlushSettings struct {
+	// Batches is the number of batches after which the data is flushed.
+	Batches int `mapstructure:"batches"`
+
+	// Interval is the period at which the data is flushed.
+	Interval time.Duration `mapstructure:"interval"`
+}
+
+// enabled returns whether the written data is buffered.
+func (fs FlushSettings) enabled() bool {
+	return fs.Batches > 0 || fs.Interval > 0
 }
 
+const (
+	formatJSON     = "json"
+	formatProtobuf = "protobuf"
+
+	compressionNone = "none"
+	compressionGzip = "gzip"
+	compressionZstd = "zstd"
+)
+
 var _ config.Exporter = (*Config)(nil)
 
 // Validate checks if the exporter configuration is valid
@@ -35,6 +81,24 @@ func (cfg *Config) Validate() error {
 	if cfg.Path == "" {
 		return errors.New("path must be non-empty")
 	}
+	if cfg.Format != formatJSON && cfg.Format != formatProtobuf {
+		return fmt.Errorf("format must be %q or %q, got %q", formatJSON, formatProtobuf, cfg.Format)
+	}
+	if cfg.Compression != compressionNone && cfg.Compression != compressionGzip {
+		return fmt.Errorf("compression must be %q or %q, got %q", compressionNone, compressionGzip, cfg.Compression)
+	}
+	if cfg.FrameCompression != compressionNone && cfg.FrameCompression != compressionZstd {
+		return fmt.Errorf("frame_compression must be %q or %q, got %q", compressionNone, compressionZstd, cfg.FrameCompression)
+	}
+	if cfg.FrameCompression == compressionZstd && cfg.Format != formatProtobuf {
+		return fmt.Errorf("frame_compression requires the %q format", formatProtobuf)
+	}
+	if cfg.FlushEvery.Batches < 0 {
+		return errors.New("flush_every batches must be non-negative")
+	}
+	if cfg.FlushEvery.Interval < 0 {
+		return errors.New("flush_every interval must be non-negative")
+	}
 
 	return nil
 }
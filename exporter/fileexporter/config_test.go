@@ -17,6 +17,7 @@ package fileexporter
 import (
 	"path"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -44,5 +45,89 @@ func TestLoadConfig(t *testing.T) {
 		&Config{
 			ExporterSettings: config.NewExporterSettings(config.NewIDWithName(typeStr, "2")),
 			Path:             "./filename.json",
+			Format:           formatJSON,
+			Compression:      compressionNone,
+			FrameCompression: compressionNone,
 		})
+
+	e2 := cfg.Exporters[config.NewIDWithName(typeStr, "3")]
+	assert.Equal(t, e2,
+		&Config{
+			ExporterSettings: config.NewExporterSettings(config.NewIDWithName(typeStr, "3")),
+			Path:             "./filename.pb",
+			Format:           formatProtobuf,
+			Compression:      compressionNone,
+			FrameCompression: compressionNone,
+		})
+
+	e3 := cfg.Exporters[config.NewIDWithName(typeStr, "4")]
+	assert.Equal(t, e3,
+		&Config{
+			ExporterSettings: config.NewExporterSettings(config.NewIDWithName(typeStr, "4")),
+			Path:             "./filename.json.gz",
+			Format:           formatJSON,
+			Compression:      compressionGzip,
+			FrameCompression: compressionNone,
+		})
+
+	e4 := cfg.Exporters[config.NewIDWithName(typeStr, "5")]
+	assert.Equal(t, e4,
+		&Config{
+			ExporterSettings: config.NewExporterSettings(config.NewIDWithName(typeStr, "5")),
+			Path:             "./filename.json",
+			Format:           formatJSON,
+			Compression:      compressionNone,
+			FrameCompression: compressionNone,
+			FlushEvery: FlushSettings{
+				Batches:  100,
+				Interval: 5 * time.Second,
+			},
+		})
+
+	e5 := cfg.Exporters[config.NewIDWithName(typeStr, "6")]
+	assert.Equal(t, e5,
+		&Config{
+			ExporterSettings: config.NewExporterSettings(config.NewIDWithName(typeStr, "6")),
+			Path:             "./filename.pb",
+			Format:           formatProtobuf,
+			Compression:      compressionNone,
+			FrameCompression: compressionZstd,
+		})
+}
+
+func TestConfigValidate(t *testing.T) {
+	cfg := createDefaultConfig().(*Config)
+	cfg.Path = "./filename.json"
+	assert.NoError(t, cfg.Validate())
+
+	cfg.Format = formatProtobuf
+	assert.NoError(t, cfg.Validate())
+
+	cfg.Format = "xml"
+	assert.EqualError(t, cfg.Validate(), `format must be "json" or "protobuf", got "xml"`)
+
+	cfg.Format = formatJSON
+	cfg.Compression = compressionGzip
+	assert.NoError(t, cfg.Validate())
+
+	cfg.Compression = "zstd"
+	assert.EqualError(t, cfg.Validate(), `compression must be "none" or "gzip", got "zstd"`)
+
+	cfg.Compression = compressionNone
+	cfg.FrameCompression = compressionZstd
+	assert.EqualError(t, cfg.Validate(), `frame_compression requires the "protobuf" format`)
+
+	cfg.Format = formatProtobuf
+	assert.NoError(t, cfg.Validate())
+
+	cfg.FrameCompression = compressionGzip
+	assert.EqualError(t, cfg.Validate(), `frame_compression must be "none" or "zstd", got "gzip"`)
+
+	cfg.FrameCompression = compressionNone
+	cfg.FlushEvery.Batches = -1
+	assert.EqualError(t, cfg.Validate(), "flush_every batches must be non-negative")
+
+	cfg.FlushEvery.Batches = 0
+	cfg.FlushEvery.Interval = -time.Second
+	assert.EqualError(t, cfg.Validate(), "flush_every interval must be non-negative")
 }
@@ -15,13 +15,19 @@
 package fileexporter
 
 import (
+	"bufio"
+	"compress/gzip"
 	"context"
+	"encoding/binary"
+	"fmt"
 	"io"
 	"os"
 	"sync"
+	"time"
 
 	"github.com/gogo/protobuf/jsonpb"
 	"github.com/gogo/protobuf/proto"
+	"github.com/klauspost/compress/zstd"
 
 	"go.opentelemetry.io/collector/component"
 	"go.opentelemetry.io/collector/consumer"
@@ -33,11 +39,39 @@ import (
 var marshaler = &jsonpb.Marshaler{}
 
 // fileExporter is the implementation of file exporter that writes telemetry data to a file
-// in Protobuf-JSON format.
+// in Protobuf-JSON format, or length-prefixed Protobuf format.
 type fileExporter struct {
-	path  string
-	file  io.WriteCloser
-	mutex sync.Mutex
+	path        string
+	format      string
+	compression string
+	// frameCompression of the frames written in the protobuf format.
+	frameCompression string
+	// frameEncoder compresses the frames, it is only set when frameCompression is zstd.
+	frameEncoder *zstd.Encoder
+	flushEvery   FlushSettings
+	file         io.WriteCloser
+	// buf buffers the data written to file, it is only set when flushEvery is enabled.
+	buf *bufio.Writer
+	// pendingBatches is the number of batches written to buf since the last flush.
+	pendingBatches int
+	mutex          sync.Mutex
+	// Used to stop the goroutine flushing at regular intervals.
+	stopCh chan struct{}
+	stopWg sync.WaitGroup
+	// shutdownOnce guards against closing stopCh and file more than once.
+	shutdownOnce sync.Once
+	shutdownErr  error
+}
+
+func newFileExporter(cfg *Config) *fileExporter {
+	return &fileExporter{
+		path:             cfg.Path,
+		format:           cfg.Format,
+		compression:      cfg.Compression,
+		frameCompression: cfg.FrameCompression,
+		flushEvery:       cfg.FlushEvery,
+		stopCh:           make(chan struct{}),
+	}
 }
 
 func (e *fileExporter) Capabilities() consumer.Capabilities {
@@ -45,37 +79,204 @@ func (e *fileExporter) Capabilities() consumer.Capabilities {
 }
 
 func (e *fileExporter) ConsumeTraces(_ context.Context, td pdata.Traces) error {
-	return exportMessageAsLine(e, internal.TracesToOtlp(td.InternalRep()))
+	return exportMessage(e, internal.TracesToOtlp(td.InternalRep()))
 }
 
 func (e *fileExporter) ConsumeMetrics(_ context.Context, md pdata.Metrics) error {
-	return exportMessageAsLine(e, internal.MetricsToOtlp(md.InternalRep()))
+	return exportMessage(e, internal.MetricsToOtlp(md.InternalRep()))
 }
 
 func (e *fileExporter) ConsumeLogs(_ context.Context, ld pdata.Logs) error {
-	return exportMessageAsLine(e, internal.LogsToOtlp(ld.InternalRep()))
+	return exportMessage(e, internal.LogsToOtlp(ld.InternalRep()))
 }
 
-func exportMessageAsLine(e *fileExporter, message proto.Message) error {
+func exportMessage(e *fileExporter, message proto.Message) error {
 	// Ensure only one write operation happens at a time.
 	e.mutex.Lock()
 	defer e.mutex.Unlock()
-	if err := marshaler.Marshal(e.file, message); err != nil {
+	if e.buf == nil {
+		return e.writeMessage(e.file, message)
+	}
+	if err := e.writeMessage(e.buf, message); err != nil {
 		return err
 	}
-	if _, err := io.WriteString(e.file, "\n"); err != nil {
+	e.pendingBatches++
+	if e.flushEvery.Batches > 0 && e.pendingBatches >= e.flushEvery.Batches {
+		return e.flush()
+	}
+	return nil
+}
+
+func (e *fileExporter) writeMessage(w io.Writer, message proto.Message) error {
+	if e.format == formatProtobuf {
+		return exportMessageAsFrame(w, message, e.frameEncoder)
+	}
+	return exportMessageAsLine(w, message)
+}
+
+// flush writes the buffered data to the file, including the data buffered by the
+// gzip compression. Must be called with the mutex held.
+func (e *fileExporter) flush() error {
+	e.pendingBatches = 0
+	if err := e.buf.Flush(); err != nil {
 		return err
 	}
+	if gf, ok := e.file.(*gzipFile); ok {
+		return gf.Flush()
+	}
 	return nil
 }
 
-func (e *fileExporter) Start(context.Context, component.Host) error {
-	var err error
-	e.file, err = os.OpenFile(e.path, os.O_RDWR|os.O_CREATE|os.O_TRUNC, 0600)
+// flushPeriodically flushes the buffered data every flushEvery.Interval.
+func (e *fileExporter) flushPeriodically() {
+	defer e.stopWg.Done()
+	ticker := time.NewTicker(e.flushEvery.Interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-e.stopCh:
+			return
+		case <-ticker.C:
+			e.mutex.Lock()
+			if e.pendingBatches > 0 {
+				// A failed flush is reported by the next export or by the shutdown.
+				_ = e.flush()
+			}
+			e.mutex.Unlock()
+		}
+	}
+}
+
+func exportMessageAsLine(w io.Writer, message proto.Message) error {
+	if err := marshaler.Marshal(w, message); err != nil {
+		return err
+	}
+	if _, err := io.WriteString(w, "\n"); err != nil {
+		return err
+	}
+	return nil
+}
+
+// frameCodecZstd is the first byte of the frames compressed with zstd, followed by the
+// compressed message. A Protobuf message cannot start with this byte, since it would
+// be the tag of the field number 0, so the reader can tell apart the compressed frames.
+const frameCodecZstd = 0x01
+
+// exportMessageAsFrame writes the message as a [varint length][bytes] frame,
+// which allows reading back the messages without any other delimiter. If encoder
+// is set, the bytes are the frameCodecZstd byte followed by the compressed message.
+func exportMessageAsFrame(w io.Writer, message proto.Message, encoder *zstd.Encoder) error {
+	buf, err := proto.Marshal(message)
+	if err != nil {
+		return err
+	}
+	if encoder != nil {
+		buf = encoder.EncodeAll(buf, []byte{frameCodecZstd})
+	}
+	frame := make([]byte, binary.MaxVarintLen64, binary.MaxVarintLen64+len(buf))
+	n := binary.PutUvarint(frame, uint64(len(buf)))
+	frame = append(frame[:n], buf...)
+	_, err = w.Write(frame)
 	return err
 }
 
+var (
+	// frameDecoder decompresses the frames read by ReadFrame. It is created on the
+	// first compressed frame and reused, since DecodeAll is safe for concurrent use.
+	frameDecoder     *zstd.Decoder
+	frameDecoderErr  error
+	frameDecoderOnce sync.Once
+)
+
+// ReadFrame reads into message the next frame of a file written in the protobuf
+// format, decompressing the frames written with the zstd frame compression. It
+// returns io.EOF once all the frames were read.
+func ReadFrame(r *bufio.Reader, message proto.Message) error {
+	size, err := binary.ReadUvarint(r)
+	if err != nil {
+		return err
+	}
+	buf := make([]byte, size)
+	if _, err = io.ReadFull(r, buf); err != nil {
+		return err
+	}
+	if len(buf) > 0 && buf[0] == frameCodecZstd {
+		frameDecoderOnce.Do(func() {
+			frameDecoder, frameDecoderErr = zstd.NewReader(nil)
+		})
+		if frameDecoderErr != nil {
+			return frameDecoderErr
+		}
+		if buf, err = frameDecoder.DecodeAll(buf[1:], nil); err != nil {
+			return fmt.Errorf("failed to decompress the frame: %w", err)
+		}
+	}
+	return proto.Unmarshal(buf, message)
+}
+
+func (e *fileExporter) Start(context.Context, component.Host) error {
+	file, err := os.OpenFile(e.path, os.O_RDWR|os.O_CREATE|os.O_TRUNC, 0600)
+	if err != nil {
+		return err
+	}
+	e.file = file
+	if e.frameCompression == compressionZstd {
+		if e.frameEncoder, err = zstd.NewWriter(nil); err != nil {
+			_ = file.Close()
+			return err
+		}
+	}
+	if e.compression == compressionGzip {
+		e.file = &gzipFile{Writer: gzip.NewWriter(file), file: file}
+	}
+	if e.flushEvery.enabled() {
+		e.buf = bufio.NewWriter(e.file)
+	}
+	if e.flushEvery.Interval > 0 {
+		e.stopWg.Add(1)
+		go e.flushPeriodically()
+	}
+	return nil
+}
+
+// gzipFile compresses the data written to file, and writes the end of the gzip
+// stream to the file when it is closed.
+type gzipFile struct {
+	*gzip.Writer
+	file *os.File
+}
+
+func (gf *gzipFile) Close() error {
+	if err := gf.Writer.Close(); err != nil {
+		_ = gf.file.Close()
+		return err
+	}
+	return gf.file.Close()
+}
+
 // Shutdown stops the exporter and is invoked during shutdown.
 func (e *fileExporter) Shutdown(context.Context) error {
+	e.shutdownOnce.Do(func() {
+		e.shutdownErr = e.shutdown()
+	})
+	return e.shutdownErr
+}
+
+func (e *fileExporter) shutdown() error {
+	if e.stopCh != nil {
+		close(e.stopCh)
+		e.stopWg.Wait()
+	}
+	if e.frameEncoder != nil {
+		defer e.frameEncoder.Close()
+	}
+	if e.buf != nil {
+		e.mutex.Lock()
+		defer e.mutex.Unlock()
+		if err := e.buf.Flush(); err != nil {
+			_ = e.file.Close()
+			return err
+		}
+	}
 	return e.file.Close()
 }
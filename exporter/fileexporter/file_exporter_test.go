@@ -14,11 +14,16 @@
 package fileexporter
 
 import (
+	"bufio"
 	"bytes"
+	"compress/gzip"
 	"context"
+	"encoding/binary"
+	"io"
 	"io/ioutil"
 	"os"
 	"testing"
+	"time"
 
 	"github.com/gogo/protobuf/jsonpb"
 	"github.com/stretchr/testify/assert"
@@ -123,6 +128,187 @@ func TestFileLogsExporterErrors(t *testing.T) {
 	assert.NoError(t, fe.Shutdown(context.Background()))
 }
 
+func TestFileExporterProtobufFormat(t *testing.T) {
+	fe := newFileExporter(&Config{Path: tempFileName(t), Format: formatProtobuf})
+	require.NotNil(t, fe)
+
+	td := testdata.GenerateTracesTwoSpansSameResource()
+	md := testdata.GenerateMetricsTwoMetrics()
+	ld := testdata.GenerateLogsTwoLogRecordsSameResource()
+	assert.NoError(t, fe.Start(context.Background(), componenttest.NewNopHost()))
+	assert.NoError(t, fe.ConsumeTraces(context.Background(), td))
+	assert.NoError(t, fe.ConsumeMetrics(context.Background(), md))
+	assert.NoError(t, fe.ConsumeLogs(context.Background(), ld))
+	assert.NoError(t, fe.Shutdown(context.Background()))
+
+	f, err := os.Open(fe.path)
+	require.NoError(t, err)
+	defer f.Close()
+	r := bufio.NewReader(f)
+
+	gotTraces := &collectortrace.ExportTraceServiceRequest{}
+	require.NoError(t, ReadFrame(r, gotTraces))
+	assert.EqualValues(t, internal.TracesToOtlp(td.InternalRep()), gotTraces)
+
+	gotMetrics := &collectormetrics.ExportMetricsServiceRequest{}
+	require.NoError(t, ReadFrame(r, gotMetrics))
+	assert.EqualValues(t, internal.MetricsToOtlp(md.InternalRep()), gotMetrics)
+
+	gotLogs := &collectorlogs.ExportLogsServiceRequest{}
+	require.NoError(t, ReadFrame(r, gotLogs))
+	assert.EqualValues(t, internal.LogsToOtlp(ld.InternalRep()), gotLogs)
+
+	_, err = r.ReadByte()
+	assert.Equal(t, io.EOF, err)
+}
+
+func TestFileExporterFrameCompression(t *testing.T) {
+	fe := newFileExporter(&Config{Path: tempFileName(t), Format: formatProtobuf, FrameCompression: compressionZstd, FlushEvery: FlushSettings{Batches: 1}})
+	require.NotNil(t, fe)
+
+	td := testdata.GenerateTracesManySpansSameResource(100)
+	md := testdata.GenerateMetricsTwoMetrics()
+	assert.NoError(t, fe.Start(context.Background(), componenttest.NewNopHost()))
+	assert.NoError(t, fe.ConsumeTraces(context.Background(), td))
+	assert.NoError(t, fe.ConsumeMetrics(context.Background(), md))
+
+	f, err := os.Open(fe.path)
+	require.NoError(t, err)
+	defer f.Close()
+	r := bufio.NewReader(f)
+
+	// The frames are compressed, and can be read back before the shutdown.
+	otlpTraces := internal.TracesToOtlp(td.InternalRep())
+	header, err := r.Peek(binary.MaxVarintLen64 + 1)
+	require.NoError(t, err)
+	size, n := binary.Uvarint(header)
+	assert.Less(t, int(size), otlpTraces.Size())
+	assert.EqualValues(t, frameCodecZstd, header[n])
+
+	gotTraces := &collectortrace.ExportTraceServiceRequest{}
+	require.NoError(t, ReadFrame(r, gotTraces))
+	assert.EqualValues(t, otlpTraces, gotTraces)
+
+	gotMetrics := &collectormetrics.ExportMetricsServiceRequest{}
+	require.NoError(t, ReadFrame(r, gotMetrics))
+	assert.EqualValues(t, internal.MetricsToOtlp(md.InternalRep()), gotMetrics)
+
+	assert.NoError(t, fe.Shutdown(context.Background()))
+	_, err = r.ReadByte()
+	assert.Equal(t, io.EOF, err)
+}
+
+func TestReadFrameCorrupted(t *testing.T) {
+	frame := append([]byte{9, frameCodecZstd}, "not zstd"...)
+	err := ReadFrame(bufio.NewReader(bytes.NewReader(frame)), &collectortrace.ExportTraceServiceRequest{})
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "failed to decompress the frame")
+}
+
+func TestFileExporterProtobufFormatError(t *testing.T) {
+	mf := &testutil.LimitedWriter{
+		MaxLen: 42,
+	}
+	fe := &fileExporter{file: mf, format: formatProtobuf}
+
+	// Cannot call Start since we inject directly the WriterCloser.
+	assert.Error(t, fe.ConsumeTraces(context.Background(), testdata.GenerateTracesTwoSpansSameResource()))
+	assert.NoError(t, fe.Shutdown(context.Background()))
+}
+
+func TestFileExporterGzipCompression(t *testing.T) {
+	fe := newFileExporter(&Config{Path: tempFileName(t), Format: formatJSON, Compression: compressionGzip})
+	require.NotNil(t, fe)
+
+	td := testdata.GenerateTracesTwoSpansSameResource()
+	assert.NoError(t, fe.Start(context.Background(), componenttest.NewNopHost()))
+	assert.NoError(t, fe.ConsumeTraces(context.Background(), td))
+	assert.NoError(t, fe.ConsumeTraces(context.Background(), td))
+	assert.NoError(t, fe.Shutdown(context.Background()))
+
+	f, err := os.Open(fe.path)
+	require.NoError(t, err)
+	defer f.Close()
+	gr, err := gzip.NewReader(f)
+	require.NoError(t, err)
+	scanner := bufio.NewScanner(gr)
+	var unmarshaler = &jsonpb.Unmarshaler{}
+	for i := 0; i < 2; i++ {
+		require.True(t, scanner.Scan())
+		got := &collectortrace.ExportTraceServiceRequest{}
+		assert.NoError(t, unmarshaler.Unmarshal(bytes.NewReader(scanner.Bytes()), got))
+		assert.EqualValues(t, internal.TracesToOtlp(td.InternalRep()), got)
+	}
+	assert.False(t, scanner.Scan())
+	assert.NoError(t, scanner.Err())
+}
+
+func TestFileExporterFlushEveryBatches(t *testing.T) {
+	fe := newFileExporter(&Config{Path: tempFileName(t), Format: formatJSON, FlushEvery: FlushSettings{Batches: 2}})
+	require.NotNil(t, fe)
+
+	td := testdata.GenerateTracesTwoSpansSameResource()
+	assert.NoError(t, fe.Start(context.Background(), componenttest.NewNopHost()))
+	assert.NoError(t, fe.ConsumeTraces(context.Background(), td))
+	assert.Equal(t, 0, countLines(t, fe.path))
+	assert.NoError(t, fe.ConsumeTraces(context.Background(), td))
+	assert.Equal(t, 2, countLines(t, fe.path))
+
+	// The remaining data is flushed on shutdown.
+	assert.NoError(t, fe.ConsumeTraces(context.Background(), td))
+	assert.Equal(t, 2, countLines(t, fe.path))
+	assert.NoError(t, fe.Shutdown(context.Background()))
+	assert.Equal(t, 3, countLines(t, fe.path))
+}
+
+func TestFileExporterFlushEveryInterval(t *testing.T) {
+	fe := newFileExporter(&Config{Path: tempFileName(t), Format: formatJSON, FlushEvery: FlushSettings{Interval: 10 * time.Millisecond}})
+	require.NotNil(t, fe)
+
+	assert.NoError(t, fe.Start(context.Background(), componenttest.NewNopHost()))
+	t.Cleanup(func() {
+		assert.NoError(t, fe.Shutdown(context.Background()))
+	})
+	assert.NoError(t, fe.ConsumeTraces(context.Background(), testdata.GenerateTracesTwoSpansSameResource()))
+	assert.Eventually(t, func() bool {
+		return countLines(t, fe.path) == 1
+	}, 10*time.Second, 5*time.Millisecond)
+}
+
+func TestFileExporterShutdownTwice(t *testing.T) {
+	fe := newFileExporter(&Config{Path: tempFileName(t), Format: formatJSON, FlushEvery: FlushSettings{Interval: time.Minute}})
+	require.NotNil(t, fe)
+
+	assert.NoError(t, fe.Start(context.Background(), componenttest.NewNopHost()))
+	assert.NoError(t, fe.Shutdown(context.Background()))
+	assert.NoError(t, fe.Shutdown(context.Background()))
+}
+
+func TestFileExporterFlushEveryGzip(t *testing.T) {
+	fe := newFileExporter(&Config{Path: tempFileName(t), Format: formatJSON, Compression: compressionGzip, FlushEvery: FlushSettings{Batches: 1}})
+	require.NotNil(t, fe)
+
+	assert.NoError(t, fe.Start(context.Background(), componenttest.NewNopHost()))
+	assert.NoError(t, fe.ConsumeTraces(context.Background(), testdata.GenerateTracesTwoSpansSameResource()))
+
+	// The flushed data can be decompressed before the gzip stream is complete.
+	f, err := os.Open(fe.path)
+	require.NoError(t, err)
+	defer f.Close()
+	gr, err := gzip.NewReader(f)
+	require.NoError(t, err)
+	scanner := bufio.NewScanner(gr)
+	assert.True(t, scanner.Scan())
+	assert.NoError(t, fe.Shutdown(context.Background()))
+}
+
+// countLines returns the number of lines written to the file at path.
+func countLines(t *testing.T, path string) int {
+	buf, err := ioutil.ReadFile(path)
+	require.NoError(t, err)
+	return bytes.Count(buf, []byte("\n"))
+}
+
 // tempFileName provides a temporary file name for testing.
 func tempFileName(t *testing.T) string {
 	tmpfile, err := ioutil.TempFile("", "*.json")
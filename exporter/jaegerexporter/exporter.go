@@ -126,7 +126,7 @@ func (s *protoGRPCSender) shutdown(context.Context) error {
 	return nil
 }
 
-func (s *protoGRPCSender) start(_ context.Context, host component.Host) error {
+func (s *protoGRPCSender) start(ctx context.Context, host component.Host) error {
 	if s.clientSettings == nil {
 		return fmt.Errorf("client settings not found")
 	}
@@ -135,7 +135,7 @@ func (s *protoGRPCSender) start(_ context.Context, host component.Host) error {
 		return err
 	}
 
-	conn, err := grpc.Dial(s.clientSettings.Endpoint, opts...)
+	conn, err := s.clientSettings.DialContext(ctx, s.clientSettings.Endpoint, opts...)
 	if err != nil {
 		return err
 	}
@@ -0,0 +1,163 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package loggingexporter
+
+import (
+	"hash/fnv"
+	"math"
+	"math/bits"
+	"sort"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+
+	"go.opentelemetry.io/collector/consumer/pdata"
+	"go.opentelemetry.io/collector/internal/metricskey"
+)
+
+// cardinalityTracker estimates the number of distinct series of every metric name
+// over a window, and logs a warning for the metrics above the threshold when the
+// window ends. The window ends on the first batch received after its end.
+type cardinalityTracker struct {
+	logger    *zap.Logger
+	threshold int
+	window    time.Duration
+	now       func() time.Time
+
+	mu          sync.Mutex
+	windowStart time.Time
+	series      map[string]*hyperLogLog
+}
+
+func newCardinalityTracker(settings CardinalitySettings, logger *zap.Logger) *cardinalityTracker {
+	return &cardinalityTracker{
+		logger:    logger,
+		threshold: settings.Threshold,
+		window:    settings.Window,
+		now:       time.Now,
+		series:    make(map[string]*hyperLogLog),
+	}
+}
+
+// observe counts the series of md, after reporting the previous window if it ended.
+func (ct *cardinalityTracker) observe(md pdata.Metrics) {
+	ct.mu.Lock()
+	defer ct.mu.Unlock()
+
+	now := ct.now()
+	if ct.windowStart.IsZero() {
+		ct.windowStart = now
+	} else if now.Sub(ct.windowStart) >= ct.window {
+		ct.report()
+		ct.series = make(map[string]*hyperLogLog)
+		ct.windowStart = now
+	}
+
+	rms := md.ResourceMetrics()
+	for i := 0; i < rms.Len(); i++ {
+		rm := rms.At(i)
+		resKey := metricskey.Resource(rm.Resource())
+		ilms := rm.InstrumentationLibraryMetrics()
+		for j := 0; j < ilms.Len(); j++ {
+			metrics := ilms.At(j).Metrics()
+			for k := 0; k < metrics.Len(); k++ {
+				m := metrics.At(k)
+				hll, ok := ct.series[m.Name()]
+				if !ok {
+					hll = &hyperLogLog{}
+					ct.series[m.Name()] = hll
+				}
+				for _, labels := range m.DataPointLabels() {
+					hll.add(seriesHash(metricskey.Series(resKey, labels)))
+				}
+			}
+		}
+	}
+}
+
+// report logs a warning for every metric with more series than the threshold.
+func (ct *cardinalityTracker) report() {
+	names := make([]string, 0, len(ct.series))
+	for name := range ct.series {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if estimate := ct.series[name].estimate(); estimate > uint64(ct.threshold) {
+			ct.logger.Warn("Metric cardinality exceeds threshold",
+				zap.String("metric", name),
+				zap.Uint64("estimated_series", estimate),
+				zap.Int("threshold", ct.threshold),
+				zap.Duration("window", ct.window))
+		}
+	}
+}
+
+// seriesHash returns the hash of the series identified by the given key.
+func seriesHash(key string) uint64 {
+	h := fnv.New64a()
+	h.Write([]byte(key)) // nolint: errcheck
+	return h.Sum64()
+}
+
+// hllPrecision is the number of bits of the hashes selecting the register of a
+// hyperLogLog. 2^10 registers use 1 KiB of memory per metric name for a standard
+// error of 1.04/sqrt(2^10), about 3%.
+const hllPrecision = 10
+
+// hyperLogLog estimates the number of distinct hashes added to it, see
+// http://algo.inria.fr/flajolet/Publications/FlFuGaMe07.pdf.
+type hyperLogLog [1 << hllPrecision]uint8
+
+func (h *hyperLogLog) add(hash uint64) {
+	// FNV hashes are not evenly distributed enough in their high bits, mix them.
+	hash = mix64(hash)
+	idx := hash >> (64 - hllPrecision)
+	// The bit set after the remaining bits bounds the rank if they are all zeros.
+	rank := uint8(bits.LeadingZeros64(hash<<hllPrecision|1<<(hllPrecision-1))) + 1
+	if rank > h[idx] {
+		h[idx] = rank
+	}
+}
+
+func (h *hyperLogLog) estimate() uint64 {
+	m := float64(len(h))
+	var sum float64
+	var zeros int
+	for _, rank := range h {
+		sum += math.Ldexp(1, -int(rank))
+		if rank == 0 {
+			zeros++
+		}
+	}
+	estimate := 0.7213 / (1 + 1.079/m) * m * m / sum
+	// Small cardinalities are more accurately estimated by linear counting.
+	if estimate <= 2.5*m && zeros > 0 {
+		estimate = m * math.Log(m/float64(zeros))
+	}
+	return uint64(estimate + 0.5)
+}
+
+// mix64 is the finalizer of MurmurHash3, which spreads the entropy of every bit of
+// the input to all the bits of the output.
+func mix64(h uint64) uint64 {
+	h ^= h >> 33
+	h *= 0xff51afd7ed558ccd
+	h ^= h >> 33
+	h *= 0xc4ceb9fe1a85ec53
+	h ^= h >> 33
+	return h
+}
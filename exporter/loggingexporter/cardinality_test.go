@@ -0,0 +1,87 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package loggingexporter
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+
+	"go.opentelemetry.io/collector/consumer/pdata"
+	"go.opentelemetry.io/collector/consumer/pdatabuilder"
+)
+
+func TestHyperLogLogEstimate(t *testing.T) {
+	for _, n := range []int{0, 1, 10, 1000, 100000} {
+		t.Run(fmt.Sprint(n), func(t *testing.T) {
+			hll := &hyperLogLog{}
+			for i := 0; i < n; i++ {
+				// Every value is added twice, duplicates are not counted.
+				hll.add(uint64(i))
+				hll.add(uint64(i))
+			}
+			// The standard error is about 3%.
+			assert.InEpsilon(t, n+1, hll.estimate()+1, 0.1)
+		})
+	}
+}
+
+// seriesMetrics returns a gauge with a data point for every label value, for every
+// given resource.
+func seriesMetrics(name string, resources []string, labelValues int) pdata.Metrics {
+	b := pdatabuilder.NewMetrics()
+	for _, res := range resources {
+		b.Resource(pdatabuilder.Attrs{"host.name": res}).DoubleGauge(name)
+		for i := 0; i < labelValues; i++ {
+			b.DoubleDataPoint(1, map[string]string{"id": fmt.Sprint(i)})
+		}
+	}
+	return b.Build()
+}
+
+func TestCardinalityTracker(t *testing.T) {
+	core, logs := observer.New(zapcore.WarnLevel)
+	ct := newCardinalityTracker(CardinalitySettings{Threshold: 10, Window: time.Minute}, zap.New(core))
+	now := time.Date(2021, 6, 1, 12, 0, 0, 0, time.UTC)
+	ct.now = func() time.Time { return now }
+
+	ct.observe(seriesMetrics("requests", []string{"a", "b"}, 20))
+	ct.observe(seriesMetrics("requests", []string{"a"}, 20))
+	ct.observe(seriesMetrics("cpu", []string{"a", "b", "c"}, 1))
+	// Nothing is reported before the end of the window.
+	assert.Equal(t, 0, logs.Len())
+
+	now = now.Add(time.Minute)
+	ct.observe(seriesMetrics("cpu", []string{"a"}, 1))
+	entries := logs.TakeAll()
+	require.Len(t, entries, 1)
+	assert.Equal(t, "Metric cardinality exceeds threshold", entries[0].Message)
+	fields := entries[0].ContextMap()
+	assert.Equal(t, "requests", fields["metric"])
+	// The resources are part of the series, the same labels are not counted twice.
+	assert.InDelta(t, 40, fields["estimated_series"], 2)
+	assert.Equal(t, int64(10), fields["threshold"])
+
+	// The series of the previous window are forgotten.
+	now = now.Add(time.Minute)
+	ct.observe(seriesMetrics("requests", []string{"a"}, 1))
+	assert.Equal(t, 0, logs.Len())
+}
@@ -15,7 +15,15 @@
 package loggingexporter
 
 import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"go.uber.org/zap/zapcore"
+
 	"go.opentelemetry.io/collector/config"
+	"go.opentelemetry.io/collector/internal/otlptext"
 )
 
 // Config defines configuration for logging exporter.
@@ -30,11 +38,266 @@ type Config struct {
 
 	// SamplingThereafter defines the sampling rate after the initial samples are logged.
 	SamplingThereafter int `mapstructure:"sampling_thereafter"`
+
+	// WarnBatchSize defines, per signal, the batch size above which a warning is logged.
+	WarnBatchSize WarnBatchSizeSettings `mapstructure:"warn_batch_size"`
+
+	// SampleRatio defines the ratio, between 0 and 1, of traces rendered when the
+	// LogLevel is debug. Traces are selected based on a hash of the trace ID so all
+	// the spans of a trace are rendered or skipped together.
+	SampleRatio float64 `mapstructure:"sample_ratio"`
+
+	// TraceIDs defines the IDs, as 32 hex digits, of the traces rendered when the LogLevel
+	// is debug, e.g. to debug a known problematic request. Empty means all the traces.
+	TraceIDs []string `mapstructure:"trace_ids"`
+
+	// LogDataPointCount defines whether the number of data points is logged along
+	// with the number of metrics, since a single metric can carry many data points.
+	LogDataPointCount bool `mapstructure:"log_data_point_count"`
+
+	// FlattenAttributes defines whether the attributes are rendered, when the LogLevel
+	// is debug, as a flat list of dotted keys with nested maps and arrays expanded.
+	FlattenAttributes bool `mapstructure:"flatten_attributes"`
+
+	// SpanKinds defines the kinds of the spans rendered when the LogLevel is debug; options
+	// are unspecified, internal, server, client, producer and consumer. Empty means all kinds.
+	SpanKinds []string `mapstructure:"span_kinds"`
+
+	// DebugOnError defines whether, when the LogLevel is not debug, the resource spans
+	// containing at least one span with an error status are rendered at info level.
+	DebugOnError bool `mapstructure:"debug_on_error"`
+
+	// RenderAttributeKeys defines the keys of the span attributes rendered when the LogLevel
+	// is debug, the number of omitted attributes is rendered instead. Empty means all keys.
+	RenderAttributeKeys []string `mapstructure:"render_attribute_keys"`
+
+	// FilterAllAttributes defines whether the RenderAttributeKeys also apply to the
+	// resource and log record attributes.
+	FilterAllAttributes bool `mapstructure:"filter_all_attributes"`
+
+	// SpanTree defines whether, when the LogLevel is debug, the spans of every trace are
+	// rendered as a tree using the parent span IDs instead of a flat list.
+	SpanTree bool `mapstructure:"span_tree"`
+
+	// CompactSpans defines whether, when the LogLevel is debug, every span is rendered on a
+	// single line with its service, name, kind, duration, status and IDs, like an access
+	// log. It takes precedence over SpanTree.
+	CompactSpans bool `mapstructure:"compact_spans"`
+
+	// GroupAttributes defines whether, when the LogLevel is debug, the span attributes are
+	// rendered grouped by namespace, the part of their key before the first dot.
+	GroupAttributes bool `mapstructure:"group_attributes"`
+
+	// HoistCommonLabels defines whether, when the LogLevel is debug, the labels shared by
+	// all the data points of a metric are rendered once before its data points.
+	HoistCommonLabels bool `mapstructure:"hoist_common_labels"`
+
+	// MaxArrayElements defines the number of elements of the array values rendered when
+	// the LogLevel is debug, followed by the number of omitted elements. Zero renders
+	// all the elements.
+	MaxArrayElements int `mapstructure:"max_array_elements"`
+
+	// MaxEventsPerSpan defines the number of events of every span rendered when the
+	// LogLevel is debug, followed by the number of omitted events. Zero renders all
+	// the events.
+	MaxEventsPerSpan int `mapstructure:"max_events_per_span"`
+
+	// EventNameFilter defines the names of the span events rendered when the LogLevel
+	// is debug. A name ending with "*" matches the names with that prefix. Empty means
+	// all the events.
+	EventNameFilter []string `mapstructure:"event_name_filter"`
+
+	// ExcludeAttributes defines the keys and values of the attributes of the spans and
+	// the log records which are not rendered when the LogLevel is debug, e.g. to skip
+	// the health checks. A value ending with "*" matches the values with that prefix.
+	ExcludeAttributes map[string]string `mapstructure:"exclude_attributes"`
+
+	// Sanitization defines how the non-printable characters and the invalid UTF-8 bytes
+	// of the rendered text are handled; options are escape, strip and none.
+	Sanitization string `mapstructure:"sanitization"`
+
+	// MinSeverity defines the minimum severity of the log records rendered when the LogLevel
+	// is debug, e.g. WARN or ERROR2. Empty means all the log records are rendered.
+	MinSeverity string `mapstructure:"min_severity"`
+
+	// GroupByResourceAttribute defines the key of a resource attribute, e.g. service.name,
+	// by the values of which the number of spans of every batch is broken down in the
+	// info summary. The spans without the attribute are counted as unknown. Empty disables it.
+	GroupByResourceAttribute string `mapstructure:"group_by_resource_attribute"`
+
+	// Cardinality defines the tracking of the number of series of every metric name.
+	Cardinality CardinalitySettings `mapstructure:"cardinality"`
+
+	// Deltas defines the rendering, when the LogLevel is debug, of the change of the
+	// cumulative sums since their previously rendered value.
+	Deltas DeltasSettings `mapstructure:"deltas"`
+
+	// Outputs defines the destinations the data is written to, each with its own log level,
+	// e.g. the summary to stdout and the detailed rendering to a file. Empty means the
+	// data is written to stderr with the LogLevel.
+	Outputs []OutputSettings `mapstructure:"outputs"`
+
+	// Format defines the name of the registered marshalers used to render the data,
+	// see RegisterMarshalers. Defaults to text, which renders the data with otlptext.
+	Format string `mapstructure:"format"`
+}
+
+// WarnBatchSizeSettings defines the batch size thresholds for every signal.
+// A zero value disables the check for the corresponding signal.
+type WarnBatchSizeSettings struct {
+	// Spans is the maximum number of spans in a batch before a warning is logged.
+	Spans int `mapstructure:"spans"`
+
+	// Metrics is the maximum number of metrics in a batch before a warning is logged.
+	Metrics int `mapstructure:"metrics"`
+
+	// Logs is the maximum number of log records in a batch before a warning is logged.
+	Logs int `mapstructure:"logs"`
+}
+
+// CardinalitySettings defines the tracking of the number of distinct series, i.e.
+// combinations of resource attributes and labels, of every metric name.
+type CardinalitySettings struct {
+	// Threshold is the estimated number of series of a metric within a Window above
+	// which a warning is logged. Zero (default) disables the tracking.
+	Threshold int `mapstructure:"threshold"`
+
+	// Window is the period over which the series are counted.
+	Window time.Duration `mapstructure:"window"`
+}
+
+// DeltasSettings defines the rendering of the change of the value of every series of
+// the cumulative sums since the previous batch, e.g. to spot which counters are moving.
+type DeltasSettings struct {
+	// Enabled defines whether the deltas are rendered.
+	Enabled bool `mapstructure:"enabled"`
+
+	// MaxSeries is the maximum number of series whose last value is kept, each taking
+	// in the order of 50 bytes of memory. No delta is rendered for the series above it.
+	MaxSeries int `mapstructure:"max_series"`
+}
+
+// OutputSettings defines a destination the data is written to, either a Path or Syslog.
+type OutputSettings struct {
+	// Path is stdout, stderr or the path of a file the data is appended to.
+	Path string `mapstructure:"path"`
+
+	// Syslog defines a syslog destination the data is sent to as syslog messages.
+	Syslog *SyslogSettings `mapstructure:"syslog"`
+
+	// LogLevel defines the log level of the destination, defaults to the LogLevel of the
+	// exporter. The detailed rendering is only written to the destinations at debug level.
+	LogLevel string `mapstructure:"loglevel"`
+}
+
+// SyslogSettings defines a local or remote syslog destination.
+type SyslogSettings struct {
+	// Endpoint is the host:port of a remote syslog server. Empty means the local syslog
+	// daemon, through its unix socket.
+	Endpoint string `mapstructure:"endpoint"`
+
+	// Transport is the protocol used to reach the Endpoint, udp or tcp.
+	Transport string `mapstructure:"transport"`
+
+	// Facility is the syslog facility of the messages, e.g. user or local0.
+	Facility string `mapstructure:"facility"`
+
+	// Tag is the tag of the messages, usually the name of the program.
+	Tag string `mapstructure:"tag"`
+
+	// Severities maps the log levels, e.g. debug, to the syslog severity of their messages,
+	// e.g. notice. The log levels which are not mapped keep their default severity.
+	Severities map[string]string `mapstructure:"severities"`
 }
 
 var _ config.Exporter = (*Config)(nil)
 
 // Validate checks if the exporter configuration is valid
 func (cfg *Config) Validate() error {
+	if cfg.WarnBatchSize.Spans < 0 || cfg.WarnBatchSize.Metrics < 0 || cfg.WarnBatchSize.Logs < 0 {
+		return errors.New("warn_batch_size values must be non-negative")
+	}
+	if cfg.Cardinality.Threshold < 0 {
+		return errors.New("cardinality threshold must be non-negative")
+	}
+	if cfg.Cardinality.Threshold > 0 && cfg.Cardinality.Window <= 0 {
+		return errors.New("cardinality window must be positive")
+	}
+	if cfg.Deltas.MaxSeries < 0 {
+		return errors.New("deltas max_series must be non-negative")
+	}
+	if cfg.MaxArrayElements < 0 {
+		return errors.New("max_array_elements must be non-negative")
+	}
+	if cfg.MaxEventsPerSpan < 0 {
+		return errors.New("max_events_per_span must be non-negative")
+	}
+	if cfg.SampleRatio < 0 || cfg.SampleRatio > 1 {
+		return errors.New("sample_ratio must be between 0 and 1")
+	}
+	for _, id := range cfg.TraceIDs {
+		if _, err := otlptext.ParseTraceID(id); err != nil {
+			return err
+		}
+	}
+	for _, kind := range cfg.SpanKinds {
+		if _, err := otlptext.ParseSpanKind(kind); err != nil {
+			return err
+		}
+	}
+	if cfg.MinSeverity != "" {
+		if _, err := otlptext.ParseSeverityNumber(cfg.MinSeverity); err != nil {
+			return err
+		}
+	}
+	if _, err := otlptext.ParseSanitization(cfg.Sanitization); err != nil {
+		return err
+	}
+	paths := make(map[string]bool, len(cfg.Outputs))
+	for _, output := range cfg.Outputs {
+		if output.Syslog != nil {
+			if output.Path != "" {
+				return errors.New("outputs must not have both a path and syslog")
+			}
+			if err := output.Syslog.validate(); err != nil {
+				return err
+			}
+		} else if output.Path == "" {
+			return errors.New("outputs path must not be empty")
+		}
+		if output.Path != "" && paths[output.Path] {
+			return fmt.Errorf("duplicate outputs path %q", output.Path)
+		}
+		paths[output.Path] = true
+		var level zapcore.Level
+		if err := level.UnmarshalText([]byte(cfg.outputLogLevel(output))); err != nil {
+			return err
+		}
+	}
+	if _, err := getMarshalers(cfg.Format); err != nil {
+		return err
+	}
 	return nil
 }
+
+// outputLogLevel returns the log level of the given output.
+func (cfg *Config) outputLogLevel(output OutputSettings) string {
+	if output.LogLevel == "" {
+		return cfg.LogLevel
+	}
+	return output.LogLevel
+}
+
+// isDebug returns true if the data is rendered, i.e. the LogLevel or the log level
+// of one of the Outputs is debug.
+func (cfg *Config) isDebug() bool {
+	if len(cfg.Outputs) == 0 {
+		return strings.ToLower(cfg.LogLevel) == "debug"
+	}
+	for _, output := range cfg.Outputs {
+		if strings.ToLower(cfg.outputLogLevel(output)) == "debug" {
+			return true
+		}
+	}
+	return false
+}
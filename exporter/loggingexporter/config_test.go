@@ -17,6 +17,7 @@ package loggingexporter
 import (
 	"path"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -47,5 +48,127 @@ func TestLoadConfig(t *testing.T) {
 			LogLevel:           "debug",
 			SamplingInitial:    10,
 			SamplingThereafter: 50,
+			WarnBatchSize: WarnBatchSizeSettings{
+				Spans: 10000,
+				Logs:  5000,
+			},
+			SampleRatio:              0.25,
+			TraceIDs:                 []string{"0102030405060708090a0b0c0d0e0f10"},
+			LogDataPointCount:        true,
+			FlattenAttributes:        true,
+			SpanKinds:                []string{"server", "client"},
+			DebugOnError:             true,
+			Format:                   JSONFormat,
+			RenderAttributeKeys:      []string{"http.method", "http.status_code"},
+			FilterAllAttributes:      true,
+			SpanTree:                 true,
+			CompactSpans:             true,
+			GroupAttributes:          true,
+			HoistCommonLabels:        true,
+			MaxArrayElements:         10,
+			MaxEventsPerSpan:         20,
+			EventNameFilter:          []string{"exception", "db.*"},
+			ExcludeAttributes:        map[string]string{"http.target": "/health*"},
+			Sanitization:             "strip",
+			MinSeverity:              "warn",
+			GroupByResourceAttribute: "service.name",
+			Cardinality: CardinalitySettings{
+				Threshold: 1000,
+				Window:    5 * time.Minute,
+			},
+			Deltas: DeltasSettings{
+				Enabled:   true,
+				MaxSeries: 500,
+			},
+			Outputs: []OutputSettings{
+				{Path: "stdout"},
+				{Path: "/var/log/otelcol/data.log", LogLevel: "debug"},
+				{Syslog: &SyslogSettings{
+					Endpoint:   "syslog.example.com:514",
+					Transport:  "tcp",
+					Facility:   "local0",
+					Severities: map[string]string{"info": "notice"},
+				}},
+			},
 		})
 }
+
+func TestConfigValidate(t *testing.T) {
+	cfg := createDefaultConfig().(*Config)
+	assert.NoError(t, cfg.Validate())
+
+	cfg.WarnBatchSize.Metrics = -1
+	assert.Error(t, cfg.Validate())
+
+	cfg = createDefaultConfig().(*Config)
+	cfg.MaxArrayElements = -1
+	assert.EqualError(t, cfg.Validate(), "max_array_elements must be non-negative")
+
+	cfg = createDefaultConfig().(*Config)
+	cfg.TraceIDs = []string{"0102030405060708090a0b0c0d0e0f10", "0102"}
+	assert.EqualError(t, cfg.Validate(), `invalid trace ID "0102", must be 32 hex digits`)
+
+	cfg = createDefaultConfig().(*Config)
+	cfg.MaxEventsPerSpan = -1
+	assert.EqualError(t, cfg.Validate(), "max_events_per_span must be non-negative")
+
+	cfg = createDefaultConfig().(*Config)
+	cfg.Outputs = []OutputSettings{{Path: "stdout"}, {LogLevel: "debug"}}
+	assert.EqualError(t, cfg.Validate(), "outputs path must not be empty")
+
+	cfg = createDefaultConfig().(*Config)
+	cfg.Outputs = []OutputSettings{{Path: "stdout"}, {Path: "stdout", LogLevel: "debug"}}
+	assert.EqualError(t, cfg.Validate(), `duplicate outputs path "stdout"`)
+
+	cfg = createDefaultConfig().(*Config)
+	cfg.Outputs = []OutputSettings{{Path: "stdout", Syslog: &SyslogSettings{}}}
+	assert.EqualError(t, cfg.Validate(), "outputs must not have both a path and syslog")
+
+	cfg = createDefaultConfig().(*Config)
+	cfg.Outputs = []OutputSettings{{Syslog: &SyslogSettings{}}, {Syslog: &SyslogSettings{Facility: "unknown"}}}
+	assert.EqualError(t, cfg.Validate(), `unknown syslog facility "unknown"`)
+
+	cfg = createDefaultConfig().(*Config)
+	cfg.Outputs = []OutputSettings{{Path: "stdout", LogLevel: "verbose"}}
+	assert.Error(t, cfg.Validate())
+
+	cfg = createDefaultConfig().(*Config)
+	cfg.SampleRatio = 1.5
+	assert.Error(t, cfg.Validate())
+
+	cfg = createDefaultConfig().(*Config)
+	cfg.Cardinality.Threshold = -1
+	assert.EqualError(t, cfg.Validate(), "cardinality threshold must be non-negative")
+
+	cfg = createDefaultConfig().(*Config)
+	cfg.Cardinality = CardinalitySettings{Threshold: 100}
+	assert.EqualError(t, cfg.Validate(), "cardinality window must be positive")
+
+	cfg = createDefaultConfig().(*Config)
+	cfg.Deltas.MaxSeries = -1
+	assert.EqualError(t, cfg.Validate(), "deltas max_series must be non-negative")
+
+	cfg = createDefaultConfig().(*Config)
+	cfg.SpanKinds = []string{"Server", "CLIENT"}
+	assert.NoError(t, cfg.Validate())
+	cfg.SpanKinds = []string{"server", "remote"}
+	assert.EqualError(t, cfg.Validate(), `unknown span kind "remote"`)
+
+	cfg = createDefaultConfig().(*Config)
+	cfg.MinSeverity = "ERROR2"
+	assert.NoError(t, cfg.Validate())
+	cfg.MinSeverity = "critical"
+	assert.EqualError(t, cfg.Validate(), `unknown severity "critical"`)
+
+	cfg = createDefaultConfig().(*Config)
+	cfg.Sanitization = "None"
+	assert.NoError(t, cfg.Validate())
+	cfg.Sanitization = "remove"
+	assert.EqualError(t, cfg.Validate(), `unknown sanitization "remove"`)
+
+	cfg = createDefaultConfig().(*Config)
+	cfg.Format = JSONFormat
+	assert.NoError(t, cfg.Validate())
+	cfg.Format = "yaml"
+	assert.EqualError(t, cfg.Validate(), `unknown format "yaml", registered formats are [json proto text]`)
+}
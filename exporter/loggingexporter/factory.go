@@ -16,6 +16,7 @@ package loggingexporter
 
 import (
 	"context"
+	"time"
 
 	"go.uber.org/zap"
 	"go.uber.org/zap/zapcore"
@@ -23,6 +24,8 @@ import (
 	"go.opentelemetry.io/collector/component"
 	"go.opentelemetry.io/collector/config"
 	"go.opentelemetry.io/collector/exporter/exporterhelper"
+	"go.opentelemetry.io/collector/internal/otlptext"
+	"go.opentelemetry.io/collector/internal/sharedcomponent"
 )
 
 const (
@@ -30,6 +33,7 @@ const (
 	typeStr                   = "logging"
 	defaultSamplingInitial    = 2
 	defaultSamplingThereafter = 500
+	defaultCardinalityWindow  = time.Minute
 )
 
 // NewFactory creates a factory for Logging exporter
@@ -48,47 +52,63 @@ func createDefaultConfig() config.Exporter {
 		LogLevel:           "info",
 		SamplingInitial:    defaultSamplingInitial,
 		SamplingThereafter: defaultSamplingThereafter,
+		SampleRatio:        1,
+		Format:             TextFormat,
+		Sanitization:       "escape",
+		MaxArrayElements:   otlptext.DefaultMaxArrayElements,
+		Cardinality: CardinalitySettings{
+			Window: defaultCardinalityWindow,
+		},
+		Deltas: DeltasSettings{
+			MaxSeries: otlptext.DefaultMaxDeltaSeries,
+		},
 	}
 }
 
 func createTracesExporter(_ context.Context, _ component.ExporterCreateParams, config config.Exporter) (component.TracesExporter, error) {
 	cfg := config.(*Config)
 
-	exporterLogger, err := createLogger(cfg)
+	exporterLogger, outputs, err := createLogger(cfg)
 	if err != nil {
 		return nil, err
 	}
 
-	return newTracesExporter(config, cfg.LogLevel, exporterLogger)
+	return newTracesExporter(cfg, exporterLogger, outputs...)
 }
 
 func createMetricsExporter(_ context.Context, _ component.ExporterCreateParams, config config.Exporter) (component.MetricsExporter, error) {
 	cfg := config.(*Config)
 
-	exporterLogger, err := createLogger(cfg)
+	exporterLogger, outputs, err := createLogger(cfg)
 	if err != nil {
 		return nil, err
 	}
 
-	return newMetricsExporter(config, cfg.LogLevel, exporterLogger)
+	return newMetricsExporter(cfg, exporterLogger, outputs...)
 }
 
 func createLogsExporter(_ context.Context, _ component.ExporterCreateParams, config config.Exporter) (component.LogsExporter, error) {
 	cfg := config.(*Config)
 
-	exporterLogger, err := createLogger(cfg)
+	exporterLogger, outputs, err := createLogger(cfg)
 	if err != nil {
 		return nil, err
 	}
 
-	return newLogsExporter(config, cfg.LogLevel, exporterLogger)
+	return newLogsExporter(cfg, exporterLogger, outputs...)
 }
 
-func createLogger(cfg *Config) (*zap.Logger, error) {
+// createLogger returns the logger of the exporter and the outputs it writes to, which
+// have to be started and shut down along with the exporter.
+func createLogger(cfg *Config) (*zap.Logger, []component.Component, error) {
+	if len(cfg.Outputs) > 0 {
+		return createOutputsLogger(cfg)
+	}
+
 	var level zapcore.Level
 	err := (&level).UnmarshalText([]byte(cfg.LogLevel))
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
 	// We take development config as the base since it matches the purpose
@@ -102,7 +122,27 @@ func createLogger(cfg *Config) (*zap.Logger, error) {
 
 	logginglogger, err := conf.Build()
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
-	return logginglogger, nil
+	return logginglogger, nil, nil
 }
+
+// createOutputsLogger returns a logger writing to every output at its own log level,
+// with the same encoding and sampling as the logger built by createLogger. The
+// outputs are shared by the exporters of all the signals of cfg, and opened when
+// the first of them starts.
+func createOutputsLogger(cfg *Config) (*zap.Logger, []component.Component, error) {
+	o, err := newOutputs(cfg)
+	if err != nil {
+		return nil, nil, err
+	}
+	shared := sharedOutputs.GetOrAdd(cfg, func() component.Component {
+		return o
+	})
+	return shared.Unwrap().(*outputs).logger, []component.Component{shared}, nil
+}
+
+// This is the map of the outputs of the exporters of a configuration. The factory
+// creates the exporter of every signal separately, but they must write to the same
+// files and syslog connections.
+var sharedOutputs = sharedcomponent.NewSharedComponents()
@@ -16,13 +16,19 @@ package loggingexporter
 
 import (
 	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	"go.uber.org/zap"
 
 	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/component/componenttest"
 	"go.opentelemetry.io/collector/config/configcheck"
+	"go.opentelemetry.io/collector/internal/testdata"
 )
 
 func TestCreateDefaultConfig(t *testing.T) {
@@ -58,3 +64,80 @@ func TestCreateLogsExporter(t *testing.T) {
 	assert.NoError(t, err)
 	assert.NotNil(t, te)
 }
+
+func TestCreateTracesExporterOutputs(t *testing.T) {
+	dir := t.TempDir()
+	summaryPath := filepath.Join(dir, "summary.log")
+	detailedPath := filepath.Join(dir, "detailed.log")
+	factory := NewFactory()
+	cfg := factory.CreateDefaultConfig().(*Config)
+	cfg.Outputs = []OutputSettings{
+		{Path: summaryPath},
+		{Path: detailedPath, LogLevel: "debug"},
+	}
+	require.NoError(t, cfg.Validate())
+
+	te, err := factory.CreateTracesExporter(context.Background(), component.ExporterCreateParams{Logger: zap.NewNop()}, cfg)
+	require.NoError(t, err)
+	require.NoError(t, te.Start(context.Background(), componenttest.NewNopHost()))
+	assert.NoError(t, te.ConsumeTraces(context.Background(), testdata.GenerateTracesOneSpan()))
+	assert.NoError(t, te.Shutdown(context.Background()))
+
+	summary, err := ioutil.ReadFile(summaryPath)
+	require.NoError(t, err)
+	assert.Contains(t, string(summary), "TracesExporter")
+	assert.NotContains(t, string(summary), "ResourceSpans #0")
+
+	detailed, err := ioutil.ReadFile(detailedPath)
+	require.NoError(t, err)
+	assert.Contains(t, string(detailed), "TracesExporter")
+	assert.Contains(t, string(detailed), "ResourceSpans #0")
+}
+
+func TestCreateTracesExporterOutputsInvalidPath(t *testing.T) {
+	factory := NewFactory()
+	cfg := factory.CreateDefaultConfig().(*Config)
+	cfg.Outputs = []OutputSettings{
+		{Path: "stdout"},
+		{Path: filepath.Join(t.TempDir(), "missing", "detailed.log"), LogLevel: "debug"},
+	}
+
+	// The files are only opened when the exporter starts.
+	te, err := factory.CreateTracesExporter(context.Background(), component.ExporterCreateParams{Logger: zap.NewNop()}, cfg)
+	require.NoError(t, err)
+	assert.Error(t, te.Start(context.Background(), componenttest.NewNopHost()))
+	assert.NoError(t, te.Shutdown(context.Background()))
+}
+
+func TestCreateExportersSharedOutputs(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "detailed.log")
+	factory := NewFactory()
+	cfg := factory.CreateDefaultConfig().(*Config)
+	cfg.Outputs = []OutputSettings{{Path: path, LogLevel: "debug"}}
+	params := component.ExporterCreateParams{Logger: zap.NewNop()}
+
+	te, err := factory.CreateTracesExporter(context.Background(), params, cfg)
+	require.NoError(t, err)
+	me, err := factory.CreateMetricsExporter(context.Background(), params, cfg)
+	require.NoError(t, err)
+	_, err = os.Stat(path)
+	assert.True(t, os.IsNotExist(err), "the file is opened before the exporter starts")
+
+	// The exporters of both signals write to the same outputs, opened once.
+	shared := sharedOutputs.GetOrAdd(cfg, func() component.Component {
+		t.Fatal("the outputs are not shared")
+		return nil
+	})
+	assert.Len(t, shared.Unwrap().(*outputs).files, 1)
+	require.NoError(t, te.Start(context.Background(), componenttest.NewNopHost()))
+	require.NoError(t, me.Start(context.Background(), componenttest.NewNopHost()))
+	assert.NoError(t, te.ConsumeTraces(context.Background(), testdata.GenerateTracesOneSpan()))
+	assert.NoError(t, me.ConsumeMetrics(context.Background(), testdata.GenerateMetricsOneMetric()))
+	assert.NoError(t, te.Shutdown(context.Background()))
+	assert.NoError(t, me.Shutdown(context.Background()))
+
+	detailed, err := ioutil.ReadFile(path)
+	require.NoError(t, err)
+	assert.Contains(t, string(detailed), "TracesExporter")
+	assert.Contains(t, string(detailed), "MetricsExporter")
+}
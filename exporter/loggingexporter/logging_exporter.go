@@ -16,65 +16,279 @@ package loggingexporter
 
 import (
 	"context"
+	"fmt"
 	"os"
+	"sort"
 	"strings"
+	"time"
 
+	"go.uber.org/multierr"
 	"go.uber.org/zap"
 
 	"go.opentelemetry.io/collector/component"
-	"go.opentelemetry.io/collector/config"
 	"go.opentelemetry.io/collector/consumer"
+	"go.opentelemetry.io/collector/consumer/consumerhelper"
 	"go.opentelemetry.io/collector/consumer/pdata"
 	"go.opentelemetry.io/collector/exporter/exporterhelper"
 	"go.opentelemetry.io/collector/internal/otlptext"
+	tracetranslator "go.opentelemetry.io/collector/translator/trace"
 )
 
 type loggingExporter struct {
-	logger *zap.Logger
-	debug  bool
+	// lastReceived tracks the time of the last pushed batch, see consumerhelper.LivenessReporter.
+	lastReceived      consumerhelper.LivenessTracker
+	logger            *zap.Logger
+	debug             bool
+	warnBatchSize     WarnBatchSizeSettings
+	marshalers        Marshalers
+	logDataPointCount bool
+	debugOnError      bool
+	groupByAttribute  string
+	// cardinality is only set when the cardinality tracking is enabled.
+	cardinality *cardinalityTracker
+}
+
+func newLoggingExporter(cfg *Config, logger *zap.Logger) (*loggingExporter, error) {
+	marshalers, err := newMarshalers(cfg)
+	if err != nil {
+		return nil, err
+	}
+	s := &loggingExporter{
+		debug:             cfg.isDebug(),
+		logger:            logger,
+		warnBatchSize:     cfg.WarnBatchSize,
+		marshalers:        marshalers,
+		logDataPointCount: cfg.LogDataPointCount,
+		debugOnError:      cfg.DebugOnError,
+		groupByAttribute:  cfg.GroupByResourceAttribute,
+	}
+	if cfg.Cardinality.Threshold > 0 {
+		s.cardinality = newCardinalityTracker(cfg.Cardinality, logger)
+	}
+	return s, nil
+}
+
+// newMarshalers returns the marshalers registered for the configured format. The
+// rendering options of the configuration only apply to the default text format.
+func newMarshalers(cfg *Config) (Marshalers, error) {
+	if cfg.Format != TextFormat {
+		return getMarshalers(cfg.Format)
+	}
+	// The sanitization is already validated by the config.
+	sanitization, _ := otlptext.ParseSanitization(cfg.Sanitization)
+	renderOpts := []otlptext.Option{
+		otlptext.WithFlattenAttributes(cfg.FlattenAttributes),
+		otlptext.WithAttributeKeys(cfg.RenderAttributeKeys...),
+		otlptext.WithFilterAllAttributes(cfg.FilterAllAttributes),
+		otlptext.WithMaxArrayElements(cfg.MaxArrayElements),
+		otlptext.WithSanitization(sanitization),
+	}
+	if len(cfg.ExcludeAttributes) > 0 {
+		renderOpts = append(renderOpts, otlptext.WithExcludeAttributes(otlptext.NewAttributeMatchers(cfg.ExcludeAttributes)...))
+	}
+	// Cap renderOpts so that the options of every signal appended below do not share
+	// its backing array and overwrite each other.
+	renderOpts = renderOpts[:len(renderOpts):len(renderOpts)]
+	// The span kinds are already validated by the config.
+	spanKinds := make([]pdata.SpanKind, 0, len(cfg.SpanKinds))
+	for _, name := range cfg.SpanKinds {
+		if kind, err := otlptext.ParseSpanKind(name); err == nil {
+			spanKinds = append(spanKinds, kind)
+		}
+	}
+	// The trace IDs are already validated by the config.
+	traceIDs := make([]pdata.TraceID, 0, len(cfg.TraceIDs))
+	for _, s := range cfg.TraceIDs {
+		if id, err := otlptext.ParseTraceID(s); err == nil {
+			traceIDs = append(traceIDs, id)
+		}
+	}
+	tracesOpts := append(renderOpts,
+		otlptext.WithSampleRatio(cfg.SampleRatio),
+		otlptext.WithTraceIDs(traceIDs...),
+		otlptext.WithSpanKinds(spanKinds...),
+		otlptext.WithSpanTree(cfg.SpanTree),
+		otlptext.WithCompactSpans(cfg.CompactSpans),
+		otlptext.WithGroupAttributes(cfg.GroupAttributes),
+		otlptext.WithMaxEventsPerSpan(cfg.MaxEventsPerSpan),
+		otlptext.WithEventNameFilter(cfg.EventNameFilter...))
+	// The minimum severity is already validated by the config, empty renders all the records.
+	minSeverity, _ := otlptext.ParseSeverityNumber(cfg.MinSeverity)
+	logsOpts := append(renderOpts, otlptext.WithMinSeverity(minSeverity))
+	metricsOpts := append(renderOpts, otlptext.WithHoistCommonLabels(cfg.HoistCommonLabels))
+	if cfg.Deltas.Enabled {
+		metricsOpts = append(metricsOpts, otlptext.WithDeltas(otlptext.NewDeltaTracker(cfg.Deltas.MaxSeries)))
+	}
+	return Marshalers{
+		Traces:  func(td pdata.Traces) string { return otlptext.Traces(td, tracesOpts...) },
+		Metrics: func(md pdata.Metrics) string { return otlptext.Metrics(md, metricsOpts...) },
+		Logs:    func(ld pdata.Logs) string { return otlptext.Logs(ld, logsOpts...) },
+	}, nil
+}
+
+// warnIfBatchTooLarge logs a warning if size exceeds the given non-zero threshold.
+func (s *loggingExporter) warnIfBatchTooLarge(msg string, key string, size int, threshold int) {
+	if threshold > 0 && size > threshold {
+		s.logger.Warn(msg+" batch size exceeds threshold", zap.Int(key, size), zap.Int("threshold", threshold))
+	}
+}
+
+// LastReceived implements the consumerhelper.LivenessReporter interface.
+func (s *loggingExporter) LastReceived() time.Time {
+	return s.lastReceived.LastReceived()
+}
+
+// tracesExporter, metricsExporter and logsExporter expose the LastReceived of the
+// loggingExporter on top of the exporters created by the exporterhelper.
+type tracesExporter struct {
+	component.TracesExporter
+	consumerhelper.LivenessReporter
+}
+
+type metricsExporter struct {
+	component.MetricsExporter
+	consumerhelper.LivenessReporter
+}
+
+type logsExporter struct {
+	component.LogsExporter
+	consumerhelper.LivenessReporter
 }
 
 func (s *loggingExporter) pushTraceData(
 	_ context.Context,
 	td pdata.Traces,
 ) error {
+	s.lastReceived.Record()
 
-	s.logger.Info("TracesExporter", zap.Int("#spans", td.SpanCount()))
+	spanCount := td.SpanCount()
+	if s.groupByAttribute != "" {
+		s.logger.Info("TracesExporter",
+			zap.Int("#spans", spanCount),
+			zap.String("#spans_by_"+s.groupByAttribute, spanCountsByResourceAttribute(td, s.groupByAttribute)))
+	} else {
+		s.logger.Info("TracesExporter", zap.Int("#spans", spanCount))
+	}
+	s.warnIfBatchTooLarge("TracesExporter", "#spans", spanCount, s.warnBatchSize.Spans)
 
 	if !s.debug {
+		if s.debugOnError {
+			if errTraces := resourceSpansWithErrors(td); errTraces.ResourceSpans().Len() > 0 {
+				s.logger.Info(s.marshalers.Traces(errTraces))
+			}
+		}
 		return nil
 	}
 
-	s.logger.Debug(otlptext.Traces(td))
+	s.logger.Debug(s.marshalers.Traces(td))
 
 	return nil
 }
 
+// unknownGroup is the group of the spans whose resource does not have the attribute.
+const unknownGroup = "unknown"
+
+// spanCountsByResourceAttribute returns the number of spans of td for every value of
+// the resource attribute with the given key, e.g. "payments=120 checkout=80", sorted
+// by decreasing number of spans.
+func spanCountsByResourceAttribute(td pdata.Traces, key string) string {
+	counts := make(map[string]int)
+	rss := td.ResourceSpans()
+	for i := 0; i < rss.Len(); i++ {
+		rs := rss.At(i)
+		group := unknownGroup
+		if value, ok := rs.Resource().Attributes().Get(key); ok {
+			group = tracetranslator.AttributeValueToString(value)
+		}
+		ilss := rs.InstrumentationLibrarySpans()
+		for j := 0; j < ilss.Len(); j++ {
+			counts[group] += ilss.At(j).Spans().Len()
+		}
+	}
+
+	groups := make([]string, 0, len(counts))
+	for group := range counts {
+		groups = append(groups, group)
+	}
+	sort.Slice(groups, func(i, j int) bool {
+		if counts[groups[i]] != counts[groups[j]] {
+			return counts[groups[i]] > counts[groups[j]]
+		}
+		return groups[i] < groups[j]
+	})
+	var sb strings.Builder
+	for i, group := range groups {
+		if i > 0 {
+			sb.WriteByte(' ')
+		}
+		fmt.Fprintf(&sb, "%s=%d", group, counts[group])
+	}
+	return sb.String()
+}
+
+// resourceSpansWithErrors returns a copy of the resource spans of td that contain
+// at least one span with an error status.
+func resourceSpansWithErrors(td pdata.Traces) pdata.Traces {
+	errTraces := pdata.NewTraces()
+	rss := td.ResourceSpans()
+	for i := 0; i < rss.Len(); i++ {
+		rs := rss.At(i)
+		if hasErrorSpan(rs) {
+			rs.CopyTo(errTraces.ResourceSpans().AppendEmpty())
+		}
+	}
+	return errTraces
+}
+
+func hasErrorSpan(rs pdata.ResourceSpans) bool {
+	ilss := rs.InstrumentationLibrarySpans()
+	for i := 0; i < ilss.Len(); i++ {
+		spans := ilss.At(i).Spans()
+		for j := 0; j < spans.Len(); j++ {
+			if spans.At(j).Status().Code() == pdata.StatusCodeError {
+				return true
+			}
+		}
+	}
+	return false
+}
+
 func (s *loggingExporter) pushMetricsData(
 	_ context.Context,
 	md pdata.Metrics,
 ) error {
-	s.logger.Info("MetricsExporter", zap.Int("#metrics", md.MetricCount()))
+	s.lastReceived.Record()
+
+	metricCount := md.MetricCount()
+	if s.logDataPointCount {
+		s.logger.Info("MetricsExporter", zap.Int("#metrics", metricCount), zap.Int("#datapoints", md.DataPointCount()))
+	} else {
+		s.logger.Info("MetricsExporter", zap.Int("#metrics", metricCount))
+	}
+	s.warnIfBatchTooLarge("MetricsExporter", "#metrics", metricCount, s.warnBatchSize.Metrics)
+	if s.cardinality != nil {
+		s.cardinality.observe(md)
+	}
 
 	if !s.debug {
 		return nil
 	}
 
-	s.logger.Debug(otlptext.Metrics(md))
+	s.logger.Debug(s.marshalers.Metrics(md))
 
 	return nil
 }
 
 // newTracesExporter creates an exporter.TracesExporter that just drops the
 // received data and logs debugging messages.
-func newTracesExporter(config config.Exporter, level string, logger *zap.Logger) (component.TracesExporter, error) {
-	s := &loggingExporter{
-		debug:  strings.ToLower(level) == "debug",
-		logger: logger,
+func newTracesExporter(cfg *Config, logger *zap.Logger, outputs ...component.Component) (component.TracesExporter, error) {
+	s, err := newLoggingExporter(cfg, logger)
+	if err != nil {
+		return nil, err
 	}
 
-	return exporterhelper.NewTracesExporter(
-		config,
+	exp, err := exporterhelper.NewTracesExporter(
+		cfg,
 		logger,
 		s.pushTraceData,
 		exporterhelper.WithCapabilities(consumer.Capabilities{MutatesData: false}),
@@ -82,20 +296,25 @@ func newTracesExporter(config config.Exporter, level string, logger *zap.Logger)
 		exporterhelper.WithTimeout(exporterhelper.TimeoutSettings{Timeout: 0}),
 		exporterhelper.WithRetry(exporterhelper.RetrySettings{Enabled: false}),
 		exporterhelper.WithQueue(exporterhelper.QueueSettings{Enabled: false}),
-		exporterhelper.WithShutdown(loggerSync(logger)),
+		exporterhelper.WithStart(outputsStart(outputs)),
+		exporterhelper.WithShutdown(loggerShutdown(logger, outputs)),
 	)
+	if err != nil {
+		return nil, err
+	}
+	return &tracesExporter{TracesExporter: exp, LivenessReporter: s}, nil
 }
 
 // newMetricsExporter creates an exporter.MetricsExporter that just drops the
 // received data and logs debugging messages.
-func newMetricsExporter(config config.Exporter, level string, logger *zap.Logger) (component.MetricsExporter, error) {
-	s := &loggingExporter{
-		debug:  strings.ToLower(level) == "debug",
-		logger: logger,
+func newMetricsExporter(cfg *Config, logger *zap.Logger, outputs ...component.Component) (component.MetricsExporter, error) {
+	s, err := newLoggingExporter(cfg, logger)
+	if err != nil {
+		return nil, err
 	}
 
-	return exporterhelper.NewMetricsExporter(
-		config,
+	exp, err := exporterhelper.NewMetricsExporter(
+		cfg,
 		logger,
 		s.pushMetricsData,
 		exporterhelper.WithCapabilities(consumer.Capabilities{MutatesData: false}),
@@ -103,20 +322,25 @@ func newMetricsExporter(config config.Exporter, level string, logger *zap.Logger
 		exporterhelper.WithTimeout(exporterhelper.TimeoutSettings{Timeout: 0}),
 		exporterhelper.WithRetry(exporterhelper.RetrySettings{Enabled: false}),
 		exporterhelper.WithQueue(exporterhelper.QueueSettings{Enabled: false}),
-		exporterhelper.WithShutdown(loggerSync(logger)),
+		exporterhelper.WithStart(outputsStart(outputs)),
+		exporterhelper.WithShutdown(loggerShutdown(logger, outputs)),
 	)
+	if err != nil {
+		return nil, err
+	}
+	return &metricsExporter{MetricsExporter: exp, LivenessReporter: s}, nil
 }
 
 // newLogsExporter creates an exporter.LogsExporter that just drops the
 // received data and logs debugging messages.
-func newLogsExporter(config config.Exporter, level string, logger *zap.Logger) (component.LogsExporter, error) {
-	s := &loggingExporter{
-		debug:  strings.ToLower(level) == "debug",
-		logger: logger,
+func newLogsExporter(cfg *Config, logger *zap.Logger, outputs ...component.Component) (component.LogsExporter, error) {
+	s, err := newLoggingExporter(cfg, logger)
+	if err != nil {
+		return nil, err
 	}
 
-	return exporterhelper.NewLogsExporter(
-		config,
+	exp, err := exporterhelper.NewLogsExporter(
+		cfg,
 		logger,
 		s.pushLogData,
 		exporterhelper.WithCapabilities(consumer.Capabilities{MutatesData: false}),
@@ -124,25 +348,57 @@ func newLogsExporter(config config.Exporter, level string, logger *zap.Logger) (
 		exporterhelper.WithTimeout(exporterhelper.TimeoutSettings{Timeout: 0}),
 		exporterhelper.WithRetry(exporterhelper.RetrySettings{Enabled: false}),
 		exporterhelper.WithQueue(exporterhelper.QueueSettings{Enabled: false}),
-		exporterhelper.WithShutdown(loggerSync(logger)),
+		exporterhelper.WithStart(outputsStart(outputs)),
+		exporterhelper.WithShutdown(loggerShutdown(logger, outputs)),
 	)
+	if err != nil {
+		return nil, err
+	}
+	return &logsExporter{LogsExporter: exp, LivenessReporter: s}, nil
 }
 
 func (s *loggingExporter) pushLogData(
 	_ context.Context,
 	ld pdata.Logs,
 ) error {
-	s.logger.Info("LogsExporter", zap.Int("#logs", ld.LogRecordCount()))
+	s.lastReceived.Record()
+
+	logRecordCount := ld.LogRecordCount()
+	s.logger.Info("LogsExporter", zap.Int("#logs", logRecordCount))
+	s.warnIfBatchTooLarge("LogsExporter", "#logs", logRecordCount, s.warnBatchSize.Logs)
 
 	if !s.debug {
 		return nil
 	}
 
-	s.logger.Debug(otlptext.Logs(ld))
+	s.logger.Debug(s.marshalers.Logs(ld))
 
 	return nil
 }
 
+// outputsStart returns a function starting the outputs of the logger.
+func outputsStart(outputs []component.Component) func(context.Context, component.Host) error {
+	return func(ctx context.Context, host component.Host) error {
+		for _, output := range outputs {
+			if err := output.Start(ctx, host); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+}
+
+// loggerShutdown returns a function flushing the logger then shutting down its outputs.
+func loggerShutdown(logger *zap.Logger, outputs []component.Component) func(context.Context) error {
+	return func(ctx context.Context) error {
+		err := loggerSync(logger)(ctx)
+		for _, output := range outputs {
+			err = multierr.Append(err, output.Shutdown(ctx))
+		}
+		return err
+	}
+}
+
 func loggerSync(logger *zap.Logger) func(context.Context) error {
 	return func(context.Context) error {
 		// Currently Sync() return a different error depending on the OS.
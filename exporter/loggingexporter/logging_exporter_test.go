@@ -15,19 +15,30 @@ package loggingexporter
 
 import (
 	"context"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
 
-	"go.opentelemetry.io/collector/config"
+	"go.opentelemetry.io/collector/consumer/consumerhelper"
 	"go.opentelemetry.io/collector/consumer/pdata"
+	"go.opentelemetry.io/collector/consumer/pdatabuilder"
 	"go.opentelemetry.io/collector/internal/testdata"
 )
 
+func newTestConfig(level string) *Config {
+	cfg := createDefaultConfig().(*Config)
+	cfg.LogLevel = level
+	return cfg
+}
+
 func TestLoggingTracesExporterNoErrors(t *testing.T) {
-	lte, err := newTracesExporter(&config.ExporterSettings{}, "Debug", zap.NewNop())
+	lte, err := newTracesExporter(newTestConfig("Debug"), zap.NewNop())
 	require.NotNil(t, lte)
 	assert.NoError(t, err)
 
@@ -38,7 +49,7 @@ func TestLoggingTracesExporterNoErrors(t *testing.T) {
 }
 
 func TestLoggingMetricsExporterNoErrors(t *testing.T) {
-	lme, err := newMetricsExporter(&config.ExporterSettings{}, "DEBUG", zap.NewNop())
+	lme, err := newMetricsExporter(newTestConfig("DEBUG"), zap.NewNop())
 	require.NotNil(t, lme)
 	assert.NoError(t, err)
 
@@ -51,7 +62,7 @@ func TestLoggingMetricsExporterNoErrors(t *testing.T) {
 }
 
 func TestLoggingLogsExporterNoErrors(t *testing.T) {
-	lle, err := newLogsExporter(&config.ExporterSettings{}, "debug", zap.NewNop())
+	lle, err := newLogsExporter(newTestConfig("debug"), zap.NewNop())
 	require.NotNil(t, lle)
 	assert.NoError(t, err)
 
@@ -62,3 +73,495 @@ func TestLoggingLogsExporterNoErrors(t *testing.T) {
 
 	assert.NoError(t, lle.Shutdown(context.Background()))
 }
+
+func TestLoggingExporterWarnBatchSize(t *testing.T) {
+	core, logs := observer.New(zapcore.InfoLevel)
+	cfg := newTestConfig("info")
+	cfg.WarnBatchSize = WarnBatchSizeSettings{
+		Spans:   2,
+		Metrics: 1,
+	}
+
+	lte, err := newTracesExporter(cfg, zap.New(core))
+	require.NoError(t, err)
+	assert.NoError(t, lte.ConsumeTraces(context.Background(), testdata.GenerateTracesTwoSpansSameResource()))
+	assert.Len(t, takeWarnings(logs), 0)
+	assert.NoError(t, lte.ConsumeTraces(context.Background(), testdata.GenerateTracesTwoSpansSameResourceOneDifferent()))
+	warns := takeWarnings(logs)
+	require.Len(t, warns, 1)
+	assert.Equal(t, int64(3), warns[0].ContextMap()["#spans"])
+	assert.Equal(t, int64(2), warns[0].ContextMap()["threshold"])
+
+	lme, err := newMetricsExporter(cfg, zap.New(core))
+	require.NoError(t, err)
+	assert.NoError(t, lme.ConsumeMetrics(context.Background(), testdata.GenerateMetricsTwoMetrics()))
+	warns = takeWarnings(logs)
+	require.Len(t, warns, 1)
+	assert.Equal(t, int64(2), warns[0].ContextMap()["#metrics"])
+
+	// Zero threshold disables the check, the info summary is still logged.
+	lle, err := newLogsExporter(cfg, zap.New(core))
+	require.NoError(t, err)
+	assert.NoError(t, lle.ConsumeLogs(context.Background(), testdata.GenerateLogsManyLogRecordsSameResource(10)))
+	entries := logs.TakeAll()
+	require.Len(t, entries, 1)
+	assert.Equal(t, zapcore.InfoLevel, entries[0].Level)
+	assert.Equal(t, "LogsExporter", entries[0].Message)
+}
+
+func takeWarnings(logs *observer.ObservedLogs) []observer.LoggedEntry {
+	var warns []observer.LoggedEntry
+	for _, entry := range logs.TakeAll() {
+		if entry.Level == zapcore.WarnLevel {
+			warns = append(warns, entry)
+		}
+	}
+	return warns
+}
+
+func TestLoggingTracesExporterSampleRatio(t *testing.T) {
+	core, logs := observer.New(zapcore.DebugLevel)
+	cfg := newTestConfig("debug")
+	cfg.SampleRatio = 0
+
+	lte, err := newTracesExporter(cfg, zap.New(core))
+	require.NoError(t, err)
+	assert.NoError(t, lte.ConsumeTraces(context.Background(), testdata.GenerateTracesTwoSpansSameResource()))
+	entries := logs.TakeAll()
+	require.Len(t, entries, 2)
+	// The summary reflects all the spans even if none is rendered.
+	assert.Equal(t, int64(2), entries[0].ContextMap()["#spans"])
+	assert.NotContains(t, entries[1].Message, "Span #")
+}
+
+func TestLoggingTracesExporterTraceIDs(t *testing.T) {
+	td := pdatabuilder.NewTraces().
+		Span("match-root").WithIDs([16]byte{1}, [8]byte{1}).
+		Span("other").WithIDs([16]byte{2}, [8]byte{2}).
+		Span("match-child").WithIDs([16]byte{1}, [8]byte{3}).WithParentSpanID([8]byte{1}).
+		Build()
+	core, logs := observer.New(zapcore.DebugLevel)
+	cfg := newTestConfig("debug")
+	cfg.TraceIDs = []string{"01000000000000000000000000000000"}
+
+	lte, err := newTracesExporter(cfg, zap.New(core))
+	require.NoError(t, err)
+	assert.NoError(t, lte.ConsumeTraces(context.Background(), td))
+	entries := logs.TakeAll()
+	require.Len(t, entries, 2)
+	assert.Equal(t, int64(3), entries[0].ContextMap()["#spans"])
+	assert.Contains(t, entries[1].Message, "match-root")
+	assert.Contains(t, entries[1].Message, "match-child")
+	assert.NotContains(t, entries[1].Message, "other")
+}
+
+func TestLoggingTracesExporterSpanKinds(t *testing.T) {
+	td := pdatabuilder.NewTraces().
+		Span("server-span").WithKind(pdata.SpanKindServer).
+		Span("client-span").WithKind(pdata.SpanKindClient).
+		Span("internal-span").WithKind(pdata.SpanKindInternal).
+		Build()
+	core, logs := observer.New(zapcore.DebugLevel)
+	cfg := newTestConfig("debug")
+	cfg.SpanKinds = []string{"server", "Client"}
+
+	lte, err := newTracesExporter(cfg, zap.New(core))
+	require.NoError(t, err)
+	assert.NoError(t, lte.ConsumeTraces(context.Background(), td))
+	entries := logs.TakeAll()
+	require.Len(t, entries, 2)
+	// The summary reflects all the spans even if only some are rendered.
+	assert.Equal(t, int64(3), entries[0].ContextMap()["#spans"])
+	assert.Contains(t, entries[1].Message, "server-span")
+	assert.Contains(t, entries[1].Message, "client-span")
+	assert.NotContains(t, entries[1].Message, "internal-span")
+}
+
+func TestLoggingTracesExporterRenderAttributeKeys(t *testing.T) {
+	td := pdatabuilder.NewTraces().
+		Resource(pdatabuilder.Attrs{"service.name": "svc", "host.name": "host"}).
+		Span("span").WithAttrs(pdatabuilder.Attrs{"http.method": "GET", "net.peer.ip": "10.0.0.1", "thread.name": "main"}).
+		Build()
+	core, logs := observer.New(zapcore.DebugLevel)
+	cfg := newTestConfig("debug")
+	cfg.RenderAttributeKeys = []string{"http.method", "service.name"}
+
+	lte, err := newTracesExporter(cfg, zap.New(core))
+	require.NoError(t, err)
+	assert.NoError(t, lte.ConsumeTraces(context.Background(), td))
+	entries := logs.TakeAll()
+	require.Len(t, entries, 2)
+	assert.Contains(t, entries[1].Message, "http.method")
+	assert.NotContains(t, entries[1].Message, "net.peer.ip")
+	assert.Contains(t, entries[1].Message, "+2 more")
+	// The resource attributes are not filtered by default.
+	assert.Contains(t, entries[1].Message, "host.name")
+
+	cfg.FilterAllAttributes = true
+	lte, err = newTracesExporter(cfg, zap.New(core))
+	require.NoError(t, err)
+	assert.NoError(t, lte.ConsumeTraces(context.Background(), td))
+	entries = logs.TakeAll()
+	require.Len(t, entries, 2)
+	assert.Contains(t, entries[1].Message, "service.name")
+	assert.NotContains(t, entries[1].Message, "host.name")
+	assert.Contains(t, entries[1].Message, "+1 more")
+}
+
+func TestLoggingTracesExporterCompactSpans(t *testing.T) {
+	core, logs := observer.New(zapcore.DebugLevel)
+	cfg := newTestConfig("debug")
+	cfg.CompactSpans = true
+
+	lte, err := newTracesExporter(cfg, zap.New(core))
+	require.NoError(t, err)
+	assert.NoError(t, lte.ConsumeTraces(context.Background(), testdata.GenerateTracesSpanTree()))
+	entries := logs.TakeAll()
+	require.Len(t, entries, 2)
+	lines := strings.Split(strings.TrimSuffix(entries[1].Message, "\n"), "\n")
+	require.Len(t, lines, 5)
+	assert.Contains(t, lines, `service="frontend" name="frontend" kind=server duration=1.000000468s status=STATUS_CODE_UNSET trace_id=01020304000000000000000000000000 span_id=0100000000000000`)
+}
+
+func TestLoggingTracesExporterSpanTree(t *testing.T) {
+	core, logs := observer.New(zapcore.DebugLevel)
+	cfg := newTestConfig("debug")
+	cfg.SpanTree = true
+
+	lte, err := newTracesExporter(cfg, zap.New(core))
+	require.NoError(t, err)
+	assert.NoError(t, lte.ConsumeTraces(context.Background(), testdata.GenerateTracesSpanTree()))
+	entries := logs.TakeAll()
+	require.Len(t, entries, 2)
+	assert.Contains(t, entries[1].Message, "-> frontend [server]")
+	assert.Contains(t, entries[1].Message, "\n   -> backend-call [client]")
+	assert.Contains(t, entries[1].Message, "Orphan spans:")
+}
+
+func TestLoggingTracesExporterGroupAttributes(t *testing.T) {
+	td := pdatabuilder.NewTraces().
+		Span("span").WithAttrs(pdatabuilder.Attrs{"http.method": "GET", "component": "grpc"}).
+		Build()
+	core, logs := observer.New(zapcore.DebugLevel)
+	cfg := newTestConfig("debug")
+	cfg.GroupAttributes = true
+
+	lte, err := newTracesExporter(cfg, zap.New(core))
+	require.NoError(t, err)
+	assert.NoError(t, lte.ConsumeTraces(context.Background(), td))
+	entries := logs.TakeAll()
+	require.Len(t, entries, 2)
+	assert.Contains(t, entries[1].Message, "   [http]\n     -> http.method: STRING(GET)\n   [general]\n")
+}
+
+func TestLoggingTracesExporterMaxArrayElements(t *testing.T) {
+	long := make([]interface{}, 500)
+	for i := range long {
+		long[i] = "host"
+	}
+	td := pdatabuilder.NewTraces().
+		Resource(pdatabuilder.Attrs{"host.names": long}).
+		Span("span").
+		Build()
+	core, logs := observer.New(zapcore.DebugLevel)
+	cfg := newTestConfig("debug")
+	cfg.MaxArrayElements = 2
+
+	lte, err := newTracesExporter(cfg, zap.New(core))
+	require.NoError(t, err)
+	assert.NoError(t, lte.ConsumeTraces(context.Background(), td))
+	entries := logs.TakeAll()
+	require.Len(t, entries, 2)
+	assert.Contains(t, entries[1].Message, "-> host.names: ARRAY([host, host, ... (+498 more)])")
+}
+
+func TestLoggingTracesExporterSpanEvents(t *testing.T) {
+	td := pdatabuilder.NewTraces().Span("span").Build()
+	events := td.ResourceSpans().At(0).InstrumentationLibrarySpans().At(0).Spans().At(0).Events()
+	for i := 0; i < 100; i++ {
+		events.AppendEmpty().SetName("retry")
+	}
+	events.AppendEmpty().SetName("exception")
+	core, logs := observer.New(zapcore.DebugLevel)
+	cfg := newTestConfig("debug")
+	cfg.MaxEventsPerSpan = 5
+	cfg.EventNameFilter = []string{"exception", "db.*"}
+
+	lte, err := newTracesExporter(cfg, zap.New(core))
+	require.NoError(t, err)
+	assert.NoError(t, lte.ConsumeTraces(context.Background(), td))
+	entries := logs.TakeAll()
+	require.Len(t, entries, 2)
+	assert.Equal(t, 1, strings.Count(entries[1].Message, "SpanEvent #"))
+	assert.Contains(t, entries[1].Message, "SpanEvent #100\n")
+	assert.Contains(t, entries[1].Message, "Omitted events: 100\n")
+}
+
+func TestLoggingExporterExcludeAttributes(t *testing.T) {
+	core, logs := observer.New(zapcore.DebugLevel)
+	cfg := newTestConfig("debug")
+	cfg.ExcludeAttributes = map[string]string{"http.target": "/health*"}
+
+	lte, err := newTracesExporter(cfg, zap.New(core))
+	require.NoError(t, err)
+	td := pdatabuilder.NewTraces().
+		Span("health").WithAttr("http.target", "/healthz").
+		Span("checkout").WithAttr("http.target", "/checkout").
+		Build()
+	assert.NoError(t, lte.ConsumeTraces(context.Background(), td))
+	entries := logs.TakeAll()
+	require.Len(t, entries, 2)
+	// The summary still counts the excluded spans.
+	assert.Equal(t, int64(2), entries[0].ContextMap()["#spans"])
+	assert.NotContains(t, entries[1].Message, "/healthz")
+	assert.Contains(t, entries[1].Message, "/checkout")
+
+	lle, err := newLogsExporter(cfg, zap.New(core))
+	require.NoError(t, err)
+	ld := pdatabuilder.NewLogs().
+		Log("health check").WithAttr("http.target", "/health").
+		Log("order placed").
+		Build()
+	assert.NoError(t, lle.ConsumeLogs(context.Background(), ld))
+	entries = logs.TakeAll()
+	require.Len(t, entries, 2)
+	assert.Equal(t, int64(2), entries[0].ContextMap()["#logs"])
+	assert.NotContains(t, entries[1].Message, "health check")
+	assert.Contains(t, entries[1].Message, "order placed")
+}
+
+func TestLoggingMetricsExporterDeltas(t *testing.T) {
+	newMetrics := func(value int64) pdata.Metrics {
+		return pdatabuilder.NewMetrics().IntSum("requests", true).IntDataPoint(value, nil).Build()
+	}
+	core, logs := observer.New(zapcore.DebugLevel)
+	cfg := newTestConfig("debug")
+	cfg.Deltas.Enabled = true
+
+	lme, err := newMetricsExporter(cfg, zap.New(core))
+	require.NoError(t, err)
+	assert.NoError(t, lme.ConsumeMetrics(context.Background(), newMetrics(10)))
+	assert.NoError(t, lme.ConsumeMetrics(context.Background(), newMetrics(14)))
+	entries := logs.TakeAll()
+	require.Len(t, entries, 4)
+	assert.NotContains(t, entries[1].Message, "Delta:")
+	assert.Contains(t, entries[3].Message, "Delta: +4")
+}
+
+func TestLoggingMetricsExporterHoistCommonLabels(t *testing.T) {
+	md := pdatabuilder.NewMetrics().IntGauge("latency_bucket").
+		IntDataPoint(1, map[string]string{"host": "h1", "le": "10"}).
+		IntDataPoint(2, map[string]string{"host": "h1", "le": "100"}).
+		Build()
+	core, logs := observer.New(zapcore.DebugLevel)
+	cfg := newTestConfig("debug")
+	cfg.HoistCommonLabels = true
+
+	lme, err := newMetricsExporter(cfg, zap.New(core))
+	require.NoError(t, err)
+	assert.NoError(t, lme.ConsumeMetrics(context.Background(), md))
+	entries := logs.TakeAll()
+	require.Len(t, entries, 2)
+	assert.Contains(t, entries[1].Message, "Common data point labels:\n     -> host: h1\n")
+	assert.Equal(t, 1, strings.Count(entries[1].Message, "-> host: h1"))
+}
+
+func TestLoggingTracesExporterSanitization(t *testing.T) {
+	td := pdatabuilder.NewTraces().
+		Span("span").WithAttr("user.agent", "curl\x1b[2J\xff").
+		Build()
+	core, logs := observer.New(zapcore.DebugLevel)
+	cfg := newTestConfig("debug")
+
+	lte, err := newTracesExporter(cfg, zap.New(core))
+	require.NoError(t, err)
+	assert.NoError(t, lte.ConsumeTraces(context.Background(), td))
+	entries := logs.TakeAll()
+	require.Len(t, entries, 2)
+	assert.Contains(t, entries[1].Message, `curl\x1b[2J\xff`)
+
+	cfg.Sanitization = "strip"
+	lte, err = newTracesExporter(cfg, zap.New(core))
+	require.NoError(t, err)
+	assert.NoError(t, lte.ConsumeTraces(context.Background(), td))
+	entries = logs.TakeAll()
+	require.Len(t, entries, 2)
+	assert.Contains(t, entries[1].Message, "curl[2J")
+	assert.NotContains(t, entries[1].Message, "\x1b")
+}
+
+func TestLoggingLogsExporterMinSeverity(t *testing.T) {
+	ld := pdatabuilder.NewLogs().
+		Log("debug record").WithSeverity(pdata.SeverityNumberDEBUG, "DEBUG").
+		Log("warn record").WithSeverity(pdata.SeverityNumberWARN, "WARN").
+		Build()
+	core, logs := observer.New(zapcore.DebugLevel)
+	cfg := newTestConfig("debug")
+	cfg.MinSeverity = "warn"
+
+	lle, err := newLogsExporter(cfg, zap.New(core))
+	require.NoError(t, err)
+	assert.NoError(t, lle.ConsumeLogs(context.Background(), ld))
+	entries := logs.TakeAll()
+	require.Len(t, entries, 2)
+	// The summary reflects all the log records.
+	assert.Equal(t, int64(2), entries[0].ContextMap()["#logs"])
+	assert.NotContains(t, entries[1].Message, "debug record")
+	assert.Contains(t, entries[1].Message, "warn record")
+}
+
+func TestLoggingTracesExporterGroupByResourceAttribute(t *testing.T) {
+	td := pdatabuilder.NewTraces().
+		Resource(pdatabuilder.Attrs{"service.name": "checkout"}).Span("a").Span("b").
+		Resource(pdatabuilder.Attrs{"service.name": "payments"}).Span("c").Span("d").
+		Resource(pdatabuilder.Attrs{"service.name": "payments"}).Span("e").
+		Resource(pdatabuilder.Attrs{"host.name": "host1"}).Span("f").
+		Build()
+	core, logs := observer.New(zapcore.InfoLevel)
+	cfg := newTestConfig("info")
+	cfg.GroupByResourceAttribute = "service.name"
+
+	lte, err := newTracesExporter(cfg, zap.New(core))
+	require.NoError(t, err)
+	assert.NoError(t, lte.ConsumeTraces(context.Background(), td))
+	entries := logs.TakeAll()
+	require.Len(t, entries, 1)
+	assert.Equal(t, int64(6), entries[0].ContextMap()["#spans"])
+	assert.Equal(t, "payments=3 checkout=2 unknown=1", entries[0].ContextMap()["#spans_by_service.name"])
+}
+
+func TestSpanCountsByResourceAttribute(t *testing.T) {
+	assert.Equal(t, "", spanCountsByResourceAttribute(pdata.NewTraces(), "service.name"))
+
+	td := pdatabuilder.NewTraces().
+		Resource(pdatabuilder.Attrs{"k8s.pod.uid": int64(2)}).Span("a").
+		Resource(pdatabuilder.Attrs{"k8s.pod.uid": int64(1)}).Span("b").
+		Build()
+	// Equal counts are sorted by value.
+	assert.Equal(t, "1=1 2=1", spanCountsByResourceAttribute(td, "k8s.pod.uid"))
+	assert.Equal(t, "unknown=2", spanCountsByResourceAttribute(td, "service.name"))
+}
+
+func TestLoggingTracesExporterDebugOnError(t *testing.T) {
+	td := pdatabuilder.NewTraces().
+		Resource(pdatabuilder.Attrs{"service.name": "ok-service"}).
+		Span("ok-span").
+		Resource(pdatabuilder.Attrs{"service.name": "error-service"}).
+		Span("ok-span-in-error-resource").
+		Span("error-span").WithStatus(pdata.StatusCodeError, "failed").
+		Build()
+	core, logs := observer.New(zapcore.InfoLevel)
+	cfg := newTestConfig("info")
+	cfg.DebugOnError = true
+
+	lte, err := newTracesExporter(cfg, zap.New(core))
+	require.NoError(t, err)
+	assert.NoError(t, lte.ConsumeTraces(context.Background(), td))
+	entries := logs.TakeAll()
+	require.Len(t, entries, 2)
+	assert.Equal(t, int64(3), entries[0].ContextMap()["#spans"])
+	assert.Contains(t, entries[1].Message, "error-service")
+	assert.Contains(t, entries[1].Message, "ok-span-in-error-resource")
+	assert.Contains(t, entries[1].Message, "error-span")
+	assert.NotContains(t, entries[1].Message, "ok-service")
+
+	// Nothing is rendered without error spans.
+	assert.NoError(t, lte.ConsumeTraces(context.Background(), pdatabuilder.NewTraces().Span("ok-span").Build()))
+	assert.Len(t, logs.TakeAll(), 1)
+
+	// Disabled by default.
+	lte, err = newTracesExporter(newTestConfig("info"), zap.New(core))
+	require.NoError(t, err)
+	assert.NoError(t, lte.ConsumeTraces(context.Background(), td))
+	assert.Len(t, logs.TakeAll(), 1)
+}
+
+func TestLoggingExporterLogDataPointCount(t *testing.T) {
+	md := testdata.GeneratMetricsAllTypesWithSampleDatapoints()
+	core, logs := observer.New(zapcore.InfoLevel)
+
+	lme, err := newMetricsExporter(newTestConfig("info"), zap.New(core))
+	require.NoError(t, err)
+	assert.NoError(t, lme.ConsumeMetrics(context.Background(), md))
+	entries := logs.TakeAll()
+	require.Len(t, entries, 1)
+	assert.NotContains(t, entries[0].ContextMap(), "#datapoints")
+
+	cfg := newTestConfig("info")
+	cfg.LogDataPointCount = true
+	lme, err = newMetricsExporter(cfg, zap.New(core))
+	require.NoError(t, err)
+	assert.NoError(t, lme.ConsumeMetrics(context.Background(), md))
+	entries = logs.TakeAll()
+	require.Len(t, entries, 1)
+	assert.Equal(t, int64(md.MetricCount()), entries[0].ContextMap()["#metrics"])
+	assert.Equal(t, int64(md.DataPointCount()), entries[0].ContextMap()["#datapoints"])
+	assert.Greater(t, md.DataPointCount(), md.MetricCount())
+}
+
+func TestLoggingMetricsExporterCardinality(t *testing.T) {
+	core, logs := observer.New(zapcore.WarnLevel)
+	cfg := newTestConfig("info")
+	cfg.Cardinality = CardinalitySettings{Threshold: 5, Window: time.Nanosecond}
+
+	lme, err := newMetricsExporter(cfg, zap.New(core))
+	require.NoError(t, err)
+	assert.NoError(t, lme.ConsumeMetrics(context.Background(), seriesMetrics("requests", []string{"a"}, 10)))
+	time.Sleep(time.Millisecond)
+	// The report of the first window is logged on the next batch.
+	assert.NoError(t, lme.ConsumeMetrics(context.Background(), seriesMetrics("requests", []string{"a"}, 1)))
+	entries := logs.TakeAll()
+	require.Len(t, entries, 1)
+	assert.Equal(t, "requests", entries[0].ContextMap()["metric"])
+}
+
+func TestLoggingExporterFlattenAttributes(t *testing.T) {
+	ld := pdata.NewLogs()
+	lr := ld.ResourceLogs().AppendEmpty().InstrumentationLibraryLogs().AppendEmpty().Logs().AppendEmpty()
+	nested := pdata.NewAttributeValueMap()
+	nested.MapVal().InsertString("method", "GET")
+	lr.Attributes().Insert("http", nested)
+	core, logs := observer.New(zapcore.DebugLevel)
+
+	lle, err := newLogsExporter(newTestConfig("debug"), zap.New(core))
+	require.NoError(t, err)
+	assert.NoError(t, lle.ConsumeLogs(context.Background(), ld))
+	entries := logs.TakeAll()
+	require.Len(t, entries, 2)
+	assert.NotContains(t, entries[1].Message, "http.method")
+
+	cfg := newTestConfig("debug")
+	cfg.FlattenAttributes = true
+	lle, err = newLogsExporter(cfg, zap.New(core))
+	require.NoError(t, err)
+	assert.NoError(t, lle.ConsumeLogs(context.Background(), ld))
+	entries = logs.TakeAll()
+	require.Len(t, entries, 2)
+	assert.Contains(t, entries[1].Message, "-> http.method: STRING(GET)")
+}
+
+func TestLoggingExporterLastReceived(t *testing.T) {
+	lte, err := newTracesExporter(newTestConfig("info"), zap.NewNop())
+	require.NoError(t, err)
+	lme, err := newMetricsExporter(newTestConfig("info"), zap.NewNop())
+	require.NoError(t, err)
+	lle, err := newLogsExporter(newTestConfig("info"), zap.NewNop())
+	require.NoError(t, err)
+
+	for _, exp := range []interface{}{lte, lme, lle} {
+		lr, ok := exp.(consumerhelper.LivenessReporter)
+		require.True(t, ok)
+		assert.True(t, lr.LastReceived().IsZero())
+	}
+
+	assert.NoError(t, lte.ConsumeTraces(context.Background(), pdata.NewTraces()))
+	assert.False(t, lte.(consumerhelper.LivenessReporter).LastReceived().IsZero())
+	// Each signal is tracked independently.
+	assert.True(t, lme.(consumerhelper.LivenessReporter).LastReceived().IsZero())
+
+	assert.NoError(t, lme.ConsumeMetrics(context.Background(), pdata.NewMetrics()))
+	assert.False(t, lme.(consumerhelper.LivenessReporter).LastReceived().IsZero())
+
+	assert.NoError(t, lle.ConsumeLogs(context.Background(), pdata.NewLogs()))
+	assert.False(t, lle.(consumerhelper.LivenessReporter).LastReceived().IsZero())
+}
@@ -0,0 +1,120 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package loggingexporter
+
+import (
+	"encoding/base64"
+	"fmt"
+	"sort"
+	"sync"
+
+	"go.opentelemetry.io/collector/consumer/pdata"
+	"go.opentelemetry.io/collector/internal/otlptext"
+)
+
+const (
+	// TextFormat renders the data with the otlptext package, this is the default format.
+	TextFormat = "text"
+	// JSONFormat renders the data using the OTLP/JSON encoding.
+	JSONFormat = "json"
+	// ProtoFormat renders the data using the OTLP/protobuf encoding, base64 encoded
+	// so that the binary data can be logged.
+	ProtoFormat = "proto"
+)
+
+// TracesMarshaler renders traces as a string to be logged.
+type TracesMarshaler func(pdata.Traces) string
+
+// MetricsMarshaler renders metrics as a string to be logged.
+type MetricsMarshaler func(pdata.Metrics) string
+
+// LogsMarshaler renders logs as a string to be logged.
+type LogsMarshaler func(pdata.Logs) string
+
+// Marshalers groups the marshalers of all the signals for a format.
+type Marshalers struct {
+	Traces  TracesMarshaler
+	Metrics MetricsMarshaler
+	Logs    LogsMarshaler
+}
+
+var (
+	marshalersMu sync.RWMutex
+	marshalers   = map[string]Marshalers{}
+)
+
+func init() {
+	RegisterMarshalers(TextFormat, Marshalers{
+		Traces:  func(td pdata.Traces) string { return otlptext.Traces(td) },
+		Metrics: func(md pdata.Metrics) string { return otlptext.Metrics(md) },
+		Logs:    func(ld pdata.Logs) string { return otlptext.Logs(ld) },
+	})
+	RegisterMarshalers(JSONFormat, Marshalers{
+		Traces:  func(td pdata.Traces) string { return jsonString(td.ToOtlpJSONBytes()) },
+		Metrics: func(md pdata.Metrics) string { return jsonString(md.ToOtlpJSONBytes()) },
+		Logs:    func(ld pdata.Logs) string { return jsonString(ld.ToOtlpJSONBytes()) },
+	})
+	RegisterMarshalers(ProtoFormat, Marshalers{
+		Traces:  func(td pdata.Traces) string { return protoString(td.ToOtlpProtoBytes()) },
+		Metrics: func(md pdata.Metrics) string { return protoString(md.ToOtlpProtoBytes()) },
+		Logs:    func(ld pdata.Logs) string { return protoString(ld.ToOtlpProtoBytes()) },
+	})
+}
+
+// RegisterMarshalers makes the given marshalers available to the logging exporter
+// under the given format name. It is intended to be called from an init function
+// of custom distributions and panics if the format is already registered or if
+// any of the marshalers is nil.
+func RegisterMarshalers(format string, m Marshalers) {
+	if m.Traces == nil || m.Metrics == nil || m.Logs == nil {
+		panic(fmt.Sprintf("loggingexporter: nil marshaler for format %q", format))
+	}
+	marshalersMu.Lock()
+	defer marshalersMu.Unlock()
+	if _, ok := marshalers[format]; ok {
+		panic(fmt.Sprintf("loggingexporter: format %q already registered", format))
+	}
+	marshalers[format] = m
+}
+
+// getMarshalers returns the marshalers registered for the given format.
+func getMarshalers(format string) (Marshalers, error) {
+	marshalersMu.RLock()
+	defer marshalersMu.RUnlock()
+	m, ok := marshalers[format]
+	if !ok {
+		formats := make([]string, 0, len(marshalers))
+		for f := range marshalers {
+			formats = append(formats, f)
+		}
+		sort.Strings(formats)
+		return Marshalers{}, fmt.Errorf("unknown format %q, registered formats are %v", format, formats)
+	}
+	return m, nil
+}
+
+func jsonString(buf []byte, err error) string {
+	if err != nil {
+		return fmt.Sprintf("failed to marshal to JSON: %v", err)
+	}
+	return string(buf)
+}
+
+func protoString(buf []byte, err error) string {
+	if err != nil {
+		return fmt.Sprintf("failed to marshal to protobuf: %v", err)
+	}
+	return base64.StdEncoding.EncodeToString(buf)
+}
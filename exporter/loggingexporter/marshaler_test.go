@@ -0,0 +1,109 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package loggingexporter
+
+import (
+	"context"
+	"encoding/base64"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+
+	"go.opentelemetry.io/collector/consumer/pdata"
+	"go.opentelemetry.io/collector/internal/testdata"
+)
+
+func TestRegisterMarshalers(t *testing.T) {
+	m := Marshalers{
+		Traces:  func(td pdata.Traces) string { return "custom traces" },
+		Metrics: func(md pdata.Metrics) string { return "custom metrics" },
+		Logs:    func(ld pdata.Logs) string { return "custom logs" },
+	}
+	RegisterMarshalers("custom", m)
+	defer func() {
+		marshalersMu.Lock()
+		delete(marshalers, "custom")
+		marshalersMu.Unlock()
+	}()
+
+	assert.Panics(t, func() { RegisterMarshalers("custom", m) })
+	assert.Panics(t, func() { RegisterMarshalers("partial", Marshalers{Traces: m.Traces}) })
+
+	core, logs := observer.New(zapcore.DebugLevel)
+	cfg := newTestConfig("debug")
+	cfg.Format = "custom"
+	require.NoError(t, cfg.Validate())
+
+	te, err := newTracesExporter(cfg, zap.New(core))
+	require.NoError(t, err)
+	assert.NoError(t, te.ConsumeTraces(context.Background(), testdata.GenerateTracesOneSpan()))
+	me, err := newMetricsExporter(cfg, zap.New(core))
+	require.NoError(t, err)
+	assert.NoError(t, me.ConsumeMetrics(context.Background(), testdata.GenerateMetricsOneMetric()))
+	le, err := newLogsExporter(cfg, zap.New(core))
+	require.NoError(t, err)
+	assert.NoError(t, le.ConsumeLogs(context.Background(), testdata.GenerateLogsOneLogRecord()))
+
+	assert.Len(t, logs.FilterMessage("custom traces").All(), 1)
+	assert.Len(t, logs.FilterMessage("custom metrics").All(), 1)
+	assert.Len(t, logs.FilterMessage("custom logs").All(), 1)
+}
+
+func TestJSONFormat(t *testing.T) {
+	core, logs := observer.New(zapcore.DebugLevel)
+	cfg := newTestConfig("debug")
+	cfg.Format = JSONFormat
+
+	td := testdata.GenerateTracesOneSpan()
+	te, err := newTracesExporter(cfg, zap.New(core))
+	require.NoError(t, err)
+	assert.NoError(t, te.ConsumeTraces(context.Background(), td))
+
+	entries := logs.All()
+	require.Len(t, entries, 2)
+	got, err := pdata.TracesFromOtlpJSONBytes([]byte(entries[1].Message))
+	require.NoError(t, err)
+	assert.Equal(t, td, got)
+}
+
+func TestProtoFormat(t *testing.T) {
+	core, logs := observer.New(zapcore.DebugLevel)
+	cfg := newTestConfig("debug")
+	cfg.Format = ProtoFormat
+
+	td := testdata.GenerateTracesOneSpan()
+	te, err := newTracesExporter(cfg, zap.New(core))
+	require.NoError(t, err)
+	assert.NoError(t, te.ConsumeTraces(context.Background(), td))
+
+	entries := logs.All()
+	require.Len(t, entries, 2)
+	buf, err := base64.StdEncoding.DecodeString(entries[1].Message)
+	require.NoError(t, err)
+	got, err := pdata.TracesFromOtlpProtoBytes(buf)
+	require.NoError(t, err)
+	assert.Equal(t, td, got)
+}
+
+func TestUnknownFormat(t *testing.T) {
+	cfg := newTestConfig("debug")
+	cfg.Format = "unknown"
+	_, err := newTracesExporter(cfg, zap.NewNop())
+	assert.EqualError(t, err, `unknown format "unknown", registered formats are [json proto text]`)
+}
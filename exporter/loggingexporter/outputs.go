@@ -0,0 +1,153 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package loggingexporter
+
+import (
+	"context"
+	"os"
+	"sync"
+	"time"
+
+	"go.uber.org/multierr"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+
+	"go.opentelemetry.io/collector/component"
+)
+
+// outputs is the logger writing to the outputs of a configuration, along with the
+// files and syslog connections it writes to. The files are opened on Start, the
+// syslog connections on their first message, and all of them are closed on Shutdown.
+type outputs struct {
+	logger  *zap.Logger
+	files   []*outputFile
+	syslogs []*syslogWriter
+}
+
+var _ component.Component = (*outputs)(nil)
+
+func newOutputs(cfg *Config) (*outputs, error) {
+	o := &outputs{}
+	encoder := zapcore.NewConsoleEncoder(zap.NewDevelopmentEncoderConfig())
+	cores := make([]zapcore.Core, 0, len(cfg.Outputs))
+	for _, output := range cfg.Outputs {
+		var level zapcore.Level
+		if err := (&level).UnmarshalText([]byte(cfg.outputLogLevel(output))); err != nil {
+			return nil, err
+		}
+		if output.Syslog != nil {
+			core, w, err := newSyslogCore(output.Syslog, level)
+			if err != nil {
+				return nil, err
+			}
+			cores = append(cores, core)
+			o.syslogs = append(o.syslogs, w)
+			continue
+		}
+		var w zapcore.WriteSyncer
+		switch output.Path {
+		case "stdout":
+			w = zapcore.Lock(os.Stdout)
+		case "stderr":
+			w = zapcore.Lock(os.Stderr)
+		default:
+			f := &outputFile{path: output.Path}
+			o.files = append(o.files, f)
+			w = f
+		}
+		cores = append(cores, zapcore.NewCore(encoder, w, level))
+	}
+	core := zapcore.NewSamplerWithOptions(zapcore.NewTee(cores...), time.Second, cfg.SamplingInitial, cfg.SamplingThereafter)
+	o.logger = zap.New(core,
+		zap.Development(),
+		zap.AddCaller(),
+		zap.AddStacktrace(zapcore.WarnLevel),
+		zap.ErrorOutput(zapcore.Lock(os.Stderr)))
+	return o, nil
+}
+
+// Start opens the files of the outputs.
+func (o *outputs) Start(context.Context, component.Host) error {
+	for i, f := range o.files {
+		if err := f.open(); err != nil {
+			for _, opened := range o.files[:i] {
+				_ = opened.Close()
+			}
+			return err
+		}
+	}
+	return nil
+}
+
+// Shutdown closes the files and the syslog connections of the outputs.
+func (o *outputs) Shutdown(context.Context) error {
+	var err error
+	for _, f := range o.files {
+		err = multierr.Append(err, f.Close())
+	}
+	for _, w := range o.syslogs {
+		err = multierr.Append(err, w.Close())
+	}
+	return err
+}
+
+// outputFile is a zapcore.WriteSyncer appending to the file at path once it is
+// opened, the entries written before are dropped.
+type outputFile struct {
+	path string
+
+	mu   sync.Mutex
+	file *os.File
+}
+
+func (f *outputFile) open() error {
+	file, err := os.OpenFile(f.path, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0644)
+	if err != nil {
+		return err
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.file = file
+	return nil
+}
+
+func (f *outputFile) Write(p []byte) (int, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.file == nil {
+		return len(p), nil
+	}
+	return f.file.Write(p)
+}
+
+func (f *outputFile) Sync() error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.file == nil {
+		return nil
+	}
+	return f.file.Sync()
+}
+
+func (f *outputFile) Close() error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.file == nil {
+		return nil
+	}
+	err := f.file.Close()
+	f.file = nil
+	return err
+}
@@ -0,0 +1,350 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package loggingexporter
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+const (
+	defaultSyslogTransport = "udp"
+	defaultSyslogFacility  = "user"
+	defaultSyslogTag       = "otelcol"
+	// syslogTimeout bounds the connection to the syslog server and every write.
+	syslogTimeout = 5 * time.Second
+	// syslogMinBackoff and syslogMaxBackoff bound the delay before connecting again
+	// after a failed connection, the delay doubles after every failure.
+	syslogMinBackoff = time.Second
+	syslogMaxBackoff = time.Minute
+	// maxSyslogDatagramSize is the maximum size of the messages sent in a datagram,
+	// below the 65507 bytes limit of UDP to leave room for the header. The larger
+	// messages are split.
+	maxSyslogDatagramSize = 60000
+)
+
+// syslogFacilities are the syslog facilities by name, see RFC 5424.
+var syslogFacilities = map[string]int{
+	"kern":     0,
+	"user":     1,
+	"mail":     2,
+	"daemon":   3,
+	"auth":     4,
+	"syslog":   5,
+	"lpr":      6,
+	"news":     7,
+	"uucp":     8,
+	"cron":     9,
+	"authpriv": 10,
+	"ftp":      11,
+	"local0":   16,
+	"local1":   17,
+	"local2":   18,
+	"local3":   19,
+	"local4":   20,
+	"local5":   21,
+	"local6":   22,
+	"local7":   23,
+}
+
+// syslogSeverities are the syslog severities by name, see RFC 5424.
+var syslogSeverities = map[string]int{
+	"emerg":   0,
+	"alert":   1,
+	"crit":    2,
+	"err":     3,
+	"warning": 4,
+	"notice":  5,
+	"info":    6,
+	"debug":   7,
+}
+
+// defaultSyslogSeverities are the syslog severities of the messages of every log level.
+var defaultSyslogSeverities = map[zapcore.Level]int{
+	zapcore.DebugLevel:  syslogSeverities["debug"],
+	zapcore.InfoLevel:   syslogSeverities["info"],
+	zapcore.WarnLevel:   syslogSeverities["warning"],
+	zapcore.ErrorLevel:  syslogSeverities["err"],
+	zapcore.DPanicLevel: syslogSeverities["crit"],
+	zapcore.PanicLevel:  syslogSeverities["crit"],
+	zapcore.FatalLevel:  syslogSeverities["emerg"],
+}
+
+func (s *SyslogSettings) validate() error {
+	switch s.Transport {
+	case "", "udp", "tcp":
+	default:
+		return fmt.Errorf("syslog transport must be %q or %q, got %q", "udp", "tcp", s.Transport)
+	}
+	if _, ok := syslogFacilities[strings.ToLower(s.Facility)]; s.Facility != "" && !ok {
+		return fmt.Errorf("unknown syslog facility %q", s.Facility)
+	}
+	_, err := s.severities()
+	return err
+}
+
+// severities returns the syslog severities of the messages of every log level.
+func (s *SyslogSettings) severities() (map[zapcore.Level]int, error) {
+	severities := make(map[zapcore.Level]int, len(defaultSyslogSeverities))
+	for level, severity := range defaultSyslogSeverities {
+		severities[level] = severity
+	}
+	for levelName, severityName := range s.Severities {
+		var level zapcore.Level
+		if err := level.UnmarshalText([]byte(levelName)); err != nil {
+			return nil, err
+		}
+		severity, ok := syslogSeverities[strings.ToLower(severityName)]
+		if !ok {
+			return nil, fmt.Errorf("unknown syslog severity %q", severityName)
+		}
+		severities[level] = severity
+	}
+	return severities, nil
+}
+
+// newSyslogCore returns a core sending the entries at the given level or above to the
+// syslog destination, along with the writer to close on shutdown.
+func newSyslogCore(s *SyslogSettings, level zapcore.LevelEnabler) (zapcore.Core, *syslogWriter, error) {
+	severities, err := s.severities()
+	if err != nil {
+		return nil, nil, err
+	}
+	w := newSyslogWriter(s)
+	// The syslog messages carry their own timestamp.
+	encoderConfig := zap.NewDevelopmentEncoderConfig()
+	encoderConfig.TimeKey = ""
+	return &syslogCore{
+		LevelEnabler: level,
+		encoder:      zapcore.NewConsoleEncoder(encoderConfig),
+		writer:       w,
+		severities:   severities,
+	}, w, nil
+}
+
+// syslogCore is a zapcore.Core writing every entry as a syslog message, with the
+// severity of its level.
+type syslogCore struct {
+	zapcore.LevelEnabler
+	encoder    zapcore.Encoder
+	writer     *syslogWriter
+	severities map[zapcore.Level]int
+}
+
+func (c *syslogCore) With(fields []zapcore.Field) zapcore.Core {
+	clone := *c
+	clone.encoder = c.encoder.Clone()
+	for _, field := range fields {
+		field.AddTo(clone.encoder)
+	}
+	return &clone
+}
+
+func (c *syslogCore) Check(entry zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if c.Enabled(entry.Level) {
+		return ce.AddCore(entry, c)
+	}
+	return ce
+}
+
+func (c *syslogCore) Write(entry zapcore.Entry, fields []zapcore.Field) error {
+	buf, err := c.encoder.EncodeEntry(entry, fields)
+	if err != nil {
+		return err
+	}
+	defer buf.Free()
+	return c.writer.write(c.severities[entry.Level], bytes.TrimSuffix(buf.Bytes(), []byte("\n")))
+}
+
+func (c *syslogCore) Sync() error {
+	return nil
+}
+
+// syslogWriter writes syslog messages to a local or remote syslog destination. The
+// connection is established on the first write, and established again on the next
+// write after a failure, so a syslog server can be restarted.
+type syslogWriter struct {
+	network  string
+	address  string
+	facility int
+	tag      string
+	hostname string
+	pid      int
+
+	mu   sync.Mutex
+	conn net.Conn
+	// local is true if the connection is to the local syslog daemon.
+	local bool
+	// backoff is the delay before connecting again after a failed connection, and
+	// nextConnect the time it can be attempted.
+	backoff     time.Duration
+	nextConnect time.Time
+}
+
+func newSyslogWriter(s *SyslogSettings) *syslogWriter {
+	w := &syslogWriter{
+		network:  s.Transport,
+		address:  s.Endpoint,
+		facility: syslogFacilities[defaultSyslogFacility],
+		tag:      s.Tag,
+		pid:      os.Getpid(),
+	}
+	if w.network == "" {
+		w.network = defaultSyslogTransport
+	}
+	if s.Facility != "" {
+		w.facility = syslogFacilities[strings.ToLower(s.Facility)]
+	}
+	if w.tag == "" {
+		w.tag = defaultSyslogTag
+	}
+	w.hostname, _ = os.Hostname()
+	if w.hostname == "" {
+		w.hostname = "localhost"
+	}
+	return w
+}
+
+// write sends msg with the given severity, trying once more on a new connection if the
+// current one failed. After a failed connection, the messages are dropped until the
+// backoff delay elapses, so that the writes are not blocked by the connection timeout.
+func (w *syslogWriter) write(severity int, msg []byte) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.conn != nil {
+		if err := w.send(severity, msg); err == nil {
+			return nil
+		}
+		_ = w.conn.Close()
+		w.conn = nil
+	}
+	if now := time.Now(); now.Before(w.nextConnect) {
+		return fmt.Errorf("failed to write to syslog: connection backed off for %v", w.nextConnect.Sub(now))
+	}
+	if err := w.connect(); err != nil {
+		w.backoff *= 2
+		if w.backoff < syslogMinBackoff {
+			w.backoff = syslogMinBackoff
+		} else if w.backoff > syslogMaxBackoff {
+			w.backoff = syslogMaxBackoff
+		}
+		w.nextConnect = time.Now().Add(w.backoff)
+		return fmt.Errorf("failed to write to syslog: %w", err)
+	}
+	w.backoff = 0
+	if err := w.send(severity, msg); err != nil {
+		_ = w.conn.Close()
+		w.conn = nil
+		return fmt.Errorf("failed to write to syslog: %w", err)
+	}
+	return nil
+}
+
+// syslogSockets are the usual paths of the unix socket of the local syslog daemon.
+var syslogSockets = []string{"/dev/log", "/var/run/syslog", "/var/run/log"}
+
+func (w *syslogWriter) connect() error {
+	dialer := net.Dialer{Timeout: syslogTimeout}
+	if w.address != "" {
+		conn, err := dialer.Dial(w.network, w.address)
+		if err != nil {
+			return err
+		}
+		w.conn, w.local = conn, false
+		return nil
+	}
+	for _, network := range []string{"unixgram", "unix"} {
+		for _, path := range syslogSockets {
+			if conn, err := dialer.Dial(network, path); err == nil {
+				w.conn, w.local = conn, true
+				return nil
+			}
+		}
+	}
+	return errors.New("local syslog daemon not found")
+}
+
+// send sends msg, split in several messages if it does not fit in a datagram.
+func (w *syslogWriter) send(severity int, msg []byte) error {
+	if w.network == "tcp" && !w.local {
+		return w.sendMessage(severity, msg)
+	}
+	for _, part := range splitMessage(msg, maxSyslogDatagramSize) {
+		if err := w.sendMessage(severity, part); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// splitMessage splits msg in parts of at most size bytes, preferably at the end of
+// a line.
+func splitMessage(msg []byte, size int) [][]byte {
+	var parts [][]byte
+	for len(msg) > size {
+		if i := bytes.LastIndexByte(msg[:size], '\n'); i > 0 {
+			parts = append(parts, msg[:i])
+			msg = msg[i+1:]
+		} else {
+			parts = append(parts, msg[:size])
+			msg = msg[size:]
+		}
+	}
+	return append(parts, msg)
+}
+
+// sendMessage formats msg like log/syslog: the messages to a remote server carry an
+// RFC 3339 timestamp and the hostname. The messages sent over TCP are framed by octet
+// counting, see RFC 6587, so that the multi-line messages are kept whole.
+func (w *syslogWriter) sendMessage(severity int, msg []byte) error {
+	var b bytes.Buffer
+	pri := w.facility*8 + severity
+	if w.local {
+		fmt.Fprintf(&b, "<%d>%s %s[%d]: %s\n", pri, time.Now().Format(time.Stamp), w.tag, w.pid, msg)
+	} else {
+		fmt.Fprintf(&b, "<%d>%s %s %s[%d]: %s", pri, time.Now().Format(time.RFC3339), w.hostname, w.tag, w.pid, msg)
+	}
+	frame := b.Bytes()
+	if w.network == "tcp" && !w.local {
+		frame = append([]byte(strconv.Itoa(len(frame))+" "), frame...)
+	}
+	if err := w.conn.SetWriteDeadline(time.Now().Add(syslogTimeout)); err != nil {
+		return err
+	}
+	_, err := w.conn.Write(frame)
+	return err
+}
+
+// Close closes the connection to the syslog destination, if any.
+func (w *syslogWriter) Close() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.conn == nil {
+		return nil
+	}
+	err := w.conn.Close()
+	w.conn = nil
+	return err
+}
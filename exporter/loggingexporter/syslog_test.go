@@ -0,0 +1,190 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package loggingexporter
+
+import (
+	"bufio"
+	"context"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/internal/testdata"
+)
+
+func TestSyslogSettingsValidate(t *testing.T) {
+	assert.NoError(t, (&SyslogSettings{}).validate())
+	assert.NoError(t, (&SyslogSettings{Transport: "tcp", Facility: "LOCAL0", Severities: map[string]string{"debug": "NOTICE"}}).validate())
+	assert.EqualError(t, (&SyslogSettings{Transport: "quic"}).validate(), `syslog transport must be "udp" or "tcp", got "quic"`)
+	assert.EqualError(t, (&SyslogSettings{Facility: "local8"}).validate(), `unknown syslog facility "local8"`)
+	assert.EqualError(t, (&SyslogSettings{Severities: map[string]string{"info": "verbose"}}).validate(), `unknown syslog severity "verbose"`)
+	assert.Error(t, (&SyslogSettings{Severities: map[string]string{"verbose": "info"}}).validate())
+}
+
+func TestCreateTracesExporterSyslogOutput(t *testing.T) {
+	listener, err := net.ListenPacket("udp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer listener.Close()
+
+	factory := NewFactory()
+	cfg := factory.CreateDefaultConfig().(*Config)
+	cfg.Outputs = []OutputSettings{{
+		LogLevel: "debug",
+		Syslog: &SyslogSettings{
+			Endpoint:   listener.LocalAddr().String(),
+			Facility:   "local0",
+			Tag:        "collector",
+			Severities: map[string]string{"info": "notice"},
+		},
+	}}
+	require.NoError(t, cfg.Validate())
+
+	te, err := factory.CreateTracesExporter(context.Background(), component.ExporterCreateParams{Logger: zap.NewNop()}, cfg)
+	require.NoError(t, err)
+	require.NoError(t, te.Start(context.Background(), componenttest.NewNopHost()))
+	assert.NoError(t, te.ConsumeTraces(context.Background(), testdata.GenerateTracesOneSpan()))
+	assert.NoError(t, te.Shutdown(context.Background()))
+
+	readMessage := func() string {
+		buf := make([]byte, 65536)
+		require.NoError(t, listener.SetReadDeadline(time.Now().Add(5*time.Second)))
+		n, _, err := listener.ReadFrom(buf)
+		require.NoError(t, err)
+		return string(buf[:n])
+	}
+	// local0 is 16 and notice is 5.
+	summary := readMessage()
+	assert.True(t, strings.HasPrefix(summary, "<133>"), summary)
+	assert.Contains(t, summary, " collector[")
+	assert.Contains(t, summary, "TracesExporter")
+	// The rendering is sent whole, as a single multi-line message at debug severity.
+	detailed := readMessage()
+	assert.True(t, strings.HasPrefix(detailed, "<135>"), detailed)
+	assert.Contains(t, detailed, "ResourceSpans #0\n")
+	assert.Contains(t, detailed, "Span #0\n")
+}
+
+func TestSyslogWriterTCPReconnect(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer listener.Close()
+	conns := make(chan net.Conn, 2)
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			conns <- conn
+		}
+	}()
+	// readFrame reads an octet counted syslog message.
+	readFrame := func(r *bufio.Reader) string {
+		length, err := r.ReadString(' ')
+		require.NoError(t, err)
+		n, err := strconv.Atoi(strings.TrimSuffix(length, " "))
+		require.NoError(t, err)
+		msg := make([]byte, n)
+		_, err = io.ReadFull(r, msg)
+		require.NoError(t, err)
+		return string(msg)
+	}
+
+	w := newSyslogWriter(&SyslogSettings{Endpoint: listener.Addr().String(), Transport: "tcp"})
+	defer w.Close()
+	require.NoError(t, w.write(6, []byte("first\nmessage")))
+	conn := <-conns
+	msg := readFrame(bufio.NewReader(conn))
+	// user is 1 and info is 6.
+	assert.True(t, strings.HasPrefix(msg, "<14>"), msg)
+	assert.True(t, strings.HasSuffix(msg, " otelcol["+strconv.Itoa(w.pid)+"]: first\nmessage"), msg)
+
+	// The server drops the connection, the writes fail once it is noticed and the
+	// writer connects again.
+	require.NoError(t, conn.Close())
+	var second net.Conn
+	require.Eventually(t, func() bool {
+		_ = w.write(3, []byte("second"))
+		select {
+		case second = <-conns:
+			return true
+		default:
+			return false
+		}
+	}, 5*time.Second, 10*time.Millisecond)
+	defer second.Close()
+	assert.True(t, strings.HasSuffix(readFrame(bufio.NewReader(second)), "]: second"))
+}
+
+func TestSyslogWriterUDPSplit(t *testing.T) {
+	conn, err := net.ListenPacket("udp", "localhost:0")
+	require.NoError(t, err)
+	defer conn.Close()
+
+	w := newSyslogWriter(&SyslogSettings{Endpoint: conn.LocalAddr().String()})
+	defer w.Close()
+	line := strings.Repeat("a", 999) + "\n"
+	require.NoError(t, w.write(6, []byte(strings.Repeat(line, 100))))
+
+	// The 100000 bytes are split at the end of a line in two datagrams.
+	buf := make([]byte, 65536)
+	var lines int
+	for i := 0; i < 2; i++ {
+		require.NoError(t, conn.SetReadDeadline(time.Now().Add(5*time.Second)))
+		n, _, err := conn.ReadFrom(buf)
+		require.NoError(t, err)
+		assert.LessOrEqual(t, n, maxSyslogDatagramSize+100)
+		lines += strings.Count(string(buf[:n]), strings.Repeat("a", 999))
+	}
+	assert.Equal(t, 100, lines)
+}
+
+func TestSplitMessage(t *testing.T) {
+	assert.Equal(t, [][]byte{[]byte("ab")}, splitMessage([]byte("ab"), 2))
+	assert.Equal(t, [][]byte{[]byte("ab"), []byte("cd")}, splitMessage([]byte("ab\ncd"), 3))
+	assert.Equal(t, [][]byte{[]byte("abc"), []byte("d")}, splitMessage([]byte("abcd"), 3))
+}
+
+func TestSyslogWriterBackoff(t *testing.T) {
+	// A listener closed right away gives an address refusing the connections.
+	listener, err := net.Listen("tcp", "localhost:0")
+	require.NoError(t, err)
+	require.NoError(t, listener.Close())
+
+	w := newSyslogWriter(&SyslogSettings{Endpoint: listener.Addr().String(), Transport: "tcp"})
+	defer w.Close()
+	err = w.write(6, []byte("first"))
+	require.Error(t, err)
+	assert.NotContains(t, err.Error(), "backed off")
+	assert.Equal(t, syslogMinBackoff, w.backoff)
+
+	// The next write does not connect again until the backoff delay elapses.
+	err = w.write(6, []byte("second"))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "backed off")
+
+	w.nextConnect = time.Time{}
+	require.Error(t, w.write(6, []byte("third")))
+	assert.Equal(t, 2*syslogMinBackoff, w.backoff)
+}
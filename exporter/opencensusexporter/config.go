@@ -15,6 +15,11 @@
 package opencensusexporter
 
 import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
 	"go.opentelemetry.io/collector/config"
 	"go.opentelemetry.io/collector/config/configgrpc"
 	"go.opentelemetry.io/collector/exporter/exporterhelper"
@@ -29,11 +34,212 @@ type Config struct {
 
 	// The number of workers that send the gRPC requests.
 	NumWorkers int `mapstructure:"num_workers"`
+
+	// CompressionMinBytes is the minimum size in bytes of an export request for it
+	// to be sent compressed, smaller requests are sent uncompressed. Only applies
+	// when Compression is enabled. Zero (default) compresses all the requests.
+	CompressionMinBytes int `mapstructure:"compression_min_bytes"`
+
+	// MaxPayloadBytes is the maximum serialized size in bytes of an export request,
+	// before compression. Larger requests are split into multiple requests along span
+	// or metric boundaries, a single span or metric is never split. Zero (default)
+	// disables the splitting.
+	MaxPayloadBytes int `mapstructure:"max_payload_bytes"`
+
+	// IdleConnTimeout is the maximum amount of time the connection can stay without
+	// exports before it is closed and re-dialed, so that a fresh connection is ready
+	// for the next export. Zero (default) disables it.
+	IdleConnTimeout time.Duration `mapstructure:"idle_conn_timeout"`
+
+	// IdleWorkerTimeout is the maximum amount of time the RPC of a worker can stay
+	// without exports before it is closed, so that only the workers needed by the
+	// load keep an RPC. The RPC is created again on the next export of the worker,
+	// on the connection which is kept. Zero (default) disables it.
+	IdleWorkerTimeout time.Duration `mapstructure:"idle_worker_timeout"`
+
+	// MaxConnectionAge is the maximum amount of time a connection is used, plus or
+	// minus a 10% jitter, before it is replaced by a new connection, so that the
+	// exports are rebalanced across the backend replicas behind an L4 load balancer.
+	// The new connection is established before the old one is closed.
+	// Zero (default) disables it.
+	MaxConnectionAge time.Duration `mapstructure:"max_connection_age"`
+
+	// Warmup defines whether the RPCs of all the workers are created when the exporter
+	// starts, so that the first exports do not wait for the connection and the RPCs.
+	// The start succeeds even if the warmup fails.
+	Warmup bool `mapstructure:"warmup"`
+
+	// RampUp is the duration over which the number of workers exporting concurrently
+	// grows from 1 to NumWorkers when the exporter starts, to not overwhelm a cold
+	// backend. The exports beyond the current number of workers wait for a worker.
+	// Zero (default) disables it.
+	RampUp time.Duration `mapstructure:"ramp_up"`
+
+	// WorkerAssignment defines how the batches are assigned to the workers:
+	// "roundrobin" (default) sends each batch with the first available worker, and
+	// "hash" always sends the batches with the same resources with the same worker,
+	// for reproducible load tests and ordered delivery per resource.
+	WorkerAssignment string `mapstructure:"worker_assignment"`
+
+	// TracesCompression overrides Compression for the traces exporter.
+	// Set to "none" to disable the compression of traces.
+	TracesCompression string `mapstructure:"traces_compression"`
+
+	// MetricsCompression overrides Compression for the metrics exporter.
+	// Set to "none" to disable the compression of metrics.
+	MetricsCompression string `mapstructure:"metrics_compression"`
+
+	// PerAttemptTimeout is the maximum amount of time a single export attempt can
+	// take before the RPC is canceled and the attempt fails, so that it can be retried
+	// on a new RPC. If the exporter timeout is smaller it takes precedence.
+	// Zero (default) disables it.
+	PerAttemptTimeout time.Duration `mapstructure:"per_attempt_timeout"`
+
+	// DNS overrides how the host of the endpoint is resolved. By default the
+	// resolver of gRPC is used.
+	DNS DNSSettings `mapstructure:"dns"`
+
+	// TracesHeaders are sent with the traces requests in addition to the Headers,
+	// taking precedence over the Headers with the same name.
+	TracesHeaders map[string]string `mapstructure:"traces_headers"`
+
+	// MetricsHeaders are sent with the metrics requests in addition to the Headers,
+	// taking precedence over the Headers with the same name.
+	MetricsHeaders map[string]string `mapstructure:"metrics_headers"`
+
+	// ResourceAttributes are added to the resource of all the exported data, e.g. to
+	// stamp the environment or the region of the collector.
+	ResourceAttributes map[string]string `mapstructure:"resource_attributes"`
+
+	// ResourceAttributesConflict defines how the ResourceAttributes already set on a
+	// resource are handled: "keep" (default) keeps the existing value and "overwrite"
+	// replaces it.
+	ResourceAttributesConflict string `mapstructure:"resource_attributes_conflict"`
+
+	// TimestampSkew defines how the spans with timestamps too far from the current
+	// time are handled before being exported. Disabled by default.
+	TimestampSkew TimestampSkewSettings `mapstructure:"timestamp_skew"`
+
+	// RateLimit caps the rate of the export requests sent across all the workers,
+	// separately for traces and metrics. Disabled by default.
+	RateLimit RateLimitSettings `mapstructure:"rate_limit"`
 }
 
+// compressionNone is the per signal compression value that disables the compression.
+const compressionNone = "none"
+
 var _ config.Exporter = (*Config)(nil)
 
 // Validate checks if the exporter configuration is valid
 func (cfg *Config) Validate() error {
+	if err := cfg.GRPCClientSettings.Validate(); err != nil {
+		return err
+	}
+	if err := cfg.RetrySettings.Validate(); err != nil {
+		return fmt.Errorf("invalid retry_on_failure: %w", err)
+	}
+	if cfg.VerifyChecksum {
+		// The checksum is only attached to the unary RPCs, the OpenCensus protocol is streaming.
+		return errors.New("verify_checksum is not supported by the OpenCensus streaming RPCs")
+	}
+	if cfg.CompressionMinBytes < 0 {
+		return errors.New("compression_min_bytes must be non-negative")
+	}
+	if cfg.MaxPayloadBytes < 0 {
+		return errors.New("max_payload_bytes must be non-negative")
+	}
+	if cfg.IdleConnTimeout < 0 {
+		return errors.New("idle_conn_timeout must be non-negative")
+	}
+	if cfg.IdleWorkerTimeout < 0 {
+		return errors.New("idle_worker_timeout must be non-negative")
+	}
+	if cfg.MaxConnectionAge < 0 {
+		return errors.New("max_connection_age must be non-negative")
+	}
+	if cfg.RampUp < 0 {
+		return errors.New("ramp_up must be non-negative")
+	}
+	if err := validateWorkerAssignment(cfg.WorkerAssignment); err != nil {
+		return err
+	}
+	if cfg.PerAttemptTimeout < 0 {
+		return errors.New("per_attempt_timeout must be non-negative")
+	}
+	if err := cfg.DNS.validate(); err != nil {
+		return err
+	}
+	if err := cfg.TimestampSkew.validate(); err != nil {
+		return err
+	}
+	if err := cfg.RateLimit.validate(); err != nil {
+		return err
+	}
+	if err := validateResourceConflict(cfg.ResourceAttributesConflict); err != nil {
+		return err
+	}
+	if err := validateSignalHeaders(cfg.TracesHeaders); err != nil {
+		return fmt.Errorf("invalid traces_headers: %w", err)
+	}
+	if err := validateSignalHeaders(cfg.MetricsHeaders); err != nil {
+		return fmt.Errorf("invalid metrics_headers: %w", err)
+	}
+	if err := validateSignalCompression(cfg.TracesCompression); err != nil {
+		return fmt.Errorf("invalid traces_compression: %w", err)
+	}
+	if err := validateSignalCompression(cfg.MetricsCompression); err != nil {
+		return fmt.Errorf("invalid metrics_compression: %w", err)
+	}
 	return nil
 }
+
+var _ config.UnsetEnvValidatable = (*Config)(nil)
+
+// ValidateUnsetEnv rejects an endpoint referencing unset environment variables,
+// e.g. "${OTEL_BACKEND}:55678", instead of dialing the endpoint left by their
+// expansion to empty strings.
+func (cfg *Config) ValidateUnsetEnv(unset map[string][]string) error {
+	if names := unset["endpoint"]; len(names) > 0 {
+		return fmt.Errorf("endpoint references the unset environment variable %s", names[0])
+	}
+	return nil
+}
+
+func validateSignalCompression(compression string) error {
+	if compression == "" || compression == compressionNone {
+		return nil
+	}
+	if configgrpc.GetGRPCCompressionKey(compression) == configgrpc.CompressionUnsupported {
+		return fmt.Errorf("unsupported compression type %q", compression)
+	}
+	return nil
+}
+
+// reservedHeaders are the headers set by gRPC itself, which cannot be overridden.
+var reservedHeaders = map[string]bool{
+	"content-type": true,
+	"te":           true,
+	"user-agent":   true,
+}
+
+func validateSignalHeaders(headers map[string]string) error {
+	for name := range headers {
+		lowerName := strings.ToLower(name)
+		if reservedHeaders[lowerName] || strings.HasPrefix(lowerName, ":") || strings.HasPrefix(lowerName, "grpc-") {
+			return fmt.Errorf("reserved header %q", name)
+		}
+	}
+	return nil
+}
+
+// signalCompression returns the compression to be used for a signal, given its override.
+func (cfg *Config) signalCompression(override string) string {
+	switch override {
+	case "":
+		return cfg.Compression
+	case compressionNone:
+		return ""
+	default:
+		return override
+	}
+}
@@ -15,6 +15,7 @@
 package opencensusexporter
 
 import (
+	"os"
 	"path"
 	"testing"
 	"time"
@@ -49,10 +50,11 @@ func TestLoadConfig(t *testing.T) {
 		&Config{
 			ExporterSettings: config.NewExporterSettings(config.NewIDWithName(typeStr, "2")),
 			RetrySettings: exporterhelper.RetrySettings{
-				Enabled:         true,
-				InitialInterval: 10 * time.Second,
-				MaxInterval:     1 * time.Minute,
-				MaxElapsedTime:  10 * time.Minute,
+				Enabled:              true,
+				InitialInterval:      10 * time.Second,
+				MaxInterval:          1 * time.Minute,
+				MaxElapsedTime:       10 * time.Minute,
+				RetryableStatusCodes: []string{"UNAVAILABLE", "RESOURCE_EXHAUSTED"},
 			},
 			QueueSettings: exporterhelper.QueueSettings{
 				Enabled:      true,
@@ -78,9 +80,183 @@ func TestLoadConfig(t *testing.T) {
 					PermitWithoutStream: true,
 					Timeout:             30,
 				},
-				WriteBufferSize: 512 * 1024,
-				BalancerName:    "round_robin",
+				WriteBufferSize:   512 * 1024,
+				BalancerName:      "round_robin",
+				MaxRecvMsgSizeMiB: 16,
+				MaxSendMsgSizeMiB: 8,
+			},
+			NumWorkers:          123,
+			CompressionMinBytes: 1024,
+			MaxPayloadBytes:     4194304,
+			IdleConnTimeout:     5 * time.Minute,
+			IdleWorkerTimeout:   time.Minute,
+			MaxConnectionAge:    30 * time.Minute,
+			Warmup:              true,
+			RampUp:              30 * time.Second,
+			WorkerAssignment:    "hash",
+			TracesCompression:   "gzip",
+			MetricsCompression:  "none",
+			PerAttemptTimeout:   2 * time.Second,
+			DNS: DNSSettings{
+				Nameserver: "10.0.0.2:53",
+				Hosts:      map[string]string{"collector.internal": "10.1.2.3"},
+			},
+			TracesHeaders:  map[string]string{"x-route": "traces"},
+			MetricsHeaders: map[string]string{"x-route": "metrics"},
+			ResourceAttributes: map[string]string{
+				"deployment.environment": "production",
+				"cloud.region":           "us-east-1",
+			},
+			ResourceAttributesConflict: "overwrite",
+			TimestampSkew: TimestampSkewSettings{
+				MaxSkew: time.Hour,
+				Action:  "drop",
+			},
+			RateLimit: RateLimitSettings{
+				RequestsPerSecond: 100,
+				Burst:             20,
+				Action:            "drop",
 			},
-			NumWorkers: 123,
 		})
 }
+
+func TestLoadConfigEndpointFromEnv(t *testing.T) {
+	factories, err := componenttest.NopFactories()
+	assert.NoError(t, err)
+	factories.Exporters[typeStr] = NewFactory()
+
+	if value, ok := os.LookupEnv("OTEL_BACKEND"); ok {
+		t.Cleanup(func() { assert.NoError(t, os.Setenv("OTEL_BACKEND", value)) })
+	} else {
+		t.Cleanup(func() { assert.NoError(t, os.Unsetenv("OTEL_BACKEND")) })
+	}
+	require.NoError(t, os.Setenv("OTEL_BACKEND", "opencensus.backend"))
+	cfg, err := configtest.LoadConfigFile(t, path.Join(".", "testdata", "config_env.yaml"), factories)
+	require.NoError(t, err)
+	ocCfg := cfg.Exporters[config.NewID(typeStr)].(*Config)
+	assert.Equal(t, "opencensus.backend:55678", ocCfg.Endpoint)
+
+	require.NoError(t, os.Unsetenv("OTEL_BACKEND"))
+	_, err = configtest.LoadConfigFile(t, path.Join(".", "testdata", "config_env.yaml"), factories)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "endpoint references the unset environment variable OTEL_BACKEND")
+}
+
+func TestConfigValidate(t *testing.T) {
+	cfg := createDefaultConfig().(*Config)
+	assert.NoError(t, cfg.Validate())
+
+	cfg.CompressionMinBytes = -1
+	assert.Error(t, cfg.Validate())
+
+	cfg = createDefaultConfig().(*Config)
+	cfg.VerifyChecksum = true
+	assert.EqualError(t, cfg.Validate(), "verify_checksum is not supported by the OpenCensus streaming RPCs")
+
+	cfg = createDefaultConfig().(*Config)
+	cfg.Endpoint = "localhost:55678"
+	assert.NoError(t, cfg.Validate())
+	cfg.Endpoint = "dns:///opencensus.backend:55678"
+	assert.NoError(t, cfg.Validate())
+	cfg.Endpoint = ":55678"
+	assert.NoError(t, cfg.Validate())
+	assert.NoError(t, cfg.ValidateUnsetEnv(map[string][]string{"headers::tenant": {"TENANT"}}))
+	assert.EqualError(t, cfg.ValidateUnsetEnv(map[string][]string{"endpoint": {"OTEL_BACKEND"}}),
+		"endpoint references the unset environment variable OTEL_BACKEND")
+
+	cfg = createDefaultConfig().(*Config)
+	cfg.MaxPayloadBytes = -1
+	assert.Error(t, cfg.Validate())
+
+	cfg = createDefaultConfig().(*Config)
+	cfg.RetrySettings.RetryableStatusCodes = []string{"NOT_A_CODE"}
+	assert.Error(t, cfg.Validate())
+
+	cfg = createDefaultConfig().(*Config)
+	cfg.IdleConnTimeout = -time.Second
+	assert.Error(t, cfg.Validate())
+
+	cfg = createDefaultConfig().(*Config)
+	cfg.IdleWorkerTimeout = -time.Second
+	assert.Error(t, cfg.Validate())
+
+	cfg = createDefaultConfig().(*Config)
+	cfg.MaxConnectionAge = -time.Second
+	assert.Error(t, cfg.Validate())
+
+	cfg = createDefaultConfig().(*Config)
+	cfg.PerAttemptTimeout = -time.Second
+	assert.Error(t, cfg.Validate())
+
+	cfg = createDefaultConfig().(*Config)
+	cfg.MaxRecvMsgSizeMiB = -1
+	assert.Error(t, cfg.Validate())
+
+	cfg = createDefaultConfig().(*Config)
+	cfg.TimestampSkew.MaxSkew = -time.Minute
+	assert.Error(t, cfg.Validate())
+
+	cfg = createDefaultConfig().(*Config)
+	cfg.TimestampSkew.Action = "ignore"
+	assert.EqualError(t, cfg.Validate(), `timestamp_skew action must be "clamp" or "drop", got "ignore"`)
+
+	cfg = createDefaultConfig().(*Config)
+	cfg.RateLimit.RequestsPerSecond = -1
+	assert.EqualError(t, cfg.Validate(), "rate_limit requests_per_second must be non-negative")
+
+	cfg = createDefaultConfig().(*Config)
+	cfg.RateLimit.Burst = -1
+	assert.EqualError(t, cfg.Validate(), "rate_limit burst must be non-negative")
+
+	cfg = createDefaultConfig().(*Config)
+	cfg.RateLimit.Action = "reject"
+	assert.EqualError(t, cfg.Validate(), `rate_limit action must be "wait" or "drop", got "reject"`)
+
+	cfg = createDefaultConfig().(*Config)
+	cfg.RampUp = -time.Second
+	assert.EqualError(t, cfg.Validate(), "ramp_up must be non-negative")
+
+	cfg = createDefaultConfig().(*Config)
+	cfg.WorkerAssignment = "random"
+	assert.EqualError(t, cfg.Validate(), `worker_assignment must be "roundrobin" or "hash", got "random"`)
+
+	cfg = createDefaultConfig().(*Config)
+	cfg.ResourceAttributesConflict = "merge"
+	assert.EqualError(t, cfg.Validate(), `resource_attributes_conflict must be "keep" or "overwrite", got "merge"`)
+
+	cfg = createDefaultConfig().(*Config)
+	cfg.TracesHeaders = map[string]string{"x-route": "traces"}
+	cfg.MetricsHeaders = map[string]string{"x-route": "metrics"}
+	assert.NoError(t, cfg.Validate())
+	cfg.TracesHeaders = map[string]string{"Content-Type": "application/json"}
+	assert.EqualError(t, cfg.Validate(), `invalid traces_headers: reserved header "Content-Type"`)
+	cfg.TracesHeaders = nil
+	cfg.MetricsHeaders = map[string]string{"grpc-timeout": "1S"}
+	assert.EqualError(t, cfg.Validate(), `invalid metrics_headers: reserved header "grpc-timeout"`)
+
+	cfg = createDefaultConfig().(*Config)
+	cfg.DNS.Nameserver = "10.0.0.2"
+	assert.Error(t, cfg.Validate())
+
+	cfg = createDefaultConfig().(*Config)
+	cfg.TracesCompression = "gzip"
+	cfg.MetricsCompression = "none"
+	assert.NoError(t, cfg.Validate())
+
+	cfg.TracesCompression = "unknown"
+	assert.EqualError(t, cfg.Validate(), `invalid traces_compression: unsupported compression type "unknown"`)
+
+	cfg.TracesCompression = ""
+	cfg.MetricsCompression = "unknown"
+	assert.EqualError(t, cfg.Validate(), `invalid metrics_compression: unsupported compression type "unknown"`)
+}
+
+func TestConfigSignalCompression(t *testing.T) {
+	cfg := createDefaultConfig().(*Config)
+	cfg.Compression = "gzip"
+	assert.Equal(t, "gzip", cfg.signalCompression(""))
+	assert.Equal(t, "", cfg.signalCompression("none"))
+
+	cfg.Compression = ""
+	assert.Equal(t, "gzip", cfg.signalCompression("gzip"))
+}
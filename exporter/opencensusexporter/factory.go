@@ -17,6 +17,8 @@ package opencensusexporter
 import (
 	"context"
 
+	"go.uber.org/zap"
+
 	"go.opentelemetry.io/collector/component"
 	"go.opentelemetry.io/collector/config"
 	"go.opentelemetry.io/collector/config/configgrpc"
@@ -29,6 +31,20 @@ const (
 	typeStr = "opencensus"
 )
 
+// defaultRetryableStatusCodes are the gRPC status codes retried when
+// retryable_status_codes is not set, following the OTLP specification.
+var defaultRetryableStatusCodes = []string{
+	"CANCELLED",
+	"DEADLINE_EXCEEDED",
+	"PERMISSION_DENIED",
+	"UNAUTHENTICATED",
+	"RESOURCE_EXHAUSTED",
+	"ABORTED",
+	"OUT_OF_RANGE",
+	"UNAVAILABLE",
+	"DATA_LOSS",
+}
+
 // NewFactory creates a factory for OTLP exporter.
 func NewFactory() component.ExporterFactory {
 	return exporterhelper.NewFactory(
@@ -46,7 +62,15 @@ func createDefaultConfig() config.Exporter {
 			// We almost read 0 bytes, so no need to tune ReadBufferSize.
 			WriteBufferSize: 512 * 1024,
 		},
-		NumWorkers: 2,
+		NumWorkers:       2,
+		WorkerAssignment: workerAssignmentRoundRobin,
+		TimestampSkew: TimestampSkewSettings{
+			Action: skewActionClamp,
+		},
+		RateLimit: RateLimitSettings{
+			Action: rateLimitActionWait,
+		},
+		ResourceAttributesConflict: resourceConflictKeep,
 	}
 }
 
@@ -56,14 +80,17 @@ func createTracesExporter(ctx context.Context, params component.ExporterCreatePa
 	if err != nil {
 		return nil, err
 	}
+	oce.logger = params.Logger
+	warnLargeMsgSizes(params.Logger, oCfg.GRPCClientSettings)
 
 	return exporterhelper.NewTracesExporter(
 		cfg,
 		params.Logger,
 		oce.pushTraceData,
 		exporterhelper.WithCapabilities(consumer.Capabilities{MutatesData: false}),
-		exporterhelper.WithRetry(oCfg.RetrySettings),
+		exporterhelper.WithRetry(retrySettings(oCfg)),
 		exporterhelper.WithQueue(oCfg.QueueSettings),
+		exporterhelper.WithClock(oce.clock),
 		exporterhelper.WithStart(oce.start),
 		exporterhelper.WithShutdown(oce.shutdown))
 }
@@ -74,14 +101,40 @@ func createMetricsExporter(ctx context.Context, params component.ExporterCreateP
 	if err != nil {
 		return nil, err
 	}
+	oce.logger = params.Logger
+	warnLargeMsgSizes(params.Logger, oCfg.GRPCClientSettings)
 
 	return exporterhelper.NewMetricsExporter(
 		cfg,
 		params.Logger,
 		oce.pushMetricsData,
 		exporterhelper.WithCapabilities(consumer.Capabilities{MutatesData: false}),
-		exporterhelper.WithRetry(oCfg.RetrySettings),
+		exporterhelper.WithRetry(retrySettings(oCfg)),
 		exporterhelper.WithQueue(oCfg.QueueSettings),
+		exporterhelper.WithClock(oce.clock),
 		exporterhelper.WithStart(oce.start),
 		exporterhelper.WithShutdown(oce.shutdown))
 }
+
+// warnLargeMsgSizes logs a warning for every message size limit above the
+// configgrpc.MsgSizeWarningThresholdMiB.
+func warnLargeMsgSizes(logger *zap.Logger, gcs configgrpc.GRPCClientSettings) {
+	if gcs.MaxRecvMsgSizeMiB > configgrpc.MsgSizeWarningThresholdMiB {
+		logger.Warn("max_recv_msg_size_mib is unusually large, make sure it is in MiB",
+			zap.Int("max_recv_msg_size_mib", gcs.MaxRecvMsgSizeMiB))
+	}
+	if gcs.MaxSendMsgSizeMiB > configgrpc.MsgSizeWarningThresholdMiB {
+		logger.Warn("max_send_msg_size_mib is unusually large, make sure it is in MiB",
+			zap.Int("max_send_msg_size_mib", gcs.MaxSendMsgSizeMiB))
+	}
+}
+
+// retrySettings returns the RetrySettings of cfg, retrying the
+// defaultRetryableStatusCodes if no retryable status codes are configured.
+func retrySettings(cfg *Config) exporterhelper.RetrySettings {
+	rCfg := cfg.RetrySettings
+	if rCfg.RetryableStatusCodes == nil {
+		rCfg.RetryableStatusCodes = defaultRetryableStatusCodes
+	}
+	return rCfg
+}
@@ -22,6 +22,8 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
 
 	"go.opentelemetry.io/collector/component"
 	"go.opentelemetry.io/collector/component/componenttest"
@@ -29,7 +31,9 @@ import (
 	"go.opentelemetry.io/collector/config/configcheck"
 	"go.opentelemetry.io/collector/config/configgrpc"
 	"go.opentelemetry.io/collector/config/configtls"
+	"go.opentelemetry.io/collector/internal/testdata"
 	"go.opentelemetry.io/collector/testutil"
+	"go.opentelemetry.io/collector/testutil/octest"
 )
 
 func TestCreateDefaultConfig(t *testing.T) {
@@ -38,6 +42,27 @@ func TestCreateDefaultConfig(t *testing.T) {
 	assert.NoError(t, configcheck.ValidateConfig(cfg))
 }
 
+func TestCreateExporterWarnsLargeMsgSizes(t *testing.T) {
+	cfg := createDefaultConfig().(*Config)
+	cfg.Endpoint = "localhost:55678"
+	cfg.MaxRecvMsgSizeMiB = 16
+	core, logs := observer.New(zapcore.WarnLevel)
+	params := component.ExporterCreateParams{Logger: zap.New(core)}
+
+	_, err := createTracesExporter(context.Background(), params, cfg)
+	require.NoError(t, err)
+	assert.Equal(t, 0, logs.Len())
+
+	cfg.MaxRecvMsgSizeMiB = 4 * 1024 * 1024
+	cfg.MaxSendMsgSizeMiB = 1024
+	_, err = createMetricsExporter(context.Background(), params, cfg)
+	require.NoError(t, err)
+	entries := logs.TakeAll()
+	require.Len(t, entries, 2)
+	assert.Equal(t, int64(4*1024*1024), entries[0].ContextMap()["max_recv_msg_size_mib"])
+	assert.Equal(t, int64(1024), entries[1].ContextMap()["max_send_msg_size_mib"])
+}
+
 func TestCreateTracesExporter(t *testing.T) {
 	endpoint := testutil.GetAvailableLocalAddress(t)
 	tests := []struct {
@@ -110,6 +135,19 @@ func TestCreateTracesExporter(t *testing.T) {
 				NumWorkers: 3,
 			},
 		},
+		{
+			name: "PerSignalCompression",
+			config: Config{
+				ExporterSettings: config.NewExporterSettings(config.NewID(typeStr)),
+				GRPCClientSettings: configgrpc.GRPCClientSettings{
+					Endpoint:    endpoint,
+					Compression: configgrpc.CompressionGzip,
+				},
+				NumWorkers:         3,
+				TracesCompression:  configgrpc.CompressionGzip,
+				MetricsCompression: compressionNone,
+			},
+		},
 		{
 			name: "Headers",
 			config: Config{
@@ -182,6 +220,35 @@ func TestCreateTracesExporter(t *testing.T) {
 	}
 }
 
+func TestCreateExportersSendData(t *testing.T) {
+	srv, err := octest.NewMockServer()
+	require.NoError(t, err)
+	defer srv.Stop()
+
+	cfg := createDefaultConfig().(*Config)
+	cfg.GRPCClientSettings.Endpoint = srv.Endpoint()
+	cfg.GRPCClientSettings.TLSSetting.Insecure = true
+	params := component.ExporterCreateParams{Logger: zap.NewNop()}
+
+	tExporter, err := createTracesExporter(context.Background(), params, cfg)
+	require.NoError(t, err)
+	require.NoError(t, tExporter.Start(context.Background(), componenttest.NewNopHost()))
+	assert.NoError(t, tExporter.ConsumeTraces(context.Background(), testdata.GenerateTracesOneSpan()))
+	assert.Eventually(t, func() bool {
+		return srv.SpansCount() == 1
+	}, 10*time.Second, 5*time.Millisecond)
+	require.NoError(t, tExporter.Shutdown(context.Background()))
+
+	mExporter, err := createMetricsExporter(context.Background(), params, cfg)
+	require.NoError(t, err)
+	require.NoError(t, mExporter.Start(context.Background(), componenttest.NewNopHost()))
+	assert.NoError(t, mExporter.ConsumeMetrics(context.Background(), testdata.GenerateMetricsOneMetric()))
+	assert.Eventually(t, func() bool {
+		return srv.MetricsCount() == 1
+	}, 10*time.Second, 5*time.Millisecond)
+	require.NoError(t, mExporter.Shutdown(context.Background()))
+}
+
 func checkErrorsAndStartAndShutdown(t *testing.T, exporter component.Exporter, err error, mustFail, mustFailOnStart bool) {
 	if mustFail {
 		assert.NotNil(t, err)
@@ -198,3 +265,12 @@ func checkErrorsAndStartAndShutdown(t *testing.T, exporter component.Exporter, e
 	require.NoError(t, sErr)
 	require.NoError(t, exporter.Shutdown(context.Background()))
 }
+
+func TestRetrySettings(t *testing.T) {
+	cfg := createDefaultConfig().(*Config)
+	assert.Equal(t, defaultRetryableStatusCodes, retrySettings(cfg).RetryableStatusCodes)
+	assert.Nil(t, cfg.RetrySettings.RetryableStatusCodes)
+
+	cfg.RetrySettings.RetryableStatusCodes = []string{"UNAVAILABLE"}
+	assert.Equal(t, []string{"UNAVAILABLE"}, retrySettings(cfg).RetryableStatusCodes)
+}
@@ -0,0 +1,198 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package opencensusexporter
+
+import (
+	"context"
+	"strconv"
+	"sync/atomic"
+
+	"go.opencensus.io/stats"
+	"go.opencensus.io/stats/view"
+	"go.opencensus.io/tag"
+	"google.golang.org/grpc/encoding"
+	grpcstats "google.golang.org/grpc/stats"
+
+	"go.opentelemetry.io/collector/config"
+	"go.opentelemetry.io/collector/obsreport"
+)
+
+var (
+	tagKeyExporter = tag.MustNewKey(obsreport.ExporterKey)
+	tagKeyDataType = tag.MustNewKey("data_type")
+	tagKeyWorker   = tag.MustNewKey("worker")
+	tagKeyAction   = tag.MustNewKey("action")
+
+	mWorkerSentItems   = stats.Int64("opencensusexporter_worker_sent_items", "Number of spans or metric points successfully sent by a worker", stats.UnitDimensionless)
+	mWorkerFailedItems = stats.Int64("opencensusexporter_worker_failed_items", "Number of spans or metric points that a worker failed to send", stats.UnitDimensionless)
+	mBusyWorkers       = stats.Int64("opencensusexporter_busy_workers", "Number of workers currently sending data", stats.UnitDimensionless)
+	mSkewedSpans       = stats.Int64("opencensusexporter_skewed_spans", "Number of spans clamped or dropped because of timestamps exceeding the max skew", stats.UnitDimensionless)
+
+	mRateLimitedRequests = stats.Int64("opencensusexporter_rate_limited_requests", "Number of export requests delayed or dropped by the rate limit", stats.UnitDimensionless)
+
+	mUncompressedBytes = stats.Int64("opencensusexporter_uncompressed_bytes", "Size of the compressed export requests before compression", stats.UnitBytes)
+	mCompressedBytes   = stats.Int64("opencensusexporter_compressed_bytes", "Size of the compressed export requests after compression", stats.UnitBytes)
+)
+
+// grpcMessageHeaderLen is the size of the header of every gRPC message, made of the
+// compressed flag and the message length, included in the size of the messages on
+// the wire.
+const grpcMessageHeaderLen = 5
+
+// MetricViews returns the metrics views related to the workers, the skewed spans, the
+// rate limit and the compression of the exporter.
+func MetricViews() []*view.View {
+	workerTagKeys := []tag.Key{tagKeyExporter, tagKeyDataType, tagKeyWorker}
+	return []*view.View{
+		{
+			Name:        mWorkerSentItems.Name(),
+			Measure:     mWorkerSentItems,
+			Description: mWorkerSentItems.Description(),
+			TagKeys:     workerTagKeys,
+			Aggregation: view.Sum(),
+		},
+		{
+			Name:        mWorkerFailedItems.Name(),
+			Measure:     mWorkerFailedItems,
+			Description: mWorkerFailedItems.Description(),
+			TagKeys:     workerTagKeys,
+			Aggregation: view.Sum(),
+		},
+		{
+			Name:        mBusyWorkers.Name(),
+			Measure:     mBusyWorkers,
+			Description: mBusyWorkers.Description(),
+			TagKeys:     []tag.Key{tagKeyExporter, tagKeyDataType},
+			Aggregation: view.LastValue(),
+		},
+		{
+			Name:        mSkewedSpans.Name(),
+			Measure:     mSkewedSpans,
+			Description: mSkewedSpans.Description(),
+			TagKeys:     []tag.Key{tagKeyExporter, tagKeyAction},
+			Aggregation: view.Sum(),
+		},
+		{
+			Name:        mRateLimitedRequests.Name(),
+			Measure:     mRateLimitedRequests,
+			Description: mRateLimitedRequests.Description(),
+			TagKeys:     []tag.Key{tagKeyExporter, tagKeyDataType, tagKeyAction},
+			Aggregation: view.Sum(),
+		},
+		{
+			Name:        mUncompressedBytes.Name(),
+			Measure:     mUncompressedBytes,
+			Description: mUncompressedBytes.Description(),
+			TagKeys:     []tag.Key{tagKeyExporter, tagKeyDataType},
+			Aggregation: view.Sum(),
+		},
+		{
+			Name:        mCompressedBytes.Name(),
+			Measure:     mCompressedBytes,
+			Description: mCompressedBytes.Description(),
+			TagKeys:     []tag.Key{tagKeyExporter, tagKeyDataType},
+			Aggregation: view.Sum(),
+		},
+	}
+}
+
+// workerMetrics records the metrics of the workers of an exporter, which help
+// to detect unevenly distributed work or a wedged connection on one worker.
+type workerMetrics struct {
+	// busy is the number of workers currently sending data, accessed atomically.
+	busy int64
+	ctx  context.Context
+	// workerCtxs holds the context with the tags of every worker, by worker index.
+	workerCtxs []context.Context
+}
+
+func newWorkerMetrics(exporter config.ComponentID, dataType config.DataType, numWorkers int) *workerMetrics {
+	ctx, _ := tag.New(context.Background(),
+		tag.Upsert(tagKeyExporter, exporter.String()),
+		tag.Upsert(tagKeyDataType, string(dataType)))
+	wm := &workerMetrics{ctx: ctx}
+	for i := 0; i < numWorkers; i++ {
+		workerCtx, _ := tag.New(ctx, tag.Upsert(tagKeyWorker, strconv.Itoa(i)))
+		wm.workerCtxs = append(wm.workerCtxs, workerCtx)
+	}
+	return wm
+}
+
+// startSend must be called when a worker starts sending data.
+func (wm *workerMetrics) startSend() {
+	stats.Record(wm.ctx, mBusyWorkers.M(atomic.AddInt64(&wm.busy, 1)))
+}
+
+// endSend must be called when a worker finishes sending the given number of
+// items, that failed to be sent if err is not nil.
+func (wm *workerMetrics) endSend(worker int, numItems int, err error) {
+	if err != nil {
+		stats.Record(wm.workerCtxs[worker], mWorkerFailedItems.M(int64(numItems)))
+	} else {
+		stats.Record(wm.workerCtxs[worker], mWorkerSentItems.M(int64(numItems)))
+	}
+	stats.Record(wm.ctx, mBusyWorkers.M(atomic.AddInt64(&wm.busy, -1)))
+}
+
+// compressionStatsHandler is a gRPC stats handler recording the size of the compressed
+// export requests before and after compression, to compute the compression ratio. The
+// requests sent uncompressed, e.g. because of CompressionMinBytes, are not recorded.
+type compressionStatsHandler struct {
+	// ctx holds the exporter and data type tags.
+	ctx context.Context
+}
+
+var _ grpcstats.Handler = (*compressionStatsHandler)(nil)
+
+// rpcCompressionKey is the context key of the rpcCompression of an RPC.
+type rpcCompressionKey struct{}
+
+// rpcCompression holds whether the messages of an RPC are compressed, accessed atomically.
+type rpcCompression struct {
+	compressed int32
+}
+
+func newCompressionStatsHandler(ctx context.Context) *compressionStatsHandler {
+	return &compressionStatsHandler{ctx: ctx}
+}
+
+func (h *compressionStatsHandler) TagRPC(ctx context.Context, _ *grpcstats.RPCTagInfo) context.Context {
+	return context.WithValue(ctx, rpcCompressionKey{}, new(rpcCompression))
+}
+
+func (h *compressionStatsHandler) HandleRPC(ctx context.Context, s grpcstats.RPCStats) {
+	c, ok := ctx.Value(rpcCompressionKey{}).(*rpcCompression)
+	if !ok {
+		return
+	}
+	switch s := s.(type) {
+	case *grpcstats.OutHeader:
+		if s.Compression != "" && s.Compression != encoding.Identity {
+			atomic.StoreInt32(&c.compressed, 1)
+		}
+	case *grpcstats.OutPayload:
+		if atomic.LoadInt32(&c.compressed) == 1 {
+			stats.Record(h.ctx,
+				mUncompressedBytes.M(int64(s.Length)),
+				mCompressedBytes.M(int64(s.WireLength-grpcMessageHeaderLen)))
+		}
+	}
+}
+
+func (h *compressionStatsHandler) TagConn(ctx context.Context, _ *grpcstats.ConnTagInfo) context.Context {
+	return ctx
+}
+
+func (h *compressionStatsHandler) HandleConn(context.Context, grpcstats.ConnStats) {}
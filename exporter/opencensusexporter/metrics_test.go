@@ -0,0 +1,197 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package opencensusexporter
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opencensus.io/stats/view"
+	"go.opencensus.io/tag"
+
+	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/config"
+	"go.opentelemetry.io/collector/config/configgrpc"
+	"go.opentelemetry.io/collector/config/configtls"
+	"go.opentelemetry.io/collector/internal/testdata"
+	"go.opentelemetry.io/collector/testutil/octest"
+)
+
+func TestMetricViews(t *testing.T) {
+	expectedViewNames := []string{
+		"opencensusexporter_worker_sent_items",
+		"opencensusexporter_worker_failed_items",
+		"opencensusexporter_busy_workers",
+		"opencensusexporter_skewed_spans",
+		"opencensusexporter_rate_limited_requests",
+		"opencensusexporter_uncompressed_bytes",
+		"opencensusexporter_compressed_bytes",
+	}
+
+	views := MetricViews()
+	require.Len(t, views, len(expectedViewNames))
+	for i, viewName := range expectedViewNames {
+		assert.Equal(t, viewName, views[i].Name)
+	}
+}
+
+func TestWorkerMetrics(t *testing.T) {
+	views := MetricViews()
+	require.NoError(t, view.Register(views...))
+	defer view.Unregister(views...)
+
+	wm := newWorkerMetrics(config.NewIDWithName(typeStr, "worker_metrics"), config.TracesDataType, 2)
+	wm.startSend()
+	wm.startSend()
+	assertBusyWorkers(t, 2)
+	wm.endSend(0, 5, nil)
+	wm.endSend(1, 3, errors.New("my_error"))
+	assertBusyWorkers(t, 0)
+
+	wm.startSend()
+	wm.endSend(0, 2, nil)
+
+	assertWorkerItems(t, mWorkerSentItems.Name(), "0", 7)
+	assertWorkerItems(t, mWorkerFailedItems.Name(), "1", 3)
+	rows, err := view.RetrieveData(mWorkerSentItems.Name())
+	require.NoError(t, err)
+	assert.Len(t, rows, 1)
+}
+
+func TestSendTraces_WorkerMetrics(t *testing.T) {
+	views := MetricViews()
+	require.NoError(t, view.Register(views...))
+	defer view.Unregister(views...)
+
+	srv, err := octest.NewMockServer()
+	require.NoError(t, err)
+	defer srv.Stop()
+
+	cfg := createDefaultConfig().(*Config)
+	cfg.GRPCClientSettings = configgrpc.GRPCClientSettings{
+		Endpoint: srv.Endpoint(),
+		TLSSetting: configtls.TLSClientSetting{
+			Insecure: true,
+		},
+	}
+	cfg.NumWorkers = 1
+
+	exp, err := newTracesExporter(context.Background(), cfg)
+	require.NoError(t, err)
+	require.NoError(t, exp.start(context.Background(), componenttest.NewNopHost()))
+	t.Cleanup(func() {
+		assert.NoError(t, exp.shutdown(context.Background()))
+	})
+
+	assert.NoError(t, exp.pushTraceData(context.Background(), testdata.GenerateTracesTwoSpansSameResource()))
+	assert.NoError(t, exp.pushTraceData(context.Background(), testdata.GenerateTracesOneSpan()))
+	assertWorkerItems(t, mWorkerSentItems.Name(), "0", 3)
+	assertBusyWorkers(t, 0)
+}
+
+func TestSendTraces_CompressionMetrics(t *testing.T) {
+	views := MetricViews()
+	require.NoError(t, view.Register(views...))
+	defer view.Unregister(views...)
+
+	srv, err := octest.NewMockServer()
+	require.NoError(t, err)
+	defer srv.Stop()
+
+	cfg := createDefaultConfig().(*Config)
+	cfg.GRPCClientSettings = configgrpc.GRPCClientSettings{
+		Endpoint:    srv.Endpoint(),
+		Compression: configgrpc.CompressionGzip,
+		TLSSetting: configtls.TLSClientSetting{
+			Insecure: true,
+		},
+	}
+	cfg.NumWorkers = 1
+	cfg.CompressionMinBytes = 1024
+	cfg.MetricsCompression = compressionNone
+
+	tExp, err := newTracesExporter(context.Background(), cfg)
+	require.NoError(t, err)
+	require.NoError(t, tExp.start(context.Background(), componenttest.NewNopHost()))
+	t.Cleanup(func() {
+		assert.NoError(t, tExp.shutdown(context.Background()))
+	})
+
+	// The small request is sent uncompressed and not recorded.
+	assert.NoError(t, tExp.pushTraceData(context.Background(), testdata.GenerateTracesOneSpan()))
+	assert.Equal(t, int64(0), compressionBytes(t, mUncompressedBytes.Name(), config.TracesDataType))
+
+	assert.NoError(t, tExp.pushTraceData(context.Background(), testdata.GenerateTracesManySpansSameResource(100)))
+	assert.Eventually(t, func() bool {
+		return compressionBytes(t, mUncompressedBytes.Name(), config.TracesDataType) > 0
+	}, 10*time.Second, 5*time.Millisecond)
+	uncompressed := compressionBytes(t, mUncompressedBytes.Name(), config.TracesDataType)
+	compressed := compressionBytes(t, mCompressedBytes.Name(), config.TracesDataType)
+	assert.GreaterOrEqual(t, uncompressed, int64(1024))
+	assert.Greater(t, compressed, int64(0))
+	assert.Less(t, compressed, uncompressed)
+
+	// Nothing is recorded for a signal without compression.
+	mExp, err := newMetricsExporter(context.Background(), cfg)
+	require.NoError(t, err)
+	require.NoError(t, mExp.start(context.Background(), componenttest.NewNopHost()))
+	t.Cleanup(func() {
+		assert.NoError(t, mExp.shutdown(context.Background()))
+	})
+	assert.NoError(t, mExp.pushMetricsData(context.Background(), testdata.GenerateMetricsManyMetricsSameResource(100)))
+	assert.Eventually(t, func() bool {
+		return srv.MetricsCount() == 100
+	}, 10*time.Second, 5*time.Millisecond)
+	assert.Equal(t, int64(0), compressionBytes(t, mUncompressedBytes.Name(), config.MetricsDataType))
+}
+
+// compressionBytes returns the number of bytes recorded in the given view for the data type.
+func compressionBytes(t *testing.T, viewName string, dataType config.DataType) int64 {
+	rows, err := view.RetrieveData(viewName)
+	require.NoError(t, err)
+	for _, row := range rows {
+		for _, tg := range row.Tags {
+			if tg == (tag.Tag{Key: tagKeyDataType, Value: string(dataType)}) {
+				return int64(row.Data.(*view.SumData).Value)
+			}
+		}
+	}
+	return 0
+}
+
+func assertWorkerItems(t *testing.T, viewName string, worker string, expected int64) {
+	rows, err := view.RetrieveData(viewName)
+	require.NoError(t, err)
+	for _, row := range rows {
+		for _, tg := range row.Tags {
+			if tg == (tag.Tag{Key: tagKeyWorker, Value: worker}) {
+				assert.Equal(t, float64(expected), row.Data.(*view.SumData).Value)
+				return
+			}
+		}
+	}
+	assert.Fail(t, "no data for worker", worker)
+}
+
+func assertBusyWorkers(t *testing.T, expected int64) {
+	rows, err := view.RetrieveData(mBusyWorkers.Name())
+	require.NoError(t, err)
+	require.Len(t, rows, 1)
+	assert.Equal(t, float64(expected), rows[0].Data.(*view.LastValueData).Value)
+}
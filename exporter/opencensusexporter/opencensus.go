@@ -18,46 +18,120 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"io"
+	"math/rand"
+	"sync"
+	"sync/atomic"
+	"time"
 
 	commonpb "github.com/census-instrumentation/opencensus-proto/gen-go/agent/common/v1"
 	agentmetricspb "github.com/census-instrumentation/opencensus-proto/gen-go/agent/metrics/v1"
 	agenttracepb "github.com/census-instrumentation/opencensus-proto/gen-go/agent/trace/v1"
 	resourcepb "github.com/census-instrumentation/opencensus-proto/gen-go/resource/v1"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/connectivity"
+	"google.golang.org/grpc/encoding"
 	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/emptypb"
+
+	"go.uber.org/zap"
 
 	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/config"
+	"go.opentelemetry.io/collector/config/configtls"
+	"go.opentelemetry.io/collector/consumer/consumererror"
 	"go.opentelemetry.io/collector/consumer/pdata"
+	"go.opentelemetry.io/collector/exporter/exporterhelper"
 	"go.opentelemetry.io/collector/translator/internaldata"
 )
 
 // See https://godoc.org/google.golang.org/grpc#ClientConn.NewStream
 // why we need to keep the cancel func to cancel the stream
 type tracesClientWithCancel struct {
+	// worker is the index of the worker that owns this client, used to tag the worker metrics.
+	worker int
 	cancel context.CancelFunc
 	tsec   agenttracepb.TraceService_ExportClient
+	// rpcCtx is the context of the RPCs, canceled by cancel.
+	rpcCtx context.Context
+	// lastExport is the time of the last export on the RPC, or of its creation.
+	lastExport time.Time
+	// uncompressedTsec is only set when CompressionMinBytes applies, and is
+	// used to send the requests smaller than the threshold. It is opened with
+	// rpcCtx on the first request smaller than the threshold.
+	uncompressedTsec agenttracepb.TraceService_ExportClient
 }
 
 // See https://godoc.org/google.golang.org/grpc#ClientConn.NewStream
 // why we need to keep the cancel func to cancel the stream
 type metricsClientWithCancel struct {
+	// worker is the index of the worker that owns this client, used to tag the worker metrics.
+	worker int
 	cancel context.CancelFunc
 	msec   agentmetricspb.MetricsService_ExportClient
+	// rpcCtx is the context of the RPCs, canceled by cancel.
+	rpcCtx context.Context
+	// lastExport is the time of the last export on the RPC, or of its creation.
+	lastExport time.Time
+	// uncompressedMsec is only set when CompressionMinBytes applies, and is
+	// used to send the requests smaller than the threshold. It is opened with
+	// rpcCtx on the first request smaller than the threshold.
+	uncompressedMsec agentmetricspb.MetricsService_ExportClient
 }
 
 type ocExporter struct {
-	cfg *Config
+	// lastExportNanos is the unix time in nanoseconds of the last export, used
+	// to detect idle connections. Accessed atomically, keep it 64-bit aligned.
+	lastExportNanos int64
+
+	cfg    *Config
+	logger *zap.Logger
+	// compression is the compression used by this exporter, see Config.signalCompression.
+	compression   string
+	workerMetrics *workerMetrics
+	// timestampNormalizer is only set for the traces exporter.
+	timestampNormalizer *timestampNormalizer
+	resourceEnricher    *resourceEnricher
+	rateLimiter         *rateLimiter
+	// clock drives the rate limit, the rotation of the connection and the detection
+	// of the idle connection and workers.
+	clock exporterhelper.Clock
 	// gRPC clients and connection.
 	traceSvcClient   agenttracepb.TraceServiceClient
 	metricsSvcClient agentmetricspb.MetricsServiceClient
-	// In any of the channels we keep always NumWorkers object (sometimes nil),
-	// to make sure we don't open more than NumWorkers RPCs at any moment.
-	tracesClients  chan *tracesClientWithCancel
-	metricsClients chan *metricsClientWithCancel
+	// In any of the channels we keep always NumWorkers object (sometimes without RPC),
+	// to make sure we don't open more than NumWorkers RPCs at any moment, or twice
+	// as many when CompressionMinBytes applies since every worker then sends the
+	// small requests on a second, uncompressed, RPC opened lazily. There is
+	// a single channel shared by all the workers, or one channel per worker with the
+	// hash WorkerAssignment, see tracesChan and metricsChan.
+	tracesClients  []chan *tracesClientWithCancel
+	metricsClients []chan *metricsClientWithCancel
 	grpcClientConn *grpc.ClientConn
-	metadata       metadata.MD
+	dialOpts       []grpc.DialOption
+	// metadata holds the headers sent with every RPC of the signal.
+	metadata metadata.MD
+	// redialMu serializes the re-dials of the connection, idle or too old.
+	redialMu sync.Mutex
+	// Used to stop the goroutines that re-dial the connection.
+	stopCh chan struct{}
+	stopWg sync.WaitGroup
 }
 
+const (
+	// maxConnectionAgeJitter is the fraction of MaxConnectionAge added or removed at
+	// random to the age of every connection, so that the connections of several
+	// exporters started at the same time are not rotated at the same time.
+	maxConnectionAgeJitter = 0.1
+	// rotationConnectTimeout is the maximum amount of time to wait for a new
+	// connection to be ready when rotating the connection.
+	rotationConnectTimeout = 10 * time.Second
+)
+
 func newOcExporter(_ context.Context, cfg *Config) (*ocExporter, error) {
 	if cfg.Endpoint == "" {
 		return nil, errors.New("OpenCensus exporter cfg requires an Endpoint")
@@ -68,73 +142,476 @@ func newOcExporter(_ context.Context, cfg *Config) (*ocExporter, error) {
 	}
 
 	oce := &ocExporter{
-		cfg:      cfg,
-		metadata: metadata.New(cfg.GRPCClientSettings.Headers),
+		cfg:              cfg,
+		logger:           zap.NewNop(),
+		resourceEnricher: newResourceEnricher(cfg.ResourceAttributes, cfg.ResourceAttributesConflict),
+		clock:            exporterhelper.NewSystemClock(),
+		stopCh:           make(chan struct{}),
 	}
 	return oce, nil
 }
 
 // start creates the gRPC client Connection
 func (oce *ocExporter) start(ctx context.Context, host component.Host) error {
-	dialOpts, err := oce.cfg.GRPCClientSettings.ToDialOptions(host.GetExtensions())
+	grpcSettings := oce.cfg.GRPCClientSettings
+	grpcSettings.Compression = oce.compression
+	dialOpts, err := grpcSettings.ToDialOptions(host.GetExtensions())
 	if err != nil {
 		return err
 	}
-	var clientConn *grpc.ClientConn
-	if clientConn, err = grpc.DialContext(ctx, oce.cfg.GRPCClientSettings.Endpoint, dialOpts...); err != nil {
+	if oce.cfg.DNS.enabled() {
+		dialOpts = append(dialOpts, grpc.WithResolvers(newDNSResolverBuilder(oce.cfg.DNS, oce.clock)))
+	}
+	if oce.compression != "" {
+		dialOpts = append(dialOpts, grpc.WithStatsHandler(newCompressionStatsHandler(oce.workerMetrics.ctx)))
+	}
+	oce.dialOpts = dialOpts
+	if err = oce.dial(ctx); err != nil {
 		return err
 	}
 
-	oce.grpcClientConn = clientConn
+	oce.fillClients()
+	if oce.cfg.Warmup {
+		oce.warmup()
+	}
+	if oce.cfg.RampUp > 0 && oce.cfg.NumWorkers > 1 {
+		oce.stopWg.Add(1)
+		go oce.rampUp(oce.holdClients(oce.cfg.NumWorkers - 1))
+	}
+	oce.recordExport()
+	if oce.cfg.IdleConnTimeout > 0 {
+		oce.stopWg.Add(1)
+		go oce.redialIdleConn()
+	}
+	if oce.cfg.MaxConnectionAge > 0 {
+		oce.stopWg.Add(1)
+		go oce.rotateConn()
+	}
+	if oce.cfg.IdleWorkerTimeout > 0 {
+		oce.stopWg.Add(1)
+		go oce.closeIdleWorkers()
+	}
+	return nil
+}
 
+// dial creates the gRPC client connection and the service clients using it.
+func (oce *ocExporter) dial(ctx context.Context) error {
+	clientConn, err := oce.newClientConn(ctx)
+	if err != nil {
+		return err
+	}
+	oce.setClientConn(clientConn)
+	return nil
+}
+
+// newClientConn creates a new gRPC client connection to the endpoint.
+func (oce *ocExporter) newClientConn(ctx context.Context) (*grpc.ClientConn, error) {
+	target := oce.cfg.GRPCClientSettings.Endpoint
+	if oce.cfg.DNS.enabled() {
+		target = dnsScheme + ":///" + target
+	}
+	return oce.cfg.GRPCClientSettings.DialContext(ctx, target, oce.dialOpts...)
+}
+
+// setClientConn makes clientConn the connection used by the service clients.
+func (oce *ocExporter) setClientConn(clientConn *grpc.ClientConn) {
+	oce.grpcClientConn = clientConn
 	if oce.tracesClients != nil {
 		oce.traceSvcClient = agenttracepb.NewTraceServiceClient(oce.grpcClientConn)
-		// Try to create rpc clients now.
-		for i := 0; i < oce.cfg.NumWorkers; i++ {
-			// Populate the channel with NumWorkers nil RPCs to keep the number of workers
-			// constant in the channel.
-			oce.tracesClients <- nil
-		}
 	}
-
 	if oce.metricsClients != nil {
 		oce.metricsSvcClient = agentmetricspb.NewMetricsServiceClient(oce.grpcClientConn)
-		// Try to create rpc clients now.
-		for i := 0; i < oce.cfg.NumWorkers; i++ {
-			// Populate the channel with NumWorkers nil RPCs to keep the number of workers
-			// constant in the channel.
-			oce.metricsClients <- nil
+	}
+}
+
+// fillClients populates the channels with NumWorkers clients without RPC to keep
+// the number of workers constant in the channel. The RPCs are created on the first export.
+func (oce *ocExporter) fillClients() {
+	for i := 0; i < oce.cfg.NumWorkers; i++ {
+		if oce.tracesClients != nil {
+			oce.tracesChan(i) <- &tracesClientWithCancel{worker: i}
+		}
+		if oce.metricsClients != nil {
+			oce.metricsChan(i) <- &metricsClientWithCancel{worker: i}
 		}
 	}
-	return nil
 }
 
-func (oce *ocExporter) shutdown(context.Context) error {
+// newClientChans returns the number and the capacity of the channels of the clients,
+// a single channel shared by all the workers, or one channel per worker with the
+// hash WorkerAssignment.
+func (oce *ocExporter) newClientChans() (numChans, capacity int) {
+	if oce.cfg.WorkerAssignment == workerAssignmentHash {
+		return oce.cfg.NumWorkers, 1
+	}
+	return 1, oce.cfg.NumWorkers
+}
+
+// tracesChan returns the channel of the traces client of the worker.
+func (oce *ocExporter) tracesChan(worker int) chan *tracesClientWithCancel {
+	return oce.tracesClients[worker%len(oce.tracesClients)]
+}
+
+// metricsChan returns the channel of the metrics client of the worker.
+func (oce *ocExporter) metricsChan(worker int) chan *metricsClientWithCancel {
+	return oce.metricsClients[worker%len(oce.metricsClients)]
+}
+
+// warmup creates the RPCs of all the clients in the channels, which also waits for
+// the connection to be established. The clients whose RPC cannot be created are
+// put back without RPC, and create it on their first export as usual.
+func (oce *ocExporter) warmup() {
+	var failed int
+	var lastErr error
+	for i := 0; i < oce.cfg.NumWorkers; i++ {
+		if oce.tracesClients != nil {
+			tClient := <-oce.tracesChan(i)
+			if newClient, err := oce.createTraceServiceRPC(tClient.worker); err != nil {
+				failed++
+				lastErr = err
+			} else {
+				tClient = newClient
+			}
+			oce.tracesChan(i) <- tClient
+		}
+		if oce.metricsClients != nil {
+			mClient := <-oce.metricsChan(i)
+			if newClient, err := oce.createMetricsServiceRPC(mClient.worker); err != nil {
+				failed++
+				lastErr = err
+			} else {
+				mClient = newClient
+			}
+			oce.metricsChan(i) <- mClient
+		}
+	}
+	if failed > 0 {
+		oce.logger.Warn("Failed to warm up the RPCs of some workers, they will be created on the first export",
+			zap.Int("failed_workers", failed),
+			zap.Int("num_workers", oce.cfg.NumWorkers),
+			zap.Error(lastErr))
+	}
+}
+
+// heldClients are the clients removed from the channels by holdClients.
+type heldClients struct {
+	traces  []*tracesClientWithCancel
+	metrics []*metricsClientWithCancel
+}
+
+// holdClients removes the clients of the last n workers from the channels, to
+// limit the number of concurrent exports until they are released.
+func (oce *ocExporter) holdClients(n int) *heldClients {
+	held := &heldClients{}
+	for i := oce.cfg.NumWorkers - n; i < oce.cfg.NumWorkers; i++ {
+		if oce.tracesClients != nil {
+			held.traces = append(held.traces, <-oce.tracesChan(i))
+		}
+		if oce.metricsClients != nil {
+			held.metrics = append(held.metrics, <-oce.metricsChan(i))
+		}
+	}
+	return held
+}
+
+// releaseClient puts back one of the held clients in the channels.
+func (oce *ocExporter) releaseClient(held *heldClients) {
+	if len(held.traces) > 0 {
+		oce.tracesChan(held.traces[0].worker) <- held.traces[0]
+		held.traces = held.traces[1:]
+	}
+	if len(held.metrics) > 0 {
+		oce.metricsChan(held.metrics[0].worker) <- held.metrics[0]
+		held.metrics = held.metrics[1:]
+	}
+}
+
+// rampUp releases the held clients at regular intervals, so that all the NumWorkers
+// workers are available after RampUp. All the remaining clients are released when
+// the exporter stops, so that they can be drained.
+func (oce *ocExporter) rampUp(held *heldClients) {
+	defer oce.stopWg.Done()
+	interval := oce.cfg.RampUp / time.Duration(oce.cfg.NumWorkers-1)
+	for len(held.traces) > 0 || len(held.metrics) > 0 {
+		select {
+		case <-oce.stopCh:
+			for len(held.traces) > 0 || len(held.metrics) > 0 {
+				oce.releaseClient(held)
+			}
+			return
+		case <-oce.clock.After(interval):
+			oce.releaseClient(held)
+		}
+	}
+}
+
+// drainClients removes all the clients from the channels, waiting for the
+// in-flight exports to finish, and cancels the RPCs.
+func (oce *ocExporter) drainClients() {
+	for i := 0; i < oce.cfg.NumWorkers; i++ {
+		if oce.tracesClients != nil {
+			if tClient := <-oce.tracesChan(i); tClient.cancel != nil {
+				tClient.cancel()
+			}
+		}
+		if oce.metricsClients != nil {
+			if mClient := <-oce.metricsChan(i); mClient.cancel != nil {
+				mClient.cancel()
+			}
+		}
+	}
+}
+
+// closeIdleWorkers periodically closes the RPCs of the workers without exports for
+// IdleWorkerTimeout, so that the number of RPCs follows the load. The clients are
+// put back without RPC, and create it again on their next export, which only opens
+// a new stream on the existing connection.
+func (oce *ocExporter) closeIdleWorkers() {
+	defer oce.stopWg.Done()
+	// Checking every half timeout closes the RPCs at most 1.5 timeout after their last export.
+	for {
+		select {
+		case <-oce.stopCh:
+			return
+		case <-oce.clock.After(oce.cfg.IdleWorkerTimeout / 2):
+		}
+
+		oce.redialMu.Lock()
+		now := oce.clock.Now()
+		// Only the clients available in the channels are checked, without waiting for
+		// the clients in use, which are not idle. They are all taken before being put
+		// back, so that every client is checked once.
+		for _, clients := range oce.tracesClients {
+			var checked []*tracesClientWithCancel
+			for n := len(clients); n > 0; n-- {
+				var tClient *tracesClientWithCancel
+				select {
+				case tClient = <-clients:
+				default:
+				}
+				if tClient == nil {
+					break
+				}
+				if tClient.tsec != nil && now.Sub(tClient.lastExport) >= oce.cfg.IdleWorkerTimeout {
+					tClient.cancel()
+					tClient = &tracesClientWithCancel{worker: tClient.worker}
+				}
+				checked = append(checked, tClient)
+			}
+			for _, tClient := range checked {
+				clients <- tClient
+			}
+		}
+		for _, clients := range oce.metricsClients {
+			var checked []*metricsClientWithCancel
+			for n := len(clients); n > 0; n-- {
+				var mClient *metricsClientWithCancel
+				select {
+				case mClient = <-clients:
+				default:
+				}
+				if mClient == nil {
+					break
+				}
+				if mClient.msec != nil && now.Sub(mClient.lastExport) >= oce.cfg.IdleWorkerTimeout {
+					mClient.cancel()
+					mClient = &metricsClientWithCancel{worker: mClient.worker}
+				}
+				checked = append(checked, mClient)
+			}
+			for _, mClient := range checked {
+				clients <- mClient
+			}
+		}
+		oce.redialMu.Unlock()
+	}
+}
+
+func (oce *ocExporter) recordExport() {
+	atomic.StoreInt64(&oce.lastExportNanos, oce.clock.Now().UnixNano())
+}
+
+// redialIdleConn closes and re-dials the connection every time there are no
+// exports for IdleConnTimeout, since firewalls and NATs may silently drop idle
+// connections, making the next export fail.
+func (oce *ocExporter) redialIdleConn() {
+	defer oce.stopWg.Done()
+	wait := oce.cfg.IdleConnTimeout
+	for {
+		select {
+		case <-oce.stopCh:
+			return
+		case <-oce.clock.After(wait):
+		}
+
+		idle := oce.clock.Now().Sub(time.Unix(0, atomic.LoadInt64(&oce.lastExportNanos)))
+		if idle < oce.cfg.IdleConnTimeout {
+			wait = oce.cfg.IdleConnTimeout - idle
+			continue
+		}
+
+		oce.redialMu.Lock()
+		oce.drainClients()
+		oldConn := oce.grpcClientConn
+		// If the dial fails keep using the old connection, it will be retried after another timeout.
+		if err := oce.dial(context.Background()); err == nil {
+			_ = oldConn.Close()
+		}
+		oce.fillClients()
+		oce.redialMu.Unlock()
+		oce.recordExport()
+		wait = oce.cfg.IdleConnTimeout
+	}
+}
+
+// rotateConn replaces the connection every MaxConnectionAge, with jitter, so that
+// the exports are rebalanced across the backend replicas behind an L4 load balancer.
+// The new connection is established before the old one is closed, so the exports
+// only wait for the in-flight exports to finish while the connection is replaced.
+func (oce *ocExporter) rotateConn() {
+	defer oce.stopWg.Done()
+	for {
+		select {
+		case <-oce.stopCh:
+			return
+		case <-oce.clock.After(jitterConnectionAge(oce.cfg.MaxConnectionAge)):
+		}
+
+		// If the new connection cannot be established keep using the old connection,
+		// the rotation is retried after another MaxConnectionAge.
+		if newConn, err := oce.newReadyClientConn(); err != nil {
+			oce.logger.Warn("Failed to rotate the connection, keeping the current one", zap.Error(err))
+		} else {
+			oce.redialMu.Lock()
+			oce.drainClients()
+			oldConn := oce.grpcClientConn
+			oce.setClientConn(newConn)
+			oce.fillClients()
+			oce.redialMu.Unlock()
+			_ = oldConn.Close()
+		}
+	}
+}
+
+// newReadyClientConn creates a new gRPC client connection and waits for it to be
+// ready, for at most rotationConnectTimeout or until the exporter stops.
+func (oce *ocExporter) newReadyClientConn() (*grpc.ClientConn, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), rotationConnectTimeout)
+	defer cancel()
+	go func() {
+		select {
+		case <-oce.stopCh:
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+
+	clientConn, err := oce.newClientConn(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for state := clientConn.GetState(); state != connectivity.Ready; state = clientConn.GetState() {
+		if !clientConn.WaitForStateChange(ctx, state) {
+			_ = clientConn.Close()
+			return nil, fmt.Errorf("new connection not ready: %w", ctx.Err())
+		}
+	}
+	return clientConn, nil
+}
+
+// jitterConnectionAge returns maxAge plus or minus at most maxConnectionAgeJitter of it.
+func jitterConnectionAge(maxAge time.Duration) time.Duration {
+	jitter := maxConnectionAgeJitter * float64(maxAge) * (2*rand.Float64() - 1)
+	return maxAge + time.Duration(jitter)
+}
+
+// shutdown is called by the exporterhelper once the sending queue is drained, so no
+// export is in progress. The streams are closed gracefully, waiting for the server to
+// receive the data sent on them, before the connection is closed, since a successful
+// Send only means that the data was buffered.
+func (oce *ocExporter) shutdown(ctx context.Context) error {
+	close(oce.stopCh)
+	oce.stopWg.Wait()
+	ctx, cancel := context.WithTimeout(ctx, closeStreamsTimeout)
+	defer cancel()
 	if oce.tracesClients != nil {
-		// First remove all the clients from the channel.
+		// First remove all the clients from the channels.
 		for i := 0; i < oce.cfg.NumWorkers; i++ {
-			<-oce.tracesClients
+			tClient := <-oce.tracesChan(i)
+			closeStreams(ctx, tClient.cancel, tClient.tsec, tClient.uncompressedTsec)
+		}
+		// Now close the channels
+		for _, clients := range oce.tracesClients {
+			close(clients)
 		}
-		// Now close the channel
-		close(oce.tracesClients)
 	}
 	if oce.metricsClients != nil {
-		// First remove all the clients from the channel.
+		// First remove all the clients from the channels.
 		for i := 0; i < oce.cfg.NumWorkers; i++ {
-			<-oce.metricsClients
+			mClient := <-oce.metricsChan(i)
+			closeStreams(ctx, mClient.cancel, mClient.msec, mClient.uncompressedMsec)
+		}
+		// Now close the channels
+		for _, clients := range oce.metricsClients {
+			close(clients)
 		}
-		// Now close the channel
-		close(oce.metricsClients)
 	}
 	return oce.grpcClientConn.Close()
 }
 
+// closeStreamsTimeout bounds the wait for the server to end the streams at shutdown.
+const closeStreamsTimeout = 5 * time.Second
+
+// closeStreams half-closes the streams and waits for the server to end them, or for
+// ctx to be done, then cancels them. The nil streams are skipped.
+func closeStreams(ctx context.Context, cancel context.CancelFunc, streams ...grpc.ClientStream) {
+	var wg sync.WaitGroup
+	for _, stream := range streams {
+		if stream == nil {
+			continue
+		}
+		wg.Add(1)
+		go func(stream grpc.ClientStream) {
+			defer wg.Done()
+			if stream.CloseSend() != nil {
+				return
+			}
+			// The responses of the OpenCensus export RPCs are empty, RecvMsg returns
+			// io.EOF once the server processed all the requests and ended the stream.
+			for stream.RecvMsg(&emptypb.Empty{}) == nil {
+			}
+		}(stream)
+	}
+	done := make(chan struct{})
+	go func() {
+		wg.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-ctx.Done():
+	}
+	if cancel != nil {
+		cancel()
+	}
+	// Canceling the streams unblocks the pending RecvMsg calls.
+	<-done
+}
+
 func newTracesExporter(ctx context.Context, cfg *Config) (*ocExporter, error) {
 	oce, err := newOcExporter(ctx, cfg)
 	if err != nil {
 		return nil, err
 	}
-	oce.tracesClients = make(chan *tracesClientWithCancel, oce.cfg.NumWorkers)
+	numChans, capacity := oce.newClientChans()
+	for i := 0; i < numChans; i++ {
+		oce.tracesClients = append(oce.tracesClients, make(chan *tracesClientWithCancel, capacity))
+	}
+	oce.workerMetrics = newWorkerMetrics(cfg.ID(), config.TracesDataType, cfg.NumWorkers)
+	oce.timestampNormalizer = newTimestampNormalizer(cfg.ID(), cfg.TimestampSkew, oce.clock)
+	oce.rateLimiter = newRateLimiter(cfg.ID(), config.TracesDataType, cfg.RateLimit, oce.clock)
+	oce.metadata = mergeHeaders(cfg.Headers, cfg.TracesHeaders)
+	oce.compression = cfg.signalCompression(cfg.TracesCompression)
 	return oce, nil
 }
 
@@ -143,32 +620,130 @@ func newMetricsExporter(ctx context.Context, cfg *Config) (*ocExporter, error) {
 	if err != nil {
 		return nil, err
 	}
-	oce.metricsClients = make(chan *metricsClientWithCancel, oce.cfg.NumWorkers)
+	numChans, capacity := oce.newClientChans()
+	for i := 0; i < numChans; i++ {
+		oce.metricsClients = append(oce.metricsClients, make(chan *metricsClientWithCancel, capacity))
+	}
+	oce.workerMetrics = newWorkerMetrics(cfg.ID(), config.MetricsDataType, cfg.NumWorkers)
+	oce.rateLimiter = newRateLimiter(cfg.ID(), config.MetricsDataType, cfg.RateLimit, oce.clock)
+	oce.compression = cfg.signalCompression(cfg.MetricsCompression)
+	oce.metadata = mergeHeaders(cfg.Headers, cfg.MetricsHeaders)
 	return oce, nil
 }
 
-func (oce *ocExporter) pushTraceData(_ context.Context, td pdata.Traces) error {
-	// Get first available trace Client.
-	tClient, ok := <-oce.tracesClients
+// streamError returns the error of a failed Send. Send returns io.EOF when the stream
+// was closed by the server, in that case the status sent by the server is received
+// with recv. A RESOURCE_EXHAUSTED status with a retry delay is returned as an
+// exporterhelper.ThrottleError, so that the retry waits for the delay requested by the server.
+func streamError(err error, recv func() error) error {
+	if err == io.EOF {
+		if recvErr := recv(); recvErr != nil && recvErr != io.EOF {
+			err = recvErr
+		}
+	}
+	st, ok := status.FromError(err)
+	if !ok || st.Code() != codes.ResourceExhausted {
+		return err
+	}
+	for _, detail := range st.Details() {
+		if retryInfo, ok := detail.(*errdetails.RetryInfo); ok && retryInfo.RetryDelay.AsDuration() > 0 {
+			return exporterhelper.NewThrottleRetry(err, retryInfo.RetryDelay.AsDuration())
+		}
+	}
+	return err
+}
+
+// mergeHeaders returns the metadata with the base headers overridden by the signal headers.
+func mergeHeaders(base, signal map[string]string) metadata.MD {
+	md := metadata.New(base)
+	for name, value := range signal {
+		md.Set(name, value)
+	}
+	return md
+}
+
+// attemptContext returns the context of an export attempt, bounded by the
+// PerAttemptTimeout in addition to any deadline already set in ctx.
+func (oce *ocExporter) attemptContext(ctx context.Context) (context.Context, context.CancelFunc) {
+	if oce.cfg.PerAttemptTimeout <= 0 {
+		return ctx, func() {}
+	}
+	return context.WithTimeout(ctx, oce.cfg.PerAttemptTimeout)
+}
+
+// cancelOnDone calls cancelRPC if ctx is done before the returned stop func is
+// called, since the RPCs are long lived streams and a deadline cannot be set on
+// every sent message. The stop func returns true if cancelRPC was called.
+func (oce *ocExporter) cancelOnDone(ctx context.Context, cancelRPC context.CancelFunc) (stop func() bool) {
+	if oce.cfg.PerAttemptTimeout <= 0 {
+		return func() bool { return false }
+	}
+	doneCh := make(chan struct{})
+	canceledCh := make(chan bool, 1)
+	go func() {
+		select {
+		case <-ctx.Done():
+			cancelRPC()
+			canceledCh <- true
+		case <-doneCh:
+			canceledCh <- false
+		}
+	}()
+	return func() bool {
+		close(doneCh)
+		return <-canceledCh
+	}
+}
+
+func (oce *ocExporter) pushTraceData(ctx context.Context, td pdata.Traces) error {
+	td = oce.timestampNormalizer.normalize(td)
+	td = oce.resourceEnricher.enrichTraces(td)
+	if err := oce.rateLimiter.acquire(ctx); err != nil {
+		return err
+	}
+
+	// Get first available trace Client, or the client of the worker assigned to td.
+	var worker int
+	if oce.cfg.WorkerAssignment == workerAssignmentHash {
+		worker = tracesWorker(td, oce.cfg.NumWorkers)
+	}
+	tClient, ok := <-oce.tracesChan(worker)
 	if !ok {
 		err := errors.New("failed to push traces, OpenCensus exporter was already stopped")
 		return err
 	}
 
-	// In any of the metricsClients channel we keep always NumWorkers object (sometimes nil),
+	oce.workerMetrics.startSend()
+	tClient, err := oce.exportTraces(ctx, tClient, td)
+	oce.workerMetrics.endSend(tClient.worker, td.SpanCount(), err)
+	oce.tracesChan(tClient.worker) <- tClient
+	if err != nil {
+		return err
+	}
+	oce.recordExport()
+	return nil
+}
+
+// exportTraces sends td using the RPC of tClient, and returns the client that
+// must be put back in the channel, without RPC if the RPC failed.
+func (oce *ocExporter) exportTraces(ctx context.Context, tClient *tracesClientWithCancel, td pdata.Traces) (*tracesClientWithCancel, error) {
+	// In any of the tracesClients channel we keep always NumWorkers object (sometimes without RPC),
 	// to make sure we don't open more than NumWorkers RPCs at any moment.
-	// Here check if the client is nil and create a new one if that is the case. A nil
-	// object means that an error happened: could not connect, service went down, etc.
-	if tClient == nil {
-		var err error
-		tClient, err = oce.createTraceServiceRPC()
+	// Here check if the client has an RPC and create a new one if that is not the case. A missing
+	// RPC means that an error happened: could not connect, service went down, etc.
+	if tClient.tsec == nil {
+		newClient, err := oce.createTraceServiceRPC(tClient.worker)
 		if err != nil {
-			// Cannot create an RPC, put back nil to keep the number of workers constant.
-			oce.tracesClients <- nil
-			return err
+			// Cannot create an RPC, put back the client to keep the number of workers constant.
+			return tClient, err
 		}
+		tClient = newClient
 	}
 
+	ctx, cancel := oce.attemptContext(ctx)
+	defer cancel()
+	stop := oce.cancelOnDone(ctx, tClient.cancel)
+
 	rss := td.ResourceSpans()
 	for i := 0; i < rss.Len(); i++ {
 		node, resource, spans := internaldata.ResourceSpansToOC(rss.At(i))
@@ -184,40 +759,91 @@ func (oce *ocExporter) pushTraceData(_ context.Context, td pdata.Traces) error {
 			Resource: resource,
 			Node:     node,
 		}
-		if err := tClient.tsec.Send(req); err != nil {
-			// Error received, cancel the context used to create the RPC to free all resources,
-			// put back nil to keep the number of workers constant.
-			tClient.cancel()
-			oce.tracesClients <- nil
-			return err
+		// sent is the number of spans of the resource spans already sent.
+		sent := 0
+		for _, req := range splitTraceRequest(req, oce.cfg.MaxPayloadBytes) {
+			tsec, err := oce.tracesStream(tClient, req)
+			if err == nil {
+				if err = tsec.Send(req); err != nil {
+					err = streamError(err, func() error {
+						_, recvErr := tsec.Recv()
+						return recvErr
+					})
+				}
+			}
+			if err != nil {
+				// Error received, cancel the context used to create the RPC to free all resources,
+				// put back a client without RPC to keep the number of workers constant.
+				if stop() {
+					err = fmt.Errorf("export attempt canceled: %w", ctx.Err())
+				}
+				tClient.cancel()
+				if i > 0 || sent > 0 {
+					// Only the spans not sent yet must be retried.
+					err = consumererror.NewTraces(err, unsentTraces(td, i, sent))
+				}
+				return &tracesClientWithCancel{worker: tClient.worker}, err
+			}
+			sent += len(req.Spans)
 		}
 	}
-	oce.tracesClients <- tClient
-	return nil
+	if stop() {
+		// The RPC was canceled after the last message was sent, it cannot be reused.
+		return &tracesClientWithCancel{worker: tClient.worker}, fmt.Errorf("export attempt canceled: %w", ctx.Err())
+	}
+	tClient.lastExport = oce.clock.Now()
+	return tClient, nil
 }
 
-func (oce *ocExporter) pushMetricsData(_ context.Context, md pdata.Metrics) error {
-	// Get first available mClient.
-	mClient, ok := <-oce.metricsClients
+func (oce *ocExporter) pushMetricsData(ctx context.Context, md pdata.Metrics) error {
+	md = oce.resourceEnricher.enrichMetrics(md)
+	if err := oce.rateLimiter.acquire(ctx); err != nil {
+		return err
+	}
+
+	// Get first available mClient, or the client of the worker assigned to md.
+	var worker int
+	if oce.cfg.WorkerAssignment == workerAssignmentHash {
+		worker = metricsWorker(md, oce.cfg.NumWorkers)
+	}
+	mClient, ok := <-oce.metricsChan(worker)
 	if !ok {
 		err := errors.New("failed to push metrics, OpenCensus exporter was already stopped")
 		return err
 	}
 
-	// In any of the metricsClients channel we keep always NumWorkers object (sometimes nil),
+	oce.workerMetrics.startSend()
+	mClient, err := oce.exportMetrics(ctx, mClient, md)
+	_, numPoints := md.MetricAndDataPointCount()
+	oce.workerMetrics.endSend(mClient.worker, numPoints, err)
+	oce.metricsChan(mClient.worker) <- mClient
+	if err != nil {
+		return err
+	}
+	oce.recordExport()
+	return nil
+}
+
+// exportMetrics sends md using the RPC of mClient, and returns the client that
+// must be put back in the channel, without RPC if the RPC failed.
+func (oce *ocExporter) exportMetrics(ctx context.Context, mClient *metricsClientWithCancel, md pdata.Metrics) (*metricsClientWithCancel, error) {
+	// In any of the metricsClients channel we keep always NumWorkers object (sometimes without RPC),
 	// to make sure we don't open more than NumWorkers RPCs at any moment.
-	// Here check if the client is nil and create a new one if that is the case. A nil
-	// object means that an error happened: could not connect, service went down, etc.
-	if mClient == nil {
-		var err error
-		mClient, err = oce.createMetricsServiceRPC()
+	// Here check if the client has an RPC and create a new one if that is not the case. A missing
+	// RPC means that an error happened: could not connect, service went down, etc.
+	if mClient.msec == nil {
+		newClient, err := oce.createMetricsServiceRPC(mClient.worker)
 		if err != nil {
-			// Cannot create an RPC, put back nil to keep the number of workers constant.
-			oce.metricsClients <- nil
-			return err
+			// Cannot create an RPC, put back the client to keep the number of workers constant.
+			return mClient, err
 		}
+		mClient = newClient
 	}
 
+	ctx, cancel := oce.attemptContext(ctx)
+	defer cancel()
+	stop := oce.cancelOnDone(ctx, mClient.cancel)
+
 	rms := md.ResourceMetrics()
 	for i := 0; i < rms.Len(); i++ {
 		ocReq := agentmetricspb.ExportMetricsServiceRequest{}
@@ -230,44 +856,108 @@ func (oce *ocExporter) pushMetricsData(_ context.Context, md pdata.Metrics) erro
 		if ocReq.Resource == nil {
 			ocReq.Resource = &resourcepb.Resource{}
 		}
-		if err := mClient.msec.Send(&ocReq); err != nil {
-			// Error received, cancel the context used to create the RPC to free all resources,
-			// put back nil to keep the number of workers constant.
-			mClient.cancel()
-			oce.metricsClients <- nil
-			return err
+		// sent is the number of metrics of the resource metrics already sent.
+		sent := 0
+		for _, req := range splitMetricsRequest(&ocReq, oce.cfg.MaxPayloadBytes) {
+			msec, err := oce.metricsStream(mClient, req)
+			if err == nil {
+				if err = msec.Send(req); err != nil {
+					err = streamError(err, func() error {
+						_, recvErr := msec.Recv()
+						return recvErr
+					})
+				}
+			}
+			if err != nil {
+				// Error received, cancel the context used to create the RPC to free all resources,
+				// put back a client without RPC to keep the number of workers constant.
+				if stop() {
+					err = fmt.Errorf("export attempt canceled: %w", ctx.Err())
+				}
+				mClient.cancel()
+				if i > 0 || sent > 0 {
+					// Only the metrics not sent yet must be retried.
+					err = consumererror.NewMetrics(err, unsentMetrics(md, i, sent))
+				}
+				return &metricsClientWithCancel{worker: mClient.worker}, err
+			}
+			sent += len(req.Metrics)
 		}
 	}
-	oce.metricsClients <- mClient
-	return nil
+	if stop() {
+		// The RPC was canceled after the last message was sent, it cannot be reused.
+		return &metricsClientWithCancel{worker: mClient.worker}, fmt.Errorf("export attempt canceled: %w", ctx.Err())
+	}
+	mClient.lastExport = oce.clock.Now()
+	return mClient, nil
 }
 
-func (oce *ocExporter) createTraceServiceRPC() (*tracesClientWithCancel, error) {
+func (oce *ocExporter) createTraceServiceRPC(worker int) (*tracesClientWithCancel, error) {
 	// Initiate the trace service by sending over node identifier info.
 	ctx, cancel := context.WithCancel(context.Background())
-	if len(oce.cfg.Headers) > 0 {
-		ctx = metadata.NewOutgoingContext(ctx, metadata.New(oce.cfg.Headers))
+	if len(oce.metadata) > 0 {
+		ctx = metadata.NewOutgoingContext(ctx, oce.metadata)
 	}
 	// Cannot use grpc.WaitForReady(cfg.WaitForReady) because will block forever.
 	traceClient, err := oce.traceSvcClient.Export(ctx)
 	if err != nil {
 		cancel()
-		return nil, fmt.Errorf("TraceServiceClient: %w", err)
+		return nil, fmt.Errorf("TraceServiceClient: %w", configtls.WrapHandshakeError(err))
 	}
-	return &tracesClientWithCancel{cancel: cancel, tsec: traceClient}, nil
+	tClient := &tracesClientWithCancel{worker: worker, cancel: cancel, tsec: traceClient, rpcCtx: ctx, lastExport: oce.clock.Now()}
+	return tClient, nil
 }
 
-func (oce *ocExporter) createMetricsServiceRPC() (*metricsClientWithCancel, error) {
+func (oce *ocExporter) createMetricsServiceRPC(worker int) (*metricsClientWithCancel, error) {
 	// Initiate the trace service by sending over node identifier info.
 	ctx, cancel := context.WithCancel(context.Background())
-	if len(oce.cfg.Headers) > 0 {
-		ctx = metadata.NewOutgoingContext(ctx, metadata.New(oce.cfg.Headers))
+	if len(oce.metadata) > 0 {
+		ctx = metadata.NewOutgoingContext(ctx, oce.metadata)
 	}
 	// Cannot use grpc.WaitForReady(cfg.WaitForReady) because will block forever.
 	metricsClient, err := oce.metricsSvcClient.Export(ctx)
 	if err != nil {
 		cancel()
-		return nil, fmt.Errorf("MetricsServiceClient: %w", err)
+		return nil, fmt.Errorf("MetricsServiceClient: %w", configtls.WrapHandshakeError(err))
 	}
-	return &metricsClientWithCancel{cancel: cancel, msec: metricsClient}, nil
+	mClient := &metricsClientWithCancel{worker: worker, cancel: cancel, msec: metricsClient, rpcCtx: ctx, lastExport: oce.clock.Now()}
+	return mClient, nil
+}
+
+// tracesStream returns the stream of tClient sending req, opening the
+// uncompressed stream if req is the first request smaller than
+// CompressionMinBytes.
+func (oce *ocExporter) tracesStream(tClient *tracesClientWithCancel, req *agenttracepb.ExportTraceServiceRequest) (agenttracepb.TraceService_ExportClient, error) {
+	if !oce.useUncompressedStream() || proto.Size(req) >= oce.cfg.CompressionMinBytes {
+		return tClient.tsec, nil
+	}
+	if tClient.uncompressedTsec == nil {
+		stream, err := oce.traceSvcClient.Export(tClient.rpcCtx, grpc.UseCompressor(encoding.Identity))
+		if err != nil {
+			return nil, fmt.Errorf("TraceServiceClient: %w", err)
+		}
+		tClient.uncompressedTsec = stream
+	}
+	return tClient.uncompressedTsec, nil
+}
+
+// metricsStream is the tracesStream of metrics.
+func (oce *ocExporter) metricsStream(mClient *metricsClientWithCancel, req *agentmetricspb.ExportMetricsServiceRequest) (agentmetricspb.MetricsService_ExportClient, error) {
+	if !oce.useUncompressedStream() || proto.Size(req) >= oce.cfg.CompressionMinBytes {
+		return mClient.msec, nil
+	}
+	if mClient.uncompressedMsec == nil {
+		stream, err := oce.metricsSvcClient.Export(mClient.rpcCtx, grpc.UseCompressor(encoding.Identity))
+		if err != nil {
+			return nil, fmt.Errorf("MetricsServiceClient: %w", err)
+		}
+		mClient.uncompressedMsec = stream
+	}
+	return mClient.uncompressedMsec, nil
+}
+
+// useUncompressedStream returns true if small requests need to be sent on a
+// separate stream, since the compression is configured per stream in gRPC.
+func (oce *ocExporter) useUncompressedStream() bool {
+	return oce.compression != "" && oce.cfg.CompressionMinBytes > 0
 }
@@ -16,21 +16,36 @@ package opencensusexporter
 
 import (
 	"context"
+	"errors"
+	"io"
+	"net"
+	"strings"
 	"testing"
 	"time"
 
+	agenttracepb "github.com/census-instrumentation/opencensus-proto/gen-go/agent/trace/v1"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"go.uber.org/zap"
+	"go.uber.org/zap/zaptest/observer"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/connectivity"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/durationpb"
 
 	"go.opentelemetry.io/collector/component"
 	"go.opentelemetry.io/collector/component/componenttest"
 	"go.opentelemetry.io/collector/config/configgrpc"
 	"go.opentelemetry.io/collector/config/configtls"
 	"go.opentelemetry.io/collector/consumer/consumertest"
+	"go.opentelemetry.io/collector/consumer/pdata"
+	"go.opentelemetry.io/collector/exporter/exporterhelper"
 	"go.opentelemetry.io/collector/internal/testdata"
 	"go.opentelemetry.io/collector/receiver/opencensusreceiver"
 	"go.opentelemetry.io/collector/testutil"
+	"go.opentelemetry.io/collector/testutil/octest"
 )
 
 func TestSendTraces(t *testing.T) {
@@ -131,6 +146,36 @@ func TestSendTraces_AfterStop(t *testing.T) {
 	assert.Error(t, exp.ConsumeTraces(context.Background(), td))
 }
 
+func TestSendTraces_ShutdownDrainsQueue(t *testing.T) {
+	srv, err := octest.NewMockServer()
+	require.NoError(t, err)
+	defer srv.Stop()
+
+	factory := NewFactory()
+	cfg := factory.CreateDefaultConfig().(*Config)
+	cfg.GRPCClientSettings = configgrpc.GRPCClientSettings{
+		Endpoint: srv.Endpoint(),
+		TLSSetting: configtls.TLSClientSetting{
+			Insecure: true,
+		},
+	}
+	cfg.NumWorkers = 1
+	cfg.QueueSettings = exporterhelper.DefaultQueueSettings()
+	cfg.QueueSettings.NumConsumers = 1
+	exp, err := factory.CreateTracesExporter(context.Background(), component.ExporterCreateParams{Logger: zap.NewNop()}, cfg)
+	require.NoError(t, err)
+	require.NoError(t, exp.Start(context.Background(), componenttest.NewNopHost()))
+
+	const batches = 50
+	for i := 0; i < batches; i++ {
+		require.NoError(t, exp.ConsumeTraces(context.Background(), testdata.GenerateTracesManySpansSameResource(10)))
+	}
+	// Shutdown drains the queue and waits for the server to receive the data
+	// before closing the connection, so no span is lost.
+	require.NoError(t, exp.Shutdown(context.Background()))
+	assert.EqualValues(t, batches*10, srv.SpansCount())
+}
+
 func TestSendMetrics(t *testing.T) {
 	sink := new(consumertest.MetricsSink)
 	rFactory := opencensusreceiver.NewFactory()
@@ -227,3 +272,879 @@ func TestSendMetrics_AfterStop(t *testing.T) {
 	md := testdata.GenerateMetricsOneMetric()
 	assert.Error(t, exp.ConsumeMetrics(context.Background(), md))
 }
+
+func TestSendData_CompressionMinBytes(t *testing.T) {
+	srv, err := octest.NewMockServer()
+	require.NoError(t, err)
+	defer srv.Stop()
+
+	cfg := createDefaultConfig().(*Config)
+	cfg.GRPCClientSettings = configgrpc.GRPCClientSettings{
+		Endpoint:    srv.Endpoint(),
+		Compression: configgrpc.CompressionGzip,
+		TLSSetting: configtls.TLSClientSetting{
+			Insecure: true,
+		},
+	}
+	cfg.NumWorkers = 1
+	cfg.CompressionMinBytes = 1024
+
+	tExp, err := newTracesExporter(context.Background(), cfg)
+	require.NoError(t, err)
+	require.NoError(t, tExp.start(context.Background(), componenttest.NewNopHost()))
+	t.Cleanup(func() {
+		assert.NoError(t, tExp.shutdown(context.Background()))
+	})
+	// The uncompressed RPC is only opened by the first small request.
+	assert.NoError(t, tExp.pushTraceData(context.Background(), testdata.GenerateTracesManySpansSameResource(100)))
+	tClient := <-tExp.tracesChan(0)
+	assert.Nil(t, tClient.uncompressedTsec)
+	tExp.tracesChan(0) <- tClient
+	assert.NoError(t, tExp.pushTraceData(context.Background(), testdata.GenerateTracesOneSpan()))
+	assert.Eventually(t, func() bool {
+		return srv.SpansCount() == 101
+	}, 10*time.Second, 5*time.Millisecond)
+	tClient = <-tExp.tracesChan(0)
+	assert.NotNil(t, tClient.uncompressedTsec)
+	tExp.tracesChan(0) <- tClient
+
+	mExp, err := newMetricsExporter(context.Background(), cfg)
+	require.NoError(t, err)
+	require.NoError(t, mExp.start(context.Background(), componenttest.NewNopHost()))
+	t.Cleanup(func() {
+		assert.NoError(t, mExp.shutdown(context.Background()))
+	})
+	assert.NoError(t, mExp.pushMetricsData(context.Background(), testdata.GenerateMetricsOneMetric()))
+	assert.Eventually(t, func() bool {
+		return srv.MetricsCount() == 1
+	}, 10*time.Second, 5*time.Millisecond)
+	mClient := <-mExp.metricsChan(0)
+	assert.NotNil(t, mClient.uncompressedMsec)
+	mExp.metricsChan(0) <- mClient
+}
+
+func TestSendData_CompressionMinBytesNoCompression(t *testing.T) {
+	cfg := createDefaultConfig().(*Config)
+	cfg.GRPCClientSettings.Endpoint = "localhost:56569"
+	cfg.CompressionMinBytes = 1024
+	oce, err := newOcExporter(context.Background(), cfg)
+	require.NoError(t, err)
+	assert.False(t, oce.useUncompressedStream())
+}
+
+func TestSendTraces_IdleConnTimeout(t *testing.T) {
+	srv, err := octest.NewMockServer()
+	require.NoError(t, err)
+	defer srv.Stop()
+
+	cfg := createDefaultConfig().(*Config)
+	cfg.GRPCClientSettings = configgrpc.GRPCClientSettings{
+		Endpoint: srv.Endpoint(),
+		TLSSetting: configtls.TLSClientSetting{
+			Insecure: true,
+		},
+	}
+	cfg.NumWorkers = 1
+	cfg.IdleConnTimeout = 50 * time.Millisecond
+
+	oce, err := newTracesExporter(context.Background(), cfg)
+	require.NoError(t, err)
+	require.NoError(t, oce.start(context.Background(), componenttest.NewNopHost()))
+	t.Cleanup(func() {
+		assert.NoError(t, oce.shutdown(context.Background()))
+	})
+
+	// Holding a worker guarantees that the connection is not re-dialed concurrently.
+	currentConn := func() *grpc.ClientConn {
+		tClient := <-oce.tracesChan(0)
+		defer func() { oce.tracesChan(0) <- tClient }()
+		return oce.grpcClientConn
+	}
+
+	assert.NoError(t, oce.pushTraceData(context.Background(), testdata.GenerateTracesOneSpan()))
+	conn := currentConn()
+	assert.Eventually(t, func() bool {
+		return currentConn() != conn
+	}, 10*time.Second, 5*time.Millisecond)
+	assert.Equal(t, connectivity.Shutdown, conn.GetState())
+
+	// The fresh connection is used for the next export.
+	assert.NoError(t, oce.pushTraceData(context.Background(), testdata.GenerateTracesOneSpan()))
+	assert.Eventually(t, func() bool {
+		return srv.SpansCount() == 2
+	}, 10*time.Second, 5*time.Millisecond)
+}
+
+func TestSendData_IdleWorkerTimeout(t *testing.T) {
+	srv, err := octest.NewMockServer()
+	require.NoError(t, err)
+	defer srv.Stop()
+
+	cfg := createDefaultConfig().(*Config)
+	cfg.GRPCClientSettings = configgrpc.GRPCClientSettings{
+		Endpoint: srv.Endpoint(),
+		TLSSetting: configtls.TLSClientSetting{
+			Insecure: true,
+		},
+	}
+	cfg.NumWorkers = 2
+	cfg.Warmup = true
+	cfg.IdleWorkerTimeout = 50 * time.Millisecond
+
+	tExp, err := newTracesExporter(context.Background(), cfg)
+	require.NoError(t, err)
+	require.NoError(t, tExp.start(context.Background(), componenttest.NewNopHost()))
+	t.Cleanup(func() {
+		assert.NoError(t, tExp.shutdown(context.Background()))
+	})
+	mExp, err := newMetricsExporter(context.Background(), cfg)
+	require.NoError(t, err)
+	require.NoError(t, mExp.start(context.Background(), componenttest.NewNopHost()))
+	t.Cleanup(func() {
+		assert.NoError(t, mExp.shutdown(context.Background()))
+	})
+
+	// Holding the idle checks guarantees that the clients are not replaced concurrently.
+	activeWorkers := func() int {
+		tExp.redialMu.Lock()
+		defer tExp.redialMu.Unlock()
+		mExp.redialMu.Lock()
+		defer mExp.redialMu.Unlock()
+		active := 0
+		for i := 0; i < cfg.NumWorkers; i++ {
+			tClient := <-tExp.tracesChan(i)
+			mClient := <-mExp.metricsChan(i)
+			if tClient.tsec != nil {
+				active++
+			}
+			if mClient.msec != nil {
+				active++
+			}
+			defer func() {
+				tExp.tracesChan(tClient.worker) <- tClient
+				mExp.metricsChan(mClient.worker) <- mClient
+			}()
+		}
+		return active
+	}
+
+	// The warmed up RPCs of all the workers are closed once idle.
+	assert.Eventually(t, func() bool {
+		return activeWorkers() == 0
+	}, 10*time.Second, 5*time.Millisecond)
+
+	// The RPCs are created again on demand, on the connection kept open.
+	conn := tExp.grpcClientConn
+	assert.NoError(t, tExp.pushTraceData(context.Background(), testdata.GenerateTracesOneSpan()))
+	assert.NoError(t, mExp.pushMetricsData(context.Background(), testdata.GenerateMetricsOneMetric()))
+	assert.Eventually(t, func() bool {
+		return srv.SpansCount() == 1 && srv.MetricsCount() == 1
+	}, 10*time.Second, 5*time.Millisecond)
+	assert.Same(t, conn, tExp.grpcClientConn)
+	assert.Equal(t, connectivity.Ready, conn.GetState())
+
+	// And closed again once idle.
+	assert.Eventually(t, func() bool {
+		return activeWorkers() == 0
+	}, 10*time.Second, 5*time.Millisecond)
+}
+
+func TestSendTraces_IdleWorkerTimeoutActiveWorker(t *testing.T) {
+	srv, err := octest.NewMockServer()
+	require.NoError(t, err)
+	defer srv.Stop()
+
+	cfg := createDefaultConfig().(*Config)
+	cfg.GRPCClientSettings = configgrpc.GRPCClientSettings{
+		Endpoint: srv.Endpoint(),
+		TLSSetting: configtls.TLSClientSetting{
+			Insecure: true,
+		},
+	}
+	cfg.NumWorkers = 1
+	cfg.IdleWorkerTimeout = time.Minute
+
+	oce, err := newTracesExporter(context.Background(), cfg)
+	require.NoError(t, err)
+	clock := newFakeClock()
+	oce.clock = clock
+	require.NoError(t, oce.start(context.Background(), componenttest.NewNopHost()))
+	t.Cleanup(func() {
+		assert.NoError(t, oce.shutdown(context.Background()))
+	})
+	currentRPC := func() agenttracepb.TraceService_ExportClient {
+		tClient := <-oce.tracesChan(0)
+		defer func() { oce.tracesChan(0) <- tClient }()
+		return tClient.tsec
+	}
+
+	// A worker exporting more often than the timeout keeps its RPC.
+	require.Eventually(t, func() bool { return clock.numWaiters() == 1 }, time.Second, time.Millisecond)
+	assert.NoError(t, oce.pushTraceData(context.Background(), testdata.GenerateTracesOneSpan()))
+	tsec := currentRPC()
+	for i := 0; i < 10; i++ {
+		clock.Advance(cfg.IdleWorkerTimeout / 4)
+		assert.NoError(t, oce.pushTraceData(context.Background(), testdata.GenerateTracesOneSpan()))
+	}
+	assert.Equal(t, tsec, currentRPC())
+
+	// And closes it once idle.
+	assert.Eventually(t, func() bool {
+		clock.Advance(cfg.IdleWorkerTimeout / 2)
+		return currentRPC() == nil
+	}, 10*time.Second, 5*time.Millisecond)
+}
+
+func TestSendTraces_MaxConnectionAge(t *testing.T) {
+	srv, err := octest.NewMockServer()
+	require.NoError(t, err)
+	defer srv.Stop()
+
+	cfg := createDefaultConfig().(*Config)
+	cfg.GRPCClientSettings = configgrpc.GRPCClientSettings{
+		Endpoint: srv.Endpoint(),
+		TLSSetting: configtls.TLSClientSetting{
+			Insecure: true,
+		},
+	}
+	cfg.NumWorkers = 2
+	cfg.MaxConnectionAge = 50 * time.Millisecond
+
+	oce, err := newTracesExporter(context.Background(), cfg)
+	require.NoError(t, err)
+	require.NoError(t, oce.start(context.Background(), componenttest.NewNopHost()))
+	t.Cleanup(func() {
+		assert.NoError(t, oce.shutdown(context.Background()))
+	})
+
+	currentConn := func() *grpc.ClientConn {
+		oce.redialMu.Lock()
+		defer oce.redialMu.Unlock()
+		return oce.grpcClientConn
+	}
+
+	// The connection is rotated even while exporting.
+	conn := currentConn()
+	var exported int
+	assert.Eventually(t, func() bool {
+		assert.NoError(t, oce.pushTraceData(context.Background(), testdata.GenerateTracesOneSpan()))
+		exported++
+		return currentConn() != conn
+	}, 10*time.Second, 5*time.Millisecond)
+	assert.Eventually(t, func() bool {
+		return conn.GetState() == connectivity.Shutdown
+	}, 10*time.Second, 5*time.Millisecond)
+
+	// The new connection is used for the next exports, and no export was lost.
+	assert.NoError(t, oce.pushTraceData(context.Background(), testdata.GenerateTracesOneSpan()))
+	exported++
+	assert.Eventually(t, func() bool {
+		return srv.SpansCount() == exported
+	}, 10*time.Second, 5*time.Millisecond)
+}
+
+func TestJitterConnectionAge(t *testing.T) {
+	for i := 0; i < 100; i++ {
+		age := jitterConnectionAge(time.Minute)
+		assert.GreaterOrEqual(t, int64(age), int64(54*time.Second))
+		assert.LessOrEqual(t, int64(age), int64(66*time.Second))
+	}
+}
+
+func TestStart_Warmup(t *testing.T) {
+	srv, err := octest.NewMockServer()
+	require.NoError(t, err)
+	defer srv.Stop()
+
+	cfg := createDefaultConfig().(*Config)
+	cfg.GRPCClientSettings = configgrpc.GRPCClientSettings{
+		Endpoint: srv.Endpoint(),
+		TLSSetting: configtls.TLSClientSetting{
+			Insecure: true,
+		},
+	}
+	cfg.NumWorkers = 3
+	cfg.Warmup = true
+
+	tExp, err := newTracesExporter(context.Background(), cfg)
+	require.NoError(t, err)
+	require.NoError(t, tExp.start(context.Background(), componenttest.NewNopHost()))
+	t.Cleanup(func() {
+		assert.NoError(t, tExp.shutdown(context.Background()))
+	})
+	mExp, err := newMetricsExporter(context.Background(), cfg)
+	require.NoError(t, err)
+	require.NoError(t, mExp.start(context.Background(), componenttest.NewNopHost()))
+	t.Cleanup(func() {
+		assert.NoError(t, mExp.shutdown(context.Background()))
+	})
+
+	// All the workers have an RPC before the first export.
+	for i := 0; i < cfg.NumWorkers; i++ {
+		tClient := <-tExp.tracesChan(0)
+		assert.NotNil(t, tClient.tsec)
+		tExp.tracesChan(0) <- tClient
+		mClient := <-mExp.metricsChan(0)
+		assert.NotNil(t, mClient.msec)
+		mExp.metricsChan(0) <- mClient
+	}
+
+	assert.NoError(t, tExp.pushTraceData(context.Background(), testdata.GenerateTracesOneSpan()))
+	assert.Eventually(t, func() bool {
+		return srv.SpansCount() == 1
+	}, 10*time.Second, 5*time.Millisecond)
+}
+
+func TestStart_WarmupNoBackend(t *testing.T) {
+	cfg := createDefaultConfig().(*Config)
+	cfg.GRPCClientSettings = configgrpc.GRPCClientSettings{
+		Endpoint: testutil.GetAvailableLocalAddress(t),
+		TLSSetting: configtls.TLSClientSetting{
+			Insecure: true,
+		},
+	}
+	cfg.Warmup = true
+	core, logs := observer.New(zap.WarnLevel)
+
+	exp, err := newTracesExporter(context.Background(), cfg)
+	require.NoError(t, err)
+	exp.logger = zap.New(core)
+	// The start succeeds even if the warmup fails.
+	require.NoError(t, exp.start(context.Background(), componenttest.NewNopHost()))
+	t.Cleanup(func() {
+		assert.NoError(t, exp.shutdown(context.Background()))
+	})
+
+	entries := logs.All()
+	require.Len(t, entries, 1)
+	assert.Equal(t, int64(cfg.NumWorkers), entries[0].ContextMap()["failed_workers"])
+	for i := 0; i < cfg.NumWorkers; i++ {
+		tClient := <-exp.tracesChan(0)
+		assert.Nil(t, tClient.tsec)
+		exp.tracesChan(0) <- tClient
+	}
+}
+
+func TestStart_RampUp(t *testing.T) {
+	srv, err := octest.NewMockServer()
+	require.NoError(t, err)
+	defer srv.Stop()
+
+	cfg := createDefaultConfig().(*Config)
+	cfg.GRPCClientSettings = configgrpc.GRPCClientSettings{
+		Endpoint: srv.Endpoint(),
+		TLSSetting: configtls.TLSClientSetting{
+			Insecure: true,
+		},
+	}
+	cfg.NumWorkers = 3
+	cfg.RampUp = 200 * time.Millisecond
+
+	exp, err := newTracesExporter(context.Background(), cfg)
+	require.NoError(t, err)
+	require.NoError(t, exp.start(context.Background(), componenttest.NewNopHost()))
+	t.Cleanup(func() {
+		assert.NoError(t, exp.shutdown(context.Background()))
+	})
+
+	// A single worker is available at start, then all of them after the ramp.
+	assert.Len(t, exp.tracesChan(0), 1)
+	assert.NoError(t, exp.pushTraceData(context.Background(), testdata.GenerateTracesOneSpan()))
+	assert.Eventually(t, func() bool {
+		return len(exp.tracesChan(0)) == cfg.NumWorkers
+	}, 10*time.Second, 5*time.Millisecond)
+}
+
+func TestStart_RampUpShutdown(t *testing.T) {
+	cfg := createDefaultConfig().(*Config)
+	cfg.GRPCClientSettings = configgrpc.GRPCClientSettings{
+		Endpoint: testutil.GetAvailableLocalAddress(t),
+		TLSSetting: configtls.TLSClientSetting{
+			Insecure: true,
+		},
+	}
+	cfg.NumWorkers = 3
+	cfg.RampUp = time.Hour
+
+	exp, err := newMetricsExporter(context.Background(), cfg)
+	require.NoError(t, err)
+	require.NoError(t, exp.start(context.Background(), componenttest.NewNopHost()))
+	assert.Len(t, exp.metricsChan(0), 1)
+	// The shutdown does not wait for the end of the ramp.
+	assert.NoError(t, exp.shutdown(context.Background()))
+}
+
+func TestStart_DialTimeout(t *testing.T) {
+	// The listener accepts the connections but never completes the TLS handshake.
+	ln, err := net.Listen("tcp", "localhost:0")
+	require.NoError(t, err)
+	defer ln.Close()
+	go func() {
+		var conns []net.Conn
+		defer func() {
+			for _, conn := range conns {
+				conn.Close()
+			}
+		}()
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			conns = append(conns, conn)
+		}
+	}()
+
+	cfg := createDefaultConfig().(*Config)
+	cfg.GRPCClientSettings = configgrpc.GRPCClientSettings{
+		Endpoint:    ln.Addr().String(),
+		DialTimeout: 100 * time.Millisecond,
+	}
+	exp, err := newTracesExporter(context.Background(), cfg)
+	require.NoError(t, err)
+	start := time.Now()
+	assert.Error(t, exp.start(context.Background(), componenttest.NewNopHost()))
+	assert.Less(t, int64(time.Since(start)), int64(5*time.Second))
+}
+
+func TestSendTraces_TLSToPlaintextServer(t *testing.T) {
+	srv, err := octest.NewMockServer()
+	require.NoError(t, err)
+	defer srv.Stop()
+
+	cfg := createDefaultConfig().(*Config)
+	cfg.GRPCClientSettings = configgrpc.GRPCClientSettings{
+		Endpoint: srv.Endpoint(),
+		TLSSetting: configtls.TLSClientSetting{
+			Insecure: false,
+		},
+	}
+	cfg.NumWorkers = 1
+
+	oce, err := newTracesExporter(context.Background(), cfg)
+	require.NoError(t, err)
+	require.NoError(t, oce.start(context.Background(), componenttest.NewNopHost()))
+	t.Cleanup(func() {
+		assert.NoError(t, oce.shutdown(context.Background()))
+	})
+
+	err = oce.pushTraceData(context.Background(), testdata.GenerateTracesOneSpan())
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "insecure: true")
+}
+
+func TestSendData_PerSignalCompression(t *testing.T) {
+	srv, err := octest.NewMockServer()
+	require.NoError(t, err)
+	defer srv.Stop()
+
+	cfg := createDefaultConfig().(*Config)
+	cfg.GRPCClientSettings = configgrpc.GRPCClientSettings{
+		Endpoint: srv.Endpoint(),
+		TLSSetting: configtls.TLSClientSetting{
+			Insecure: true,
+		},
+	}
+	cfg.TracesCompression = configgrpc.CompressionGzip
+	cfg.MetricsCompression = compressionNone
+
+	tExp, err := newTracesExporter(context.Background(), cfg)
+	require.NoError(t, err)
+	assert.Equal(t, configgrpc.CompressionGzip, tExp.compression)
+	require.NoError(t, tExp.start(context.Background(), componenttest.NewNopHost()))
+	t.Cleanup(func() {
+		assert.NoError(t, tExp.shutdown(context.Background()))
+	})
+	assert.NoError(t, tExp.pushTraceData(context.Background(), testdata.GenerateTracesOneSpan()))
+
+	mExp, err := newMetricsExporter(context.Background(), cfg)
+	require.NoError(t, err)
+	assert.Equal(t, "", mExp.compression)
+	require.NoError(t, mExp.start(context.Background(), componenttest.NewNopHost()))
+	t.Cleanup(func() {
+		assert.NoError(t, mExp.shutdown(context.Background()))
+	})
+	assert.NoError(t, mExp.pushMetricsData(context.Background(), testdata.GenerateMetricsOneMetric()))
+
+	assert.Eventually(t, func() bool {
+		return srv.SpansCount() == 1 && srv.MetricsCount() == 1
+	}, 10*time.Second, 5*time.Millisecond)
+}
+
+func TestSendTraces_PerAttemptTimeout(t *testing.T) {
+	srv, err := octest.NewMockServer()
+	require.NoError(t, err)
+	defer srv.Stop()
+
+	cfg := createDefaultConfig().(*Config)
+	cfg.GRPCClientSettings = configgrpc.GRPCClientSettings{
+		Endpoint: srv.Endpoint(),
+		TLSSetting: configtls.TLSClientSetting{
+			Insecure: true,
+		},
+	}
+	cfg.NumWorkers = 1
+	cfg.PerAttemptTimeout = 200 * time.Millisecond
+
+	exp, err := newTracesExporter(context.Background(), cfg)
+	require.NoError(t, err)
+	require.NoError(t, exp.start(context.Background(), componenttest.NewNopHost()))
+	t.Cleanup(func() {
+		assert.NoError(t, exp.shutdown(context.Background()))
+	})
+
+	// Every ResourceSpans is sent in a separate message, large enough to exceed
+	// the flow control window while the server is busy with the previous message.
+	td := pdata.NewTraces()
+	for i := 0; i < 3; i++ {
+		rs := td.ResourceSpans().AppendEmpty()
+		rs.Resource().Attributes().InsertString("large", strings.Repeat("x", 3*1024*1024))
+		rs.InstrumentationLibrarySpans().AppendEmpty().Spans().AppendEmpty()
+	}
+
+	// The slow attempt is canceled after PerAttemptTimeout.
+	srv.SetExportDelay(10 * time.Second)
+	start := time.Now()
+	err = exp.pushTraceData(context.Background(), td)
+	require.Error(t, err)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.Less(t, int64(time.Since(start)), int64(5*time.Second))
+
+	// The next attempt uses a new RPC and succeeds. Its payload is small so that it
+	// completes well within PerAttemptTimeout, even on a loaded machine.
+	srv.SetExportDelay(0)
+	assert.NoError(t, exp.pushTraceData(context.Background(), testdata.GenerateTracesOneSpan()))
+	assert.Eventually(t, func() bool {
+		return srv.SpansCount() == 1
+	}, 10*time.Second, 5*time.Millisecond)
+}
+
+func TestSendTraces_MaxSendMsgSize(t *testing.T) {
+	srv, err := octest.NewMockServer()
+	require.NoError(t, err)
+	defer srv.Stop()
+
+	cfg := createDefaultConfig().(*Config)
+	cfg.GRPCClientSettings = configgrpc.GRPCClientSettings{
+		Endpoint: srv.Endpoint(),
+		TLSSetting: configtls.TLSClientSetting{
+			Insecure: true,
+		},
+		MaxSendMsgSizeMiB: 1,
+	}
+	cfg.NumWorkers = 1
+
+	exp, err := newTracesExporter(context.Background(), cfg)
+	require.NoError(t, err)
+	require.NoError(t, exp.start(context.Background(), componenttest.NewNopHost()))
+	t.Cleanup(func() {
+		assert.NoError(t, exp.shutdown(context.Background()))
+	})
+
+	td := pdata.NewTraces()
+	rs := td.ResourceSpans().AppendEmpty()
+	rs.Resource().Attributes().InsertString("large", strings.Repeat("x", 2*1024*1024))
+	rs.InstrumentationLibrarySpans().AppendEmpty().Spans().AppendEmpty()
+	assert.Error(t, exp.pushTraceData(context.Background(), td))
+
+	assert.NoError(t, exp.pushTraceData(context.Background(), testdata.GenerateTracesOneSpan()))
+	assert.Eventually(t, func() bool {
+		return srv.SpansCount() == 1
+	}, 10*time.Second, 5*time.Millisecond)
+}
+
+func TestSendSignalHeaders(t *testing.T) {
+	srv, err := octest.NewMockServer()
+	require.NoError(t, err)
+	defer srv.Stop()
+
+	cfg := createDefaultConfig().(*Config)
+	cfg.GRPCClientSettings = configgrpc.GRPCClientSettings{
+		Endpoint: srv.Endpoint(),
+		TLSSetting: configtls.TLSClientSetting{
+			Insecure: true,
+		},
+		Headers: map[string]string{"x-route": "default", "x-shared": "shared"},
+	}
+	cfg.NumWorkers = 1
+	cfg.TracesHeaders = map[string]string{"X-Route": "traces"}
+
+	texp, err := newTracesExporter(context.Background(), cfg)
+	require.NoError(t, err)
+	require.NoError(t, texp.start(context.Background(), componenttest.NewNopHost()))
+	t.Cleanup(func() {
+		assert.NoError(t, texp.shutdown(context.Background()))
+	})
+	require.NoError(t, texp.pushTraceData(context.Background(), testdata.GenerateTracesOneSpan()))
+	assert.Eventually(t, func() bool {
+		return srv.SpansCount() == 1
+	}, 10*time.Second, 5*time.Millisecond)
+	md := srv.LastMetadata()
+	assert.Equal(t, []string{"traces"}, md.Get("x-route"))
+	assert.Equal(t, []string{"shared"}, md.Get("x-shared"))
+
+	mexp, err := newMetricsExporter(context.Background(), cfg)
+	require.NoError(t, err)
+	require.NoError(t, mexp.start(context.Background(), componenttest.NewNopHost()))
+	t.Cleanup(func() {
+		assert.NoError(t, mexp.shutdown(context.Background()))
+	})
+	require.NoError(t, mexp.pushMetricsData(context.Background(), testdata.GenerateMetricsOneMetric()))
+	assert.Eventually(t, func() bool {
+		return srv.MetricsCount() == 1
+	}, 10*time.Second, 5*time.Millisecond)
+	md = srv.LastMetadata()
+	assert.Equal(t, []string{"default"}, md.Get("x-route"))
+	assert.Equal(t, []string{"shared"}, md.Get("x-shared"))
+}
+
+func newThrottleStatus(t *testing.T, delay time.Duration) *status.Status {
+	st, err := status.New(codes.ResourceExhausted, "quota exceeded").WithDetails(&errdetails.RetryInfo{RetryDelay: durationpb.New(delay)})
+	require.NoError(t, err)
+	return st
+}
+
+func TestSendTraces_Throttled(t *testing.T) {
+	srv, err := octest.NewMockServer()
+	require.NoError(t, err)
+	defer srv.Stop()
+	srv.SetExportError(newThrottleStatus(t, 2*time.Second).Err())
+
+	cfg := createDefaultConfig().(*Config)
+	cfg.GRPCClientSettings = configgrpc.GRPCClientSettings{
+		Endpoint: srv.Endpoint(),
+		TLSSetting: configtls.TLSClientSetting{
+			Insecure: true,
+		},
+	}
+	cfg.NumWorkers = 1
+
+	exp, err := newTracesExporter(context.Background(), cfg)
+	require.NoError(t, err)
+	require.NoError(t, exp.start(context.Background(), componenttest.NewNopHost()))
+	t.Cleanup(func() {
+		assert.NoError(t, exp.shutdown(context.Background()))
+	})
+
+	// The error is only detected when sending on the stream closed by the server.
+	var throttleErr *exporterhelper.ThrottleError
+	assert.Eventually(t, func() bool {
+		return errors.As(exp.pushTraceData(context.Background(), testdata.GenerateTracesOneSpan()), &throttleErr)
+	}, 10*time.Second, 5*time.Millisecond)
+	assert.Equal(t, 2*time.Second, throttleErr.RetryAfter)
+	assert.Equal(t, codes.ResourceExhausted, status.Code(throttleErr.Err))
+}
+
+func TestStreamError(t *testing.T) {
+	throttleStatus := newThrottleStatus(t, time.Second)
+	recvErr := func(err error) func() error {
+		return func() error { return err }
+	}
+
+	// The status sent by the server is received after io.EOF.
+	err := streamError(io.EOF, recvErr(throttleStatus.Err()))
+	var throttleErr *exporterhelper.ThrottleError
+	require.True(t, errors.As(err, &throttleErr))
+	assert.Equal(t, time.Second, throttleErr.RetryAfter)
+
+	// The stream was closed without error.
+	assert.Equal(t, io.EOF, streamError(io.EOF, recvErr(io.EOF)))
+
+	// Other errors are not modified.
+	notThrottled := []error{
+		errors.New("my error"),
+		status.Error(codes.ResourceExhausted, "no retry info"),
+		newThrottleStatus(t, 0).Err(),
+		status.Error(codes.Unavailable, "unavailable"),
+	}
+	for _, want := range notThrottled {
+		assert.Equal(t, want, streamError(want, recvErr(nil)))
+	}
+	unavailable := status.Error(codes.Unavailable, "unavailable")
+	assert.Equal(t, unavailable, streamError(io.EOF, recvErr(unavailable)))
+}
+
+func TestAttemptContext(t *testing.T) {
+	cfg := createDefaultConfig().(*Config)
+	cfg.Endpoint = "localhost:55678"
+	exp, err := newTracesExporter(context.Background(), cfg)
+	require.NoError(t, err)
+
+	// Disabled by default.
+	actx, acancel := exp.attemptContext(context.Background())
+	defer acancel()
+	_, ok := actx.Deadline()
+	assert.False(t, ok)
+
+	cfg.PerAttemptTimeout = time.Hour
+	actx, acancel = exp.attemptContext(context.Background())
+	defer acancel()
+	deadline, ok := actx.Deadline()
+	require.True(t, ok)
+	assert.WithinDuration(t, time.Now().Add(time.Hour), deadline, time.Minute)
+
+	// The smaller deadline of the exporter timeout applies.
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	actx, acancel = exp.attemptContext(ctx)
+	defer acancel()
+	deadline, ok = actx.Deadline()
+	require.True(t, ok)
+	expected, _ := ctx.Deadline()
+	assert.Equal(t, expected, deadline)
+}
+
+func TestSendTraces_ResourceAttributes(t *testing.T) {
+	sink := new(consumertest.TracesSink)
+	rFactory := opencensusreceiver.NewFactory()
+	rCfg := rFactory.CreateDefaultConfig().(*opencensusreceiver.Config)
+	endpoint := testutil.GetAvailableLocalAddress(t)
+	rCfg.GRPCServerSettings.NetAddr.Endpoint = endpoint
+	params := component.ReceiverCreateParams{Logger: zap.NewNop()}
+	recv, err := rFactory.CreateTracesReceiver(context.Background(), params, rCfg, sink)
+	require.NoError(t, err)
+	require.NoError(t, recv.Start(context.Background(), componenttest.NewNopHost()))
+	t.Cleanup(func() {
+		assert.NoError(t, recv.Shutdown(context.Background()))
+	})
+
+	factory := NewFactory()
+	cfg := factory.CreateDefaultConfig().(*Config)
+	cfg.GRPCClientSettings = configgrpc.GRPCClientSettings{
+		Endpoint: endpoint,
+		TLSSetting: configtls.TLSClientSetting{
+			Insecure: true,
+		},
+	}
+	cfg.NumWorkers = 1
+	cfg.ResourceAttributes = map[string]string{"deployment.environment": "production"}
+	exp, err := factory.CreateTracesExporter(context.Background(), component.ExporterCreateParams{Logger: zap.NewNop()}, cfg)
+	require.NoError(t, err)
+	require.NoError(t, exp.Start(context.Background(), componenttest.NewNopHost()))
+	t.Cleanup(func() {
+		assert.NoError(t, exp.Shutdown(context.Background()))
+	})
+
+	td := testdata.GenerateTracesOneSpan()
+	assert.NoError(t, exp.ConsumeTraces(context.Background(), td))
+	assert.Eventually(t, func() bool {
+		return len(sink.AllTraces()) == 1
+	}, 10*time.Second, 5*time.Millisecond)
+	traces := sink.AllTraces()
+	require.Len(t, traces, 1)
+	env, ok := traces[0].ResourceSpans().At(0).Resource().Attributes().Get("deployment.environment")
+	require.True(t, ok)
+	assert.Equal(t, "production", env.StringVal())
+	// The consumed data is not modified.
+	_, ok = td.ResourceSpans().At(0).Resource().Attributes().Get("deployment.environment")
+	assert.False(t, ok)
+}
+
+func TestPushTraceData_HashWorkerAssignment(t *testing.T) {
+	srv, err := octest.NewMockServer()
+	require.NoError(t, err)
+	defer srv.Stop()
+
+	cfg := createDefaultConfig().(*Config)
+	cfg.GRPCClientSettings = configgrpc.GRPCClientSettings{
+		Endpoint: srv.Endpoint(),
+		TLSSetting: configtls.TLSClientSetting{
+			Insecure: true,
+		},
+	}
+	cfg.NumWorkers = 4
+	cfg.WorkerAssignment = workerAssignmentHash
+
+	exp, err := newTracesExporter(context.Background(), cfg)
+	require.NoError(t, err)
+	require.NoError(t, exp.start(context.Background(), componenttest.NewNopHost()))
+	t.Cleanup(func() {
+		assert.NoError(t, exp.shutdown(context.Background()))
+	})
+
+	td := testdata.GenerateTracesOneSpan()
+	worker := tracesWorker(td, cfg.NumWorkers)
+	for i := 0; i < 3; i++ {
+		require.NoError(t, exp.pushTraceData(context.Background(), td))
+	}
+
+	// The export waits for the assigned worker even if the other workers are available.
+	tClient := <-exp.tracesChan(worker)
+	assert.Equal(t, worker, tClient.worker)
+	assert.NotNil(t, tClient.tsec)
+	doneCh := make(chan error, 1)
+	go func() {
+		doneCh <- exp.pushTraceData(context.Background(), td)
+	}()
+	select {
+	case <-doneCh:
+		t.Fatal("the export did not wait for its worker")
+	case <-time.After(50 * time.Millisecond):
+	}
+	exp.tracesChan(worker) <- tClient
+	assert.NoError(t, <-doneCh)
+
+	// Only the assigned worker created an RPC.
+	for i := 0; i < cfg.NumWorkers; i++ {
+		if i == worker {
+			continue
+		}
+		client := <-exp.tracesChan(i)
+		assert.Nil(t, client.tsec)
+		exp.tracesChan(i) <- client
+	}
+}
+
+func TestSendData_MaxPayloadBytes(t *testing.T) {
+	srv, err := octest.NewMockServer()
+	require.NoError(t, err)
+	defer srv.Stop()
+
+	cfg := createDefaultConfig().(*Config)
+	cfg.GRPCClientSettings = configgrpc.GRPCClientSettings{
+		Endpoint: srv.Endpoint(),
+		TLSSetting: configtls.TLSClientSetting{
+			Insecure: true,
+		},
+	}
+	cfg.NumWorkers = 1
+	cfg.MaxPayloadBytes = 16 * 1024
+
+	tExp, err := newTracesExporter(context.Background(), cfg)
+	require.NoError(t, err)
+	require.NoError(t, tExp.start(context.Background(), componenttest.NewNopHost()))
+	t.Cleanup(func() {
+		assert.NoError(t, tExp.shutdown(context.Background()))
+	})
+	mExp, err := newMetricsExporter(context.Background(), cfg)
+	require.NoError(t, err)
+	require.NoError(t, mExp.start(context.Background(), componenttest.NewNopHost()))
+	t.Cleanup(func() {
+		assert.NoError(t, mExp.shutdown(context.Background()))
+	})
+
+	td := pdata.NewTraces()
+	spans := td.ResourceSpans().AppendEmpty().InstrumentationLibrarySpans().AppendEmpty().Spans()
+	for i := 0; i < 100; i++ {
+		spans.AppendEmpty().SetName(strings.Repeat("x", 1024))
+	}
+	require.NoError(t, tExp.pushTraceData(context.Background(), td))
+	assert.Eventually(t, func() bool {
+		return srv.SpansCount() == 100
+	}, 10*time.Second, 5*time.Millisecond)
+	assert.Greater(t, len(srv.AllTraces()), 1)
+
+	md := pdata.NewMetrics()
+	metrics := md.ResourceMetrics().AppendEmpty().InstrumentationLibraryMetrics().AppendEmpty().Metrics()
+	for i := 0; i < 100; i++ {
+		m := metrics.AppendEmpty()
+		m.SetName(strings.Repeat("x", 1024))
+		m.SetDataType(pdata.MetricDataTypeIntGauge)
+		m.IntGauge().DataPoints().AppendEmpty().SetValue(int64(i))
+	}
+	require.NoError(t, mExp.pushMetricsData(context.Background(), md))
+	assert.Eventually(t, func() bool {
+		return srv.MetricsCount() == 100
+	}, 10*time.Second, 5*time.Millisecond)
+	assert.Greater(t, len(srv.AllMetrics()), 1)
+}
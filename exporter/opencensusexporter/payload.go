@@ -0,0 +1,127 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package opencensusexporter
+
+import (
+	agentmetricspb "github.com/census-instrumentation/opencensus-proto/gen-go/agent/metrics/v1"
+	agenttracepb "github.com/census-instrumentation/opencensus-proto/gen-go/agent/trace/v1"
+	"google.golang.org/protobuf/encoding/protowire"
+	"google.golang.org/protobuf/proto"
+
+	"go.opentelemetry.io/collector/consumer/pdata"
+)
+
+// splitTraceRequest splits req into requests with the same node and resource and
+// a serialized size of at most maxBytes, along span boundaries. A span larger
+// than maxBytes on its own is sent in a request by itself. A maxBytes of zero
+// disables the splitting.
+func splitTraceRequest(req *agenttracepb.ExportTraceServiceRequest, maxBytes int) []*agenttracepb.ExportTraceServiceRequest {
+	if maxBytes <= 0 || len(req.Spans) <= 1 || proto.Size(req) <= maxBytes {
+		return []*agenttracepb.ExportTraceServiceRequest{req}
+	}
+	newReq := func() *agenttracepb.ExportTraceServiceRequest {
+		return &agenttracepb.ExportTraceServiceRequest{Node: req.Node, Resource: req.Resource}
+	}
+	baseSize := proto.Size(newReq())
+
+	var reqs []*agenttracepb.ExportTraceServiceRequest
+	cur, curSize := newReq(), baseSize
+	for _, span := range req.Spans {
+		spanSize := repeatedFieldSize(proto.Size(span))
+		if len(cur.Spans) > 0 && curSize+spanSize > maxBytes {
+			reqs = append(reqs, cur)
+			cur, curSize = newReq(), baseSize
+		}
+		cur.Spans = append(cur.Spans, span)
+		curSize += spanSize
+	}
+	return append(reqs, cur)
+}
+
+// splitMetricsRequest is the splitTraceRequest of metrics, splitting along metric
+// boundaries so that the time series of a metric are always sent together.
+func splitMetricsRequest(req *agentmetricspb.ExportMetricsServiceRequest, maxBytes int) []*agentmetricspb.ExportMetricsServiceRequest {
+	if maxBytes <= 0 || len(req.Metrics) <= 1 || proto.Size(req) <= maxBytes {
+		return []*agentmetricspb.ExportMetricsServiceRequest{req}
+	}
+	newReq := func() *agentmetricspb.ExportMetricsServiceRequest {
+		return &agentmetricspb.ExportMetricsServiceRequest{Node: req.Node, Resource: req.Resource}
+	}
+	baseSize := proto.Size(newReq())
+
+	var reqs []*agentmetricspb.ExportMetricsServiceRequest
+	cur, curSize := newReq(), baseSize
+	for _, metric := range req.Metrics {
+		metricSize := repeatedFieldSize(proto.Size(metric))
+		if len(cur.Metrics) > 0 && curSize+metricSize > maxBytes {
+			reqs = append(reqs, cur)
+			cur, curSize = newReq(), baseSize
+		}
+		cur.Metrics = append(cur.Metrics, metric)
+		curSize += metricSize
+	}
+	return append(reqs, cur)
+}
+
+// repeatedFieldSize returns the serialized size of an element of size n of a
+// repeated message field, whose field number fits in a single byte tag.
+func repeatedFieldSize(n int) int {
+	return 1 + protowire.SizeBytes(n)
+}
+
+// unsentTraces returns the spans of td not sent yet, when the first sent spans
+// of its resource spans at index resource were sent, and all the spans of the
+// resource spans before it.
+func unsentTraces(td pdata.Traces, resource, sent int) pdata.Traces {
+	unsent := td.Clone()
+	rss := unsent.ResourceSpans()
+	i := 0
+	rss.RemoveIf(func(pdata.ResourceSpans) bool {
+		i++
+		return i <= resource
+	})
+	rss.At(0).InstrumentationLibrarySpans().RemoveIf(func(ils pdata.InstrumentationLibrarySpans) bool {
+		ils.Spans().RemoveIf(func(pdata.Span) bool {
+			if sent == 0 {
+				return false
+			}
+			sent--
+			return true
+		})
+		return ils.Spans().Len() == 0
+	})
+	return unsent
+}
+
+// unsentMetrics is the unsentTraces of metrics.
+func unsentMetrics(md pdata.Metrics, resource, sent int) pdata.Metrics {
+	unsent := md.Clone()
+	rms := unsent.ResourceMetrics()
+	i := 0
+	rms.RemoveIf(func(pdata.ResourceMetrics) bool {
+		i++
+		return i <= resource
+	})
+	rms.At(0).InstrumentationLibraryMetrics().RemoveIf(func(ilm pdata.InstrumentationLibraryMetrics) bool {
+		ilm.Metrics().RemoveIf(func(pdata.Metric) bool {
+			if sent == 0 {
+				return false
+			}
+			sent--
+			return true
+		})
+		return ilm.Metrics().Len() == 0
+	})
+	return unsent
+}
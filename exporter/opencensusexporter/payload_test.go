@@ -0,0 +1,147 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package opencensusexporter
+
+import (
+	"strings"
+	"testing"
+
+	commonpb "github.com/census-instrumentation/opencensus-proto/gen-go/agent/common/v1"
+	agentmetricspb "github.com/census-instrumentation/opencensus-proto/gen-go/agent/metrics/v1"
+	agenttracepb "github.com/census-instrumentation/opencensus-proto/gen-go/agent/trace/v1"
+	metricspb "github.com/census-instrumentation/opencensus-proto/gen-go/metrics/v1"
+	resourcepb "github.com/census-instrumentation/opencensus-proto/gen-go/resource/v1"
+	tracepb "github.com/census-instrumentation/opencensus-proto/gen-go/trace/v1"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/protobuf/proto"
+
+	"go.opentelemetry.io/collector/consumer/pdata"
+)
+
+func TestSplitTraceRequest(t *testing.T) {
+	req := &agenttracepb.ExportTraceServiceRequest{
+		Node:     &commonpb.Node{},
+		Resource: &resourcepb.Resource{Type: "host"},
+	}
+	for i := 0; i < 50; i++ {
+		req.Spans = append(req.Spans, &tracepb.Span{Name: &tracepb.TruncatableString{Value: strings.Repeat("x", 100)}})
+	}
+
+	assert.Equal(t, []*agenttracepb.ExportTraceServiceRequest{req}, splitTraceRequest(req, 0))
+	assert.Equal(t, []*agenttracepb.ExportTraceServiceRequest{req}, splitTraceRequest(req, proto.Size(req)))
+
+	reqs := splitTraceRequest(req, 1000)
+	assert.Greater(t, len(reqs), 1)
+	var spans []*tracepb.Span
+	for _, r := range reqs {
+		assert.LessOrEqual(t, proto.Size(r), 1000)
+		assert.Same(t, req.Node, r.Node)
+		assert.Same(t, req.Resource, r.Resource)
+		spans = append(spans, r.Spans...)
+	}
+	assert.Equal(t, req.Spans, spans)
+
+	// A span larger than the limit is sent alone.
+	reqs = splitTraceRequest(req, 10)
+	assert.Len(t, reqs, len(req.Spans))
+	for _, r := range reqs {
+		assert.Len(t, r.Spans, 1)
+	}
+}
+
+func TestSplitMetricsRequest(t *testing.T) {
+	req := &agentmetricspb.ExportMetricsServiceRequest{
+		Node:     &commonpb.Node{},
+		Resource: &resourcepb.Resource{Type: "host"},
+	}
+	for i := 0; i < 50; i++ {
+		req.Metrics = append(req.Metrics, &metricspb.Metric{
+			MetricDescriptor: &metricspb.MetricDescriptor{Name: strings.Repeat("x", 100)},
+			Timeseries:       []*metricspb.TimeSeries{{}, {}},
+		})
+	}
+
+	assert.Equal(t, []*agentmetricspb.ExportMetricsServiceRequest{req}, splitMetricsRequest(req, 0))
+
+	reqs := splitMetricsRequest(req, 1000)
+	assert.Greater(t, len(reqs), 1)
+	var metrics []*metricspb.Metric
+	for _, r := range reqs {
+		assert.LessOrEqual(t, proto.Size(r), 1000)
+		metrics = append(metrics, r.Metrics...)
+	}
+	assert.Equal(t, req.Metrics, metrics)
+}
+
+func TestUnsentTraces(t *testing.T) {
+	td := pdata.NewTraces()
+	for _, names := range [][][]string{{{"a", "b"}, {"c", "d"}}, {{"e"}}} {
+		ilss := td.ResourceSpans().AppendEmpty().InstrumentationLibrarySpans()
+		for _, ilsNames := range names {
+			spans := ilss.AppendEmpty().Spans()
+			for _, name := range ilsNames {
+				spans.AppendEmpty().SetName(name)
+			}
+		}
+	}
+	spanNames := func(td pdata.Traces) (names []string) {
+		rss := td.ResourceSpans()
+		for i := 0; i < rss.Len(); i++ {
+			ilss := rss.At(i).InstrumentationLibrarySpans()
+			for j := 0; j < ilss.Len(); j++ {
+				for k := 0; k < ilss.At(j).Spans().Len(); k++ {
+					names = append(names, ilss.At(j).Spans().At(k).Name())
+				}
+			}
+		}
+		return names
+	}
+
+	assert.Equal(t, []string{"a", "b", "c", "d", "e"}, spanNames(unsentTraces(td, 0, 0)))
+	assert.Equal(t, []string{"d", "e"}, spanNames(unsentTraces(td, 0, 3)))
+	assert.Equal(t, []string{"e"}, spanNames(unsentTraces(td, 1, 0)))
+	// td is left unchanged.
+	assert.Equal(t, 5, td.SpanCount())
+}
+
+func TestUnsentMetrics(t *testing.T) {
+	md := pdata.NewMetrics()
+	for _, names := range [][][]string{{{"a", "b"}, {"c", "d"}}, {{"e"}}} {
+		ilms := md.ResourceMetrics().AppendEmpty().InstrumentationLibraryMetrics()
+		for _, ilmNames := range names {
+			metrics := ilms.AppendEmpty().Metrics()
+			for _, name := range ilmNames {
+				metrics.AppendEmpty().SetName(name)
+			}
+		}
+	}
+	metricNames := func(md pdata.Metrics) (names []string) {
+		rms := md.ResourceMetrics()
+		for i := 0; i < rms.Len(); i++ {
+			ilms := rms.At(i).InstrumentationLibraryMetrics()
+			for j := 0; j < ilms.Len(); j++ {
+				for k := 0; k < ilms.At(j).Metrics().Len(); k++ {
+					names = append(names, ilms.At(j).Metrics().At(k).Name())
+				}
+			}
+		}
+		return names
+	}
+
+	assert.Equal(t, []string{"a", "b", "c", "d", "e"}, metricNames(unsentMetrics(md, 0, 0)))
+	assert.Equal(t, []string{"c", "d", "e"}, metricNames(unsentMetrics(md, 0, 2)))
+	assert.Equal(t, []string{"e"}, metricNames(unsentMetrics(md, 1, 0)))
+	// md is left unchanged.
+	assert.Equal(t, 5, md.MetricCount())
+}
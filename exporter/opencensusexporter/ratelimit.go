@@ -0,0 +1,137 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package opencensusexporter
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"go.opencensus.io/stats"
+	"go.opencensus.io/tag"
+	"golang.org/x/time/rate"
+
+	"go.opentelemetry.io/collector/config"
+	"go.opentelemetry.io/collector/consumer/consumererror"
+	"go.opentelemetry.io/collector/exporter/exporterhelper"
+)
+
+const (
+	// rateLimitActionWait waits until the export requests over the limit can be sent.
+	rateLimitActionWait = "wait"
+	// rateLimitActionDrop drops the export requests over the limit.
+	rateLimitActionDrop = "drop"
+)
+
+// RateLimitSettings caps the rate of the export requests sent by the exporter
+// across all its workers, e.g. to shape the bursts sent to a shared backend.
+type RateLimitSettings struct {
+	// RequestsPerSecond is the maximum sustained number of export requests sent
+	// per second. Zero (default) disables the rate limit.
+	RequestsPerSecond float64 `mapstructure:"requests_per_second"`
+
+	// Burst is the maximum number of export requests that can be sent at once
+	// when the rate limit was not reached recently. Zero (default) is equivalent to 1.
+	Burst int `mapstructure:"burst"`
+
+	// Action applied to the export requests over the limit: "wait" (default) waits
+	// until the request can be sent, failing it if the exporter timeout expires
+	// first, and "drop" drops the request.
+	Action string `mapstructure:"action"`
+}
+
+func (rls *RateLimitSettings) validate() error {
+	if rls.RequestsPerSecond < 0 {
+		return fmt.Errorf("rate_limit requests_per_second must be non-negative")
+	}
+	if rls.Burst < 0 {
+		return fmt.Errorf("rate_limit burst must be non-negative")
+	}
+	if rls.Action != rateLimitActionWait && rls.Action != rateLimitActionDrop {
+		return fmt.Errorf("rate_limit action must be %q or %q, got %q", rateLimitActionWait, rateLimitActionDrop, rls.Action)
+	}
+	return nil
+}
+
+// errWaitExceedsDeadline is returned when the wait for an export request over the
+// rate limit would not end before the deadline of its context.
+var errWaitExceedsDeadline = errors.New("wait would exceed the context deadline")
+
+// errDroppedByRateLimit is returned, as a permanent error, for the export requests
+// dropped by the "drop" action, so that they are reported as failed and not retried.
+var errDroppedByRateLimit = errors.New("export request dropped by the rate limit")
+
+// rateLimiter applies the RateLimitSettings to the export requests, using a token
+// bucket shared by all the workers and refilled following the clock. A nil
+// rateLimiter sends all the requests.
+type rateLimiter struct {
+	limiter *rate.Limiter
+	action  string
+	clock   exporterhelper.Clock
+	// ctx holds the tags used to record the number of rate limited requests.
+	ctx context.Context
+}
+
+func newRateLimiter(exporter config.ComponentID, dataType config.DataType, cfg RateLimitSettings, clock exporterhelper.Clock) *rateLimiter {
+	if cfg.RequestsPerSecond <= 0 {
+		return nil
+	}
+	burst := cfg.Burst
+	if burst < 1 {
+		burst = 1
+	}
+	ctx, _ := tag.New(context.Background(),
+		tag.Upsert(tagKeyExporter, exporter.String()),
+		tag.Upsert(tagKeyDataType, string(dataType)),
+		tag.Upsert(tagKeyAction, cfg.Action))
+	return &rateLimiter{
+		limiter: rate.NewLimiter(rate.Limit(cfg.RequestsPerSecond), burst),
+		action:  cfg.Action,
+		clock:   clock,
+		ctx:     ctx,
+	}
+}
+
+// acquire returns nil once an export request can be sent, waiting for it if the
+// action is "wait". An error is returned if ctx is done, or its deadline expires,
+// before the request can be sent, and a permanent error if the request is dropped.
+func (rl *rateLimiter) acquire(ctx context.Context) error {
+	if rl == nil {
+		return nil
+	}
+	now := rl.clock.Now()
+	if rl.limiter.AllowN(now, 1) {
+		return nil
+	}
+	stats.Record(rl.ctx, mRateLimitedRequests.M(1))
+	if rl.action == rateLimitActionDrop {
+		return consumererror.Permanent(errDroppedByRateLimit)
+	}
+
+	// The burst is at least 1, so the reservation of a single request is always possible.
+	r := rl.limiter.ReserveN(now, 1)
+	delay := r.DelayFrom(now)
+	if deadline, ok := ctx.Deadline(); ok && time.Until(deadline) < delay {
+		r.CancelAt(now)
+		return fmt.Errorf("export request over the rate limit: %w", errWaitExceedsDeadline)
+	}
+	select {
+	case <-rl.clock.After(delay):
+		return nil
+	case <-ctx.Done():
+		r.CancelAt(rl.clock.Now())
+		return fmt.Errorf("export request over the rate limit: %w", ctx.Err())
+	}
+}
@@ -0,0 +1,203 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package opencensusexporter
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opencensus.io/stats/view"
+	"go.opencensus.io/tag"
+
+	"go.opentelemetry.io/collector/config"
+	"go.opentelemetry.io/collector/consumer/consumererror"
+	"go.opentelemetry.io/collector/exporter/exporterhelper"
+)
+
+// fakeClock is an exporterhelper.Clock whose time only passes when advanced by the tests.
+type fakeClock struct {
+	mu      sync.Mutex
+	now     time.Time
+	waiters []fakeWaiter
+}
+
+type fakeWaiter struct {
+	deadline time.Time
+	ch       chan time.Time
+}
+
+func newFakeClock() *fakeClock {
+	return &fakeClock{now: time.Unix(1600000000, 0)}
+}
+
+func (c *fakeClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+func (c *fakeClock) After(d time.Duration) <-chan time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	ch := make(chan time.Time, 1)
+	if d <= 0 {
+		ch <- c.now
+		return ch
+	}
+	c.waiters = append(c.waiters, fakeWaiter{deadline: c.now.Add(d), ch: ch})
+	return ch
+}
+
+// Advance moves the time forward by d, firing the channels of the elapsed waits.
+func (c *fakeClock) Advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = c.now.Add(d)
+	waiters := c.waiters[:0]
+	for _, w := range c.waiters {
+		if w.deadline.After(c.now) {
+			waiters = append(waiters, w)
+			continue
+		}
+		w.ch <- c.now
+	}
+	c.waiters = waiters
+}
+
+// numWaiters returns the number of pending waits.
+func (c *fakeClock) numWaiters() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return len(c.waiters)
+}
+
+func newTestRateLimiter(name string, requestsPerSecond float64, burst int, action string, clock exporterhelper.Clock) *rateLimiter {
+	return newRateLimiter(config.NewIDWithName(typeStr, name), config.TracesDataType, RateLimitSettings{
+		RequestsPerSecond: requestsPerSecond,
+		Burst:             burst,
+		Action:            action,
+	}, clock)
+}
+
+func TestRateLimiterDisabled(t *testing.T) {
+	rl := newTestRateLimiter("disabled", 0, 10, rateLimitActionDrop, exporterhelper.NewSystemClock())
+	require.Nil(t, rl)
+	for i := 0; i < 100; i++ {
+		require.NoError(t, rl.acquire(context.Background()))
+	}
+}
+
+func TestRateLimiterWait(t *testing.T) {
+	views := MetricViews()
+	require.NoError(t, view.Register(views...))
+	defer view.Unregister(views...)
+
+	clock := newFakeClock()
+	rl := newTestRateLimiter("wait", 50, 5, rateLimitActionWait, clock)
+	// The burst is sent at once.
+	for i := 0; i < 5; i++ {
+		require.NoError(t, rl.acquire(context.Background()))
+	}
+
+	// The next requests are shaped to 50 per second, one every 20ms.
+	for i := 0; i < 3; i++ {
+		done := make(chan error, 1)
+		go func() {
+			done <- rl.acquire(context.Background())
+		}()
+		require.Eventually(t, func() bool { return clock.numWaiters() == 1 }, time.Second, time.Millisecond)
+		clock.Advance(19 * time.Millisecond)
+		select {
+		case <-done:
+			t.Fatal("the request must wait for the rate limit")
+		default:
+		}
+		clock.Advance(time.Millisecond)
+		assert.NoError(t, <-done)
+	}
+	assertRateLimitedRequests(t, "wait", rateLimitActionWait, 3)
+}
+
+func TestRateLimiterWaitCancel(t *testing.T) {
+	clock := newFakeClock()
+	rl := newTestRateLimiter("wait_cancel", 1, 1, rateLimitActionWait, clock)
+	require.NoError(t, rl.acquire(context.Background()))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() {
+		done <- rl.acquire(ctx)
+	}()
+	require.Eventually(t, func() bool { return clock.numWaiters() == 1 }, time.Second, time.Millisecond)
+	cancel()
+	assert.ErrorIs(t, <-done, context.Canceled)
+
+	// The reservation of the canceled request is given back.
+	clock.Advance(time.Second)
+	assert.NoError(t, rl.acquire(context.Background()))
+}
+
+func TestRateLimiterWaitTimeout(t *testing.T) {
+	rl := newTestRateLimiter("wait_timeout", 1, 1, rateLimitActionWait, exporterhelper.NewSystemClock())
+	require.NoError(t, rl.acquire(context.Background()))
+
+	// The next request cannot be sent before the deadline.
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	err := rl.acquire(ctx)
+	assert.ErrorIs(t, err, errWaitExceedsDeadline)
+	assert.False(t, consumererror.IsPermanent(err))
+}
+
+func TestRateLimiterDrop(t *testing.T) {
+	views := MetricViews()
+	require.NoError(t, view.Register(views...))
+	defer view.Unregister(views...)
+
+	rl := newTestRateLimiter("drop", 1, 2, rateLimitActionDrop, newFakeClock())
+	var sent int
+	for i := 0; i < 5; i++ {
+		err := rl.acquire(context.Background())
+		if err == nil {
+			sent++
+			continue
+		}
+		// The dropped requests are failed without retries.
+		assert.ErrorIs(t, err, errDroppedByRateLimit)
+		assert.True(t, consumererror.IsPermanent(err))
+	}
+	assert.Equal(t, 2, sent)
+	assertRateLimitedRequests(t, "drop", rateLimitActionDrop, 3)
+}
+
+func assertRateLimitedRequests(t *testing.T, name string, action string, expected int64) {
+	rows, err := view.RetrieveData(mRateLimitedRequests.Name())
+	require.NoError(t, err)
+	for _, row := range rows {
+		expectedTags := []tag.Tag{
+			{Key: tagKeyAction, Value: action},
+			{Key: tagKeyDataType, Value: string(config.TracesDataType)},
+			{Key: tagKeyExporter, Value: config.NewIDWithName(typeStr, name).String()},
+		}
+		if assert.ObjectsAreEqual(expectedTags, row.Tags) {
+			assert.Equal(t, expected, int64(row.Data.(*view.SumData).Value))
+			return
+		}
+	}
+	assert.Fail(t, "no rate limited requests recorded", "exporter %s", name)
+}
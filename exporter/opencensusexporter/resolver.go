@@ -0,0 +1,197 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package opencensusexporter
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"sync"
+	"time"
+
+	"google.golang.org/grpc/resolver"
+
+	"go.opentelemetry.io/collector/exporter/exporterhelper"
+)
+
+// dnsScheme is the scheme of the resolver registered for exporters that
+// configure DNSSettings. The resolver is only registered in the dial options of
+// the exporter, so it does not affect any other gRPC client.
+const dnsScheme = "opencensus-dns"
+
+// dnsResolveTimeout bounds every resolution of the endpoint.
+const dnsResolveTimeout = 10 * time.Second
+
+// minResolveInterval is the minimum delay between two resolutions of the endpoint,
+// like the gRPC DNS resolver, so that a flapping backend does not hammer the nameserver.
+const minResolveInterval = 30 * time.Second
+
+// DNSSettings defines how the host of the endpoint is resolved.
+type DNSSettings struct {
+	// Nameserver is the "ip:port" of the DNS server used to resolve the endpoint
+	// instead of the one configured in the system. Useful in split-horizon DNS setups.
+	Nameserver string `mapstructure:"nameserver"`
+
+	// Hosts maps host names to static IP addresses, similar to an /etc/hosts file.
+	// Hosts take precedence over the Nameserver.
+	Hosts map[string]string `mapstructure:"hosts"`
+}
+
+// enabled returns true if the endpoint must be resolved by the dnsResolver.
+func (ds DNSSettings) enabled() bool {
+	return ds.Nameserver != "" || len(ds.Hosts) > 0
+}
+
+// validate checks that the Nameserver and the Hosts addresses are valid.
+func (ds DNSSettings) validate() error {
+	if ds.Nameserver != "" {
+		host, port, err := net.SplitHostPort(ds.Nameserver)
+		if err != nil {
+			return fmt.Errorf("invalid dns nameserver %q: %w", ds.Nameserver, err)
+		}
+		if net.ParseIP(host) == nil {
+			return fmt.Errorf("invalid dns nameserver %q: host must be an IP address", ds.Nameserver)
+		}
+		if _, err = net.LookupPort("udp", port); err != nil {
+			return fmt.Errorf("invalid dns nameserver %q: %w", ds.Nameserver, err)
+		}
+	}
+	for host, ip := range ds.Hosts {
+		if net.ParseIP(ip) == nil {
+			return fmt.Errorf("invalid dns hosts entry for %q: %q is not an IP address", host, ip)
+		}
+	}
+	return nil
+}
+
+// dnsResolverBuilder builds resolvers that resolve the endpoint using the DNSSettings.
+type dnsResolverBuilder struct {
+	settings DNSSettings
+	resolver *net.Resolver
+	clock    exporterhelper.Clock
+}
+
+var _ resolver.Builder = (*dnsResolverBuilder)(nil)
+
+func newDNSResolverBuilder(settings DNSSettings, clock exporterhelper.Clock) *dnsResolverBuilder {
+	netResolver := net.DefaultResolver
+	if settings.Nameserver != "" {
+		netResolver = &net.Resolver{
+			PreferGo: true,
+			Dial: func(ctx context.Context, network, _ string) (net.Conn, error) {
+				var d net.Dialer
+				return d.DialContext(ctx, network, settings.Nameserver)
+			},
+		}
+	}
+	return &dnsResolverBuilder{settings: settings, resolver: netResolver, clock: clock}
+}
+
+func (b *dnsResolverBuilder) Scheme() string {
+	return dnsScheme
+}
+
+func (b *dnsResolverBuilder) Build(target resolver.Target, cc resolver.ClientConn, _ resolver.BuildOptions) (resolver.Resolver, error) {
+	host, port, err := net.SplitHostPort(target.Endpoint)
+	if err != nil {
+		return nil, err
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	r := &dnsResolver{
+		builder: b,
+		host:    host,
+		port:    port,
+		cc:      cc,
+		ctx:     ctx,
+		cancel:  cancel,
+		rn:      make(chan struct{}, 1),
+	}
+	r.wg.Add(1)
+	go r.watch()
+	return r, nil
+}
+
+// dnsResolver resolves the endpoint when built and every time gRPC requests it, at
+// most once every minResolveInterval.
+type dnsResolver struct {
+	builder *dnsResolverBuilder
+	host    string
+	port    string
+	cc      resolver.ClientConn
+	ctx     context.Context
+	cancel  context.CancelFunc
+	// rn holds the pending resolution request, the requests made while one is
+	// pending are merged into it.
+	rn chan struct{}
+	wg sync.WaitGroup
+}
+
+// watch resolves the endpoint in a single goroutine, so that the states are
+// updated in the order of the resolutions.
+func (r *dnsResolver) watch() {
+	defer r.wg.Done()
+	for {
+		r.resolve()
+		select {
+		case <-r.ctx.Done():
+			return
+		case <-r.builder.clock.After(minResolveInterval):
+		}
+		select {
+		case <-r.ctx.Done():
+			return
+		case <-r.rn:
+		}
+	}
+}
+
+func (r *dnsResolver) resolve() {
+	addrs, err := r.lookup()
+	if err != nil {
+		r.cc.ReportError(err)
+		return
+	}
+	state := resolver.State{}
+	for _, addr := range addrs {
+		state.Addresses = append(state.Addresses, resolver.Address{Addr: net.JoinHostPort(addr, r.port)})
+	}
+	r.cc.UpdateState(state)
+}
+
+func (r *dnsResolver) lookup() ([]string, error) {
+	if ip, ok := r.builder.settings.Hosts[r.host]; ok {
+		return []string{ip}, nil
+	}
+	if net.ParseIP(r.host) != nil {
+		return []string{r.host}, nil
+	}
+	ctx, cancel := context.WithTimeout(r.ctx, dnsResolveTimeout)
+	defer cancel()
+	return r.builder.resolver.LookupHost(ctx, r.host)
+}
+
+// ResolveNow is called by gRPC when the connection fails, the resolution happens
+// asynchronously once minResolveInterval elapsed since the previous one.
+func (r *dnsResolver) ResolveNow(resolver.ResolveNowOptions) {
+	select {
+	case r.rn <- struct{}{}:
+	default:
+	}
+}
+
+func (r *dnsResolver) Close() {
+	r.cancel()
+	r.wg.Wait()
+}
@@ -0,0 +1,251 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package opencensusexporter
+
+import (
+	"context"
+	"encoding/binary"
+	"net"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/resolver"
+
+	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/config/configgrpc"
+	"go.opentelemetry.io/collector/config/configtls"
+	"go.opentelemetry.io/collector/internal/testdata"
+	"go.opentelemetry.io/collector/testutil/octest"
+)
+
+func TestDNSSettingsValidate(t *testing.T) {
+	tests := []struct {
+		name     string
+		settings DNSSettings
+		errMsg   string
+	}{
+		{
+			name: "empty",
+		},
+		{
+			name:     "valid",
+			settings: DNSSettings{Nameserver: "10.0.0.2:53", Hosts: map[string]string{"collector": "10.1.2.3"}},
+		},
+		{
+			name:     "valid_ipv6",
+			settings: DNSSettings{Nameserver: "[::1]:53"},
+		},
+		{
+			name:     "missing_port",
+			settings: DNSSettings{Nameserver: "10.0.0.2"},
+			errMsg:   `invalid dns nameserver "10.0.0.2": address 10.0.0.2: missing port in address`,
+		},
+		{
+			name:     "host_name",
+			settings: DNSSettings{Nameserver: "dns.example.com:53"},
+			errMsg:   `invalid dns nameserver "dns.example.com:53": host must be an IP address`,
+		},
+		{
+			name:     "invalid_port",
+			settings: DNSSettings{Nameserver: "10.0.0.2:port"},
+			errMsg:   `invalid dns nameserver "10.0.0.2:port": lookup udp/port: unknown port`,
+		},
+		{
+			name:     "invalid_hosts_ip",
+			settings: DNSSettings{Hosts: map[string]string{"collector": "collector.internal"}},
+			errMsg:   `invalid dns hosts entry for "collector": "collector.internal" is not an IP address`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.settings.validate()
+			if tt.errMsg == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.EqualError(t, err, tt.errMsg)
+			}
+		})
+	}
+}
+
+// recordingClientConn is a resolver.ClientConn recording the updated states.
+type recordingClientConn struct {
+	resolver.ClientConn
+	mu     sync.Mutex
+	states []resolver.State
+}
+
+func (cc *recordingClientConn) UpdateState(state resolver.State) {
+	cc.mu.Lock()
+	defer cc.mu.Unlock()
+	cc.states = append(cc.states, state)
+}
+
+func (cc *recordingClientConn) numStates() int {
+	cc.mu.Lock()
+	defer cc.mu.Unlock()
+	return len(cc.states)
+}
+
+func TestDNSResolverResolveNow(t *testing.T) {
+	clock := newFakeClock()
+	builder := newDNSResolverBuilder(DNSSettings{Hosts: map[string]string{"collector.invalid": "127.0.0.1"}}, clock)
+	cc := &recordingClientConn{}
+	r, err := builder.Build(resolver.Target{Endpoint: "collector.invalid:55678"}, cc, resolver.BuildOptions{})
+	require.NoError(t, err)
+	defer r.Close()
+	require.Eventually(t, func() bool { return cc.numStates() == 1 }, time.Second, time.Millisecond)
+	assert.Equal(t, []resolver.Address{{Addr: "127.0.0.1:55678"}}, cc.states[0].Addresses)
+
+	// The requests made before minResolveInterval elapsed are merged in one resolution.
+	for i := 0; i < 10; i++ {
+		r.ResolveNow(resolver.ResolveNowOptions{})
+	}
+	require.Eventually(t, func() bool { return clock.numWaiters() == 1 }, time.Second, time.Millisecond)
+	clock.Advance(minResolveInterval - time.Second)
+	assert.Equal(t, 1, cc.numStates())
+	clock.Advance(time.Second)
+	require.Eventually(t, func() bool { return cc.numStates() == 2 }, time.Second, time.Millisecond)
+
+	// No resolution happens without a request.
+	require.Eventually(t, func() bool { return clock.numWaiters() == 1 }, time.Second, time.Millisecond)
+	clock.Advance(minResolveInterval)
+	time.Sleep(10 * time.Millisecond)
+	assert.Equal(t, 2, cc.numStates())
+}
+
+func TestSendTraces_DNSHosts(t *testing.T) {
+	srv, err := octest.NewMockServer()
+	require.NoError(t, err)
+	defer srv.Stop()
+	_, port, err := net.SplitHostPort(srv.Endpoint())
+	require.NoError(t, err)
+
+	cfg := createDefaultConfig().(*Config)
+	cfg.GRPCClientSettings = configgrpc.GRPCClientSettings{
+		Endpoint: net.JoinHostPort("collector.invalid", port),
+		TLSSetting: configtls.TLSClientSetting{
+			Insecure: true,
+		},
+	}
+	cfg.DNS = DNSSettings{Hosts: map[string]string{"collector.invalid": "127.0.0.1"}}
+	sendTracesAndWait(t, cfg, srv)
+}
+
+func TestSendTraces_DNSNameserver(t *testing.T) {
+	srv, err := octest.NewMockServer()
+	require.NoError(t, err)
+	defer srv.Stop()
+	_, port, err := net.SplitHostPort(srv.Endpoint())
+	require.NoError(t, err)
+
+	cfg := createDefaultConfig().(*Config)
+	cfg.GRPCClientSettings = configgrpc.GRPCClientSettings{
+		Endpoint: net.JoinHostPort("collector.invalid", port),
+		TLSSetting: configtls.TLSClientSetting{
+			Insecure: true,
+		},
+	}
+	cfg.DNS = DNSSettings{Nameserver: startDNSServer(t, "collector.invalid.", [4]byte{127, 0, 0, 1})}
+	sendTracesAndWait(t, cfg, srv)
+}
+
+func sendTracesAndWait(t *testing.T, cfg *Config, srv *octest.MockServer) {
+	require.NoError(t, cfg.Validate())
+	exp, err := newTracesExporter(context.Background(), cfg)
+	require.NoError(t, err)
+	require.NoError(t, exp.start(context.Background(), componenttest.NewNopHost()))
+	t.Cleanup(func() {
+		assert.NoError(t, exp.shutdown(context.Background()))
+	})
+
+	// The first export may fail while the endpoint is still being resolved.
+	assert.Eventually(t, func() bool {
+		return exp.pushTraceData(context.Background(), testdata.GenerateTracesOneSpan()) == nil
+	}, 10*time.Second, 10*time.Millisecond)
+	assert.Eventually(t, func() bool {
+		return srv.SpansCount() > 0
+	}, 10*time.Second, 5*time.Millisecond)
+}
+
+// startDNSServer starts a UDP DNS server that answers the A queries for name
+// with ip, and returns its address.
+func startDNSServer(t *testing.T, name string, ip [4]byte) string {
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() { assert.NoError(t, conn.Close()) })
+
+	go func() {
+		buf := make([]byte, 512)
+		for {
+			n, addr, err := conn.ReadFrom(buf)
+			if err != nil {
+				return
+			}
+			if resp := dnsResponse(buf[:n], name, ip); resp != nil {
+				_, _ = conn.WriteTo(resp, addr)
+			}
+		}
+	}()
+	return conn.LocalAddr().String()
+}
+
+// dnsResponse returns the response to the DNS query req, answering the A query
+// for name with ip, or nil if req is not a valid query.
+func dnsResponse(req []byte, name string, ip [4]byte) []byte {
+	const headerLen = 12
+	if len(req) < headerLen || binary.BigEndian.Uint16(req[4:]) == 0 {
+		return nil
+	}
+	// Read the name of the first question, a sequence of length-prefixed labels.
+	var labels []string
+	off := headerLen
+	for off < len(req) && req[off] != 0 {
+		l := int(req[off])
+		if off+1+l > len(req) {
+			return nil
+		}
+		labels = append(labels, string(req[off+1:off+1+l]))
+		off += 1 + l
+	}
+	// Skip the terminating zero length, then read the type and the class.
+	off++
+	if off+4 > len(req) {
+		return nil
+	}
+	qtype := binary.BigEndian.Uint16(req[off:])
+	question := req[headerLen : off+4]
+
+	resp := make([]byte, headerLen, 512)
+	copy(resp, req[:2])                          // ID
+	binary.BigEndian.PutUint16(resp[2:], 0x8400) // response, authoritative
+	binary.BigEndian.PutUint16(resp[4:], 1)      // questions
+	resp = append(resp, question...)
+	if qtype == 1 && strings.Join(labels, ".")+"." == name {
+		binary.BigEndian.PutUint16(resp[6:], 1) // answers
+		resp = append(resp,
+			0xc0, headerLen, // name, pointing at the question
+			0, 1, // type A
+			0, 1, // class IN
+			0, 0, 0, 60, // TTL
+			0, 4) // data length
+		resp = append(resp, ip[:]...)
+	}
+	return resp
+}
@@ -0,0 +1,88 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package opencensusexporter
+
+import (
+	"fmt"
+
+	"go.opentelemetry.io/collector/consumer/pdata"
+)
+
+const (
+	// resourceConflictKeep keeps the value of the resource attributes already set.
+	resourceConflictKeep = "keep"
+	// resourceConflictOverwrite replaces the value of the resource attributes already set.
+	resourceConflictOverwrite = "overwrite"
+)
+
+func validateResourceConflict(conflict string) error {
+	if conflict != resourceConflictKeep && conflict != resourceConflictOverwrite {
+		return fmt.Errorf("resource_attributes_conflict must be %q or %q, got %q", resourceConflictKeep, resourceConflictOverwrite, conflict)
+	}
+	return nil
+}
+
+// resourceEnricher adds the configured static attributes to the resources of the
+// exported data.
+type resourceEnricher struct {
+	attributes map[string]string
+	overwrite  bool
+}
+
+func newResourceEnricher(attributes map[string]string, conflict string) *resourceEnricher {
+	return &resourceEnricher{
+		attributes: attributes,
+		overwrite:  conflict == resourceConflictOverwrite,
+	}
+}
+
+// enrichTraces returns a copy of td with the attributes added to every resource,
+// or td itself if there is no attribute to add.
+func (re *resourceEnricher) enrichTraces(td pdata.Traces) pdata.Traces {
+	if len(re.attributes) == 0 {
+		return td
+	}
+	td = td.Clone()
+	rss := td.ResourceSpans()
+	for i := 0; i < rss.Len(); i++ {
+		re.enrich(rss.At(i).Resource())
+	}
+	return td
+}
+
+// enrichMetrics returns a copy of md with the attributes added to every resource,
+// or md itself if there is no attribute to add.
+func (re *resourceEnricher) enrichMetrics(md pdata.Metrics) pdata.Metrics {
+	if len(re.attributes) == 0 {
+		return md
+	}
+	md = md.Clone()
+	rms := md.ResourceMetrics()
+	for i := 0; i < rms.Len(); i++ {
+		re.enrich(rms.At(i).Resource())
+	}
+	return md
+}
+
+func (re *resourceEnricher) enrich(resource pdata.Resource) {
+	attrs := resource.Attributes()
+	for key, value := range re.attributes {
+		if re.overwrite {
+			attrs.UpsertString(key, value)
+		} else {
+			attrs.InsertString(key, value)
+		}
+	}
+}
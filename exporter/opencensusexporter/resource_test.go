@@ -0,0 +1,103 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package opencensusexporter
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go.opentelemetry.io/collector/consumer/pdata"
+	"go.opentelemetry.io/collector/consumer/pdatabuilder"
+)
+
+func TestValidateResourceConflict(t *testing.T) {
+	assert.NoError(t, validateResourceConflict(resourceConflictKeep))
+	assert.NoError(t, validateResourceConflict(resourceConflictOverwrite))
+	assert.EqualError(t, validateResourceConflict("merge"), `resource_attributes_conflict must be "keep" or "overwrite", got "merge"`)
+}
+
+func TestResourceEnricherDisabled(t *testing.T) {
+	re := newResourceEnricher(nil, resourceConflictKeep)
+	td := pdatabuilder.NewTraces().Resource(pdatabuilder.Attrs{"env": "dev"}).Span("a").Build()
+	assert.Equal(t, td, re.enrichTraces(td))
+	md := pdatabuilder.NewMetrics().Resource(pdatabuilder.Attrs{"env": "dev"}).DoubleGauge("m").Build()
+	assert.Equal(t, md, re.enrichMetrics(md))
+}
+
+func TestResourceEnricherTraces(t *testing.T) {
+	attrs := map[string]string{"env": "prod", "region": "us-east-1"}
+	tests := []struct {
+		conflict string
+		wantEnv  string
+	}{
+		{conflict: resourceConflictKeep, wantEnv: "dev"},
+		{conflict: resourceConflictOverwrite, wantEnv: "prod"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.conflict, func(t *testing.T) {
+			re := newResourceEnricher(attrs, tt.conflict)
+			td := pdatabuilder.NewTraces().
+				Resource(pdatabuilder.Attrs{"env": "dev"}).Span("a").
+				Resource(nil).Span("b").
+				Build()
+			orig := td.Clone()
+
+			got := re.enrichTraces(td)
+			// The original data is not modified.
+			assert.Equal(t, orig, td)
+			rss := got.ResourceSpans()
+			require.Equal(t, 2, rss.Len())
+			assertResourceAttrs(t, map[string]string{"env": tt.wantEnv, "region": "us-east-1"}, rss.At(0).Resource())
+			assertResourceAttrs(t, attrs, rss.At(1).Resource())
+		})
+	}
+}
+
+func TestResourceEnricherMetrics(t *testing.T) {
+	attrs := map[string]string{"env": "prod"}
+	tests := []struct {
+		conflict string
+		wantEnv  string
+	}{
+		{conflict: resourceConflictKeep, wantEnv: "dev"},
+		{conflict: resourceConflictOverwrite, wantEnv: "prod"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.conflict, func(t *testing.T) {
+			re := newResourceEnricher(attrs, tt.conflict)
+			md := pdatabuilder.NewMetrics().
+				Resource(pdatabuilder.Attrs{"env": "dev", "host": "h1"}).DoubleGauge("m").DoubleDataPoint(1, nil).
+				Build()
+			orig := md.Clone()
+
+			got := re.enrichMetrics(md)
+			// The original data is not modified.
+			assert.Equal(t, orig, md)
+			require.Equal(t, 1, got.ResourceMetrics().Len())
+			assertResourceAttrs(t, map[string]string{"env": tt.wantEnv, "host": "h1"}, got.ResourceMetrics().At(0).Resource())
+		})
+	}
+}
+
+func assertResourceAttrs(t *testing.T, want map[string]string, resource pdata.Resource) {
+	got := map[string]string{}
+	resource.Attributes().Range(func(k string, v pdata.AttributeValue) bool {
+		got[k] = v.StringVal()
+		return true
+	})
+	assert.Equal(t, want, got)
+}
@@ -0,0 +1,158 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package opencensusexporter
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"go.opencensus.io/stats"
+	"go.opencensus.io/tag"
+
+	"go.opentelemetry.io/collector/config"
+	"go.opentelemetry.io/collector/consumer/pdata"
+	"go.opentelemetry.io/collector/exporter/exporterhelper"
+)
+
+const (
+	// skewActionClamp moves the out of window timestamps to the closest bound of the window.
+	skewActionClamp = "clamp"
+	// skewActionDrop drops the spans with out of window timestamps.
+	skewActionDrop = "drop"
+)
+
+// TimestampSkewSettings defines how the spans with timestamps too far from the
+// current time, e.g. because of a clock skew of the producer, are handled before
+// being exported, since some backends reject them.
+type TimestampSkewSettings struct {
+	// MaxSkew is the maximum difference, in the past or in the future, between the
+	// start and end timestamps of a span and the current time. Zero (default) disables it.
+	MaxSkew time.Duration `mapstructure:"max_skew"`
+
+	// Action applied to the spans exceeding the MaxSkew: "clamp" (default) moves the
+	// timestamps to the closest bound of the window, which preserves their order,
+	// and "drop" drops the spans.
+	Action string `mapstructure:"action"`
+}
+
+func (tss *TimestampSkewSettings) validate() error {
+	if tss.MaxSkew < 0 {
+		return fmt.Errorf("timestamp_skew max_skew must be non-negative")
+	}
+	if tss.Action != skewActionClamp && tss.Action != skewActionDrop {
+		return fmt.Errorf("timestamp_skew action must be %q or %q, got %q", skewActionClamp, skewActionDrop, tss.Action)
+	}
+	return nil
+}
+
+// timestampNormalizer applies the TimestampSkewSettings to the exported spans.
+type timestampNormalizer struct {
+	cfg TimestampSkewSettings
+	// ctx holds the tags used to record the number of skewed spans.
+	ctx   context.Context
+	clock exporterhelper.Clock
+}
+
+func newTimestampNormalizer(exporter config.ComponentID, cfg TimestampSkewSettings, clock exporterhelper.Clock) *timestampNormalizer {
+	ctx, _ := tag.New(context.Background(),
+		tag.Upsert(tagKeyExporter, exporter.String()),
+		tag.Upsert(tagKeyAction, cfg.Action))
+	return &timestampNormalizer{cfg: cfg, ctx: ctx, clock: clock}
+}
+
+// normalize returns td with the skewed spans clamped or dropped. The given td is
+// never modified, a copy is returned if any span needs to be changed.
+func (tn *timestampNormalizer) normalize(td pdata.Traces) pdata.Traces {
+	if tn.cfg.MaxSkew <= 0 {
+		return td
+	}
+	now := tn.clock.Now()
+	minTs := pdata.TimestampFromTime(now.Add(-tn.cfg.MaxSkew))
+	maxTs := pdata.TimestampFromTime(now.Add(tn.cfg.MaxSkew))
+	isSkewed := func(span pdata.Span) bool {
+		return isOutOfWindow(span.StartTimestamp(), minTs, maxTs) || isOutOfWindow(span.EndTimestamp(), minTs, maxTs)
+	}
+
+	// Check first if there is any skewed span to avoid copying the data in the common case.
+	if !anySpan(td, isSkewed) {
+		return td
+	}
+
+	td = td.Clone()
+	skewed := 0
+	rss := td.ResourceSpans()
+	for i := 0; i < rss.Len(); i++ {
+		ilss := rss.At(i).InstrumentationLibrarySpans()
+		for j := 0; j < ilss.Len(); j++ {
+			spans := ilss.At(j).Spans()
+			if tn.cfg.Action == skewActionDrop {
+				spans.RemoveIf(func(span pdata.Span) bool {
+					if isSkewed(span) {
+						skewed++
+						return true
+					}
+					return false
+				})
+				continue
+			}
+			for k := 0; k < spans.Len(); k++ {
+				span := spans.At(k)
+				if isSkewed(span) {
+					skewed++
+					span.SetStartTimestamp(clampTimestamp(span.StartTimestamp(), minTs, maxTs))
+					span.SetEndTimestamp(clampTimestamp(span.EndTimestamp(), minTs, maxTs))
+				}
+			}
+		}
+	}
+	stats.Record(tn.ctx, mSkewedSpans.M(int64(skewed)))
+	return td
+}
+
+// isOutOfWindow returns true if ts is set and outside of [minTs, maxTs].
+func isOutOfWindow(ts, minTs, maxTs pdata.Timestamp) bool {
+	return ts != 0 && (ts < minTs || ts > maxTs)
+}
+
+// clampTimestamp returns the closest timestamp to ts in [minTs, maxTs], or ts if it is not set.
+func clampTimestamp(ts, minTs, maxTs pdata.Timestamp) pdata.Timestamp {
+	switch {
+	case ts == 0:
+		return ts
+	case ts < minTs:
+		return minTs
+	case ts > maxTs:
+		return maxTs
+	}
+	return ts
+}
+
+// anySpan returns true if f returns true for any span in td.
+func anySpan(td pdata.Traces, f func(pdata.Span) bool) bool {
+	rss := td.ResourceSpans()
+	for i := 0; i < rss.Len(); i++ {
+		ilss := rss.At(i).InstrumentationLibrarySpans()
+		for j := 0; j < ilss.Len(); j++ {
+			spans := ilss.At(j).Spans()
+			for k := 0; k < spans.Len(); k++ {
+				if f(spans.At(k)) {
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
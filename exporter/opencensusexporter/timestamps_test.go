@@ -0,0 +1,127 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package opencensusexporter
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opencensus.io/stats/view"
+	"go.opencensus.io/tag"
+
+	"go.opentelemetry.io/collector/config"
+	"go.opentelemetry.io/collector/consumer/pdata"
+)
+
+var skewNow = time.Date(2021, 6, 1, 12, 0, 0, 0, time.UTC)
+
+// generateTracesWithTimestamps returns a span for every pair of start and end offsets
+// from skewNow, named after its index.
+func generateTracesWithTimestamps(offsets ...[2]time.Duration) pdata.Traces {
+	td := pdata.NewTraces()
+	spans := td.ResourceSpans().AppendEmpty().InstrumentationLibrarySpans().AppendEmpty().Spans()
+	for i, offset := range offsets {
+		span := spans.AppendEmpty()
+		span.SetName(string(rune('a' + i)))
+		span.SetStartTimestamp(pdata.TimestampFromTime(skewNow.Add(offset[0])))
+		span.SetEndTimestamp(pdata.TimestampFromTime(skewNow.Add(offset[1])))
+	}
+	return td
+}
+
+func newTestTimestampNormalizer(name string, action string) *timestampNormalizer {
+	return newTimestampNormalizer(config.NewIDWithName(typeStr, name), TimestampSkewSettings{MaxSkew: time.Minute, Action: action}, &fakeClock{now: skewNow})
+}
+
+func TestTimestampNormalizerDisabled(t *testing.T) {
+	tn := newTimestampNormalizer(config.NewID(typeStr), TimestampSkewSettings{Action: skewActionClamp}, newFakeClock())
+	td := generateTracesWithTimestamps([2]time.Duration{-time.Hour, time.Hour})
+	assert.Equal(t, td, tn.normalize(td))
+}
+
+func TestTimestampNormalizerClamp(t *testing.T) {
+	views := MetricViews()
+	require.NoError(t, view.Register(views...))
+	defer view.Unregister(views...)
+
+	tn := newTestTimestampNormalizer("clamp", skewActionClamp)
+	td := generateTracesWithTimestamps(
+		[2]time.Duration{-time.Second, 0},
+		[2]time.Duration{-time.Hour, -30 * time.Second},
+		[2]time.Duration{30 * time.Second, time.Hour},
+	)
+	orig := td.Clone()
+
+	got := tn.normalize(td)
+	// The original data is not modified.
+	assert.Equal(t, orig, td)
+	spans := got.ResourceSpans().At(0).InstrumentationLibrarySpans().At(0).Spans()
+	require.Equal(t, 3, spans.Len())
+	assert.Equal(t, pdata.TimestampFromTime(skewNow.Add(-time.Second)), spans.At(0).StartTimestamp())
+	assert.Equal(t, pdata.TimestampFromTime(skewNow.Add(-time.Minute)), spans.At(1).StartTimestamp())
+	assert.Equal(t, pdata.TimestampFromTime(skewNow.Add(-30*time.Second)), spans.At(1).EndTimestamp())
+	assert.Equal(t, pdata.TimestampFromTime(skewNow.Add(30*time.Second)), spans.At(2).StartTimestamp())
+	assert.Equal(t, pdata.TimestampFromTime(skewNow.Add(time.Minute)), spans.At(2).EndTimestamp())
+
+	assertSkewedSpans(t, "clamp", skewActionClamp, 2)
+}
+
+func TestTimestampNormalizerDrop(t *testing.T) {
+	views := MetricViews()
+	require.NoError(t, view.Register(views...))
+	defer view.Unregister(views...)
+
+	tn := newTestTimestampNormalizer("drop", skewActionDrop)
+	td := generateTracesWithTimestamps(
+		[2]time.Duration{-time.Hour, 0},
+		[2]time.Duration{-time.Second, 0},
+		[2]time.Duration{0, time.Hour},
+	)
+	orig := td.Clone()
+
+	got := tn.normalize(td)
+	assert.Equal(t, orig, td)
+	spans := got.ResourceSpans().At(0).InstrumentationLibrarySpans().At(0).Spans()
+	require.Equal(t, 1, spans.Len())
+	assert.Equal(t, "b", spans.At(0).Name())
+
+	assertSkewedSpans(t, "drop", skewActionDrop, 2)
+}
+
+func TestTimestampNormalizerNoSkew(t *testing.T) {
+	tn := newTestTimestampNormalizer("no_skew", skewActionDrop)
+	td := generateTracesWithTimestamps([2]time.Duration{-time.Second, time.Second})
+	// Unset timestamps are never considered skewed.
+	td.ResourceSpans().At(0).InstrumentationLibrarySpans().At(0).Spans().AppendEmpty()
+	assert.Equal(t, td, tn.normalize(td))
+}
+
+func assertSkewedSpans(t *testing.T, name string, action string, expected int64) {
+	rows, err := view.RetrieveData(mSkewedSpans.Name())
+	require.NoError(t, err)
+	for _, row := range rows {
+		expectedTags := []tag.Tag{
+			{Key: tagKeyAction, Value: action},
+			{Key: tagKeyExporter, Value: config.NewIDWithName(typeStr, name).String()},
+		}
+		if assert.ObjectsAreEqual(expectedTags, row.Tags) {
+			assert.Equal(t, expected, int64(row.Data.(*view.SumData).Value))
+			return
+		}
+	}
+	assert.Fail(t, "no skewed spans recorded", "exporter %s", name)
+}
@@ -0,0 +1,81 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package opencensusexporter
+
+import (
+	"fmt"
+	"hash/fnv"
+	"sort"
+
+	"go.opentelemetry.io/collector/consumer/pdata"
+	tracetranslator "go.opentelemetry.io/collector/translator/trace"
+)
+
+// Supported values of Config.WorkerAssignment.
+const (
+	workerAssignmentRoundRobin = "roundrobin"
+	workerAssignmentHash       = "hash"
+)
+
+func validateWorkerAssignment(assignment string) error {
+	if assignment != workerAssignmentRoundRobin && assignment != workerAssignmentHash {
+		return fmt.Errorf("worker_assignment must be %q or %q, got %q",
+			workerAssignmentRoundRobin, workerAssignmentHash, assignment)
+	}
+	return nil
+}
+
+// tracesWorker returns the worker assigned to td out of numWorkers, based on
+// the attributes of its resources.
+func tracesWorker(td pdata.Traces, numWorkers int) int {
+	h := fnv.New64a()
+	rss := td.ResourceSpans()
+	for i := 0; i < rss.Len(); i++ {
+		h.Write(resourceKey(rss.At(i).Resource())) // nolint: errcheck
+	}
+	return int(h.Sum64() % uint64(numWorkers))
+}
+
+// metricsWorker returns the worker assigned to md out of numWorkers, based on
+// the attributes of its resources.
+func metricsWorker(md pdata.Metrics, numWorkers int) int {
+	h := fnv.New64a()
+	rms := md.ResourceMetrics()
+	for i := 0; i < rms.Len(); i++ {
+		h.Write(resourceKey(rms.At(i).Resource())) // nolint: errcheck
+	}
+	return int(h.Sum64() % uint64(numWorkers))
+}
+
+// resourceKey returns the sorted attributes of the resource, so that the same
+// attributes always give the same key regardless of their order.
+func resourceKey(resource pdata.Resource) []byte {
+	attrs := resource.Attributes()
+	keys := make([]string, 0, attrs.Len())
+	attrs.Range(func(k string, _ pdata.AttributeValue) bool {
+		keys = append(keys, k)
+		return true
+	})
+	sort.Strings(keys)
+	var b []byte
+	for _, k := range keys {
+		v, _ := attrs.Get(k)
+		b = append(b, k...)
+		b = append(b, 0)
+		b = append(b, tracetranslator.AttributeValueToString(v)...)
+		b = append(b, 0)
+	}
+	// Separate the resources, so that moving an attribute between them changes the key.
+	return append(b, 1)
+}
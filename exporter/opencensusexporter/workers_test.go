@@ -0,0 +1,73 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package opencensusexporter
+
+import (
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"go.opentelemetry.io/collector/consumer/pdatabuilder"
+)
+
+func TestValidateWorkerAssignment(t *testing.T) {
+	assert.NoError(t, validateWorkerAssignment("roundrobin"))
+	assert.NoError(t, validateWorkerAssignment("hash"))
+	assert.EqualError(t, validateWorkerAssignment(""), `worker_assignment must be "roundrobin" or "hash", got ""`)
+}
+
+func TestTracesWorker(t *testing.T) {
+	const numWorkers = 4
+	td := pdatabuilder.NewTraces().
+		Resource(pdatabuilder.Attrs{"service.name": "svc", "host.name": "host"}).Span("a").
+		Build()
+	worker := tracesWorker(td, numWorkers)
+	assert.GreaterOrEqual(t, worker, 0)
+	assert.Less(t, worker, numWorkers)
+
+	// The same resources always land on the same worker, whatever the spans and
+	// the order of the attributes.
+	for i := 0; i < 10; i++ {
+		td = pdatabuilder.NewTraces().
+			Resource(pdatabuilder.Attrs{"host.name": "host", "service.name": "svc"}).Span(strconv.Itoa(i)).Span("b").
+			Build()
+		assert.Equal(t, worker, tracesWorker(td, numWorkers))
+	}
+
+	// Distinct resources are spread across the workers.
+	workers := map[int]bool{}
+	for i := 0; i < 100; i++ {
+		td = pdatabuilder.NewTraces().Resource(pdatabuilder.Attrs{"service.name": strconv.Itoa(i)}).Span("a").Build()
+		workers[tracesWorker(td, numWorkers)] = true
+	}
+	assert.Len(t, workers, numWorkers)
+}
+
+func TestMetricsWorker(t *testing.T) {
+	const numWorkers = 4
+	md := pdatabuilder.NewMetrics().
+		Resource(pdatabuilder.Attrs{"service.name": "svc"}).
+		Resource(pdatabuilder.Attrs{"service.name": "other"}).DoubleGauge("m").DoubleDataPoint(1, nil).
+		Build()
+	worker := metricsWorker(md, numWorkers)
+	for i := 0; i < 10; i++ {
+		md = pdatabuilder.NewMetrics().
+			Resource(pdatabuilder.Attrs{"service.name": "svc"}).
+			Resource(pdatabuilder.Attrs{"service.name": "other"}).DoubleGauge("m").DoubleDataPoint(float64(i), nil).
+			Build()
+		assert.Equal(t, worker, metricsWorker(md, numWorkers))
+	}
+	assert.Equal(t, 0, metricsWorker(md, 1))
+}
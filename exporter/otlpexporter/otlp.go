@@ -56,8 +56,8 @@ func newExporter(cfg config.Exporter) (*exporter, error) {
 
 // start actually creates the gRPC connection. The client construction is deferred till this point as this
 // is the only place we get hold of Extensions which are required to construct auth round tripper.
-func (e *exporter) start(_ context.Context, host component.Host) error {
-	w, err := newGrpcSender(e.config, host.GetExtensions())
+func (e *exporter) start(ctx context.Context, host component.Host) error {
+	w, err := newGrpcSender(ctx, e.config, host.GetExtensions())
 	if err != nil {
 		return err
 	}
@@ -100,14 +100,14 @@ type grpcSender struct {
 	callOptions    []grpc.CallOption
 }
 
-func newGrpcSender(config *Config, ext map[config.ComponentID]component.Extension) (*grpcSender, error) {
+func newGrpcSender(ctx context.Context, config *Config, ext map[config.ComponentID]component.Extension) (*grpcSender, error) {
 	dialOpts, err := config.GRPCClientSettings.ToDialOptions(ext)
 	if err != nil {
 		return nil, err
 	}
 
 	var clientConn *grpc.ClientConn
-	if clientConn, err = grpc.Dial(config.GRPCClientSettings.Endpoint, dialOpts...); err != nil {
+	if clientConn, err = config.GRPCClientSettings.DialContext(ctx, config.GRPCClientSettings.Endpoint, dialOpts...); err != nil {
 		return nil, err
 	}
 
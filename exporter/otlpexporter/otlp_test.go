@@ -363,6 +363,30 @@ func TestSendTraceDataServerDownAndUp(t *testing.T) {
 	cancel()
 }
 
+func TestStartCanceledContext(t *testing.T) {
+	ln, err := net.Listen("tcp", "localhost:")
+	require.NoError(t, err)
+	defer ln.Close()
+
+	factory := NewFactory()
+	cfg := factory.CreateDefaultConfig().(*Config)
+	cfg.GRPCClientSettings = configgrpc.GRPCClientSettings{
+		Endpoint:    ln.Addr().String(),
+		DialTimeout: 10 * time.Second,
+		TLSSetting: configtls.TLSClientSetting{
+			Insecure: true,
+		},
+	}
+	creationParams := component.ExporterCreateParams{Logger: zap.NewNop()}
+	exp, err := factory.CreateTracesExporter(context.Background(), creationParams, cfg)
+	require.NoError(t, err)
+
+	// The blocking dial is bounded by the context given to Start.
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	assert.Error(t, exp.Start(ctx, componenttest.NewNopHost()))
+}
+
 func TestSendTraceDataServerStartWhileRequest(t *testing.T) {
 	// Find the addr, but don't start the server.
 	ln, err := net.Listen("tcp", "localhost:")
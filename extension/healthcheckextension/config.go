@@ -15,6 +15,9 @@
 package healthcheckextension
 
 import (
+	"errors"
+	"time"
+
 	"go.opentelemetry.io/collector/config"
 	"go.opentelemetry.io/collector/config/confignet"
 )
@@ -33,11 +36,19 @@ type Config struct {
 	// check status.
 	// The default endpoint is "0.0.0.0:13133".
 	TCPAddr confignet.TCPAddr `mapstructure:",squash"`
+
+	// LivenessWindow, if non-zero, makes the health check fail when any exporter
+	// that reports the time of the last received data (see consumerhelper.LivenessReporter)
+	// has not received data within this window since the collector became ready.
+	LivenessWindow time.Duration `mapstructure:"liveness_window"`
 }
 
 var _ config.Extension = (*Config)(nil)
 
 // Validate checks if the extension configuration is valid
 func (cfg *Config) Validate() error {
+	if cfg.LivenessWindow < 0 {
+		return errors.New("liveness_window must be non-negative")
+	}
 	return nil
 }
@@ -16,22 +16,29 @@ package healthcheckextension
 
 import (
 	"context"
+	"fmt"
 	"net"
 	"net/http"
 	"strconv"
+	"sync/atomic"
+	"time"
 
 	"github.com/jaegertracing/jaeger/pkg/healthcheck"
 	"go.uber.org/zap"
 
 	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/consumer/consumerhelper"
 )
 
 type healthCheckExtension struct {
-	config Config
-	logger *zap.Logger
-	state  *healthcheck.HealthCheck
-	server http.Server
-	stopCh chan struct{}
+	// readySince is the UnixNano of the last call to Ready, accessed atomically.
+	readySince int64
+	config     Config
+	logger     *zap.Logger
+	state      *healthcheck.HealthCheck
+	server     http.Server
+	stopCh     chan struct{}
+	host       component.Host
 }
 
 var _ component.PipelineWatcher = (*healthCheckExtension)(nil)
@@ -57,7 +64,8 @@ func (hc *healthCheckExtension) Start(_ context.Context, host component.Host) er
 	}
 
 	// Mount HC handler
-	hc.server.Handler = hc.state.Handler()
+	hc.host = host
+	hc.server.Handler = hc.handler()
 	hc.stopCh = make(chan struct{})
 	go func() {
 		defer close(hc.stopCh)
@@ -79,7 +87,53 @@ func (hc *healthCheckExtension) Shutdown(context.Context) error {
 	return err
 }
 
+// handler returns the http.Handler of the health check. If LivenessWindow is
+// set, the state reported by the pipelines is overridden with an unavailable
+// status as long as any exporter has not received data within the window.
+func (hc *healthCheckExtension) handler() http.Handler {
+	stateHandler := hc.state.Handler()
+	if hc.config.LivenessWindow <= 0 {
+		return stateHandler
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if hc.state.Get() == healthcheck.Ready {
+			if err := hc.checkLiveness(time.Now()); err != nil {
+				w.Header().Set("Content-Type", "application/json")
+				w.WriteHeader(http.StatusServiceUnavailable)
+				fmt.Fprintf(w, `{"status":%q,"error":%q}`, healthcheck.Unavailable.String(), err.Error())
+				return
+			}
+		}
+		stateHandler.ServeHTTP(w, r)
+	})
+}
+
+// checkLiveness returns an error if any of the exporters implementing
+// consumerhelper.LivenessReporter did not receive data within the LivenessWindow.
+// Exporters that did not receive any data yet are measured from the time the
+// collector became ready.
+func (hc *healthCheckExtension) checkLiveness(now time.Time) error {
+	readySince := time.Unix(0, atomic.LoadInt64(&hc.readySince))
+	for dataType, exporters := range hc.host.GetExporters() {
+		for id, exp := range exporters {
+			lr, ok := exp.(consumerhelper.LivenessReporter)
+			if !ok {
+				continue
+			}
+			last := lr.LastReceived()
+			if last.Before(readySince) {
+				last = readySince
+			}
+			if now.Sub(last) > hc.config.LivenessWindow {
+				return fmt.Errorf("exporter %q in the %s pipeline did not receive data in the last %v", id.String(), dataType, hc.config.LivenessWindow)
+			}
+		}
+	}
+	return nil
+}
+
 func (hc *healthCheckExtension) Ready() error {
+	atomic.StoreInt64(&hc.readySince, time.Now().UnixNano())
 	hc.state.Set(healthcheck.Ready)
 	return nil
 }
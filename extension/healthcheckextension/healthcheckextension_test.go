@@ -20,14 +20,18 @@ import (
 	"net/http"
 	"runtime"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"go.uber.org/zap"
 
 	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/component/componenthelper"
 	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/config"
 	"go.opentelemetry.io/collector/config/confignet"
+	"go.opentelemetry.io/collector/consumer/consumerhelper"
 	"go.opentelemetry.io/collector/testutil"
 )
 
@@ -135,6 +139,68 @@ func TestHealthCheckShutdownWithoutStart(t *testing.T) {
 	require.NoError(t, hcExt.Shutdown(context.Background()))
 }
 
+func TestHealthCheckExtensionLivenessWindow(t *testing.T) {
+	cfg := Config{
+		TCPAddr: confignet.TCPAddr{
+			Endpoint: testutil.GetAvailableLocalAddress(t),
+		},
+		LivenessWindow: 500 * time.Millisecond,
+	}
+
+	lt := &consumerhelper.LivenessTracker{}
+	host := &exportersHost{
+		Host: componenttest.NewNopHost(),
+		exporters: map[config.DataType]map[config.ComponentID]component.Exporter{
+			config.TracesDataType: {
+				config.NewID("live"):  &livenessExporter{Component: componenthelper.New(), LivenessReporter: lt},
+				config.NewID("other"): componenthelper.New(),
+			},
+		},
+	}
+
+	hcExt := newServer(cfg, zap.NewNop())
+	require.NoError(t, hcExt.Start(context.Background(), host))
+	t.Cleanup(func() { require.NoError(t, hcExt.Shutdown(context.Background())) })
+
+	client := &http.Client{}
+	url := "http://" + cfg.TCPAddr.Endpoint
+	getStatus := func() int {
+		resp, err := client.Get(url)
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		return resp.StatusCode
+	}
+
+	assert.Equal(t, http.StatusServiceUnavailable, getStatus())
+
+	// Healthy right after becoming ready, even if no data was received yet.
+	require.NoError(t, hcExt.Ready())
+	assert.Equal(t, http.StatusOK, getStatus())
+
+	// No data received within the window.
+	assert.Eventually(t, func() bool {
+		return getStatus() == http.StatusServiceUnavailable
+	}, 10*time.Second, 10*time.Millisecond)
+
+	lt.Record()
+	assert.Equal(t, http.StatusOK, getStatus())
+}
+
+type livenessExporter struct {
+	component.Component
+	consumerhelper.LivenessReporter
+}
+
+// exportersHost implements a component.Host that returns a fixed set of exporters.
+type exportersHost struct {
+	component.Host
+	exporters map[config.DataType]map[config.ComponentID]component.Exporter
+}
+
+func (eh *exportersHost) GetExporters() map[config.DataType]map[config.ComponentID]component.Exporter {
+	return eh.exporters
+}
+
 // assertNoErrorHost implements a component.Host that asserts that there were no errors.
 type assertNoErrorHost struct {
 	component.Host
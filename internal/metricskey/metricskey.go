@@ -0,0 +1,74 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package metricskey computes the keys identifying the resources, metrics and
+// series of the metrics data, e.g. to track the state of the series across batches.
+package metricskey
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"go.opentelemetry.io/collector/consumer/pdata"
+)
+
+// Attributes returns a key identifying the given attributes regardless of their order.
+// The nested maps and arrays are flattened, see pdata.AttributeMap.Flatten.
+func Attributes(attrs pdata.AttributeMap) string {
+	var b strings.Builder
+	flat := attrs.Flatten("")
+	keys := make([]string, 0, len(flat))
+	for k := range flat {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		fmt.Fprintf(&b, "%s=%v\x00", k, flat[k])
+	}
+	return b.String()
+}
+
+// Labels returns a key identifying the given labels regardless of their order.
+func Labels(labels pdata.StringMap) string {
+	var b strings.Builder
+	keys := make([]string, 0, labels.Len())
+	labels.Range(func(k string, _ string) bool {
+		keys = append(keys, k)
+		return true
+	})
+	sort.Strings(keys)
+	for _, k := range keys {
+		v, _ := labels.Get(k)
+		fmt.Fprintf(&b, "%s=%s\x00", k, v)
+	}
+	return b.String()
+}
+
+// Resource returns the identity of a resource, made of its sorted attributes.
+func Resource(resource pdata.Resource) string {
+	return Attributes(resource.Attributes())
+}
+
+// Metric returns the identity of a metric, made of the identity of its resource,
+// as returned by Resource, its instrumentation library and its name.
+func Metric(resKey string, il pdata.InstrumentationLibrary, metric pdata.Metric) string {
+	return fmt.Sprintf("%s\x01%s\x00%s\x01%s\x01", resKey, il.Name(), il.Version(), metric.Name())
+}
+
+// Series returns the identity of a series, made of the identity of its metric, as
+// returned by Metric, and the sorted labels of the data point.
+func Series(metKey string, labels pdata.StringMap) string {
+	return metKey + Labels(labels)
+}
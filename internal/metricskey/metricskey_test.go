@@ -0,0 +1,70 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package metricskey
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"go.opentelemetry.io/collector/consumer/pdata"
+)
+
+func TestAttributes(t *testing.T) {
+	a := pdata.NewAttributeMap()
+	a.InsertString("service.name", "svc")
+	a.InsertInt("pid", 42)
+	b := pdata.NewAttributeMap()
+	b.InsertInt("pid", 42)
+	b.InsertString("service.name", "svc")
+	assert.Equal(t, Attributes(a), Attributes(b))
+
+	b.UpdateInt("pid", 43)
+	assert.NotEqual(t, Attributes(a), Attributes(b))
+	assert.Empty(t, Attributes(pdata.NewAttributeMap()))
+}
+
+func TestLabels(t *testing.T) {
+	a := pdata.NewStringMap().InitFromMap(map[string]string{"a": "1", "b": "2"})
+	b := pdata.NewStringMap()
+	b.Insert("b", "2")
+	b.Insert("a", "1")
+	assert.Equal(t, Labels(a), Labels(b))
+
+	// The separators keep the keys and values from being confused.
+	assert.NotEqual(t,
+		Labels(pdata.NewStringMap().InitFromMap(map[string]string{"a": "1b=2"})),
+		Labels(a))
+}
+
+func TestSeries(t *testing.T) {
+	resource := pdata.NewResource()
+	resource.Attributes().InsertString("host.name", "host")
+	il := pdata.NewInstrumentationLibrary()
+	il.SetName("lib")
+	metric := pdata.NewMetric()
+	metric.SetName("requests")
+	metKey := Metric(Resource(resource), il, metric)
+
+	other := pdata.NewMetric()
+	other.SetName("errors")
+	assert.NotEqual(t, metKey, Metric(Resource(resource), il, other))
+	il.SetVersion("v2")
+	assert.NotEqual(t, metKey, Metric(Resource(resource), il, metric))
+
+	labels := pdata.NewStringMap().InitFromMap(map[string]string{"code": "200"})
+	assert.Equal(t, Series(metKey, labels), Series(metKey, labels))
+	assert.NotEqual(t, Series(metKey, labels), Series(metKey, pdata.NewStringMap()))
+}
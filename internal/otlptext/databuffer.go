@@ -16,6 +16,7 @@ package otlptext
 
 import (
 	"fmt"
+	"sort"
 	"strconv"
 	"strings"
 
@@ -25,10 +26,50 @@ import (
 
 type dataBuffer struct {
 	str strings.Builder
+	// flattenAttributes renders the attributes with nested values expanded into dotted keys.
+	flattenAttributes bool
+	// attributeKeys are the keys of the span attributes to render, all if empty.
+	attributeKeys map[string]struct{}
+	// filterAllAttributes applies the attributeKeys to the resource and log attributes.
+	filterAllAttributes bool
+	// groupAttributes renders the span attributes grouped by namespace.
+	groupAttributes bool
+	// maxArrayElements is the number of rendered elements of the arrays, all if zero.
+	maxArrayElements int
+	// sanitization defines how the non-printable characters of the entries are handled.
+	sanitization Sanitization
+	// deltas tracks the values of the cumulative sums to render their deltas, if set.
+	deltas *DeltaTracker
+	// deltaResourceKey identifies the resource of the metrics being rendered.
+	deltaResourceKey string
+	// maxEventsPerSpan is the number of rendered events of every span, all if zero.
+	maxEventsPerSpan int
+	// eventNames are the names of the rendered span events, all if empty.
+	eventNames []string
+	// hoistCommonLabels renders the labels shared by the data points of a metric once.
+	hoistCommonLabels bool
+	// commonLabels are the labels shared by the data points of the metric being
+	// rendered, omitted from the labels of every data point.
+	commonLabels map[string]string
+}
+
+func newDataBuffer(o *options) *dataBuffer {
+	return &dataBuffer{
+		flattenAttributes:   o.flattenAttributes,
+		attributeKeys:       o.attributeKeys,
+		filterAllAttributes: o.filterAllAttributes,
+		groupAttributes:     o.groupAttributes,
+		maxArrayElements:    o.maxArrayElements,
+		sanitization:        o.sanitization,
+		deltas:              o.deltas,
+		hoistCommonLabels:   o.hoistCommonLabels,
+		maxEventsPerSpan:    o.maxEventsPerSpan,
+		eventNames:          o.eventNames,
+	}
 }
 
 func (b *dataBuffer) logEntry(format string, a ...interface{}) {
-	b.str.WriteString(fmt.Sprintf(format, a...))
+	b.str.WriteString(sanitize(fmt.Sprintf(format, a...), b.sanitization))
 	b.str.WriteString("\n")
 }
 
@@ -42,10 +83,146 @@ func (b *dataBuffer) logAttributeMap(label string, am pdata.AttributeMap) {
 	}
 
 	b.logEntry("%s:", label)
+	b.logAttributes("     -> ", am)
+}
+
+// logFilteredAttributeMap logs only the attributes with one of the attributeKeys,
+// followed by the number of omitted attributes.
+func (b *dataBuffer) logFilteredAttributeMap(label string, am pdata.AttributeMap) {
+	if len(b.attributeKeys) == 0 {
+		b.logAttributeMap(label, am)
+		return
+	}
+	if am.Len() == 0 {
+		return
+	}
+
+	filtered := b.filterAttributes(am)
+	b.logEntry("%s:", label)
+	b.logAttributes("     -> ", filtered)
+	if omitted := am.Len() - filtered.Len(); omitted > 0 {
+		b.logEntry("     -> +%d more", omitted)
+	}
+}
+
+// filterAttributes returns the attributes with one of the attributeKeys, or am itself
+// if there is no attributeKeys.
+func (b *dataBuffer) filterAttributes(am pdata.AttributeMap) pdata.AttributeMap {
+	if len(b.attributeKeys) == 0 {
+		return am
+	}
+	filtered := pdata.NewAttributeMap()
 	am.Range(func(k string, v pdata.AttributeValue) bool {
-		b.logEntry("     -> %s: %s(%s)", k, v.Type().String(), attributeValueToString(v))
+		if _, ok := b.attributeKeys[k]; ok {
+			filtered.Insert(k, v)
+		}
 		return true
 	})
+	return filtered
+}
+
+// logSpanAttributes logs the span attributes, filtered by the attributeKeys and grouped
+// by namespace if groupAttributes is set.
+func (b *dataBuffer) logSpanAttributes(am pdata.AttributeMap) {
+	if !b.groupAttributes {
+		b.logFilteredAttributeMap("Attributes", am)
+		return
+	}
+	if am.Len() == 0 {
+		return
+	}
+
+	filtered := b.filterAttributes(am)
+	b.logEntry("Attributes:")
+	b.logGroupedAttributes(filtered)
+	if omitted := am.Len() - filtered.Len(); omitted > 0 {
+		b.logEntry("     -> +%d more", omitted)
+	}
+}
+
+// logResource logs the resource attributes and, if any, the number of attributes dropped
+// by the SDK, e.g. because of its attribute limits.
+func (b *dataBuffer) logResource(r pdata.Resource) {
+	b.logResourceLabels(r.Attributes())
+	if dropped := r.DroppedAttributesCount(); dropped > 0 {
+		b.logEntry("Resource dropped attributes count: %d", dropped)
+	}
+}
+
+// logResourceLabels logs the resource attributes, filtered if filterAllAttributes is set.
+func (b *dataBuffer) logResourceLabels(am pdata.AttributeMap) {
+	if b.filterAllAttributes {
+		b.logFilteredAttributeMap("Resource labels", am)
+		return
+	}
+	b.logAttributeMap("Resource labels", am)
+}
+
+// logAttributes logs every attribute in its own line starting with the given indent.
+func (b *dataBuffer) logAttributes(indent string, am pdata.AttributeMap) {
+	for _, attr := range b.renderAttributes(am) {
+		b.logEntry("%s%s: %s", indent, attr.key, attr.value)
+	}
+}
+
+// generalAttributeNamespace is the namespace of the attributes without a dot in their key.
+const generalAttributeNamespace = "general"
+
+// logGroupedAttributes logs the attributes sorted and grouped by the namespace of
+// their key, with the attributes of the general namespace last.
+func (b *dataBuffer) logGroupedAttributes(am pdata.AttributeMap) {
+	groups := map[string][]renderedAttribute{}
+	for _, attr := range b.renderAttributes(am) {
+		namespace := generalAttributeNamespace
+		if i := strings.IndexByte(attr.key, '.'); i > 0 {
+			namespace = attr.key[:i]
+		}
+		groups[namespace] = append(groups[namespace], attr)
+	}
+	namespaces := make([]string, 0, len(groups))
+	for namespace := range groups {
+		if namespace != generalAttributeNamespace {
+			namespaces = append(namespaces, namespace)
+		}
+	}
+	sort.Strings(namespaces)
+	if _, ok := groups[generalAttributeNamespace]; ok {
+		namespaces = append(namespaces, generalAttributeNamespace)
+	}
+	for _, namespace := range namespaces {
+		attrs := groups[namespace]
+		sort.Slice(attrs, func(i, j int) bool { return attrs[i].key < attrs[j].key })
+		b.logEntry("   [%s]", namespace)
+		for _, attr := range attrs {
+			b.logEntry("     -> %s: %s", attr.key, attr.value)
+		}
+	}
+}
+
+type renderedAttribute struct {
+	key   string
+	value string
+}
+
+// renderAttributes returns the rendered key and value of every attribute, in the
+// order of the map, or sorted by key and flattened if flattenAttributes is set.
+func (b *dataBuffer) renderAttributes(am pdata.AttributeMap) []renderedAttribute {
+	if !b.flattenAttributes {
+		attrs := make([]renderedAttribute, 0, am.Len())
+		am.Range(func(k string, v pdata.AttributeValue) bool {
+			attrs = append(attrs, renderedAttribute{key: k, value: v.Type().String() + "(" + b.attributeValueToString(v) + ")"})
+			return true
+		})
+		return attrs
+	}
+
+	flat := am.Flatten("")
+	attrs := make([]renderedAttribute, 0, len(flat))
+	for k, v := range flat {
+		attrs = append(attrs, renderedAttribute{key: k, value: flattenedValueToString(v)})
+	}
+	sort.Slice(attrs, func(i, j int) bool { return attrs[i].key < attrs[j].key })
+	return attrs
 }
 
 func (b *dataBuffer) logStringMap(description string, sm pdata.StringMap) {
@@ -60,11 +237,24 @@ func (b *dataBuffer) logStringMap(description string, sm pdata.StringMap) {
 	})
 }
 
+// TODO: Render the schema URLs of the resources and instrumentation libraries once
+// the generated OTLP protos are updated to a version that includes them (v0.9.0),
+// currently pdata has no access to these fields. The same goes for the dropped
+// attributes count of the instrumentation libraries.
 func (b *dataBuffer) logInstrumentationLibrary(il pdata.InstrumentationLibrary) {
-	b.logEntry(
-		"InstrumentationLibrary %s %s",
-		il.Name(),
-		il.Version())
+	b.logEntry("InstrumentationLibrary %s", instrumentationLibraryToString(il))
+}
+
+// instrumentationLibraryToString returns the library as name@version, omitting the
+// version if not set, so the producer of the data can be clearly identified.
+func instrumentationLibraryToString(il pdata.InstrumentationLibrary) string {
+	if il.Name() == "" && il.Version() == "" {
+		return "<empty>"
+	}
+	if il.Version() == "" {
+		return il.Name()
+	}
+	return il.Name() + "@" + il.Version()
 }
 
 func (b *dataBuffer) logMetricDescriptor(md pdata.Metric) {
@@ -75,24 +265,27 @@ func (b *dataBuffer) logMetricDescriptor(md pdata.Metric) {
 	b.logEntry("     -> DataType: %s", md.DataType().String())
 }
 
+// TODO: Render the flags of the data points, highlighting the ones with no recorded
+// value, once the generated OTLP protos are updated to a version that defines them,
+// currently the data points have no flags field.
 func (b *dataBuffer) logMetricDataPoints(m pdata.Metric) {
 	switch m.DataType() {
 	case pdata.MetricDataTypeNone:
 		return
 	case pdata.MetricDataTypeIntGauge:
-		b.logIntDataPoints(m.IntGauge().DataPoints())
+		b.logIntDataPoints(m.IntGauge().DataPoints(), "")
 	case pdata.MetricDataTypeDoubleGauge:
-		b.logDoubleDataPoints(m.DoubleGauge().DataPoints())
+		b.logDoubleDataPoints(m.DoubleGauge().DataPoints(), "")
 	case pdata.MetricDataTypeIntSum:
 		data := m.IntSum()
 		b.logEntry("     -> IsMonotonic: %t", data.IsMonotonic())
 		b.logEntry("     -> AggregationTemporality: %s", data.AggregationTemporality().String())
-		b.logIntDataPoints(data.DataPoints())
+		b.logIntDataPoints(data.DataPoints(), b.deltaSeriesPrefix(m.Name(), data.AggregationTemporality()))
 	case pdata.MetricDataTypeDoubleSum:
 		data := m.DoubleSum()
 		b.logEntry("     -> IsMonotonic: %t", data.IsMonotonic())
 		b.logEntry("     -> AggregationTemporality: %s", data.AggregationTemporality().String())
-		b.logDoubleDataPoints(data.DataPoints())
+		b.logDoubleDataPoints(data.DataPoints(), b.deltaSeriesPrefix(m.Name(), data.AggregationTemporality()))
 	case pdata.MetricDataTypeIntHistogram:
 		data := m.IntHistogram()
 		b.logEntry("     -> AggregationTemporality: %s", data.AggregationTemporality().String())
@@ -107,27 +300,49 @@ func (b *dataBuffer) logMetricDataPoints(m pdata.Metric) {
 	}
 }
 
-func (b *dataBuffer) logIntDataPoints(ps pdata.IntDataPointSlice) {
+// deltaSeriesPrefix returns the prefix identifying the series of the given sum for
+// the deltas, or an empty string if the deltas are not rendered for the sum.
+func (b *dataBuffer) deltaSeriesPrefix(name string, temporality pdata.AggregationTemporality) string {
+	if b.deltas == nil || temporality != pdata.AggregationTemporalityCumulative {
+		return ""
+	}
+	return b.deltaResourceKey + "\x00" + name
+}
+
+// logIntDataPoints logs the data points, with their deltas if deltaSeries is not empty.
+func (b *dataBuffer) logIntDataPoints(ps pdata.IntDataPointSlice, deltaSeries string) {
 	for i := 0; i < ps.Len(); i++ {
 		p := ps.At(i)
 		b.logEntry("IntDataPoints #%d", i)
 		b.logDataPointLabels(p.LabelsMap())
 
-		b.logEntry("StartTimestamp: %s", p.StartTimestamp())
-		b.logEntry("Timestamp: %s", p.Timestamp())
+		b.logDataPointTimestamps(p.StartTimestamp(), p.Timestamp())
 		b.logEntry("Value: %d", p.Value())
+		if deltaSeries == "" {
+			continue
+		}
+		if delta, ok := b.deltas.intDelta(deltaSeriesHash(deltaSeries, p.LabelsMap()), p.Value()); ok {
+			b.logEntry("Delta: %+d", delta)
+		}
 	}
 }
 
-func (b *dataBuffer) logDoubleDataPoints(ps pdata.DoubleDataPointSlice) {
+// logDoubleDataPoints logs the data points, with their deltas if deltaSeries is not empty.
+func (b *dataBuffer) logDoubleDataPoints(ps pdata.DoubleDataPointSlice, deltaSeries string) {
 	for i := 0; i < ps.Len(); i++ {
 		p := ps.At(i)
 		b.logEntry("DoubleDataPoints #%d", i)
 		b.logDataPointLabels(p.LabelsMap())
 
-		b.logEntry("StartTimestamp: %s", p.StartTimestamp())
-		b.logEntry("Timestamp: %s", p.Timestamp())
+		b.logDataPointTimestamps(p.StartTimestamp(), p.Timestamp())
+		// The %f verb renders the special values explicitly as NaN, +Inf and -Inf.
 		b.logEntry("Value: %f", p.Value())
+		if deltaSeries == "" {
+			continue
+		}
+		if delta, ok := b.deltas.doubleDelta(deltaSeriesHash(deltaSeries, p.LabelsMap()), p.Value()); ok {
+			b.logEntry("Delta: %+f", delta)
+		}
 	}
 }
 
@@ -137,8 +352,7 @@ func (b *dataBuffer) logDoubleHistogramDataPoints(ps pdata.HistogramDataPointSli
 		b.logEntry("HistogramDataPoints #%d", i)
 		b.logDataPointLabels(p.LabelsMap())
 
-		b.logEntry("StartTimestamp: %s", p.StartTimestamp())
-		b.logEntry("Timestamp: %s", p.Timestamp())
+		b.logDataPointTimestamps(p.StartTimestamp(), p.Timestamp())
 		b.logEntry("Count: %d", p.Count())
 		b.logEntry("Sum: %f", p.Sum())
 
@@ -164,8 +378,7 @@ func (b *dataBuffer) logIntHistogramDataPoints(ps pdata.IntHistogramDataPointSli
 		b.logEntry("HistogramDataPoints #%d", i)
 		b.logDataPointLabels(p.LabelsMap())
 
-		b.logEntry("StartTimestamp: %s", p.StartTimestamp())
-		b.logEntry("Timestamp: %s", p.Timestamp())
+		b.logDataPointTimestamps(p.StartTimestamp(), p.Timestamp())
 		b.logEntry("Count: %d", p.Count())
 		b.logEntry("Sum: %d", p.Sum())
 
@@ -191,8 +404,7 @@ func (b *dataBuffer) logDoubleSummaryDataPoints(ps pdata.SummaryDataPointSlice)
 		b.logEntry("SummaryDataPoints #%d", i)
 		b.logDataPointLabels(p.LabelsMap())
 
-		b.logEntry("StartTimestamp: %s", p.StartTimestamp())
-		b.logEntry("Timestamp: %s", p.Timestamp())
+		b.logDataPointTimestamps(p.StartTimestamp(), p.Timestamp())
 		b.logEntry("Count: %d", p.Count())
 		b.logEntry("Sum: %f", p.Sum())
 
@@ -204,16 +416,95 @@ func (b *dataBuffer) logDoubleSummaryDataPoints(ps pdata.SummaryDataPointSlice)
 	}
 }
 
+// logDataPointTimestamps logs the start timestamp and the timestamp of a data point,
+// along with the time window between them to help diagnosing temporality issues.
+func (b *dataBuffer) logDataPointTimestamps(start pdata.Timestamp, ts pdata.Timestamp) {
+	if start == 0 {
+		b.logEntry("StartTimestamp: unset")
+	} else {
+		b.logEntry("StartTimestamp: %s", start)
+	}
+	b.logEntry("Timestamp: %s", ts)
+	if start != 0 && ts != 0 {
+		b.logEntry("Window: %s", ts.AsTime().Sub(start.AsTime()))
+	}
+}
+
 func (b *dataBuffer) logDataPointLabels(labels pdata.StringMap) {
-	b.logStringMap("Data point labels", labels)
+	if len(b.commonLabels) == 0 {
+		b.logStringMap("Data point labels", labels)
+		return
+	}
+	first := true
+	labels.Range(func(k string, v string) bool {
+		if _, ok := b.commonLabels[k]; ok {
+			return true
+		}
+		if first {
+			b.logEntry("Data point labels:")
+			first = false
+		}
+		b.logEntry("     -> %s: %s", k, v)
+		return true
+	})
+}
+
+// logCommonDataPointLabels logs the labels shared by all the data points of the metric,
+// if hoistCommonLabels is set and the metric has several data points, and keeps them
+// to be omitted from the labels of every data point.
+func (b *dataBuffer) logCommonDataPointLabels(m pdata.Metric) {
+	b.commonLabels = nil
+	if !b.hoistCommonLabels {
+		return
+	}
+	labels := m.DataPointLabels()
+	if len(labels) < 2 {
+		return
+	}
+	common := make(map[string]string, labels[0].Len())
+	labels[0].Range(func(k string, v string) bool {
+		common[k] = v
+		return true
+	})
+	for _, l := range labels[1:] {
+		for k, v := range common {
+			if lv, ok := l.Get(k); !ok || lv != v {
+				delete(common, k)
+			}
+		}
+	}
+	if len(common) == 0 {
+		return
+	}
+	keys := make([]string, 0, len(common))
+	for k := range common {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	b.logEntry("Common data point labels:")
+	for _, k := range keys {
+		b.logEntry("     -> %s: %s", k, common[k])
+	}
+	b.commonLabels = common
 }
 
 func (b *dataBuffer) logLogRecord(lr pdata.LogRecord) {
+	// The IDs are rendered first to easily find the related trace.
+	if !lr.TraceID().IsEmpty() {
+		b.logEntry("Trace ID: %s", lr.TraceID().HexString())
+	}
+	if !lr.SpanID().IsEmpty() {
+		b.logEntry("Span ID: %s", lr.SpanID().HexString())
+	}
 	b.logEntry("Timestamp: %s", lr.Timestamp())
 	b.logEntry("Severity: %s", lr.SeverityText())
 	b.logEntry("ShortName: %s", lr.Name())
-	b.logEntry("Body: %s", attributeValueToString(lr.Body()))
-	b.logAttributeMap("Attributes", lr.Attributes())
+	b.logEntry("Body: %s", b.attributeValueToString(lr.Body()))
+	if b.filterAllAttributes {
+		b.logFilteredAttributeMap("Attributes", lr.Attributes())
+	} else {
+		b.logAttributeMap("Attributes", lr.Attributes())
+	}
 }
 
 func (b *dataBuffer) logEvents(description string, se pdata.SpanEventSlice) {
@@ -222,8 +513,14 @@ func (b *dataBuffer) logEvents(description string, se pdata.SpanEventSlice) {
 	}
 
 	b.logEntry("%s:", description)
+	rendered := 0
 	for i := 0; i < se.Len(); i++ {
 		e := se.At(i)
+		if !b.isEventRendered(e.Name()) || (b.maxEventsPerSpan > 0 && rendered == b.maxEventsPerSpan) {
+			continue
+		}
+		rendered++
+		// The events keep their index in the span, so the omitted ones can be located.
 		b.logEntry("SpanEvent #%d", i)
 		b.logEntry("     -> Name: %s", e.Name())
 		b.logEntry("     -> Timestamp: %s", e.Timestamp())
@@ -233,11 +530,28 @@ func (b *dataBuffer) logEvents(description string, se pdata.SpanEventSlice) {
 			continue
 		}
 		b.logEntry("     -> Attributes:")
-		e.Attributes().Range(func(k string, v pdata.AttributeValue) bool {
-			b.logEntry("         -> %s: %s(%s)", k, v.Type().String(), attributeValueToString(v))
+		b.logAttributes("         -> ", e.Attributes())
+	}
+	if omitted := se.Len() - rendered; omitted > 0 {
+		b.logEntry("Omitted events: %d", omitted)
+	}
+}
+
+// isEventRendered returns true if the span events with the given name have to be rendered.
+func (b *dataBuffer) isEventRendered(name string) bool {
+	if len(b.eventNames) == 0 {
+		return true
+	}
+	for _, n := range b.eventNames {
+		if strings.HasSuffix(n, "*") {
+			if strings.HasPrefix(name, strings.TrimSuffix(n, "*")) {
+				return true
+			}
+		} else if name == n {
 			return true
-		})
+		}
 	}
+	return false
 }
 
 func (b *dataBuffer) logLinks(description string, sl pdata.SpanLinkSlice) {
@@ -258,14 +572,11 @@ func (b *dataBuffer) logLinks(description string, sl pdata.SpanLinkSlice) {
 			continue
 		}
 		b.logEntry("     -> Attributes:")
-		l.Attributes().Range(func(k string, v pdata.AttributeValue) bool {
-			b.logEntry("         -> %s: %s(%s)", k, v.Type().String(), attributeValueToString(v))
-			return true
-		})
+		b.logAttributes("         -> ", l.Attributes())
 	}
 }
 
-func attributeValueToString(av pdata.AttributeValue) string {
+func (b *dataBuffer) attributeValueToString(av pdata.AttributeValue) string {
 	switch av.Type() {
 	case pdata.AttributeValueTypeString:
 		return av.StringVal()
@@ -276,7 +587,7 @@ func attributeValueToString(av pdata.AttributeValue) string {
 	case pdata.AttributeValueTypeInt:
 		return strconv.FormatInt(av.IntVal(), 10)
 	case pdata.AttributeValueTypeArray:
-		return attributeValueArrayToString(av.ArrayVal())
+		return b.attributeValueArrayToString(av.ArrayVal())
 	case pdata.AttributeValueTypeMap:
 		return attributeMapToString(av.MapVal())
 	default:
@@ -284,19 +595,45 @@ func attributeValueToString(av pdata.AttributeValue) string {
 	}
 }
 
-func attributeValueArrayToString(av pdata.AnyValueArray) string {
-	var b strings.Builder
-	b.WriteByte('[')
-	for i := 0; i < av.Len(); i++ {
-		if i < av.Len()-1 {
-			fmt.Fprintf(&b, "%s, ", attributeValueToString(av.At(i)))
+// flattenedValueToString formats a value returned by pdata.AttributeMap.Flatten
+// the same way attributeValueToString formats the corresponding AttributeValue.
+func flattenedValueToString(v interface{}) string {
+	switch val := v.(type) {
+	case string:
+		return pdata.AttributeValueTypeString.String() + "(" + val + ")"
+	case bool:
+		return pdata.AttributeValueTypeBool.String() + "(" + strconv.FormatBool(val) + ")"
+	case float64:
+		return pdata.AttributeValueTypeDouble.String() + "(" + strconv.FormatFloat(val, 'f', -1, 64) + ")"
+	case int64:
+		return pdata.AttributeValueTypeInt.String() + "(" + strconv.FormatInt(val, 10) + ")"
+	default:
+		return pdata.AttributeValueTypeNull.String() + "()"
+	}
+}
+
+// attributeValueArrayToString renders the first maxArrayElements elements of the
+// array, followed by the number of omitted elements.
+func (b *dataBuffer) attributeValueArrayToString(av pdata.AnyValueArray) string {
+	n := av.Len()
+	if b.maxArrayElements > 0 && n > b.maxArrayElements {
+		n = b.maxArrayElements
+	}
+	var sb strings.Builder
+	sb.WriteByte('[')
+	for i := 0; i < n; i++ {
+		if i < n-1 {
+			fmt.Fprintf(&sb, "%s, ", b.attributeValueToString(av.At(i)))
 		} else {
-			b.WriteString(attributeValueToString(av.At(i)))
+			sb.WriteString(b.attributeValueToString(av.At(i)))
 		}
 	}
+	if omitted := av.Len() - n; omitted > 0 {
+		fmt.Fprintf(&sb, ", ... (+%d more)", omitted)
+	}
 
-	b.WriteByte(']')
-	return b.String()
+	sb.WriteByte(']')
+	return sb.String()
 }
 
 func attributeMapToString(av pdata.AttributeMap) string {
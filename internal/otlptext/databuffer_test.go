@@ -15,11 +15,13 @@
 package otlptext
 
 import (
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
 
 	"go.opentelemetry.io/collector/consumer/pdata"
+	"go.opentelemetry.io/collector/consumer/pdatabuilder"
 )
 
 func TestNestedArraySerializesCorrectly(t *testing.T) {
@@ -35,7 +37,34 @@ func TestNestedArraySerializesCorrectly(t *testing.T) {
 	ava.ArrayVal().AppendEmpty().SetDoubleVal(5.5)
 
 	assert.Equal(t, 5, ava.ArrayVal().Len())
-	assert.Equal(t, "[foo, 42, [bar], true, 5.5]", attributeValueToString(ava))
+	assert.Equal(t, "[foo, 42, [bar], true, 5.5]", newDataBuffer(newOptions(nil)).attributeValueToString(ava))
+}
+
+func TestMaxArrayElements(t *testing.T) {
+	long := make([]interface{}, 1000)
+	for i := range long {
+		long[i] = i
+	}
+	nested := []interface{}{"a", []interface{}{1, 2, 3, 4}, "b", "c"}
+
+	td := pdatabuilder.NewTraces().
+		Resource(pdatabuilder.Attrs{"host.ips": long}).
+		Span("span").WithAttrs(pdatabuilder.Attrs{"long": long, "nested": nested}).
+		Build()
+	traces := Traces(td, WithMaxArrayElements(3))
+	assert.Contains(t, traces, "-> host.ips: ARRAY([0, 1, 2, ... (+997 more)])")
+	assert.Contains(t, traces, "-> long: ARRAY([0, 1, 2, ... (+997 more)])")
+	assert.Contains(t, traces, "-> nested: ARRAY([a, [1, 2, 3, ... (+1 more)], b, ... (+1 more)])")
+
+	ld := pdatabuilder.NewLogs().Log("message").WithAttr("long", long).Build()
+	assert.Contains(t, Logs(ld, WithMaxArrayElements(2)), "-> long: ARRAY([0, 1, ... (+998 more)])")
+
+	// The default is generous but finite, zero renders all the elements.
+	traces = Traces(td)
+	assert.Contains(t, traces, ", 99, ... (+900 more)])")
+	traces = Traces(td, WithMaxArrayElements(0))
+	assert.Contains(t, traces, ", 998, 999])")
+	assert.NotContains(t, traces, "more)")
 }
 
 func TestNestedMapSerializesCorrectly(t *testing.T) {
@@ -54,5 +83,67 @@ func TestNestedMapSerializesCorrectly(t *testing.T) {
 }`
 
 	assert.Equal(t, 2, ava.MapVal().Len())
-	assert.Equal(t, expected, attributeValueToString(ava))
+	assert.Equal(t, expected, newDataBuffer(newOptions(nil)).attributeValueToString(ava))
+}
+
+func TestFlattenAttributes(t *testing.T) {
+	nested := pdata.NewAttributeValueMap()
+	nested.MapVal().InsertInt("b", 13)
+	arr := pdata.NewAttributeValueArray()
+	arr.ArrayVal().AppendEmpty().SetDoubleVal(1.5)
+	nested.MapVal().Insert("c", arr)
+
+	am := pdata.NewAttributeMap()
+	am.InsertString("z", "last")
+	am.Insert("a", nested)
+	am.InsertBool("m", true)
+
+	buf := newDataBuffer(newOptions([]Option{WithFlattenAttributes(true)}))
+	buf.logAttributeMap("Attributes", am)
+	expected := `Attributes:
+     -> a.b: INT(13)
+     -> a.c.0: DOUBLE(1.5)
+     -> m: BOOL(true)
+     -> z: STRING(last)
+`
+	assert.Equal(t, expected, buf.str.String())
+
+	buf = newDataBuffer(newOptions(nil))
+	buf.logAttributeMap("Attributes", am)
+	assert.Contains(t, buf.str.String(), "     -> a: MAP(")
+}
+
+func TestFilteredAttributes(t *testing.T) {
+	am := pdata.NewAttributeMap()
+	am.InsertString("http.method", "GET")
+	am.InsertInt("http.status_code", 200)
+	am.InsertString("net.peer.ip", "10.0.0.1")
+	am.InsertString("thread.name", "main")
+
+	buf := newDataBuffer(newOptions([]Option{WithAttributeKeys("http.status_code", "http.method", "missing")}))
+	buf.logFilteredAttributeMap("Attributes", am)
+	expected := `Attributes:
+     -> http.method: STRING(GET)
+     -> http.status_code: INT(200)
+     -> +2 more
+`
+	assert.Equal(t, expected, buf.str.String())
+
+	// All the attributes are omitted.
+	buf = newDataBuffer(newOptions([]Option{WithAttributeKeys("missing")}))
+	buf.logFilteredAttributeMap("Attributes", am)
+	assert.Equal(t, "Attributes:\n     -> +4 more\n", buf.str.String())
+
+	// No keys renders all the attributes.
+	buf = newDataBuffer(newOptions([]Option{WithAttributeKeys()}))
+	buf.logFilteredAttributeMap("Attributes", am)
+	assert.Equal(t, 5, strings.Count(buf.str.String(), "\n"))
+
+	// The resource attributes are only filtered with WithFilterAllAttributes.
+	buf = newDataBuffer(newOptions([]Option{WithAttributeKeys("http.method")}))
+	buf.logResourceLabels(am)
+	assert.NotContains(t, buf.str.String(), "more")
+	buf = newDataBuffer(newOptions([]Option{WithAttributeKeys("http.method"), WithFilterAllAttributes(true)}))
+	buf.logResourceLabels(am)
+	assert.Equal(t, "Resource labels:\n     -> http.method: STRING(GET)\n     -> +3 more\n", buf.str.String())
 }
@@ -0,0 +1,109 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package otlptext
+
+import (
+	"hash/fnv"
+	"sort"
+	"strings"
+	"sync"
+
+	"go.opentelemetry.io/collector/consumer/pdata"
+	tracetranslator "go.opentelemetry.io/collector/translator/trace"
+)
+
+// DefaultMaxDeltaSeries is the default maximum number of series tracked by a
+// DeltaTracker, see NewDeltaTracker.
+const DefaultMaxDeltaSeries = 10000
+
+// DeltaTracker keeps the last rendered value of every series of the cumulative sums,
+// a series being identified by a hash of its resource attributes, metric name and
+// labels, so that WithDeltas can render the change of the value since the previous
+// batch. It is safe for concurrent use.
+type DeltaTracker struct {
+	maxSeries int
+
+	mu   sync.Mutex
+	last map[uint64]seriesValue
+}
+
+// seriesValue is the last value of a series, depending on the type of the sum.
+type seriesValue struct {
+	intValue    int64
+	doubleValue float64
+}
+
+// NewDeltaTracker returns a DeltaTracker keeping the last value of at most maxSeries
+// series, each taking in the order of 50 bytes of memory. Once maxSeries series are
+// tracked, no delta is rendered for the new series. Zero means DefaultMaxDeltaSeries.
+func NewDeltaTracker(maxSeries int) *DeltaTracker {
+	if maxSeries <= 0 {
+		maxSeries = DefaultMaxDeltaSeries
+	}
+	return &DeltaTracker{
+		maxSeries: maxSeries,
+		last:      make(map[uint64]seriesValue),
+	}
+}
+
+// intDelta records value as the last value of the series, and returns the difference
+// with the previous value if the series was already tracked.
+func (dt *DeltaTracker) intDelta(series uint64, value int64) (int64, bool) {
+	dt.mu.Lock()
+	defer dt.mu.Unlock()
+	last, ok := dt.last[series]
+	if ok || len(dt.last) < dt.maxSeries {
+		dt.last[series] = seriesValue{intValue: value}
+	}
+	return value - last.intValue, ok
+}
+
+// doubleDelta is the equivalent of intDelta for the double sums.
+func (dt *DeltaTracker) doubleDelta(series uint64, value float64) (float64, bool) {
+	dt.mu.Lock()
+	defer dt.mu.Unlock()
+	last, ok := dt.last[series]
+	if ok || len(dt.last) < dt.maxSeries {
+		dt.last[series] = seriesValue{doubleValue: value}
+	}
+	return value - last.doubleValue, ok
+}
+
+// deltaResourceKey returns a key identifying the given resource attributes regardless
+// of their order.
+func deltaResourceKey(attrs pdata.AttributeMap) string {
+	pairs := make([]string, 0, attrs.Len())
+	attrs.Range(func(k string, v pdata.AttributeValue) bool {
+		pairs = append(pairs, k+"="+tracetranslator.AttributeValueToString(v))
+		return true
+	})
+	sort.Strings(pairs)
+	return strings.Join(pairs, "\x00")
+}
+
+// deltaSeriesHash returns the hash of the series with the given prefix, identifying
+// the resource and the metric, and labels.
+func deltaSeriesHash(prefix string, labels pdata.StringMap) uint64 {
+	pairs := make([]string, 0, labels.Len())
+	labels.Range(func(k string, v string) bool {
+		pairs = append(pairs, k+"="+v)
+		return true
+	})
+	sort.Strings(pairs)
+	h := fnv.New64a()
+	_, _ = h.Write([]byte(prefix))
+	_, _ = h.Write([]byte{0})
+	_, _ = h.Write([]byte(strings.Join(pairs, "\x00")))
+	return h.Sum64()
+}
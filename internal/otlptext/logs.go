@@ -14,16 +14,33 @@
 
 package otlptext
 
-import "go.opentelemetry.io/collector/consumer/pdata"
+import (
+	"fmt"
+	"strings"
+
+	"go.opentelemetry.io/collector/consumer/pdata"
+)
+
+// ParseSeverityNumber returns the SeverityNumber with the given name of the OTLP
+// enum without its prefix, e.g. WARN or ERROR2, ignoring case.
+func ParseSeverityNumber(name string) (pdata.SeverityNumber, error) {
+	for sn := pdata.SeverityNumberTRACE; sn <= pdata.SeverityNumberFATAL4; sn++ {
+		if strings.EqualFold(name, strings.TrimPrefix(sn.String(), "SEVERITY_NUMBER_")) {
+			return sn, nil
+		}
+	}
+	return pdata.SeverityNumberUNDEFINED, fmt.Errorf("unknown severity %q", name)
+}
 
 // Logs data to text
-func Logs(ld pdata.Logs) string {
-	buf := dataBuffer{}
+func Logs(ld pdata.Logs, opts ...Option) string {
+	o := newOptions(opts)
+	buf := newDataBuffer(o)
 	rls := ld.ResourceLogs()
 	for i := 0; i < rls.Len(); i++ {
 		buf.logEntry("ResourceLog #%d", i)
 		rl := rls.At(i)
-		buf.logAttributeMap("Resource labels", rl.Resource().Attributes())
+		buf.logResource(rl.Resource())
 		ills := rl.InstrumentationLibraryLogs()
 		for j := 0; j < ills.Len(); j++ {
 			buf.logEntry("InstrumentationLibraryLogs #%d", j)
@@ -32,8 +49,11 @@ func Logs(ld pdata.Logs) string {
 
 			logs := ils.Logs()
 			for k := 0; k < logs.Len(); k++ {
-				buf.logEntry("LogRecord #%d", k)
 				lr := logs.At(k)
+				if lr.SeverityNumber() < o.minSeverity || o.isExcluded(lr.Attributes()) {
+					continue
+				}
+				buf.logEntry("LogRecord #%d", k)
 				buf.logLogRecord(lr)
 			}
 		}
@@ -15,11 +15,14 @@
 package otlptext
 
 import (
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 
 	"go.opentelemetry.io/collector/consumer/pdata"
+	"go.opentelemetry.io/collector/consumer/pdatabuilder"
 	"go.opentelemetry.io/collector/internal/testdata"
 )
 
@@ -36,6 +39,7 @@ func TestLogs(t *testing.T) {
 		{"logs data with empty resource log", args{testdata.GenerateLogsOneEmptyResourceLogs()}, false},
 		{"logs data with no log records", args{testdata.GenerateLogsNoLogRecords()}, false},
 		{"logs with one empty log", args{testdata.GenerateLogsOneEmptyLogRecord()}, false},
+		{"logs with one log", args{testdata.GenerateLogsOneLogRecord()}, false},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -46,3 +50,59 @@ func TestLogs(t *testing.T) {
 		})
 	}
 }
+
+func TestLogsTraceContext(t *testing.T) {
+	ld := pdatabuilder.NewLogs().
+		Log("with ids").WithIDs([16]byte{0x5b, 0x8e, 0xff, 0xf7, 0x98, 0x03, 0x81, 0x03, 0xd2, 0x69, 0xb6, 0x33, 0x81, 0x3f, 0xc6, 0x0c}, [8]byte{0xee, 0xe1, 0x9b, 0x7e, 0xc3, 0xc1, 0xb1, 0x74}).
+		Log("without ids").
+		Build()
+
+	logs := Logs(ld)
+	assert.Contains(t, logs, "LogRecord #0\nTrace ID: 5b8efff798038103d269b633813fc60c\nSpan ID: eee19b7ec3c1b174\n")
+	assert.Contains(t, logs, "LogRecord #1\nTimestamp: ")
+	assert.Equal(t, 1, strings.Count(logs, "Trace ID:"))
+	assert.Equal(t, 1, strings.Count(logs, "Span ID:"))
+}
+
+func TestLogsResourceDroppedAttributesCount(t *testing.T) {
+	ld := testdata.GenerateLogsOneLogRecord()
+	assert.NotContains(t, Logs(ld), "Resource dropped attributes count")
+	ld.ResourceLogs().At(0).Resource().SetDroppedAttributesCount(2)
+	assert.Contains(t, Logs(ld), "Resource dropped attributes count: 2\n")
+}
+
+func TestParseSeverityNumber(t *testing.T) {
+	sn, err := ParseSeverityNumber("warn")
+	require.NoError(t, err)
+	assert.Equal(t, pdata.SeverityNumberWARN, sn)
+
+	sn, err = ParseSeverityNumber("ERROR2")
+	require.NoError(t, err)
+	assert.Equal(t, pdata.SeverityNumberERROR2, sn)
+
+	_, err = ParseSeverityNumber("UNDEFINED")
+	assert.EqualError(t, err, `unknown severity "UNDEFINED"`)
+	_, err = ParseSeverityNumber("critical")
+	assert.EqualError(t, err, `unknown severity "critical"`)
+}
+
+func TestLogsMinSeverity(t *testing.T) {
+	ld := pdatabuilder.NewLogs().
+		Log("no severity").
+		Log("debug record").WithSeverity(pdata.SeverityNumberDEBUG, "DEBUG").
+		Log("warn record").WithSeverity(pdata.SeverityNumberWARN, "WARN").
+		Log("error record").WithSeverity(pdata.SeverityNumberERROR3, "ERROR").
+		Build()
+
+	logs := Logs(ld)
+	assert.Contains(t, logs, "no severity")
+	assert.Contains(t, logs, "debug record")
+
+	logs = Logs(ld, WithMinSeverity(pdata.SeverityNumberWARN))
+	assert.NotContains(t, logs, "no severity")
+	assert.NotContains(t, logs, "debug record")
+	assert.Contains(t, logs, "LogRecord #2")
+	assert.Contains(t, logs, "warn record")
+	assert.Contains(t, logs, "LogRecord #3")
+	assert.Contains(t, logs, "error record")
+}
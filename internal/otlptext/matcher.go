@@ -0,0 +1,70 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package otlptext
+
+import (
+	"strings"
+
+	"go.opentelemetry.io/collector/consumer/pdata"
+	tracetranslator "go.opentelemetry.io/collector/translator/trace"
+)
+
+// AttributeMatcher matches the attributes with a given key and value.
+type AttributeMatcher struct {
+	key   string
+	value string
+	// prefix matches the values starting with value instead of equal to it.
+	prefix bool
+}
+
+// NewAttributeMatchers returns the matchers of the attributes with the given keys
+// and values. A value ending with "*" matches the values starting with the rest of
+// it, e.g. "/health*" matches "/healthz", the other values must be equal. The
+// values of the attributes which are not strings are compared to their string
+// representation, e.g. "200" for an int attribute.
+func NewAttributeMatchers(attrs map[string]string) []AttributeMatcher {
+	matchers := make([]AttributeMatcher, 0, len(attrs))
+	for key, value := range attrs {
+		m := AttributeMatcher{key: key, value: value}
+		if strings.HasSuffix(value, "*") {
+			m.value = strings.TrimSuffix(value, "*")
+			m.prefix = true
+		}
+		matchers = append(matchers, m)
+	}
+	return matchers
+}
+
+// matches returns true if am has an attribute matching m.
+func (m AttributeMatcher) matches(am pdata.AttributeMap) bool {
+	av, ok := am.Get(m.key)
+	if !ok {
+		return false
+	}
+	value := tracetranslator.AttributeValueToString(av)
+	if m.prefix {
+		return strings.HasPrefix(value, m.value)
+	}
+	return value == m.value
+}
+
+// isExcluded returns true if am has an attribute matching one of the excludeAttributes.
+func (o *options) isExcluded(am pdata.AttributeMap) bool {
+	for _, m := range o.excludeAttributes {
+		if m.matches(am) {
+			return true
+		}
+	}
+	return false
+}
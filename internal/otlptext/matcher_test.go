@@ -0,0 +1,75 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package otlptext
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"go.opentelemetry.io/collector/consumer/pdata"
+	"go.opentelemetry.io/collector/consumer/pdatabuilder"
+)
+
+func TestAttributeMatcher(t *testing.T) {
+	am := pdata.NewAttributeMap()
+	am.InsertString("http.target", "/healthz")
+	am.InsertInt("http.status_code", 200)
+
+	tests := []struct {
+		attrs   map[string]string
+		matches bool
+	}{
+		{attrs: map[string]string{"http.target": "/healthz"}, matches: true},
+		{attrs: map[string]string{"http.target": "/health"}, matches: false},
+		{attrs: map[string]string{"http.target": "/health*"}, matches: true},
+		{attrs: map[string]string{"http.target": "*"}, matches: true},
+		{attrs: map[string]string{"http.target": "/ready*"}, matches: false},
+		{attrs: map[string]string{"http.status_code": "200"}, matches: true},
+		{attrs: map[string]string{"http.method": "*"}, matches: false},
+	}
+	for _, tt := range tests {
+		matchers := NewAttributeMatchers(tt.attrs)
+		assert.Len(t, matchers, 1)
+		assert.Equal(t, tt.matches, matchers[0].matches(am), "%v", tt.attrs)
+	}
+}
+
+func TestExcludeAttributes(t *testing.T) {
+	matchers := WithExcludeAttributes(NewAttributeMatchers(map[string]string{
+		"http.target": "/health*",
+		"component":   "probe",
+	})...)
+
+	td := pdatabuilder.NewTraces().
+		Span("health").WithAttr("http.target", "/healthz").
+		Span("probe").WithAttr("component", "probe").
+		Span("checkout").WithAttr("http.target", "/checkout").
+		Build()
+	traces := Traces(td, matchers)
+	assert.NotContains(t, traces, "health")
+	assert.NotContains(t, traces, "Name           : probe")
+	assert.Contains(t, traces, "Name           : checkout")
+	traces = Traces(td, matchers, WithSpanTree(true))
+	assert.NotContains(t, traces, "health")
+	assert.Contains(t, traces, "checkout")
+
+	ld := pdatabuilder.NewLogs().
+		Log("health check").WithAttr("http.target", "/health").
+		Log("order placed").WithAttr("http.target", "/checkout").
+		Build()
+	logs := Logs(ld, matchers)
+	assert.NotContains(t, logs, "health check")
+	assert.Contains(t, logs, "order placed")
+}
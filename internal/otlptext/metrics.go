@@ -17,13 +17,16 @@ package otlptext
 import "go.opentelemetry.io/collector/consumer/pdata"
 
 // Metrics data to text
-func Metrics(md pdata.Metrics) string {
-	buf := dataBuffer{}
+func Metrics(md pdata.Metrics, opts ...Option) string {
+	buf := newDataBuffer(newOptions(opts))
 	rms := md.ResourceMetrics()
 	for i := 0; i < rms.Len(); i++ {
 		buf.logEntry("ResourceMetrics #%d", i)
 		rm := rms.At(i)
-		buf.logAttributeMap("Resource labels", rm.Resource().Attributes())
+		buf.logResource(rm.Resource())
+		if buf.deltas != nil {
+			buf.deltaResourceKey = deltaResourceKey(rm.Resource().Attributes())
+		}
 		ilms := rm.InstrumentationLibraryMetrics()
 		for j := 0; j < ilms.Len(); j++ {
 			buf.logEntry("InstrumentationLibraryMetrics #%d", j)
@@ -34,6 +37,7 @@ func Metrics(md pdata.Metrics) string {
 				buf.logEntry("Metric #%d", k)
 				metric := metrics.At(k)
 				buf.logMetricDescriptor(metric)
+				buf.logCommonDataPointLabels(metric)
 				buf.logMetricDataPoints(metric)
 			}
 		}
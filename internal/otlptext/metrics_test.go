@@ -15,11 +15,16 @@
 package otlptext
 
 import (
+	"math"
+	"strconv"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 
 	"go.opentelemetry.io/collector/consumer/pdata"
+	"go.opentelemetry.io/collector/consumer/pdatabuilder"
 	"go.opentelemetry.io/collector/internal/testdata"
 )
 
@@ -46,3 +51,147 @@ func TestMetrics(t *testing.T) {
 		})
 	}
 }
+
+func TestMetricsDataPointTimestamps(t *testing.T) {
+	metrics := Metrics(testdata.GenerateMetricsOneMetricNoStartTimestamp())
+
+	points := strings.Split(metrics, "IntDataPoints #")
+	require.Len(t, points, 3)
+
+	// The first data point has a start timestamp, the window is rendered.
+	assert.Contains(t, points[1], "StartTimestamp: "+testdata.TestMetricStartTimestamp.String())
+	assert.Contains(t, points[1], "Timestamp: "+testdata.TestMetricTimestamp.String())
+	assert.Contains(t, points[1], "Window: 1.000000468s")
+
+	// The second data point has no start timestamp.
+	assert.Contains(t, points[2], "StartTimestamp: unset")
+	assert.Contains(t, points[2], "Timestamp: "+testdata.TestMetricTimestamp.String())
+	assert.NotContains(t, points[2], "Window:")
+}
+
+func TestMetricsSpecialFloatValues(t *testing.T) {
+	md := pdatabuilder.NewMetrics().
+		DoubleGauge("gauge").
+		DoubleDataPoint(math.NaN(), nil).
+		DoubleDataPoint(math.Inf(1), nil).
+		DoubleDataPoint(math.Inf(-1), nil).
+		DoubleDataPoint(-1.5, nil).
+		DoubleSum("sum", true).
+		DoubleDataPoint(math.NaN(), nil).
+		DoubleDataPoint(math.Inf(1), nil).
+		DoubleDataPoint(math.Inf(-1), nil).
+		Build()
+	hm := md.ResourceMetrics().At(0).InstrumentationLibraryMetrics().At(0).Metrics().AppendEmpty()
+	hm.SetName("histogram")
+	hm.SetDataType(pdata.MetricDataTypeHistogram)
+	hdp := hm.Histogram().DataPoints().AppendEmpty()
+	hdp.SetSum(math.Inf(1))
+	hdp.SetExplicitBounds([]float64{0, math.Inf(1)})
+
+	metrics := Metrics(md)
+	parts := strings.Split(metrics, "Metric #")
+	require.Len(t, parts, 4)
+
+	for _, part := range parts[1:3] {
+		assert.Contains(t, part, "Value: NaN\n")
+		assert.Contains(t, part, "Value: +Inf\n")
+		assert.Contains(t, part, "Value: -Inf\n")
+	}
+	assert.Contains(t, parts[1], "Value: -1.500000\n")
+	assert.Contains(t, parts[3], "Sum: +Inf\n")
+	assert.Contains(t, parts[3], "ExplicitBounds #1: +Inf\n")
+}
+
+func TestMetricsDeltas(t *testing.T) {
+	newMetrics := func(requests int64, bytes float64, gauge int64) pdata.Metrics {
+		md := pdatabuilder.NewMetrics().
+			Resource(pdatabuilder.Attrs{"service.name": "svc"}).
+			IntSum("requests", true).IntDataPoint(requests, map[string]string{"code": "200"}).
+			DoubleSum("bytes", true).DoubleDataPoint(bytes, nil).
+			IntGauge("queue").IntDataPoint(gauge, nil).
+			IntSum("delta", true).IntDataPoint(requests, nil).
+			Build()
+		md.ResourceMetrics().At(0).InstrumentationLibraryMetrics().At(0).Metrics().At(3).IntSum().
+			SetAggregationTemporality(pdata.AggregationTemporalityDelta)
+		return md
+	}
+	dt := NewDeltaTracker(0)
+
+	// No delta is rendered for the first value of the series.
+	assert.NotContains(t, Metrics(newMetrics(10, 1.5, 3), WithDeltas(dt)), "Delta:")
+
+	parts := strings.Split(Metrics(newMetrics(25, 4, 7), WithDeltas(dt)), "Metric #")
+	require.Len(t, parts, 5)
+	assert.Contains(t, parts[1], "Value: 25\nDelta: +15\n")
+	assert.Contains(t, parts[2], "Value: 4.000000\nDelta: +2.500000\n")
+	// The gauges and the delta sums have no delta.
+	assert.NotContains(t, parts[3], "Delta:")
+	assert.NotContains(t, parts[4], "Delta:")
+
+	// The series of another resource are tracked separately.
+	md := newMetrics(30, 4, 7)
+	md.ResourceMetrics().At(0).Resource().Attributes().UpsertString("service.name", "other")
+	assert.NotContains(t, Metrics(md, WithDeltas(dt)), "Delta:")
+
+	// A reset of the counter renders a negative delta.
+	assert.Contains(t, Metrics(newMetrics(5, 4, 7), WithDeltas(dt)), "Delta: -20\n")
+
+	// Without WithDeltas no delta is rendered.
+	assert.NotContains(t, Metrics(newMetrics(50, 4, 7)), "Delta:")
+}
+
+func TestMetricsHoistCommonLabels(t *testing.T) {
+	b := pdatabuilder.NewMetrics().IntGauge("latency_bucket")
+	for i := 0; i < 20; i++ {
+		b.IntDataPoint(int64(i), map[string]string{
+			"host":   "h1",
+			"region": "eu",
+			"le":     strconv.Itoa(i),
+		})
+	}
+	md := b.IntGauge("single").IntDataPoint(1, map[string]string{"host": "h1"}).Build()
+
+	out := Metrics(md, WithHoistCommonLabels(true))
+	parts := strings.Split(out, "Metric #")
+	require.Len(t, parts, 3)
+	// The common labels are rendered once, sorted, before the data points.
+	assert.Contains(t, parts[1], "Common data point labels:\n     -> host: h1\n     -> region: eu\nIntDataPoints #0")
+	assert.Equal(t, 1, strings.Count(parts[1], "-> host: h1"))
+	assert.Equal(t, 1, strings.Count(parts[1], "-> region: eu"))
+	assert.Contains(t, parts[1], "IntDataPoints #7\nData point labels:\n     -> le: 7\n")
+	// A metric with a single data point is unchanged.
+	assert.NotContains(t, parts[2], "Common data point labels:")
+	assert.Contains(t, parts[2], "Data point labels:\n     -> host: h1\n")
+
+	// Without the option the labels are rendered for every data point.
+	out = Metrics(md)
+	assert.NotContains(t, out, "Common data point labels:")
+	assert.Equal(t, 21, strings.Count(out, "-> host: h1"))
+}
+
+func TestMetricsHoistCommonLabelsNoCommon(t *testing.T) {
+	md := pdatabuilder.NewMetrics().
+		DoubleGauge("cpu").
+		DoubleDataPoint(1, map[string]string{"cpu": "0"}).
+		DoubleDataPoint(2, map[string]string{"cpu": "1"}).
+		DoubleDataPoint(3, nil).
+		Build()
+	assert.Equal(t, Metrics(md), Metrics(md, WithHoistCommonLabels(true)))
+}
+
+func TestDeltaTrackerMaxSeries(t *testing.T) {
+	dt := NewDeltaTracker(2)
+	_, ok := dt.intDelta(1, 10)
+	assert.False(t, ok)
+	_, ok = dt.intDelta(2, 10)
+	assert.False(t, ok)
+	// The tracker is full, the new series are not tracked.
+	_, ok = dt.intDelta(3, 10)
+	assert.False(t, ok)
+	_, ok = dt.intDelta(3, 20)
+	assert.False(t, ok)
+	// The tracked series are still updated.
+	delta, ok := dt.intDelta(2, 15)
+	assert.True(t, ok)
+	assert.EqualValues(t, 5, delta)
+}
@@ -0,0 +1,225 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package otlptext
+
+import "go.opentelemetry.io/collector/consumer/pdata"
+
+// Option customizes how the data is rendered to text.
+type Option func(*options)
+
+type options struct {
+	sampleRatio       float64
+	traceIDs          map[pdata.TraceID]struct{}
+	flattenAttributes bool
+	spanKinds         map[pdata.SpanKind]struct{}
+	attributeKeys     map[string]struct{}
+	// filterAllAttributes applies the attributeKeys to the resource and log attributes.
+	filterAllAttributes bool
+	spanTree            bool
+	compactSpans        bool
+	groupAttributes     bool
+	maxArrayElements    int
+	excludeAttributes   []AttributeMatcher
+	sanitization        Sanitization
+	minSeverity         pdata.SeverityNumber
+	deltas              *DeltaTracker
+	hoistCommonLabels   bool
+	maxEventsPerSpan    int
+	eventNames          []string
+}
+
+// DefaultMaxArrayElements is the default number of rendered elements of the array
+// values, see WithMaxArrayElements.
+const DefaultMaxArrayElements = 100
+
+func newOptions(opts []Option) *options {
+	o := &options{
+		sampleRatio:      1,
+		maxArrayElements: DefaultMaxArrayElements,
+	}
+	for _, opt := range opts {
+		opt(o)
+	}
+	return o
+}
+
+// WithSampleRatio renders only the spans of a subset of the traces, with the given
+// ratio between 0 and 1. The selection is deterministic and based on a hash of the
+// trace ID, so all the spans of a trace are either rendered or skipped together.
+func WithSampleRatio(ratio float64) Option {
+	return func(o *options) {
+		o.sampleRatio = ratio
+	}
+}
+
+// WithTraceIDs renders only the spans of the traces with one of the given IDs, e.g. to
+// debug a known problematic request, so all the spans of a trace are either rendered
+// or skipped together. It applies in addition to WithSampleRatio. Empty renders the
+// spans of all the traces.
+func WithTraceIDs(ids ...pdata.TraceID) Option {
+	return func(o *options) {
+		o.traceIDs = make(map[pdata.TraceID]struct{}, len(ids))
+		for _, id := range ids {
+			o.traceIDs[id] = struct{}{}
+		}
+	}
+}
+
+// WithFlattenAttributes renders the attributes as a flat list of dotted keys, with
+// nested map and array values expanded as described in pdata.AttributeMap.Flatten.
+func WithFlattenAttributes(flatten bool) Option {
+	return func(o *options) {
+		o.flattenAttributes = flatten
+	}
+}
+
+// WithSpanKinds renders only the spans of the given kinds. If no kind is given
+// the spans of all the kinds are rendered.
+func WithSpanKinds(kinds ...pdata.SpanKind) Option {
+	return func(o *options) {
+		o.spanKinds = make(map[pdata.SpanKind]struct{}, len(kinds))
+		for _, kind := range kinds {
+			o.spanKinds[kind] = struct{}{}
+		}
+	}
+}
+
+// WithAttributeKeys renders only the span attributes with the given keys, followed by
+// the number of omitted attributes. If no key is given all the attributes are rendered.
+func WithAttributeKeys(keys ...string) Option {
+	return func(o *options) {
+		o.attributeKeys = make(map[string]struct{}, len(keys))
+		for _, key := range keys {
+			o.attributeKeys[key] = struct{}{}
+		}
+	}
+}
+
+// WithFilterAllAttributes applies the keys given to WithAttributeKeys to the resource
+// and log record attributes too, in addition to the span attributes.
+func WithFilterAllAttributes(filterAll bool) Option {
+	return func(o *options) {
+		o.filterAllAttributes = filterAll
+	}
+}
+
+// WithSpanTree renders the spans of every trace as a tree, indented by depth, using the
+// parent span IDs to reconstruct the tree within the batch. Only the main fields of the
+// spans are rendered and the spans with a parent missing from the batch are listed as
+// orphans, including the children of the spans skipped by WithSpanKinds or
+// WithExcludeAttributes.
+func WithSpanTree(spanTree bool) Option {
+	return func(o *options) {
+		o.spanTree = spanTree
+	}
+}
+
+// WithCompactSpans renders every span on a single line, like an access log, with
+// the service name of its resource, its name, kind, duration, status code and IDs,
+// e.g. `service="frontend" name="GET /users" kind=server duration=150ms
+// status=STATUS_CODE_OK trace_id=... span_id=...`. It takes precedence over
+// WithSpanTree, and the resource, library, attributes, events and links of the
+// spans are not rendered.
+func WithCompactSpans(compact bool) Option {
+	return func(o *options) {
+		o.compactSpans = compact
+	}
+}
+
+// WithGroupAttributes renders the span attributes grouped by namespace, the part of
+// their key before the first dot (e.g. http for http.method), under a header for every
+// namespace. The namespaces and the attributes within a namespace are sorted, and the
+// attributes without a dot are grouped last in a "general" namespace.
+func WithGroupAttributes(group bool) Option {
+	return func(o *options) {
+		o.groupAttributes = group
+	}
+}
+
+// WithMaxArrayElements renders only the first n elements of the array values, followed
+// by the number of omitted elements, e.g. "[a, b, ... (+3 more)]". It applies to the
+// array values of all the attributes and of the log bodies, including the nested
+// arrays, but not to the arrays expanded by WithFlattenAttributes. Zero renders all the
+// elements. Defaults to DefaultMaxArrayElements.
+func WithMaxArrayElements(n int) Option {
+	return func(o *options) {
+		o.maxArrayElements = n
+	}
+}
+
+// WithExcludeAttributes skips the spans and the log records with an attribute
+// matching one of the matchers, see NewAttributeMatchers.
+func WithExcludeAttributes(matchers ...AttributeMatcher) Option {
+	return func(o *options) {
+		o.excludeAttributes = matchers
+	}
+}
+
+// WithSanitization defines how the non-printable characters and the invalid UTF-8 bytes
+// of the rendered text are handled, by default they are escaped.
+func WithSanitization(s Sanitization) Option {
+	return func(o *options) {
+		o.sanitization = s
+	}
+}
+
+// WithMinSeverity renders only the log records with a severity number greater than
+// or equal to the given one, the records without a severity number are skipped.
+// SeverityNumberUNDEFINED renders all the records.
+func WithMinSeverity(sn pdata.SeverityNumber) Option {
+	return func(o *options) {
+		o.minSeverity = sn
+	}
+}
+
+// WithDeltas renders, for the data points of the cumulative sums, the change of the
+// value since the value of the same series previously rendered with dt, e.g. to spot
+// which counters are actually moving. No delta is rendered for the first value of a
+// series, nor for the series not tracked because dt is full. The same dt must be used
+// to render the successive batches.
+func WithDeltas(dt *DeltaTracker) Option {
+	return func(o *options) {
+		o.deltas = dt
+	}
+}
+
+// WithMaxEventsPerSpan renders only the first n events of every span, after the
+// filtering of WithEventNameFilter, followed by the number of omitted events. Zero
+// renders all the events.
+func WithMaxEventsPerSpan(n int) Option {
+	return func(o *options) {
+		o.maxEventsPerSpan = n
+	}
+}
+
+// WithEventNameFilter renders only the span events with one of the given names,
+// followed by the number of omitted events. A name ending with "*" matches the
+// names starting with the rest of it, e.g. "db.*". If no name is given all the
+// events are rendered.
+func WithEventNameFilter(names ...string) Option {
+	return func(o *options) {
+		o.eventNames = names
+	}
+}
+
+// WithHoistCommonLabels renders the labels shared by all the data points of a metric,
+// with the same value, once after the metric descriptor, and only the other labels for
+// every data point. This compresses the output of the metrics with many similar data
+// points, e.g. wide histograms. The metrics with a single data point are unchanged.
+func WithHoistCommonLabels(hoist bool) Option {
+	return func(o *options) {
+		o.hoistCommonLabels = hoist
+	}
+}
@@ -0,0 +1,91 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package otlptext
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Sanitization defines how the non-printable characters and the invalid UTF-8 bytes
+// of the rendered text are handled, so they cannot corrupt terminals or log pipelines.
+type Sanitization int
+
+const (
+	// SanitizeEscape replaces the non-printable characters and invalid bytes with
+	// their Go escape sequence, e.g. \x1b. This is the default.
+	SanitizeEscape Sanitization = iota
+	// SanitizeStrip removes the non-printable characters and invalid bytes.
+	SanitizeStrip
+	// SanitizeNone renders the text as is.
+	SanitizeNone
+)
+
+var sanitizationNames = map[Sanitization]string{
+	SanitizeEscape: "escape",
+	SanitizeStrip:  "strip",
+	SanitizeNone:   "none",
+}
+
+// ParseSanitization returns the Sanitization with the given name, one of escape,
+// strip or none, ignoring case.
+func ParseSanitization(name string) (Sanitization, error) {
+	for s, sName := range sanitizationNames {
+		if strings.EqualFold(name, sName) {
+			return s, nil
+		}
+	}
+	return SanitizeEscape, fmt.Errorf("unknown sanitization %q", name)
+}
+
+// sanitize returns str with the non-graphic characters, e.g. control or bidirectional
+// formatting characters, and the invalid UTF-8 bytes escaped or stripped.
+func sanitize(str string, s Sanitization) string {
+	if s == SanitizeNone || isSafe(str) {
+		return str
+	}
+	var b strings.Builder
+	for i := 0; i < len(str); {
+		r, size := utf8.DecodeRuneInString(str[i:])
+		switch {
+		case r == utf8.RuneError && size == 1:
+			if s == SanitizeEscape {
+				fmt.Fprintf(&b, `\x%02x`, str[i])
+			}
+		case !unicode.IsGraphic(r):
+			if s == SanitizeEscape {
+				quoted := strconv.QuoteRune(r)
+				b.WriteString(quoted[1 : len(quoted)-1])
+			}
+		default:
+			b.WriteString(str[i : i+size])
+		}
+		i += size
+	}
+	return b.String()
+}
+
+// isSafe returns true if str only contains printable ASCII characters, the common case.
+func isSafe(str string) bool {
+	for i := 0; i < len(str); i++ {
+		if str[i] < ' ' || str[i] > '~' {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,70 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package otlptext
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go.opentelemetry.io/collector/consumer/pdatabuilder"
+)
+
+func TestSanitize(t *testing.T) {
+	tests := []struct {
+		name    string
+		str     string
+		escaped string
+		strip   string
+	}{
+		{"printable ascii", "GET /index.html?a=b", "GET /index.html?a=b", "GET /index.html?a=b"},
+		{"printable unicode", "héllo 世界", "héllo 世界", "héllo 世界"},
+		{"control characters", "a\x1b[31mred\x00\n\tb", `a\x1b[31mred\x00\n\tb`, "a[31mredb"},
+		{"invalid utf-8", "ab\xff\xfecd", `ab\xff\xfecd`, "abcd"},
+		{"bidi override", "abc\u202edef", `abc\u202edef`, "abcdef"},
+		{"c1 control", "a\u0085b", `a\u0085b`, "ab"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.escaped, sanitize(tt.str, SanitizeEscape))
+			assert.Equal(t, tt.strip, sanitize(tt.str, SanitizeStrip))
+			assert.Equal(t, tt.str, sanitize(tt.str, SanitizeNone))
+		})
+	}
+}
+
+func TestParseSanitization(t *testing.T) {
+	s, err := ParseSanitization("Strip")
+	require.NoError(t, err)
+	assert.Equal(t, SanitizeStrip, s)
+
+	_, err = ParseSanitization("remove")
+	assert.EqualError(t, err, `unknown sanitization "remove"`)
+}
+
+func TestTracesSanitization(t *testing.T) {
+	td := pdatabuilder.NewTraces().
+		Span("bad\x1b[2Jname").WithAttr("key", "multi\nline\xff").
+		Build()
+
+	escaped := Traces(td)
+	assert.Contains(t, escaped, `bad\x1b[2Jname`)
+	assert.Contains(t, escaped, `multi\nline\xff`)
+	assert.NotContains(t, escaped, "\x1b")
+
+	assert.Contains(t, Traces(td, WithSanitization(SanitizeStrip)), "multiline")
+	assert.Contains(t, Traces(td, WithSanitization(SanitizeNone)), "multi\nline\xff")
+}
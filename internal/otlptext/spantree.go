@@ -0,0 +1,147 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package otlptext
+
+import (
+	"strings"
+	"time"
+
+	"go.opentelemetry.io/collector/consumer/pdata"
+	"go.opentelemetry.io/collector/translator/conventions"
+)
+
+// treeSpan is a span of the batch along with the indexes of its children.
+type treeSpan struct {
+	span     pdata.Span
+	service  string
+	children []int
+}
+
+// spanTree is the tree of the spans of a trace, reconstructed using the parent span IDs.
+type spanTree struct {
+	traceID pdata.TraceID
+	spans   []treeSpan
+}
+
+// newSpanTrees groups the rendered spans of td by trace, in the order of appearance.
+func newSpanTrees(td pdata.Traces, o *options) []*spanTree {
+	var trees []*spanTree
+	byTraceID := make(map[pdata.TraceID]*spanTree)
+	rss := td.ResourceSpans()
+	for i := 0; i < rss.Len(); i++ {
+		rs := rss.At(i)
+		var service string
+		if sn, ok := rs.Resource().Attributes().Get(conventions.AttributeServiceName); ok {
+			service = sn.StringVal()
+		}
+		ilss := rs.InstrumentationLibrarySpans()
+		for j := 0; j < ilss.Len(); j++ {
+			spans := ilss.At(j).Spans()
+			for k := 0; k < spans.Len(); k++ {
+				span := spans.At(k)
+				if !o.isTraceSampled(span.TraceID()) || !o.isSpanKindRendered(span.Kind()) || o.isExcluded(span.Attributes()) {
+					continue
+				}
+				tree, ok := byTraceID[span.TraceID()]
+				if !ok {
+					tree = &spanTree{traceID: span.TraceID()}
+					byTraceID[span.TraceID()] = tree
+					trees = append(trees, tree)
+				}
+				tree.spans = append(tree.spans, treeSpan{span: span, service: service})
+			}
+		}
+	}
+	return trees
+}
+
+// logSpanTree logs the spans of the tree indented by depth, starting from the root spans.
+// The spans with a parent missing from the batch, and the spans that cannot be reached
+// from a root because of a cycle in the parent span IDs, are logged as orphans.
+func (b *dataBuffer) logSpanTree(tree *spanTree) {
+	byID := make(map[pdata.SpanID]int, len(tree.spans))
+	for i, ts := range tree.spans {
+		byID[ts.span.SpanID()] = i
+	}
+	var roots, orphans []int
+	for i, ts := range tree.spans {
+		parentID := ts.span.ParentSpanID()
+		if parentID.IsEmpty() {
+			roots = append(roots, i)
+			continue
+		}
+		parent, ok := byID[parentID]
+		if !ok {
+			orphans = append(orphans, i)
+			continue
+		}
+		tree.spans[parent].children = append(tree.spans[parent].children, i)
+	}
+
+	visited := make([]bool, len(tree.spans))
+	for _, i := range roots {
+		b.logTreeSpan(tree, i, 0, visited)
+	}
+	for i := range tree.spans {
+		if !visited[i] && !containsIndex(orphans, i) {
+			orphans = append(orphans, i)
+		}
+	}
+	if len(orphans) == 0 {
+		return
+	}
+	b.logEntry("Orphan spans:")
+	for _, i := range orphans {
+		b.logTreeSpan(tree, i, 0, visited)
+	}
+}
+
+// logTreeSpan logs the span and, recursively, its children. Visited spans are skipped
+// so the spans of a cycle are logged only once.
+func (b *dataBuffer) logTreeSpan(tree *spanTree, i int, depth int, visited []bool) {
+	if visited[i] {
+		return
+	}
+	visited[i] = true
+	ts := tree.spans[i]
+	line := strings.Repeat("   ", depth) + "-> " + ts.span.Name() +
+		" [" + spanKindToString(ts.span.Kind()) + "]" +
+		" ID: " + ts.span.SpanID().HexString() +
+		" Status: " + ts.span.Status().Code().String() +
+		" Duration: " + spanDuration(ts.span).String()
+	if ts.service != "" {
+		line += " Service: " + ts.service
+	}
+	b.logEntry("%s", line)
+	for _, child := range ts.children {
+		b.logTreeSpan(tree, child, depth+1, visited)
+	}
+}
+
+func spanDuration(span pdata.Span) time.Duration {
+	if span.EndTimestamp() < span.StartTimestamp() {
+		return 0
+	}
+	return time.Duration(span.EndTimestamp() - span.StartTimestamp())
+}
+
+func containsIndex(indexes []int, i int) bool {
+	for _, idx := range indexes {
+		if idx == i {
+			return true
+		}
+	}
+	return false
+}
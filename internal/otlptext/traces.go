@@ -14,16 +14,80 @@
 
 package otlptext
 
-import "go.opentelemetry.io/collector/consumer/pdata"
+import (
+	"encoding/hex"
+	"fmt"
+	"hash/fnv"
+	"strings"
+
+	"go.opentelemetry.io/collector/consumer/pdata"
+	"go.opentelemetry.io/collector/translator/conventions"
+)
+
+// spanKindNames maps every SpanKind to the readable name used to render and filter it.
+var spanKindNames = map[pdata.SpanKind]string{
+	pdata.SpanKindUnspecified: "unspecified",
+	pdata.SpanKindInternal:    "internal",
+	pdata.SpanKindServer:      "server",
+	pdata.SpanKindClient:      "client",
+	pdata.SpanKindProducer:    "producer",
+	pdata.SpanKindConsumer:    "consumer",
+}
+
+// spanKindToString returns the readable name of the kind, or the name of the OTLP
+// enum value if the kind is unknown.
+func spanKindToString(kind pdata.SpanKind) string {
+	if name, ok := spanKindNames[kind]; ok {
+		return name
+	}
+	return kind.String()
+}
+
+// ParseTraceID returns the TraceID with the given hex representation, made of 32
+// hex digits as rendered by TraceID.HexString.
+func ParseTraceID(s string) (pdata.TraceID, error) {
+	var id [16]byte
+	if len(s) != hex.EncodedLen(len(id)) {
+		return pdata.InvalidTraceID(), fmt.Errorf("invalid trace ID %q, must be 32 hex digits", s)
+	}
+	if _, err := hex.Decode(id[:], []byte(s)); err != nil {
+		return pdata.InvalidTraceID(), fmt.Errorf("invalid trace ID %q, must be 32 hex digits", s)
+	}
+	return pdata.NewTraceID(id), nil
+}
+
+// ParseSpanKind returns the SpanKind with the given readable name, one of
+// unspecified, internal, server, client, producer or consumer, ignoring case.
+func ParseSpanKind(name string) (pdata.SpanKind, error) {
+	for kind, kindName := range spanKindNames {
+		if strings.EqualFold(name, kindName) {
+			return kind, nil
+		}
+	}
+	return pdata.SpanKindUnspecified, fmt.Errorf("unknown span kind %q", name)
+}
 
 // Traces data to text
-func Traces(td pdata.Traces) string {
-	buf := dataBuffer{}
+func Traces(td pdata.Traces, opts ...Option) string {
+	o := newOptions(opts)
+	buf := newDataBuffer(o)
+	if o.compactSpans {
+		buf.logCompactSpans(td, o)
+		return buf.str.String()
+	}
+	if o.spanTree {
+		for i, tree := range newSpanTrees(td, o) {
+			buf.logEntry("Trace #%d", i)
+			buf.logAttr("Trace ID", tree.traceID.HexString())
+			buf.logSpanTree(tree)
+		}
+		return buf.str.String()
+	}
 	rss := td.ResourceSpans()
 	for i := 0; i < rss.Len(); i++ {
 		buf.logEntry("ResourceSpans #%d", i)
 		rs := rss.At(i)
-		buf.logAttributeMap("Resource labels", rs.Resource().Attributes())
+		buf.logResource(rs.Resource())
 		ilss := rs.InstrumentationLibrarySpans()
 		for j := 0; j < ilss.Len(); j++ {
 			buf.logEntry("InstrumentationLibrarySpans #%d", j)
@@ -32,20 +96,24 @@ func Traces(td pdata.Traces) string {
 
 			spans := ils.Spans()
 			for k := 0; k < spans.Len(); k++ {
-				buf.logEntry("Span #%d", k)
 				span := spans.At(k)
+				if !o.isTraceSampled(span.TraceID()) || !o.isSpanKindRendered(span.Kind()) || o.isExcluded(span.Attributes()) {
+					continue
+				}
+				buf.logEntry("Span #%d", k)
 				buf.logAttr("Trace ID", span.TraceID().HexString())
 				buf.logAttr("Parent ID", span.ParentSpanID().HexString())
 				buf.logAttr("ID", span.SpanID().HexString())
 				buf.logAttr("Name", span.Name())
-				buf.logAttr("Kind", span.Kind().String())
+				buf.logAttr("Kind", spanKindToString(span.Kind()))
 				buf.logAttr("Start time", span.StartTimestamp().String())
 				buf.logAttr("End time", span.EndTimestamp().String())
 
 				buf.logAttr("Status code", span.Status().Code().String())
 				buf.logAttr("Status message", span.Status().Message())
+				buf.logSpanDroppedCounts(span)
 
-				buf.logAttributeMap("Attributes", span.Attributes())
+				buf.logSpanAttributes(span.Attributes())
 				buf.logEvents("Events", span.Events())
 				buf.logLinks("Links", span.Links())
 			}
@@ -54,3 +122,72 @@ func Traces(td pdata.Traces) string {
 
 	return buf.str.String()
 }
+
+// logCompactSpans logs every rendered span of td on a single line.
+func (b *dataBuffer) logCompactSpans(td pdata.Traces, o *options) {
+	rss := td.ResourceSpans()
+	for i := 0; i < rss.Len(); i++ {
+		rs := rss.At(i)
+		var service string
+		if sn, ok := rs.Resource().Attributes().Get(conventions.AttributeServiceName); ok {
+			service = sn.StringVal()
+		}
+		ilss := rs.InstrumentationLibrarySpans()
+		for j := 0; j < ilss.Len(); j++ {
+			spans := ilss.At(j).Spans()
+			for k := 0; k < spans.Len(); k++ {
+				span := spans.At(k)
+				if !o.isTraceSampled(span.TraceID()) || !o.isSpanKindRendered(span.Kind()) || o.isExcluded(span.Attributes()) {
+					continue
+				}
+				b.logEntry("service=%q name=%q kind=%s duration=%s status=%s trace_id=%s span_id=%s",
+					service,
+					span.Name(),
+					spanKindToString(span.Kind()),
+					spanDuration(span),
+					span.Status().Code().String(),
+					span.TraceID().HexString(),
+					span.SpanID().HexString())
+			}
+		}
+	}
+}
+
+// logSpanDroppedCounts logs the non-zero numbers of attributes, events and links of the
+// span dropped by the SDK, which reveal why the span seems incomplete.
+func (b *dataBuffer) logSpanDroppedCounts(span pdata.Span) {
+	if dropped := span.DroppedAttributesCount(); dropped > 0 {
+		b.logAttr("Dropped attrs", fmt.Sprint(dropped))
+	}
+	if dropped := span.DroppedEventsCount(); dropped > 0 {
+		b.logAttr("Dropped events", fmt.Sprint(dropped))
+	}
+	if dropped := span.DroppedLinksCount(); dropped > 0 {
+		b.logAttr("Dropped links", fmt.Sprint(dropped))
+	}
+}
+
+// isTraceSampled returns true if the spans of the given trace have to be rendered.
+func (o *options) isTraceSampled(traceID pdata.TraceID) bool {
+	if len(o.traceIDs) > 0 {
+		if _, ok := o.traceIDs[traceID]; !ok {
+			return false
+		}
+	}
+	if o.sampleRatio >= 1 {
+		return true
+	}
+	id := traceID.Bytes()
+	hash := fnv.New32a()
+	_, _ = hash.Write(id[:])
+	return float64(hash.Sum32()) < o.sampleRatio*(1<<32)
+}
+
+// isSpanKindRendered returns true if the spans of the given kind have to be rendered.
+func (o *options) isSpanKindRendered(kind pdata.SpanKind) bool {
+	if len(o.spanKinds) == 0 {
+		return true
+	}
+	_, ok := o.spanKinds[kind]
+	return ok
+}
@@ -15,11 +15,15 @@
 package otlptext
 
 import (
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 
 	"go.opentelemetry.io/collector/consumer/pdata"
+	"go.opentelemetry.io/collector/consumer/pdatabuilder"
 	"go.opentelemetry.io/collector/internal/testdata"
 )
 
@@ -44,3 +48,294 @@ func TestTraces(t *testing.T) {
 		})
 	}
 }
+
+func TestTracesSampleRatio(t *testing.T) {
+	td := pdata.NewTraces()
+	spans := td.ResourceSpans().AppendEmpty().InstrumentationLibrarySpans().AppendEmpty().Spans()
+	for i := 0; i < 100; i++ {
+		// Two spans for every trace.
+		for j := 0; j < 2; j++ {
+			span := spans.AppendEmpty()
+			span.SetTraceID(pdata.NewTraceID([16]byte{byte(i), 1, 2, 3}))
+			span.SetSpanID(pdata.NewSpanID([8]byte{byte(i), byte(j)}))
+		}
+	}
+
+	assert.Equal(t, 200, strings.Count(Traces(td), "Span #"))
+	assert.Equal(t, 200, strings.Count(Traces(td, WithSampleRatio(1)), "Span #"))
+	assert.Equal(t, 0, strings.Count(Traces(td, WithSampleRatio(0)), "Span #"))
+
+	sampled := Traces(td, WithSampleRatio(0.5))
+	count := strings.Count(sampled, "Span #")
+	assert.Greater(t, count, 0)
+	assert.Less(t, count, 200)
+	// The selection is deterministic and keeps all the spans of a trace together.
+	assert.Equal(t, sampled, Traces(td, WithSampleRatio(0.5)))
+	for i := 0; i < 100; i++ {
+		traceID := pdata.NewTraceID([16]byte{byte(i), 1, 2, 3}).HexString()
+		assert.Contains(t, []int{0, 2}, strings.Count(sampled, traceID))
+	}
+}
+
+func TestTracesInstrumentationLibrary(t *testing.T) {
+	td := testdata.GenerateTracesOneSpan()
+	il := td.ResourceSpans().At(0).InstrumentationLibrarySpans().At(0).InstrumentationLibrary()
+	assert.Contains(t, Traces(td), "InstrumentationLibrary <empty>\n")
+
+	il.SetName("go.opentelemetry.io/otel")
+	assert.Contains(t, Traces(td), "InstrumentationLibrary go.opentelemetry.io/otel\n")
+
+	il.SetVersion("v0.20.0")
+	assert.Contains(t, Traces(td), "InstrumentationLibrary go.opentelemetry.io/otel@v0.20.0\n")
+}
+
+// generateTracesAllSpanKinds returns a span of every kind, named after the kind.
+func generateTracesAllSpanKinds() pdata.Traces {
+	b := pdatabuilder.NewTraces()
+	for kind, name := range spanKindNames {
+		b.Span(name + "-span").WithKind(kind)
+	}
+	return b.Build()
+}
+
+func TestTracesSpanKind(t *testing.T) {
+	traces := Traces(generateTracesAllSpanKinds())
+	assert.Equal(t, len(spanKindNames), strings.Count(traces, "Span #"))
+	for _, name := range spanKindNames {
+		assert.Contains(t, traces, "    Kind           : "+name+"\n")
+	}
+	assert.Equal(t, pdata.SpanKind(100).String(), spanKindToString(pdata.SpanKind(100)))
+}
+
+func TestTracesSpanKindsFilter(t *testing.T) {
+	td := generateTracesAllSpanKinds()
+	assert.Equal(t, len(spanKindNames), strings.Count(Traces(td, WithSpanKinds()), "Span #"))
+
+	traces := Traces(td, WithSpanKinds(pdata.SpanKindServer, pdata.SpanKindClient))
+	assert.Equal(t, 2, strings.Count(traces, "Span #"))
+	assert.Contains(t, traces, "server-span")
+	assert.Contains(t, traces, "client-span")
+	assert.NotContains(t, traces, "internal-span")
+}
+
+func generateTracesManyEvents() pdata.Traces {
+	td := pdatabuilder.NewTraces().Span("span").Build()
+	events := td.ResourceSpans().At(0).InstrumentationLibrarySpans().At(0).Spans().At(0).Events()
+	for _, name := range []string{"db.query", "exception", "db.result", "message", "db.query"} {
+		events.AppendEmpty().SetName(name)
+	}
+	return td
+}
+
+func TestTracesMaxEventsPerSpan(t *testing.T) {
+	td := generateTracesManyEvents()
+	traces := Traces(td)
+	assert.Equal(t, 5, strings.Count(traces, "SpanEvent #"))
+	assert.NotContains(t, traces, "Omitted events")
+
+	traces = Traces(td, WithMaxEventsPerSpan(2))
+	assert.Equal(t, 2, strings.Count(traces, "SpanEvent #"))
+	assert.Contains(t, traces, "SpanEvent #1\n")
+	assert.NotContains(t, traces, "SpanEvent #2\n")
+	assert.Contains(t, traces, "Omitted events: 3\n")
+
+	traces = Traces(td, WithMaxEventsPerSpan(10))
+	assert.Equal(t, 5, strings.Count(traces, "SpanEvent #"))
+	assert.NotContains(t, traces, "Omitted events")
+}
+
+func TestTracesEventNameFilter(t *testing.T) {
+	td := generateTracesManyEvents()
+	traces := Traces(td, WithEventNameFilter("db.*", "message"))
+	assert.Equal(t, 4, strings.Count(traces, "SpanEvent #"))
+	assert.NotContains(t, traces, "SpanEvent #1\n")
+	assert.NotContains(t, traces, "-> Name: exception")
+	assert.Contains(t, traces, "Omitted events: 1\n")
+
+	traces = Traces(td, WithEventNameFilter("db.query"), WithMaxEventsPerSpan(1))
+	assert.Equal(t, 1, strings.Count(traces, "SpanEvent #"))
+	assert.Contains(t, traces, "SpanEvent #0\n")
+	assert.Contains(t, traces, "Omitted events: 4\n")
+
+	traces = Traces(td, WithEventNameFilter("unknown"))
+	assert.Equal(t, 0, strings.Count(traces, "SpanEvent #"))
+	assert.Contains(t, traces, "Omitted events: 5\n")
+}
+
+func TestTracesDroppedCounts(t *testing.T) {
+	td := pdatabuilder.NewTraces().Span("truncated").Span("complete").Build()
+	rs := td.ResourceSpans().At(0)
+	rs.Resource().SetDroppedAttributesCount(3)
+	span := rs.InstrumentationLibrarySpans().At(0).Spans().At(0)
+	span.SetDroppedAttributesCount(4)
+	span.SetDroppedEventsCount(5)
+	span.SetDroppedLinksCount(6)
+
+	traces := Traces(td)
+	assert.Contains(t, traces, "Resource dropped attributes count: 3\n")
+	assert.Contains(t, traces, "    Dropped attrs  : 4\n")
+	assert.Contains(t, traces, "    Dropped events : 5\n")
+	assert.Contains(t, traces, "    Dropped links  : 6\n")
+	// The zero counts are not rendered.
+	assert.Equal(t, 1, strings.Count(traces, "Dropped attrs"))
+	assert.Equal(t, 1, strings.Count(traces, "Dropped events"))
+	assert.Equal(t, 1, strings.Count(traces, "Dropped links"))
+
+	assert.NotContains(t, Traces(pdatabuilder.NewTraces().Span("complete").Build()), "ropped")
+}
+
+func TestTracesTraceIDs(t *testing.T) {
+	td := pdatabuilder.NewTraces().
+		Resource(pdatabuilder.Attrs{"service.name": "frontend"}).
+		Span("match-root").WithIDs([16]byte{1}, [8]byte{1}).
+		Span("other-root").WithIDs([16]byte{2}, [8]byte{2}).
+		Resource(pdatabuilder.Attrs{"service.name": "backend"}).
+		Span("match-child").WithIDs([16]byte{1}, [8]byte{3}).WithParentSpanID([8]byte{1}).
+		Span("other-child").WithIDs([16]byte{2}, [8]byte{4}).WithParentSpanID([8]byte{2}).
+		Span("third").WithIDs([16]byte{3}, [8]byte{5}).
+		Build()
+
+	traces := Traces(td, WithTraceIDs(pdata.NewTraceID([16]byte{1}), pdata.NewTraceID([16]byte{3})))
+	assert.Contains(t, traces, "match-root")
+	assert.Contains(t, traces, "match-child")
+	assert.Contains(t, traces, "third")
+	assert.NotContains(t, traces, "other-")
+
+	// All the spans of a matching trace are kept together in the tree.
+	tree := Traces(td, WithTraceIDs(pdata.NewTraceID([16]byte{1})), WithSpanTree(true))
+	assert.Contains(t, tree, "-> match-root")
+	assert.Contains(t, tree, "\n   -> match-child")
+	assert.NotContains(t, tree, "Orphan spans:")
+	assert.Equal(t, 1, strings.Count(tree, "Trace #"))
+
+	assert.Contains(t, Traces(td, WithTraceIDs()), "other-root")
+}
+
+func TestParseTraceID(t *testing.T) {
+	id, err := ParseTraceID("0102030405060708090a0b0c0d0e0f10")
+	require.NoError(t, err)
+	assert.Equal(t, pdata.NewTraceID([16]byte{1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16}), id)
+
+	id, err = ParseTraceID("0102030405060708090A0B0C0D0E0F10")
+	require.NoError(t, err)
+	assert.Equal(t, "0102030405060708090a0b0c0d0e0f10", id.HexString())
+
+	_, err = ParseTraceID("0102")
+	assert.EqualError(t, err, `invalid trace ID "0102", must be 32 hex digits`)
+	_, err = ParseTraceID("zz02030405060708090a0b0c0d0e0f10")
+	assert.EqualError(t, err, `invalid trace ID "zz02030405060708090a0b0c0d0e0f10", must be 32 hex digits`)
+}
+
+func TestParseSpanKind(t *testing.T) {
+	for kind, name := range spanKindNames {
+		parsed, err := ParseSpanKind(strings.ToUpper(name))
+		require.NoError(t, err)
+		assert.Equal(t, kind, parsed)
+	}
+	_, err := ParseSpanKind("unknown")
+	assert.EqualError(t, err, `unknown span kind "unknown"`)
+}
+
+func TestTracesSpanTree(t *testing.T) {
+	tree := Traces(testdata.GenerateTracesSpanTree(), WithSpanTree(true))
+	expected := `Trace #0
+    Trace ID       : 01020304000000000000000000000000
+-> frontend [server] ID: 0100000000000000 Status: STATUS_CODE_UNSET Duration: 1.000000468s Service: frontend
+   -> backend-call [client] ID: 0200000000000000 Status: STATUS_CODE_UNSET Duration: 1.000000468s Service: frontend
+      -> backend [server] ID: 0300000000000000 Status: STATUS_CODE_UNSET Duration: 1.000000468s Service: backend
+         -> db-query [client] ID: 0400000000000000 Status: STATUS_CODE_UNSET Duration: 1.000000468s Service: backend
+Orphan spans:
+-> orphan [internal] ID: 0500000000000000 Status: STATUS_CODE_UNSET Duration: 1.000000468s Service: backend
+`
+	assert.Equal(t, expected, tree)
+
+	// The children of the spans that are not rendered are orphans.
+	tree = Traces(testdata.GenerateTracesSpanTree(), WithSpanTree(true), WithSpanKinds(pdata.SpanKindServer))
+	assert.Contains(t, tree, "-> frontend")
+	assert.Contains(t, tree, "Orphan spans:\n-> backend")
+	assert.NotContains(t, tree, "backend-call")
+}
+
+func TestTracesCompactSpans(t *testing.T) {
+	start := time.Unix(1600000000, 0)
+	td := pdatabuilder.NewTraces().
+		Resource(pdatabuilder.Attrs{"service.name": "frontend"}).
+		Span("GET /users").WithKind(pdata.SpanKindServer).WithIDs([16]byte{1}, [8]byte{1}).
+		WithTimestamps(start, start.Add(150*time.Millisecond)).WithStatus(pdata.StatusCodeOk, "").
+		Span("SELECT users").WithKind(pdata.SpanKindClient).WithIDs([16]byte{1}, [8]byte{2}).
+		WithTimestamps(start.Add(time.Millisecond), start.Add(1500*time.Millisecond)).WithStatus(pdata.StatusCodeError, "timeout").
+		Resource(nil).
+		Span("no-service").WithIDs([16]byte{2}, [8]byte{3}).
+		WithTimestamps(start, start.Add(42*time.Microsecond)).
+		Build()
+
+	expected := `service="frontend" name="GET /users" kind=server duration=150ms status=STATUS_CODE_OK trace_id=01000000000000000000000000000000 span_id=0100000000000000
+service="frontend" name="SELECT users" kind=client duration=1.499s status=STATUS_CODE_ERROR trace_id=01000000000000000000000000000000 span_id=0200000000000000
+service="" name="no-service" kind=unspecified duration=42µs status=STATUS_CODE_UNSET trace_id=02000000000000000000000000000000 span_id=0300000000000000
+`
+	assert.Equal(t, expected, Traces(td, WithCompactSpans(true)))
+
+	// The compact layout takes precedence over the tree and applies the span filters.
+	expected = `service="frontend" name="GET /users" kind=server duration=150ms status=STATUS_CODE_OK trace_id=01000000000000000000000000000000 span_id=0100000000000000
+`
+	assert.Equal(t, expected, Traces(td, WithCompactSpans(true), WithSpanTree(true), WithSpanKinds(pdata.SpanKindServer)))
+}
+
+func TestTracesSpanTreeCycle(t *testing.T) {
+	td := pdatabuilder.NewTraces().
+		Span("a").WithIDs([16]byte{1}, [8]byte{1}).WithParentSpanID([8]byte{2}).
+		Span("b").WithIDs([16]byte{1}, [8]byte{2}).WithParentSpanID([8]byte{1}).
+		Span("self").WithIDs([16]byte{1}, [8]byte{3}).WithParentSpanID([8]byte{3}).
+		Span("other-trace").WithIDs([16]byte{2}, [8]byte{1}).
+		Build()
+
+	tree := Traces(td, WithSpanTree(true))
+	assert.Contains(t, tree, "Trace #0")
+	assert.Contains(t, tree, "Trace #1")
+	assert.Contains(t, tree, "Orphan spans:\n-> a [")
+	assert.Contains(t, tree, "\n   -> b [")
+	assert.Contains(t, tree, "\n-> self [")
+	assert.Equal(t, 1, strings.Count(tree, "-> self ["))
+	assert.Contains(t, tree, "\n-> other-trace [")
+}
+
+func TestTracesGroupAttributes(t *testing.T) {
+	attrs := pdatabuilder.Attrs{
+		"http.status_code": 200,
+		"component":        "grpc",
+		"db.system":        "postgresql",
+		"http.method":      "GET",
+		"db.statement":     "SELECT 1",
+		"error":            false,
+	}
+	td := pdatabuilder.NewTraces().Span("query").WithAttrs(attrs).Build()
+
+	traces := Traces(td, WithGroupAttributes(true))
+	expected := `Attributes:
+   [db]
+     -> db.statement: STRING(SELECT 1)
+     -> db.system: STRING(postgresql)
+   [http]
+     -> http.method: STRING(GET)
+     -> http.status_code: INT(200)
+   [general]
+     -> component: STRING(grpc)
+     -> error: BOOL(false)
+`
+	assert.Contains(t, traces, expected)
+
+	// The grouping applies to the filtered attributes.
+	traces = Traces(td, WithGroupAttributes(true), WithAttributeKeys("http.method", "component"))
+	expected = `Attributes:
+   [http]
+     -> http.method: STRING(GET)
+   [general]
+     -> component: STRING(grpc)
+     -> +4 more
+`
+	assert.Contains(t, traces, expected)
+
+	// The flattened keys are grouped by their first segment.
+	td = pdatabuilder.NewTraces().Span("request").WithAttr("http.headers", map[string]interface{}{"accept": "json"}).Build()
+	assert.Contains(t, Traces(td, WithGroupAttributes(true), WithFlattenAttributes(true)), "   [http]\n     -> http.headers.accept: STRING(json)\n")
+}
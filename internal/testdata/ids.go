@@ -0,0 +1,111 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package testdata
+
+import (
+	"encoding/binary"
+	"math/rand"
+	"sync"
+
+	"go.opentelemetry.io/collector/consumer/pdata"
+)
+
+// IDSource generates the trace and span IDs of the spans created by the generators,
+// e.g. to merge the traces generated by several calls without ID collisions. The
+// generators given an IDSource assign a new trace ID to all the spans created by the
+// call, and a new span ID to every span. Without IDSource the IDs are left empty.
+type IDSource interface {
+	TraceID() pdata.TraceID
+	SpanID() pdata.SpanID
+}
+
+type counterIDSource struct {
+	mu      sync.Mutex
+	traceID uint64
+	spanID  uint64
+}
+
+// NewCounterIDSource returns an IDSource generating sequential IDs, starting at 1 for
+// both the trace and the span IDs.
+func NewCounterIDSource() IDSource {
+	return &counterIDSource{}
+}
+
+func (s *counterIDSource) TraceID() pdata.TraceID {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.traceID++
+	var id [16]byte
+	binary.BigEndian.PutUint64(id[8:], s.traceID)
+	return pdata.NewTraceID(id)
+}
+
+func (s *counterIDSource) SpanID() pdata.SpanID {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.spanID++
+	var id [8]byte
+	binary.BigEndian.PutUint64(id[:], s.spanID)
+	return pdata.NewSpanID(id)
+}
+
+type randomIDSource struct {
+	mu  sync.Mutex
+	rnd *rand.Rand
+}
+
+// NewRandomIDSource returns an IDSource generating random non-empty IDs, the same
+// sequence of IDs for a given seed.
+func NewRandomIDSource(seed int64) IDSource {
+	return &randomIDSource{rnd: rand.New(rand.NewSource(seed))}
+}
+
+func (s *randomIDSource) TraceID() pdata.TraceID {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var id [16]byte
+	for id == [16]byte{} {
+		s.rnd.Read(id[:])
+	}
+	return pdata.NewTraceID(id)
+}
+
+func (s *randomIDSource) SpanID() pdata.SpanID {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var id [8]byte
+	for id == [8]byte{} {
+		s.rnd.Read(id[:])
+	}
+	return pdata.NewSpanID(id)
+}
+
+// assignIDs sets the IDs of all the spans of td using the first IDSource, if any.
+func assignIDs(td pdata.Traces, ids []IDSource) {
+	if len(ids) == 0 || ids[0] == nil {
+		return
+	}
+	traceID := ids[0].TraceID()
+	rss := td.ResourceSpans()
+	for i := 0; i < rss.Len(); i++ {
+		ilss := rss.At(i).InstrumentationLibrarySpans()
+		for j := 0; j < ilss.Len(); j++ {
+			spans := ilss.At(j).Spans()
+			for k := 0; k < spans.Len(); k++ {
+				spans.At(k).SetTraceID(traceID)
+				spans.At(k).SetSpanID(ids[0].SpanID())
+			}
+		}
+	}
+}
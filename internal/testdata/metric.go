@@ -142,6 +142,15 @@ func generateMetricsOtlpOneMetric() *otlpcollectormetrics.ExportMetricsServiceRe
 	}
 }
 
+// GenerateMetricsOneMetricNoStartTimestamp returns a counter whose first data point has a
+// start timestamp and whose second data point has none.
+func GenerateMetricsOneMetricNoStartTimestamp() pdata.Metrics {
+	md := GenerateMetricsOneMetric()
+	dps := md.ResourceMetrics().At(0).InstrumentationLibraryMetrics().At(0).Metrics().At(0).IntSum().DataPoints()
+	dps.At(1).SetStartTimestamp(0)
+	return md
+}
+
 func GenerateMetricsOneMetricOneDataPoint() pdata.Metrics {
 	md := GenerateMetricsOneEmptyInstrumentationLibrary()
 	rm0ils0 := md.ResourceMetrics().At(0).InstrumentationLibraryMetrics().At(0)
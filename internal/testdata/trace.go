@@ -20,6 +20,7 @@ import (
 	"go.opentelemetry.io/collector/consumer/pdata"
 	otlpcollectortrace "go.opentelemetry.io/collector/internal/data/protogen/collector/trace/v1"
 	otlptrace "go.opentelemetry.io/collector/internal/data/protogen/trace/v1"
+	"go.opentelemetry.io/collector/translator/conventions"
 )
 
 var (
@@ -83,10 +84,11 @@ func generateTracesOtlpOneEmptyInstrumentationLibrary() *otlpcollectortrace.Expo
 	}
 }
 
-func GenerateTracesOneSpanNoResource() pdata.Traces {
+func GenerateTracesOneSpanNoResource(ids ...IDSource) pdata.Traces {
 	td := GenerateTracesOneEmptyResourceSpans()
 	rs0 := td.ResourceSpans().At(0)
 	fillSpanOne(rs0.InstrumentationLibrarySpans().AppendEmpty().Spans().AppendEmpty())
+	assignIDs(td, ids)
 	return td
 }
 
@@ -106,10 +108,11 @@ func generateTracesOtlpOneSpanNoResource() *otlpcollectortrace.ExportTraceServic
 	}
 }
 
-func GenerateTracesOneSpan() pdata.Traces {
+func GenerateTracesOneSpan(ids ...IDSource) pdata.Traces {
 	td := GenerateTracesOneEmptyInstrumentationLibrary()
 	rs0ils0 := td.ResourceSpans().At(0).InstrumentationLibrarySpans().At(0)
 	fillSpanOne(rs0ils0.Spans().AppendEmpty())
+	assignIDs(td, ids)
 	return td
 }
 
@@ -130,11 +133,12 @@ func generateTracesOtlpOneSpan() *otlpcollectortrace.ExportTraceServiceRequest {
 	}
 }
 
-func GenerateTracesTwoSpansSameResource() pdata.Traces {
+func GenerateTracesTwoSpansSameResource(ids ...IDSource) pdata.Traces {
 	td := GenerateTracesOneEmptyInstrumentationLibrary()
 	rs0ils0 := td.ResourceSpans().At(0).InstrumentationLibrarySpans().At(0)
 	fillSpanOne(rs0ils0.Spans().AppendEmpty())
 	fillSpanTwo(rs0ils0.Spans().AppendEmpty())
+	assignIDs(td, ids)
 	return td
 }
 
@@ -157,7 +161,7 @@ func generateTracesOtlpSameResourceTwoSpans() *otlpcollectortrace.ExportTraceSer
 	}
 }
 
-func GenerateTracesTwoSpansSameResourceOneDifferent() pdata.Traces {
+func GenerateTracesTwoSpansSameResourceOneDifferent(ids ...IDSource) pdata.Traces {
 	td := pdata.NewTraces()
 	rs0 := td.ResourceSpans().AppendEmpty()
 	initResource1(rs0.Resource())
@@ -168,19 +172,56 @@ func GenerateTracesTwoSpansSameResourceOneDifferent() pdata.Traces {
 	initResource2(rs1.Resource())
 	rs1ils0 := rs1.InstrumentationLibrarySpans().AppendEmpty()
 	fillSpanThree(rs1ils0.Spans().AppendEmpty())
+	assignIDs(td, ids)
 	return td
 }
 
-func GenerateTracesManySpansSameResource(spansCount int) pdata.Traces {
+func GenerateTracesManySpansSameResource(spansCount int, ids ...IDSource) pdata.Traces {
 	td := GenerateTracesOneEmptyInstrumentationLibrary()
 	rs0ils0 := td.ResourceSpans().At(0).InstrumentationLibrarySpans().At(0)
 	rs0ils0.Spans().Resize(spansCount)
 	for i := 0; i < spansCount; i++ {
 		fillSpanOne(rs0ils0.Spans().At(i))
 	}
+	assignIDs(td, ids)
 	return td
 }
 
+// GenerateTracesSpanTree returns the spans of a trace spread over two resources, with the
+// children listed before their parents. The "frontend" root span has a "backend-call" child,
+// parent of the "backend" span of the second resource which has a "db-query" child. The
+// "orphan" span has a parent that is not part of the batch.
+func GenerateTracesSpanTree() pdata.Traces {
+	traceID := pdata.NewTraceID([16]byte{1, 2, 3, 4})
+	td := pdata.NewTraces()
+
+	backend := td.ResourceSpans().AppendEmpty()
+	backend.Resource().Attributes().InsertString(conventions.AttributeServiceName, "backend")
+	backendSpans := backend.InstrumentationLibrarySpans().AppendEmpty().Spans()
+	fillTreeSpan(backendSpans.AppendEmpty(), traceID, "db-query", 4, 3, pdata.SpanKindClient)
+	fillTreeSpan(backendSpans.AppendEmpty(), traceID, "backend", 3, 2, pdata.SpanKindServer)
+	fillTreeSpan(backendSpans.AppendEmpty(), traceID, "orphan", 5, 9, pdata.SpanKindInternal)
+
+	frontend := td.ResourceSpans().AppendEmpty()
+	frontend.Resource().Attributes().InsertString(conventions.AttributeServiceName, "frontend")
+	frontendSpans := frontend.InstrumentationLibrarySpans().AppendEmpty().Spans()
+	fillTreeSpan(frontendSpans.AppendEmpty(), traceID, "backend-call", 2, 1, pdata.SpanKindClient)
+	fillTreeSpan(frontendSpans.AppendEmpty(), traceID, "frontend", 1, 0, pdata.SpanKindServer)
+	return td
+}
+
+func fillTreeSpan(span pdata.Span, traceID pdata.TraceID, name string, id, parentID byte, kind pdata.SpanKind) {
+	span.SetTraceID(traceID)
+	span.SetSpanID(pdata.NewSpanID([8]byte{id}))
+	if parentID != 0 {
+		span.SetParentSpanID(pdata.NewSpanID([8]byte{parentID}))
+	}
+	span.SetName(name)
+	span.SetKind(kind)
+	span.SetStartTimestamp(TestSpanStartTimestamp)
+	span.SetEndTimestamp(TestSpanEndTimestamp)
+}
+
 func generateTracesOtlpTwoSpansSameResourceOneDifferent() *otlpcollectortrace.ExportTraceServiceRequest {
 	return &otlpcollectortrace.ExportTraceServiceRequest{
 		ResourceSpans: []*otlptrace.ResourceSpans{
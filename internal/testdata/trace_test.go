@@ -15,6 +15,7 @@
 package testdata
 
 import (
+	"sort"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -83,3 +84,59 @@ func TestToFromOtlpTrace(t *testing.T) {
 		})
 	}
 }
+
+func TestGenerateTracesIDSource(t *testing.T) {
+	for _, ids := range []IDSource{NewCounterIDSource(), NewRandomIDSource(42)} {
+		td := pdata.NewTraces()
+		GenerateTracesTwoSpansSameResource(ids).ResourceSpans().MoveAndAppendTo(td.ResourceSpans())
+		GenerateTracesTwoSpansSameResourceOneDifferent(ids).ResourceSpans().MoveAndAppendTo(td.ResourceSpans())
+		GenerateTracesManySpansSameResource(10, ids).ResourceSpans().MoveAndAppendTo(td.ResourceSpans())
+
+		traceIDs := map[pdata.TraceID]int{}
+		spanIDs := map[pdata.SpanID]struct{}{}
+		rss := td.ResourceSpans()
+		for i := 0; i < rss.Len(); i++ {
+			spans := rss.At(i).InstrumentationLibrarySpans().At(0).Spans()
+			for j := 0; j < spans.Len(); j++ {
+				span := spans.At(j)
+				assert.False(t, span.TraceID().IsEmpty())
+				assert.False(t, span.SpanID().IsEmpty())
+				traceIDs[span.TraceID()]++
+				spanIDs[span.SpanID()] = struct{}{}
+			}
+		}
+		// Every call generates one trace, with unique span IDs.
+		assert.Equal(t, []int{2, 3, 10}, sortedValues(traceIDs))
+		assert.Len(t, spanIDs, 15)
+	}
+}
+
+func TestCounterIDSource(t *testing.T) {
+	ids := NewCounterIDSource()
+	assert.Equal(t, pdata.NewTraceID([16]byte{15: 1}), ids.TraceID())
+	assert.Equal(t, pdata.NewTraceID([16]byte{15: 2}), ids.TraceID())
+	assert.Equal(t, pdata.NewSpanID([8]byte{7: 1}), ids.SpanID())
+}
+
+func TestRandomIDSourceSeed(t *testing.T) {
+	ids1, ids2 := NewRandomIDSource(1), NewRandomIDSource(1)
+	assert.Equal(t, ids1.TraceID(), ids2.TraceID())
+	assert.Equal(t, ids1.SpanID(), ids2.SpanID())
+	assert.NotEqual(t, NewRandomIDSource(2).TraceID(), NewRandomIDSource(1).TraceID())
+}
+
+func TestGenerateTracesDefaultIDs(t *testing.T) {
+	// Without IDSource the generated spans keep their empty IDs.
+	span := GenerateTracesOneSpan().ResourceSpans().At(0).InstrumentationLibrarySpans().At(0).Spans().At(0)
+	assert.True(t, span.TraceID().IsEmpty())
+	assert.True(t, span.SpanID().IsEmpty())
+}
+
+func sortedValues(m map[pdata.TraceID]int) []int {
+	values := make([]int, 0, len(m))
+	for _, v := range m {
+		values = append(values, v)
+	}
+	sort.Ints(values)
+	return values
+}
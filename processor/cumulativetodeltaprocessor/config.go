@@ -0,0 +1,46 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cumulativetodeltaprocessor
+
+import (
+	"errors"
+	"time"
+
+	"go.opentelemetry.io/collector/config"
+)
+
+// Config defines configuration for the cumulative to delta processor.
+type Config struct {
+	config.ProcessorSettings `mapstructure:",squash"` // squash ensures fields are correctly decoded in embedded struct
+
+	// Metrics is the list of the names of the metrics to convert. Empty means all
+	// the monotonic cumulative sums are converted.
+	Metrics []string `mapstructure:"metrics"`
+
+	// MaxStaleness is the time after which a series not seen anymore is forgotten,
+	// its next data point being handled as a first observation. Zero means the
+	// series are never forgotten.
+	MaxStaleness time.Duration `mapstructure:"max_staleness"`
+}
+
+var _ config.Processor = (*Config)(nil)
+
+// Validate checks if the processor configuration is valid
+func (cfg *Config) Validate() error {
+	if cfg.MaxStaleness < 0 {
+		return errors.New("max_staleness must be non-negative")
+	}
+	return nil
+}
@@ -0,0 +1,105 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cumulativetodeltaprocessor
+
+import (
+	"context"
+
+	"go.opentelemetry.io/collector/consumer/pdata"
+	"go.opentelemetry.io/collector/internal/metricskey"
+	"go.opentelemetry.io/collector/processor/processorhelper"
+)
+
+type cumulativeToDeltaProcessor struct {
+	metrics map[string]bool
+	tracker *deltaTracker
+}
+
+func newCumulativeToDeltaProcessor(cfg *Config) *cumulativeToDeltaProcessor {
+	var metrics map[string]bool
+	if len(cfg.Metrics) > 0 {
+		metrics = make(map[string]bool, len(cfg.Metrics))
+		for _, name := range cfg.Metrics {
+			metrics[name] = true
+		}
+	}
+	return &cumulativeToDeltaProcessor{
+		metrics: metrics,
+		tracker: newDeltaTracker(cfg.MaxStaleness),
+	}
+}
+
+// ProcessMetrics converts the monotonic cumulative sums to delta sums. The first
+// data point of every series is dropped since there is no previous value to compute
+// the delta from.
+func (ctdp *cumulativeToDeltaProcessor) ProcessMetrics(_ context.Context, md pdata.Metrics) (pdata.Metrics, error) {
+	ctdp.tracker.removeExpired()
+	md.ResourceMetrics().RemoveIf(func(rm pdata.ResourceMetrics) bool {
+		resKey := metricskey.Resource(rm.Resource())
+		rm.InstrumentationLibraryMetrics().RemoveIf(func(ilm pdata.InstrumentationLibraryMetrics) bool {
+			ilm.Metrics().RemoveIf(func(m pdata.Metric) bool {
+				if !ctdp.shouldConvert(m) {
+					return false
+				}
+				return ctdp.convertMetric(metricskey.Metric(resKey, ilm.InstrumentationLibrary(), m), m)
+			})
+			// Filter out empty InstrumentationLibraryMetrics
+			return ilm.Metrics().Len() == 0
+		})
+		// Filter out empty ResourceMetrics
+		return rm.InstrumentationLibraryMetrics().Len() == 0
+	})
+	if md.ResourceMetrics().Len() == 0 {
+		return md, processorhelper.ErrSkipProcessingData
+	}
+	return md, nil
+}
+
+func (ctdp *cumulativeToDeltaProcessor) shouldConvert(m pdata.Metric) bool {
+	if ctdp.metrics != nil && !ctdp.metrics[m.Name()] {
+		return false
+	}
+	switch m.DataType() {
+	case pdata.MetricDataTypeIntSum:
+		sum := m.IntSum()
+		return sum.IsMonotonic() && sum.AggregationTemporality() == pdata.AggregationTemporalityCumulative
+	case pdata.MetricDataTypeDoubleSum:
+		sum := m.DoubleSum()
+		return sum.IsMonotonic() && sum.AggregationTemporality() == pdata.AggregationTemporalityCumulative
+	}
+	return false
+}
+
+// convertMetric converts the data points of the metric to deltas and returns
+// whether the metric has no data point left and must be removed.
+func (ctdp *cumulativeToDeltaProcessor) convertMetric(metKey string, m pdata.Metric) bool {
+	switch m.DataType() {
+	case pdata.MetricDataTypeIntSum:
+		sum := m.IntSum()
+		sum.SetAggregationTemporality(pdata.AggregationTemporalityDelta)
+		sum.DataPoints().RemoveIf(func(dp pdata.IntDataPoint) bool {
+			return !ctdp.tracker.convertInt(metricskey.Series(metKey, dp.LabelsMap()), dp)
+		})
+		return sum.DataPoints().Len() == 0
+	case pdata.MetricDataTypeDoubleSum:
+		sum := m.DoubleSum()
+		sum.SetAggregationTemporality(pdata.AggregationTemporalityDelta)
+		sum.DataPoints().RemoveIf(func(dp pdata.DoubleDataPoint) bool {
+			return !ctdp.tracker.convertDouble(metricskey.Series(metKey, dp.LabelsMap()), dp)
+		})
+		return sum.DataPoints().Len() == 0
+	}
+	return false
+}
@@ -0,0 +1,276 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cumulativetodeltaprocessor
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go.opentelemetry.io/collector/consumer/pdata"
+	"go.opentelemetry.io/collector/consumer/pdatabuilder"
+	"go.opentelemetry.io/collector/processor/processorhelper"
+)
+
+const start = pdata.Timestamp(1000)
+
+// setTimestamps sets the start and timestamp of all the sum data points of md.
+func setTimestamps(md pdata.Metrics, start, ts pdata.Timestamp) pdata.Metrics {
+	rms := md.ResourceMetrics()
+	for i := 0; i < rms.Len(); i++ {
+		ilms := rms.At(i).InstrumentationLibraryMetrics()
+		for j := 0; j < ilms.Len(); j++ {
+			metrics := ilms.At(j).Metrics()
+			for k := 0; k < metrics.Len(); k++ {
+				switch m := metrics.At(k); m.DataType() {
+				case pdata.MetricDataTypeIntSum:
+					dps := m.IntSum().DataPoints()
+					for l := 0; l < dps.Len(); l++ {
+						dps.At(l).SetStartTimestamp(start)
+						dps.At(l).SetTimestamp(ts)
+					}
+				case pdata.MetricDataTypeDoubleSum:
+					dps := m.DoubleSum().DataPoints()
+					for l := 0; l < dps.Len(); l++ {
+						dps.At(l).SetStartTimestamp(start)
+						dps.At(l).SetTimestamp(ts)
+					}
+				}
+			}
+		}
+	}
+	return md
+}
+
+func intSum(value int64, labels map[string]string, ts pdata.Timestamp) pdata.Metrics {
+	return setTimestamps(pdatabuilder.NewMetrics().IntSum("requests", true).IntDataPoint(value, labels).Build(), start, ts)
+}
+
+func firstMetric(md pdata.Metrics) pdata.Metric {
+	return md.ResourceMetrics().At(0).InstrumentationLibraryMetrics().At(0).Metrics().At(0)
+}
+
+func TestProcessMetricsIntSum(t *testing.T) {
+	p := newCumulativeToDeltaProcessor(createDefaultConfig().(*Config))
+
+	// The first observation has no previous value and is dropped.
+	_, err := p.ProcessMetrics(context.Background(), intSum(10, nil, 2000))
+	assert.Equal(t, processorhelper.ErrSkipProcessingData, err)
+
+	md, err := p.ProcessMetrics(context.Background(), intSum(25, nil, 3000))
+	require.NoError(t, err)
+	sum := firstMetric(md).IntSum()
+	assert.Equal(t, pdata.AggregationTemporalityDelta, sum.AggregationTemporality())
+	require.Equal(t, 1, sum.DataPoints().Len())
+	dp := sum.DataPoints().At(0)
+	assert.EqualValues(t, 15, dp.Value())
+	assert.Equal(t, pdata.Timestamp(2000), dp.StartTimestamp())
+	assert.Equal(t, pdata.Timestamp(3000), dp.Timestamp())
+}
+
+func TestProcessMetricsDoubleSum(t *testing.T) {
+	p := newCumulativeToDeltaProcessor(createDefaultConfig().(*Config))
+	doubleSum := func(value float64, ts pdata.Timestamp) pdata.Metrics {
+		return setTimestamps(pdatabuilder.NewMetrics().DoubleSum("bytes", true).DoubleDataPoint(value, nil).Build(), start, ts)
+	}
+
+	_, err := p.ProcessMetrics(context.Background(), doubleSum(1.5, 2000))
+	assert.Equal(t, processorhelper.ErrSkipProcessingData, err)
+
+	md, err := p.ProcessMetrics(context.Background(), doubleSum(4, 3000))
+	require.NoError(t, err)
+	sum := firstMetric(md).DoubleSum()
+	assert.Equal(t, pdata.AggregationTemporalityDelta, sum.AggregationTemporality())
+	assert.Equal(t, 2.5, sum.DataPoints().At(0).Value())
+	assert.Equal(t, pdata.Timestamp(2000), sum.DataPoints().At(0).StartTimestamp())
+}
+
+func TestProcessMetricsReset(t *testing.T) {
+	p := newCumulativeToDeltaProcessor(createDefaultConfig().(*Config))
+	_, err := p.ProcessMetrics(context.Background(), intSum(10, nil, 2000))
+	assert.Equal(t, processorhelper.ErrSkipProcessingData, err)
+
+	// The value decreased, the counter restarted and the value is the delta.
+	md, err := p.ProcessMetrics(context.Background(), intSum(4, nil, 3000))
+	require.NoError(t, err)
+	dp := firstMetric(md).IntSum().DataPoints().At(0)
+	assert.EqualValues(t, 4, dp.Value())
+	assert.Equal(t, start, dp.StartTimestamp())
+
+	// The deltas are computed from the new start.
+	md, err = p.ProcessMetrics(context.Background(), intSum(7, nil, 4000))
+	require.NoError(t, err)
+	assert.EqualValues(t, 3, firstMetric(md).IntSum().DataPoints().At(0).Value())
+
+	// A new start timestamp is a reset even if the value increased.
+	md, err = p.ProcessMetrics(context.Background(), setTimestamps(intSum(20, nil, 0), 4500, 5000))
+	require.NoError(t, err)
+	dp = firstMetric(md).IntSum().DataPoints().At(0)
+	assert.EqualValues(t, 20, dp.Value())
+	assert.Equal(t, pdata.Timestamp(4500), dp.StartTimestamp())
+}
+
+func TestProcessMetricsMissingPoints(t *testing.T) {
+	p := newCumulativeToDeltaProcessor(createDefaultConfig().(*Config))
+	a := map[string]string{"code": "200"}
+	b := map[string]string{"code": "500"}
+	both := func(va, vb int64, ts pdata.Timestamp) pdata.Metrics {
+		return setTimestamps(pdatabuilder.NewMetrics().IntSum("requests", true).IntDataPoint(va, a).IntDataPoint(vb, b).Build(), start, ts)
+	}
+
+	_, err := p.ProcessMetrics(context.Background(), both(1, 100, 2000))
+	assert.Equal(t, processorhelper.ErrSkipProcessingData, err)
+
+	// The "500" series is missing from this batch.
+	md, err := p.ProcessMetrics(context.Background(), intSum(3, a, 3000))
+	require.NoError(t, err)
+	assert.EqualValues(t, 2, firstMetric(md).IntSum().DataPoints().At(0).Value())
+
+	// The delta of the "500" series spans the batch where it was missing.
+	md, err = p.ProcessMetrics(context.Background(), both(4, 150, 4000))
+	require.NoError(t, err)
+	dps := firstMetric(md).IntSum().DataPoints()
+	require.Equal(t, 2, dps.Len())
+	assert.EqualValues(t, 1, dps.At(0).Value())
+	assert.Equal(t, pdata.Timestamp(3000), dps.At(0).StartTimestamp())
+	assert.EqualValues(t, 50, dps.At(1).Value())
+	assert.Equal(t, pdata.Timestamp(2000), dps.At(1).StartTimestamp())
+
+	// A series observed for the first time is dropped, the others are kept.
+	md, err = p.ProcessMetrics(context.Background(), setTimestamps(pdatabuilder.NewMetrics().
+		IntSum("requests", true).IntDataPoint(5, a).IntDataPoint(1, map[string]string{"code": "404"}).Build(), start, 5000))
+	require.NoError(t, err)
+	dps = firstMetric(md).IntSum().DataPoints()
+	require.Equal(t, 1, dps.Len())
+	code, _ := dps.At(0).LabelsMap().Get("code")
+	assert.Equal(t, "200", code)
+}
+
+func TestProcessMetricsMaxStaleness(t *testing.T) {
+	cfg := createDefaultConfig().(*Config)
+	cfg.MaxStaleness = time.Minute
+	p := newCumulativeToDeltaProcessor(cfg)
+	now := time.Unix(1600000000, 0)
+	p.tracker.now = func() time.Time { return now }
+	p.tracker.lastSweep = now
+	a := map[string]string{"code": "200"}
+	b := map[string]string{"code": "500"}
+
+	_, err := p.ProcessMetrics(context.Background(), intSum(10, a, 2000))
+	assert.Equal(t, processorhelper.ErrSkipProcessingData, err)
+	_, err = p.ProcessMetrics(context.Background(), intSum(100, b, 2000))
+	assert.Equal(t, processorhelper.ErrSkipProcessingData, err)
+	assert.Equal(t, 2, p.tracker.len())
+
+	// The "200" series keeps being reported, the "500" series is forgotten.
+	now = now.Add(40 * time.Second)
+	md, err := p.ProcessMetrics(context.Background(), intSum(15, a, 3000))
+	require.NoError(t, err)
+	assert.EqualValues(t, 5, firstMetric(md).IntSum().DataPoints().At(0).Value())
+	now = now.Add(40 * time.Second)
+	md, err = p.ProcessMetrics(context.Background(), intSum(18, a, 4000))
+	require.NoError(t, err)
+	assert.EqualValues(t, 3, firstMetric(md).IntSum().DataPoints().At(0).Value())
+	assert.Equal(t, 1, p.tracker.len())
+
+	// The forgotten series starts over as a first observation.
+	_, err = p.ProcessMetrics(context.Background(), intSum(150, b, 4000))
+	assert.Equal(t, processorhelper.ErrSkipProcessingData, err)
+	md, err = p.ProcessMetrics(context.Background(), intSum(160, b, 5000))
+	require.NoError(t, err)
+	assert.EqualValues(t, 10, firstMetric(md).IntSum().DataPoints().At(0).Value())
+}
+
+func TestProcessMetricsExpiredBeforeSweep(t *testing.T) {
+	cfg := createDefaultConfig().(*Config)
+	cfg.MaxStaleness = time.Minute
+	p := newCumulativeToDeltaProcessor(cfg)
+	now := time.Unix(1600000000, 0)
+	p.tracker.now = func() time.Time { return now }
+	p.tracker.lastSweep = now
+
+	_, err := p.ProcessMetrics(context.Background(), intSum(10, nil, 2000))
+	assert.Equal(t, processorhelper.ErrSkipProcessingData, err)
+
+	// The series is not swept yet, but its data point is too old to compute a delta from.
+	now = now.Add(time.Minute)
+	p.tracker.lastSweep = now
+	_, err = p.ProcessMetrics(context.Background(), intSum(20, nil, 3000))
+	assert.Equal(t, processorhelper.ErrSkipProcessingData, err)
+}
+
+func TestProcessMetricsStalePoint(t *testing.T) {
+	p := newCumulativeToDeltaProcessor(createDefaultConfig().(*Config))
+	_, err := p.ProcessMetrics(context.Background(), intSum(10, nil, 3000))
+	assert.Equal(t, processorhelper.ErrSkipProcessingData, err)
+
+	// Out of order and duplicate data points are dropped without updating the series.
+	_, err = p.ProcessMetrics(context.Background(), intSum(5, nil, 2000))
+	assert.Equal(t, processorhelper.ErrSkipProcessingData, err)
+	_, err = p.ProcessMetrics(context.Background(), intSum(10, nil, 3000))
+	assert.Equal(t, processorhelper.ErrSkipProcessingData, err)
+
+	md, err := p.ProcessMetrics(context.Background(), intSum(12, nil, 4000))
+	require.NoError(t, err)
+	assert.EqualValues(t, 2, firstMetric(md).IntSum().DataPoints().At(0).Value())
+}
+
+func TestProcessMetricsSeriesIdentity(t *testing.T) {
+	p := newCumulativeToDeltaProcessor(createDefaultConfig().(*Config))
+	build := func(service string, value int64, ts pdata.Timestamp) pdata.Metrics {
+		return setTimestamps(pdatabuilder.NewMetrics().
+			Resource(pdatabuilder.Attrs{"service.name": service}).
+			IntSum("requests", true).IntDataPoint(value, map[string]string{"b": "2", "a": "1"}).
+			Build(), start, ts)
+	}
+
+	_, err := p.ProcessMetrics(context.Background(), build("svc1", 10, 2000))
+	assert.Equal(t, processorhelper.ErrSkipProcessingData, err)
+	// Another resource is another series.
+	_, err = p.ProcessMetrics(context.Background(), build("svc2", 100, 2000))
+	assert.Equal(t, processorhelper.ErrSkipProcessingData, err)
+
+	// The order of the labels does not matter.
+	md := setTimestamps(pdatabuilder.NewMetrics().
+		Resource(pdatabuilder.Attrs{"service.name": "svc1"}).
+		IntSum("requests", true).IntDataPoint(15, map[string]string{"a": "1", "b": "2"}).
+		Build(), start, 3000)
+	md, err = p.ProcessMetrics(context.Background(), md)
+	require.NoError(t, err)
+	assert.EqualValues(t, 5, firstMetric(md).IntSum().DataPoints().At(0).Value())
+}
+
+func TestProcessMetricsNotConverted(t *testing.T) {
+	cfg := createDefaultConfig().(*Config)
+	cfg.Metrics = []string{"requests"}
+	p := newCumulativeToDeltaProcessor(cfg)
+
+	md := setTimestamps(pdatabuilder.NewMetrics().
+		IntSum("other", true).IntDataPoint(1, nil).
+		IntSum("requests", false).IntDataPoint(1, nil).
+		IntGauge("requests").IntDataPoint(1, nil).
+		IntSum("requests", true).IntDataPoint(1, nil).
+		Build(), start, 2000)
+	md.ResourceMetrics().At(0).InstrumentationLibraryMetrics().At(0).Metrics().At(3).IntSum().
+		SetAggregationTemporality(pdata.AggregationTemporalityDelta)
+	expected := md.Clone()
+
+	got, err := p.ProcessMetrics(context.Background(), md)
+	require.NoError(t, err)
+	assert.Equal(t, expected, got)
+}
@@ -0,0 +1,165 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cumulativetodeltaprocessor
+
+import (
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/collector/consumer/pdata"
+)
+
+// deltaTracker keeps the last cumulative data point of every series to convert
+// the following data points of the series to deltas. The series not seen for
+// maxStaleness are forgotten, so that the memory does not grow with the churn of
+// the series, e.g. new label values.
+type deltaTracker struct {
+	mu     sync.Mutex
+	points map[string]trackedPoint
+	// maxStaleness is the time after which a series not seen anymore is forgotten,
+	// the series are never forgotten if zero.
+	maxStaleness time.Duration
+	now          func() time.Time
+	// lastSweep is the last time the forgotten series were removed.
+	lastSweep time.Time
+}
+
+// trackedPoint is the last cumulative data point observed for a series, only one
+// of the int and double values is used depending on the type of the metric.
+type trackedPoint struct {
+	start       pdata.Timestamp
+	timestamp   pdata.Timestamp
+	intValue    int64
+	doubleValue float64
+	// seen is the time the data point was observed.
+	seen time.Time
+}
+
+func newDeltaTracker(maxStaleness time.Duration) *deltaTracker {
+	return &deltaTracker{
+		points:       make(map[string]trackedPoint),
+		maxStaleness: maxStaleness,
+		now:          time.Now,
+		lastSweep:    time.Now(),
+	}
+}
+
+// lookup returns the last data point of the series identified by key, ignoring
+// it if the series was not seen for maxStaleness.
+func (t *deltaTracker) lookup(key string, now time.Time) (trackedPoint, bool) {
+	prev, found := t.points[key]
+	if found && t.isExpired(prev, now) {
+		return trackedPoint{}, false
+	}
+	return prev, found
+}
+
+func (t *deltaTracker) isExpired(prev trackedPoint, now time.Time) bool {
+	return t.maxStaleness > 0 && now.Sub(prev.seen) >= t.maxStaleness
+}
+
+// removeExpired removes the series not seen for maxStaleness, at most once every
+// maxStaleness, so the forgotten series are kept in memory for at most twice
+// maxStaleness.
+func (t *deltaTracker) removeExpired() {
+	if t.maxStaleness <= 0 {
+		return
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	now := t.now()
+	if now.Sub(t.lastSweep) < t.maxStaleness {
+		return
+	}
+	t.lastSweep = now
+	for key, prev := range t.points {
+		if t.isExpired(prev, now) {
+			delete(t.points, key)
+		}
+	}
+}
+
+// len returns the number of series tracked.
+func (t *deltaTracker) len() int {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return len(t.points)
+}
+
+// convertInt converts the cumulative data point to a delta against the previous
+// data point of the series identified by key. It returns false if the data point
+// has no delta and must be dropped: the first observation of the series, including
+// after it was forgotten, or a data point not newer than the previous one.
+func (t *deltaTracker) convertInt(key string, dp pdata.IntDataPoint) bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	now := t.now()
+	prev, found := t.lookup(key, now)
+	if found && isStale(prev, dp.Timestamp()) {
+		return false
+	}
+	t.points[key] = trackedPoint{start: dp.StartTimestamp(), timestamp: dp.Timestamp(), intValue: dp.Value(), seen: now}
+	if !found {
+		return false
+	}
+	if isReset(prev, dp.StartTimestamp(), dp.Value() < prev.intValue) {
+		// The counter restarted, the whole value accumulated since the reset is the delta.
+		if dp.StartTimestamp() == 0 {
+			dp.SetStartTimestamp(prev.timestamp)
+		}
+		return true
+	}
+	dp.SetStartTimestamp(prev.timestamp)
+	dp.SetValue(dp.Value() - prev.intValue)
+	return true
+}
+
+// convertDouble is the equivalent of convertInt for double data points.
+func (t *deltaTracker) convertDouble(key string, dp pdata.DoubleDataPoint) bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	now := t.now()
+	prev, found := t.lookup(key, now)
+	if found && isStale(prev, dp.Timestamp()) {
+		return false
+	}
+	t.points[key] = trackedPoint{start: dp.StartTimestamp(), timestamp: dp.Timestamp(), doubleValue: dp.Value(), seen: now}
+	if !found {
+		return false
+	}
+	if isReset(prev, dp.StartTimestamp(), dp.Value() < prev.doubleValue) {
+		if dp.StartTimestamp() == 0 {
+			dp.SetStartTimestamp(prev.timestamp)
+		}
+		return true
+	}
+	dp.SetStartTimestamp(prev.timestamp)
+	dp.SetValue(dp.Value() - prev.doubleValue)
+	return true
+}
+
+// isStale returns whether a data point is not newer than the previous data point
+// of its series, data points without timestamp are never considered stale.
+func isStale(prev trackedPoint, timestamp pdata.Timestamp) bool {
+	return timestamp != 0 && timestamp <= prev.timestamp
+}
+
+// isReset returns whether the series restarted since the previous data point,
+// either because the value decreased or because the start timestamp changed.
+func isReset(prev trackedPoint, start pdata.Timestamp, decreased bool) bool {
+	return decreased || (start != 0 && start != prev.start)
+}
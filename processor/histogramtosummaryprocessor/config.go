@@ -0,0 +1,46 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package histogramtosummaryprocessor
+
+import (
+	"fmt"
+
+	"go.opentelemetry.io/collector/config"
+)
+
+// Config defines configuration for the histogram to summary processor.
+type Config struct {
+	config.ProcessorSettings `mapstructure:",squash"` // squash ensures fields are correctly decoded in embedded struct
+
+	// Quantiles is the list of the quantiles, between 0 and 1, estimated for every
+	// converted histogram. Empty means the 0.5, 0.9 and 0.99 quantiles.
+	Quantiles []float64 `mapstructure:"quantiles"`
+
+	// Metrics is the list of the names of the metrics to convert. Empty means all
+	// the histograms are converted.
+	Metrics []string `mapstructure:"metrics"`
+}
+
+var _ config.Processor = (*Config)(nil)
+
+// Validate checks if the processor configuration is valid
+func (cfg *Config) Validate() error {
+	for _, q := range cfg.Quantiles {
+		if q < 0 || q > 1 {
+			return fmt.Errorf("quantiles must be between 0 and 1, got %v", q)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,152 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package histogramtosummaryprocessor
+
+import (
+	"context"
+
+	"go.opentelemetry.io/collector/consumer/pdata"
+)
+
+var defaultQuantiles = []float64{0.5, 0.9, 0.99}
+
+type histogramToSummaryProcessor struct {
+	quantiles []float64
+	metrics   map[string]bool
+}
+
+func newHistogramToSummaryProcessor(cfg *Config) *histogramToSummaryProcessor {
+	var metrics map[string]bool
+	if len(cfg.Metrics) > 0 {
+		metrics = make(map[string]bool, len(cfg.Metrics))
+		for _, name := range cfg.Metrics {
+			metrics[name] = true
+		}
+	}
+	quantiles := cfg.Quantiles
+	if len(quantiles) == 0 {
+		quantiles = defaultQuantiles
+	}
+	return &histogramToSummaryProcessor{
+		quantiles: quantiles,
+		metrics:   metrics,
+	}
+}
+
+// ProcessMetrics converts the histograms to summaries.
+func (htsp *histogramToSummaryProcessor) ProcessMetrics(_ context.Context, md pdata.Metrics) (pdata.Metrics, error) {
+	rms := md.ResourceMetrics()
+	for i := 0; i < rms.Len(); i++ {
+		ilms := rms.At(i).InstrumentationLibraryMetrics()
+		for j := 0; j < ilms.Len(); j++ {
+			metrics := ilms.At(j).Metrics()
+			for k := 0; k < metrics.Len(); k++ {
+				if m := metrics.At(k); htsp.metrics == nil || htsp.metrics[m.Name()] {
+					htsp.convertMetric(m)
+				}
+			}
+		}
+	}
+	return md, nil
+}
+
+// convertMetric replaces the data of m by a summary if m is a histogram.
+func (htsp *histogramToSummaryProcessor) convertMetric(m pdata.Metric) {
+	switch m.DataType() {
+	case pdata.MetricDataTypeHistogram:
+		// The histogram stays valid after the data type of the metric changes.
+		dps := m.Histogram().DataPoints()
+		m.SetDataType(pdata.MetricDataTypeSummary)
+		sdps := m.Summary().DataPoints()
+		sdps.Resize(dps.Len())
+		for i := 0; i < dps.Len(); i++ {
+			dp, sdp := dps.At(i), sdps.At(i)
+			dp.LabelsMap().CopyTo(sdp.LabelsMap())
+			sdp.SetStartTimestamp(dp.StartTimestamp())
+			sdp.SetTimestamp(dp.Timestamp())
+			sdp.SetCount(dp.Count())
+			sdp.SetSum(dp.Sum())
+			htsp.setQuantiles(sdp, dp.ExplicitBounds(), dp.BucketCounts())
+		}
+	case pdata.MetricDataTypeIntHistogram:
+		dps := m.IntHistogram().DataPoints()
+		m.SetDataType(pdata.MetricDataTypeSummary)
+		sdps := m.Summary().DataPoints()
+		sdps.Resize(dps.Len())
+		for i := 0; i < dps.Len(); i++ {
+			dp, sdp := dps.At(i), sdps.At(i)
+			dp.LabelsMap().CopyTo(sdp.LabelsMap())
+			sdp.SetStartTimestamp(dp.StartTimestamp())
+			sdp.SetTimestamp(dp.Timestamp())
+			sdp.SetCount(dp.Count())
+			sdp.SetSum(float64(dp.Sum()))
+			htsp.setQuantiles(sdp, dp.ExplicitBounds(), dp.BucketCounts())
+		}
+	}
+}
+
+// setQuantiles sets the quantiles estimated from the buckets on sdp. No quantile
+// is set if the histogram is empty, has a single bucket or inconsistent buckets.
+func (htsp *histogramToSummaryProcessor) setQuantiles(sdp pdata.SummaryDataPoint, bounds []float64, counts []uint64) {
+	if len(bounds) == 0 || len(counts) != len(bounds)+1 {
+		return
+	}
+	var total uint64
+	for _, c := range counts {
+		total += c
+	}
+	if total == 0 {
+		return
+	}
+	qvs := sdp.QuantileValues()
+	qvs.Resize(len(htsp.quantiles))
+	for i, q := range htsp.quantiles {
+		qvs.At(i).SetQuantile(q)
+		qvs.At(i).SetValue(estimateQuantile(q, bounds, counts, total))
+	}
+}
+
+// estimateQuantile estimates the q quantile of the values counted in the buckets,
+// like the histogram_quantile function of Prometheus: the values are assumed to
+// be uniformly distributed in their bucket and the quantile is linearly
+// interpolated between the bounds of the bucket containing it. The lower bound of
+// the first bucket is assumed to be 0 if its upper bound is positive, and the
+// quantiles falling in the first bucket otherwise or in the last bucket, which
+// has no upper bound, are estimated as its finite bound.
+//
+// The error of the estimation is at most the width of the bucket containing the
+// quantile, and is unbounded for the quantiles falling in the last bucket.
+func estimateQuantile(q float64, bounds []float64, counts []uint64, total uint64) float64 {
+	rank := q * float64(total)
+	var cumulative uint64
+	for i, c := range counts {
+		if float64(cumulative+c) < rank || c == 0 {
+			cumulative += c
+			continue
+		}
+		if i == len(bounds) {
+			return bounds[len(bounds)-1]
+		}
+		lower, upper := 0.0, bounds[i]
+		if i > 0 {
+			lower = bounds[i-1]
+		} else if upper <= 0 {
+			return upper
+		}
+		return lower + (upper-lower)*(rank-float64(cumulative))/float64(c)
+	}
+	// Only reached due to rounding errors for q close to 1.
+	return bounds[len(bounds)-1]
+}
@@ -0,0 +1,212 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package histogramtosummaryprocessor
+
+import (
+	"context"
+	"math/rand"
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go.opentelemetry.io/collector/consumer/pdata"
+	"go.opentelemetry.io/collector/consumer/pdatabuilder"
+)
+
+// bucketize counts the values in the buckets delimited by bounds.
+func bucketize(values []float64, bounds []float64) []uint64 {
+	counts := make([]uint64, len(bounds)+1)
+	for _, v := range values {
+		counts[sort.SearchFloat64s(bounds, v)]++
+	}
+	return counts
+}
+
+func linearBounds(start, width float64, n int) []float64 {
+	bounds := make([]float64, n)
+	for i := range bounds {
+		bounds[i] = start + float64(i)*width
+	}
+	return bounds
+}
+
+func TestEstimateQuantileUniform(t *testing.T) {
+	// 10 values in each of the buckets (0, 10], (10, 20], ..., (90, 100].
+	bounds := linearBounds(10, 10, 10)
+	counts := []uint64{10, 10, 10, 10, 10, 10, 10, 10, 10, 10, 0}
+	for _, q := range []float64{0, 0.05, 0.25, 0.5, 0.9, 0.99, 1} {
+		assert.InDelta(t, q*100, estimateQuantile(q, bounds, counts, 100), 1e-9, "quantile %v", q)
+	}
+}
+
+func TestEstimateQuantileDistributions(t *testing.T) {
+	r := rand.New(rand.NewSource(42))
+	tests := []struct {
+		name     string
+		generate func() float64
+		bounds   []float64
+		// maxError is a fifth of the width of the buckets: the values are close to
+		// uniformly distributed within a bucket, so the linear interpolation is much
+		// more accurate than the bucket width.
+		maxError float64
+	}{
+		{
+			name:     "normal",
+			generate: func() float64 { return 50 + 10*r.NormFloat64() },
+			bounds:   linearBounds(0, 5, 21),
+			maxError: 1,
+		},
+		{
+			name:     "exponential",
+			generate: func() float64 { return 100 * r.ExpFloat64() },
+			bounds:   linearBounds(25, 25, 40),
+			maxError: 5,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			values := make([]float64, 100000)
+			for i := range values {
+				values[i] = tt.generate()
+			}
+			counts := bucketize(values, tt.bounds)
+			sort.Float64s(values)
+			for _, q := range []float64{0.1, 0.5, 0.9, 0.99} {
+				want := values[int(q*float64(len(values)))]
+				got := estimateQuantile(q, tt.bounds, counts, uint64(len(values)))
+				assert.InDelta(t, want, got, tt.maxError, "quantile %v", q)
+			}
+		})
+	}
+}
+
+func TestEstimateQuantileEdgeBuckets(t *testing.T) {
+	bounds := []float64{-10, 0, 10}
+	// The first bucket has no lower bound.
+	assert.Equal(t, -10.0, estimateQuantile(0.5, bounds, []uint64{4, 0, 0, 0}, 4))
+	// The last bucket has no upper bound.
+	assert.Equal(t, 10.0, estimateQuantile(0.5, bounds, []uint64{0, 0, 0, 4}, 4))
+	// The lower bound of the first bucket is 0 when its upper bound is positive.
+	assert.Equal(t, 5.0, estimateQuantile(0.5, []float64{10, 20}, []uint64{4, 0, 0}, 4))
+	// Empty buckets are skipped.
+	assert.Equal(t, -10.0, estimateQuantile(0, bounds, []uint64{0, 4, 0, 0}, 4))
+}
+
+func newHistogramMetrics() pdata.Metrics {
+	md := pdatabuilder.NewMetrics().DoubleGauge("gauge").DoubleDataPoint(1, nil).Build()
+	metrics := md.ResourceMetrics().At(0).InstrumentationLibraryMetrics().At(0).Metrics()
+
+	m := metrics.AppendEmpty()
+	m.SetName("latency")
+	m.SetUnit("ms")
+	m.SetDataType(pdata.MetricDataTypeHistogram)
+	m.Histogram().SetAggregationTemporality(pdata.AggregationTemporalityCumulative)
+	dp := m.Histogram().DataPoints().AppendEmpty()
+	dp.LabelsMap().Insert("route", "/users")
+	dp.SetStartTimestamp(1000)
+	dp.SetTimestamp(2000)
+	dp.SetCount(20)
+	dp.SetSum(1000)
+	dp.SetExplicitBounds([]float64{50, 100})
+	dp.SetBucketCounts([]uint64{10, 10, 0})
+	// Empty histograms have no quantile.
+	m.Histogram().DataPoints().AppendEmpty().SetExplicitBounds([]float64{50, 100})
+
+	m = metrics.AppendEmpty()
+	m.SetName("size")
+	m.SetDataType(pdata.MetricDataTypeIntHistogram)
+	idp := m.IntHistogram().DataPoints().AppendEmpty()
+	idp.SetCount(4)
+	idp.SetSum(40)
+	idp.SetExplicitBounds([]float64{10, 20})
+	idp.SetBucketCounts([]uint64{0, 4, 0})
+	return md
+}
+
+func TestProcessMetrics(t *testing.T) {
+	p := newHistogramToSummaryProcessor(&Config{Quantiles: []float64{0.5, 0.75}})
+	md, err := p.ProcessMetrics(context.Background(), newHistogramMetrics())
+	require.NoError(t, err)
+	metrics := md.ResourceMetrics().At(0).InstrumentationLibraryMetrics().At(0).Metrics()
+	require.Equal(t, 3, metrics.Len())
+
+	assert.Equal(t, pdata.MetricDataTypeDoubleGauge, metrics.At(0).DataType())
+
+	m := metrics.At(1)
+	assert.Equal(t, "latency", m.Name())
+	assert.Equal(t, "ms", m.Unit())
+	require.Equal(t, pdata.MetricDataTypeSummary, m.DataType())
+	dps := m.Summary().DataPoints()
+	require.Equal(t, 2, dps.Len())
+	dp := dps.At(0)
+	assert.Equal(t, map[string]string{"route": "/users"}, labels(dp.LabelsMap()))
+	assert.Equal(t, pdata.Timestamp(1000), dp.StartTimestamp())
+	assert.Equal(t, pdata.Timestamp(2000), dp.Timestamp())
+	assert.EqualValues(t, 20, dp.Count())
+	assert.EqualValues(t, 1000, dp.Sum())
+	assert.Equal(t, map[float64]float64{0.5: 50, 0.75: 75}, quantiles(dp))
+	assert.Equal(t, 0, dps.At(1).QuantileValues().Len())
+
+	m = metrics.At(2)
+	require.Equal(t, pdata.MetricDataTypeSummary, m.DataType())
+	dp = m.Summary().DataPoints().At(0)
+	assert.EqualValues(t, 4, dp.Count())
+	assert.EqualValues(t, 40, dp.Sum())
+	assert.Equal(t, map[float64]float64{0.5: 15, 0.75: 17.5}, quantiles(dp))
+}
+
+func TestProcessMetricsFilter(t *testing.T) {
+	p := newHistogramToSummaryProcessor(&Config{Metrics: []string{"size"}})
+	md, err := p.ProcessMetrics(context.Background(), newHistogramMetrics())
+	require.NoError(t, err)
+	metrics := md.ResourceMetrics().At(0).InstrumentationLibraryMetrics().At(0).Metrics()
+	assert.Equal(t, pdata.MetricDataTypeHistogram, metrics.At(1).DataType())
+	require.Equal(t, pdata.MetricDataTypeSummary, metrics.At(2).DataType())
+	// The default quantiles are estimated.
+	assert.Equal(t, 3, metrics.At(2).Summary().DataPoints().At(0).QuantileValues().Len())
+}
+
+func TestProcessMetricsInconsistentBuckets(t *testing.T) {
+	md := newHistogramMetrics()
+	dp := md.ResourceMetrics().At(0).InstrumentationLibraryMetrics().At(0).Metrics().At(1).Histogram().DataPoints().At(0)
+	dp.SetBucketCounts([]uint64{10, 10})
+
+	p := newHistogramToSummaryProcessor(createDefaultConfig().(*Config))
+	md, err := p.ProcessMetrics(context.Background(), md)
+	require.NoError(t, err)
+	sdp := md.ResourceMetrics().At(0).InstrumentationLibraryMetrics().At(0).Metrics().At(1).Summary().DataPoints().At(0)
+	assert.EqualValues(t, 20, sdp.Count())
+	assert.Equal(t, 0, sdp.QuantileValues().Len())
+}
+
+func labels(sm pdata.StringMap) map[string]string {
+	m := map[string]string{}
+	sm.Range(func(k, v string) bool {
+		m[k] = v
+		return true
+	})
+	return m
+}
+
+func quantiles(dp pdata.SummaryDataPoint) map[float64]float64 {
+	m := map[float64]float64{}
+	qvs := dp.QuantileValues()
+	for i := 0; i < qvs.Len(); i++ {
+		m[qvs.At(i).Quantile()] = qvs.At(i).Value()
+	}
+	return m
+}
@@ -0,0 +1,85 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package metricsrelabelprocessor
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+
+	"go.opentelemetry.io/collector/config"
+	"go.opentelemetry.io/collector/internal/processor/filterset"
+)
+
+// Config defines configuration for the metrics relabel processor.
+type Config struct {
+	config.ProcessorSettings `mapstructure:",squash"` // squash ensures fields are correctly decoded in embedded struct
+
+	// Metrics are the rules renaming the metrics, the first matching rule applies.
+	Metrics []RenameRule `mapstructure:"metrics"`
+
+	// Labels are the rules renaming the label keys of the data points, the first
+	// matching rule applies.
+	Labels []RenameRule `mapstructure:"labels"`
+}
+
+// RenameRule renames the names matching Match to Replacement.
+type RenameRule struct {
+	// Match is the name to rename, or a regular expression which must match the
+	// whole name if MatchType is regexp.
+	Match string `mapstructure:"match"`
+
+	// MatchType is the type of Match, strict (default) or regexp.
+	MatchType filterset.MatchType `mapstructure:"match_type"`
+
+	// Replacement is the new name. With the regexp MatchType it can reference the
+	// capture groups of Match, e.g. $1 or ${name}.
+	Replacement string `mapstructure:"replacement"`
+}
+
+var _ config.Processor = (*Config)(nil)
+
+// Validate checks if the processor configuration is valid
+func (cfg *Config) Validate() error {
+	for _, rule := range cfg.Metrics {
+		if err := rule.validate(); err != nil {
+			return fmt.Errorf("invalid metrics rule: %w", err)
+		}
+	}
+	for _, rule := range cfg.Labels {
+		if err := rule.validate(); err != nil {
+			return fmt.Errorf("invalid labels rule: %w", err)
+		}
+	}
+	return nil
+}
+
+func (rule RenameRule) validate() error {
+	if rule.Match == "" {
+		return errors.New("match must not be empty")
+	}
+	if rule.Replacement == "" {
+		return fmt.Errorf("replacement of %q must not be empty", rule.Match)
+	}
+	switch rule.MatchType {
+	case "", filterset.Strict:
+	case filterset.Regexp:
+		if _, err := regexp.Compile(rule.Match); err != nil {
+			return fmt.Errorf("invalid regexp %q: %w", rule.Match, err)
+		}
+	default:
+		return fmt.Errorf("match_type must be %q or %q, got %q", filterset.Strict, filterset.Regexp, rule.MatchType)
+	}
+	return nil
+}
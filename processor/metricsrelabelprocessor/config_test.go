@@ -0,0 +1,72 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package metricsrelabelprocessor
+
+import (
+	"path"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/config"
+	"go.opentelemetry.io/collector/config/configtest"
+	"go.opentelemetry.io/collector/internal/processor/filterset"
+)
+
+func TestLoadConfig(t *testing.T) {
+	factories, err := componenttest.NopFactories()
+	assert.NoError(t, err)
+
+	factory := NewFactory()
+	factories.Processors[typeStr] = factory
+
+	cfg, err := configtest.LoadConfigFile(t, path.Join(".", "testdata", "config.yaml"), factories)
+	require.NoError(t, err)
+	require.NotNil(t, cfg)
+
+	assert.Equal(t, factory.CreateDefaultConfig(), cfg.Processors[config.NewID(typeStr)])
+	assert.Equal(t, &Config{
+		ProcessorSettings: config.NewProcessorSettings(config.NewIDWithName(typeStr, "legacy")),
+		Metrics: []RenameRule{
+			{Match: "http.server.duration", Replacement: "http_request_duration"},
+			{Match: `system\.(.*)\.usage`, MatchType: filterset.Regexp, Replacement: "host_${1}_usage"},
+		},
+		Labels: []RenameRule{
+			{Match: "http.method", Replacement: "method"},
+		},
+	}, cfg.Processors[config.NewIDWithName(typeStr, "legacy")])
+}
+
+func TestConfigValidate(t *testing.T) {
+	cfg := createDefaultConfig().(*Config)
+	assert.NoError(t, cfg.Validate())
+
+	cfg.Metrics = []RenameRule{{Match: "a", Replacement: "b", MatchType: "strict"}}
+	assert.NoError(t, cfg.Validate())
+
+	cfg.Metrics = []RenameRule{{Replacement: "b"}}
+	assert.EqualError(t, cfg.Validate(), "invalid metrics rule: match must not be empty")
+
+	cfg.Metrics = []RenameRule{{Match: "a"}}
+	assert.EqualError(t, cfg.Validate(), `invalid metrics rule: replacement of "a" must not be empty`)
+
+	cfg.Metrics = []RenameRule{{Match: "a", Replacement: "b", MatchType: "prefix"}}
+	assert.EqualError(t, cfg.Validate(), `invalid metrics rule: match_type must be "strict" or "regexp", got "prefix"`)
+
+	cfg.Metrics = nil
+	cfg.Labels = []RenameRule{{Match: "(", Replacement: "b", MatchType: filterset.Regexp}}
+	assert.Error(t, cfg.Validate())
+}
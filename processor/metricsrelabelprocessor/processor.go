@@ -0,0 +1,175 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package metricsrelabelprocessor
+
+import (
+	"context"
+	"regexp"
+
+	"go.uber.org/zap"
+
+	"go.opentelemetry.io/collector/consumer/pdata"
+	"go.opentelemetry.io/collector/internal/processor/filterset"
+)
+
+// renamer renames the names matching the first matching rule.
+type renamer struct {
+	rules []compiledRule
+}
+
+type compiledRule struct {
+	// match is the name to rename, only used if regexp is nil.
+	match       string
+	regexp      *regexp.Regexp
+	replacement string
+}
+
+func newRenamer(rules []RenameRule) (*renamer, error) {
+	r := &renamer{rules: make([]compiledRule, 0, len(rules))}
+	for _, rule := range rules {
+		cr := compiledRule{match: rule.Match, replacement: rule.Replacement}
+		if rule.MatchType == filterset.Regexp {
+			// The expression must match the whole name.
+			re, err := regexp.Compile("^(?:" + rule.Match + ")$")
+			if err != nil {
+				return nil, err
+			}
+			cr.regexp = re
+		}
+		r.rules = append(r.rules, cr)
+	}
+	return r, nil
+}
+
+// rename returns the new name of name, and whether a rule matched.
+func (r *renamer) rename(name string) (string, bool) {
+	for _, rule := range r.rules {
+		if rule.regexp == nil {
+			if name == rule.match {
+				return rule.replacement, true
+			}
+			continue
+		}
+		if match := rule.regexp.FindStringSubmatchIndex(name); match != nil {
+			return string(rule.regexp.ExpandString(nil, rule.replacement, name, match)), true
+		}
+	}
+	return name, false
+}
+
+type metricsRelabelProcessor struct {
+	logger  *zap.Logger
+	metrics *renamer
+	labels  *renamer
+}
+
+func newMetricsRelabelProcessor(cfg *Config, logger *zap.Logger) (*metricsRelabelProcessor, error) {
+	metrics, err := newRenamer(cfg.Metrics)
+	if err != nil {
+		return nil, err
+	}
+	labels, err := newRenamer(cfg.Labels)
+	if err != nil {
+		return nil, err
+	}
+	return &metricsRelabelProcessor{
+		logger:  logger,
+		metrics: metrics,
+		labels:  labels,
+	}, nil
+}
+
+// ProcessMetrics renames the metrics and the labels of their data points.
+func (mrp *metricsRelabelProcessor) ProcessMetrics(_ context.Context, md pdata.Metrics) (pdata.Metrics, error) {
+	rms := md.ResourceMetrics()
+	for i := 0; i < rms.Len(); i++ {
+		ilms := rms.At(i).InstrumentationLibraryMetrics()
+		for j := 0; j < ilms.Len(); j++ {
+			metrics := ilms.At(j).Metrics()
+			mrp.renameMetrics(metrics)
+			for k := 0; k < metrics.Len(); k++ {
+				mrp.renameLabels(metrics.At(k))
+			}
+		}
+	}
+	return md, nil
+}
+
+// renameMetrics renames the metrics of an instrumentation library. A metric is not
+// renamed if its new name conflicts with the name of another metric of the library,
+// since they would be merged by the backend, and the conflict is reported.
+func (mrp *metricsRelabelProcessor) renameMetrics(metrics pdata.MetricSlice) {
+	if len(mrp.metrics.rules) == 0 {
+		return
+	}
+	newNames := make([]string, metrics.Len())
+	renamed := make([]bool, metrics.Len())
+	counts := make(map[string]int, metrics.Len())
+	for i := 0; i < metrics.Len(); i++ {
+		newNames[i], renamed[i] = mrp.metrics.rename(metrics.At(i).Name())
+		counts[newNames[i]]++
+	}
+	for i := 0; i < metrics.Len(); i++ {
+		if !renamed[i] {
+			continue
+		}
+		m := metrics.At(i)
+		if counts[newNames[i]] > 1 {
+			mrp.logger.Warn("Metric not renamed, its new name conflicts with another metric",
+				zap.String("metric", m.Name()),
+				zap.String("new_name", newNames[i]))
+			continue
+		}
+		m.SetName(newNames[i])
+	}
+}
+
+// renameLabels renames the labels of the data points of m. A label is not renamed
+// if the data point already has a label with the new key, and the conflict is
+// reported once per metric.
+func (mrp *metricsRelabelProcessor) renameLabels(m pdata.Metric) {
+	if len(mrp.labels.rules) == 0 {
+		return
+	}
+	var conflicts map[string]string
+	for _, labels := range m.DataPointLabels() {
+		keys := make([]string, 0, labels.Len())
+		labels.Range(func(k string, _ string) bool {
+			keys = append(keys, k)
+			return true
+		})
+		for _, key := range keys {
+			newKey, ok := mrp.labels.rename(key)
+			if !ok || newKey == key {
+				continue
+			}
+			if _, exists := labels.Get(newKey); exists {
+				if conflicts == nil {
+					conflicts = map[string]string{}
+				}
+				conflicts[key] = newKey
+				continue
+			}
+			value, _ := labels.Get(key)
+			labels.Insert(newKey, value)
+			labels.Delete(key)
+		}
+	}
+	for key, newKey := range conflicts {
+		mrp.logger.Warn("Label not renamed, its new key conflicts with another label",
+			zap.String("metric", m.Name()),
+			zap.String("label", key),
+			zap.String("new_key", newKey))
+	}
+}
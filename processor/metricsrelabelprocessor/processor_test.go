@@ -0,0 +1,152 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package metricsrelabelprocessor
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+
+	"go.opentelemetry.io/collector/consumer/pdata"
+	"go.opentelemetry.io/collector/consumer/pdatabuilder"
+	"go.opentelemetry.io/collector/internal/processor/filterset"
+)
+
+func newTestProcessor(t *testing.T, cfg *Config) (*metricsRelabelProcessor, *observer.ObservedLogs) {
+	core, logs := observer.New(zapcore.WarnLevel)
+	mrp, err := newMetricsRelabelProcessor(cfg, zap.New(core))
+	require.NoError(t, err)
+	return mrp, logs
+}
+
+func metricNames(md pdata.Metrics) []string {
+	var names []string
+	metrics := md.ResourceMetrics().At(0).InstrumentationLibraryMetrics().At(0).Metrics()
+	for i := 0; i < metrics.Len(); i++ {
+		names = append(names, metrics.At(i).Name())
+	}
+	return names
+}
+
+func TestRenameMetrics(t *testing.T) {
+	mrp, logs := newTestProcessor(t, &Config{
+		Metrics: []RenameRule{
+			{Match: "http.server.duration", Replacement: "http_request_duration"},
+			{Match: `system\.(.*)\.usage`, MatchType: filterset.Regexp, Replacement: "host_${1}_usage"},
+			// Not applied since the previous rule matches first.
+			{Match: "system.cpu.usage", Replacement: "cpu"},
+			// The expression must match the whole name.
+			{Match: "disk", MatchType: filterset.Regexp, Replacement: "storage"},
+		},
+	})
+
+	md := pdatabuilder.NewMetrics().
+		DoubleGauge("http.server.duration").
+		DoubleGauge("system.cpu.usage").
+		DoubleGauge("system.memory.usage").
+		DoubleGauge("system.disk.io").
+		DoubleGauge("system.usage").
+		Build()
+	md, err := mrp.ProcessMetrics(context.Background(), md)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"http_request_duration", "host_cpu_usage", "host_memory_usage", "system.disk.io", "system.usage"}, metricNames(md))
+	assert.Equal(t, 0, logs.Len())
+}
+
+func TestRenameMetricsConflict(t *testing.T) {
+	mrp, logs := newTestProcessor(t, &Config{
+		Metrics: []RenameRule{
+			{Match: `(.*)\.(count|total)`, MatchType: filterset.Regexp, Replacement: "$1"},
+			{Match: "old", Replacement: "new"},
+		},
+	})
+
+	md := pdatabuilder.NewMetrics().
+		DoubleSum("requests.count", true).
+		DoubleSum("requests.total", true).
+		DoubleGauge("old").
+		DoubleGauge("new").
+		DoubleGauge("errors.count").
+		Build()
+	md, err := mrp.ProcessMetrics(context.Background(), md)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"requests.count", "requests.total", "old", "new", "errors"}, metricNames(md))
+
+	entries := logs.FilterMessage("Metric not renamed, its new name conflicts with another metric").All()
+	require.Len(t, entries, 3)
+	assert.Equal(t, "requests.count", entries[0].ContextMap()["metric"])
+	assert.Equal(t, "requests", entries[0].ContextMap()["new_name"])
+	assert.Equal(t, "old", entries[2].ContextMap()["metric"])
+	assert.Equal(t, "new", entries[2].ContextMap()["new_name"])
+}
+
+func TestRenameMetricsPerLibrary(t *testing.T) {
+	mrp, logs := newTestProcessor(t, &Config{
+		Metrics: []RenameRule{{Match: "old", Replacement: "new"}},
+	})
+
+	// The same name in different libraries is not a conflict.
+	md := pdatabuilder.NewMetrics().
+		Library("a", "").DoubleGauge("old").
+		Library("b", "").DoubleGauge("new").
+		Build()
+	md, err := mrp.ProcessMetrics(context.Background(), md)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"new"}, metricNames(md))
+	assert.Equal(t, 0, logs.Len())
+}
+
+func TestRenameLabels(t *testing.T) {
+	mrp, logs := newTestProcessor(t, &Config{
+		Labels: []RenameRule{
+			{Match: "http.method", Replacement: "method"},
+			{Match: `k8s\.(.*)\.name`, MatchType: filterset.Regexp, Replacement: "kubernetes_$1"},
+		},
+	})
+
+	md := pdatabuilder.NewMetrics().
+		IntSum("requests", true).
+		IntDataPoint(1, map[string]string{"http.method": "GET", "k8s.pod.name": "pod", "code": "200"}).
+		IntDataPoint(2, map[string]string{"http.method": "POST", "method": "post"}).
+		DoubleGauge("cpu").
+		DoubleDataPoint(0.5, map[string]string{"k8s.node.name": "node"}).
+		Build()
+	md, err := mrp.ProcessMetrics(context.Background(), md)
+	require.NoError(t, err)
+
+	metrics := md.ResourceMetrics().At(0).InstrumentationLibraryMetrics().At(0).Metrics()
+	dps := metrics.At(0).IntSum().DataPoints()
+	assert.Equal(t, map[string]string{"method": "GET", "kubernetes_pod": "pod", "code": "200"}, labelsToMap(dps.At(0).LabelsMap()))
+	// The label is not renamed since the data point already has the new key.
+	assert.Equal(t, map[string]string{"http.method": "POST", "method": "post"}, labelsToMap(dps.At(1).LabelsMap()))
+	assert.Equal(t, map[string]string{"kubernetes_node": "node"}, labelsToMap(metrics.At(1).DoubleGauge().DataPoints().At(0).LabelsMap()))
+
+	entries := logs.FilterMessage("Label not renamed, its new key conflicts with another label").All()
+	require.Len(t, entries, 1)
+	assert.Equal(t, map[string]interface{}{"metric": "requests", "label": "http.method", "new_key": "method"}, entries[0].ContextMap())
+}
+
+func labelsToMap(sm pdata.StringMap) map[string]string {
+	m := map[string]string{}
+	sm.Range(func(k string, v string) bool {
+		m[k] = v
+		return true
+	})
+	return m
+}
@@ -0,0 +1,73 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package routingprocessor
+
+import (
+	"errors"
+	"fmt"
+
+	"go.opentelemetry.io/collector/config"
+)
+
+// Config defines configuration for the routing processor.
+type Config struct {
+	config.ProcessorSettings `mapstructure:",squash"` // squash ensures fields are correctly decoded in embedded struct
+
+	// FromMetadata is the key of the gRPC metadata of the incoming requests holding the
+	// value used to select the route, e.g. X-Tenant. The key is case insensitive.
+	FromMetadata string `mapstructure:"from_metadata"`
+
+	// DefaultExporters is the list of the exporters receiving the data not matching any
+	// route. Empty means the unmatched data is dropped.
+	DefaultExporters []string `mapstructure:"default_exporters"`
+
+	// Table is the list of the routes.
+	Table []RoutingTableItem `mapstructure:"table"`
+}
+
+// RoutingTableItem defines the exporters receiving the data for a metadata value.
+type RoutingTableItem struct {
+	// Value is the value of the metadata matching the route.
+	Value string `mapstructure:"value"`
+
+	// Exporters is the list of the exporters receiving the data matching the route.
+	Exporters []string `mapstructure:"exporters"`
+}
+
+var _ config.Processor = (*Config)(nil)
+
+// Validate checks if the processor configuration is valid
+func (cfg *Config) Validate() error {
+	if cfg.FromMetadata == "" {
+		return errors.New("from_metadata must be set")
+	}
+	if len(cfg.Table) == 0 {
+		return errors.New("table must contain at least one route")
+	}
+	values := make(map[string]bool, len(cfg.Table))
+	for _, item := range cfg.Table {
+		if item.Value == "" {
+			return errors.New("table routes must have a value")
+		}
+		if values[item.Value] {
+			return fmt.Errorf("duplicate route for value %q", item.Value)
+		}
+		values[item.Value] = true
+		if len(item.Exporters) == 0 {
+			return fmt.Errorf("route for value %q must have at least one exporter", item.Value)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,112 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package routingprocessor
+
+import (
+	"path"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/config"
+	"go.opentelemetry.io/collector/config/configtest"
+)
+
+func TestLoadConfig(t *testing.T) {
+	factories, err := componenttest.NopFactories()
+	assert.NoError(t, err)
+
+	factory := NewFactory()
+	factories.Processors[typeStr] = factory
+
+	cfg, err := configtest.LoadConfigFile(t, path.Join(".", "testdata", "config.yaml"), factories)
+	require.NoError(t, err)
+	require.NotNil(t, cfg)
+
+	assert.Equal(t, &Config{
+		ProcessorSettings: config.NewProcessorSettings(config.NewID(typeStr)),
+		FromMetadata:      "X-Tenant",
+		DefaultExporters:  []string{"nop"},
+		Table: []RoutingTableItem{
+			{Value: "acme", Exporters: []string{"nop/acme"}},
+			{Value: "globex", Exporters: []string{"nop/globex", "nop"}},
+		},
+	}, cfg.Processors[config.NewID(typeStr)])
+}
+
+func TestConfigValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		cfg     *Config
+		wantErr string
+	}{
+		{
+			name: "valid",
+			cfg: &Config{
+				FromMetadata: "X-Tenant",
+				Table:        []RoutingTableItem{{Value: "acme", Exporters: []string{"otlp"}}},
+			},
+		},
+		{
+			name:    "missing from_metadata",
+			cfg:     &Config{Table: []RoutingTableItem{{Value: "acme", Exporters: []string{"otlp"}}}},
+			wantErr: "from_metadata must be set",
+		},
+		{
+			name:    "empty table",
+			cfg:     &Config{FromMetadata: "X-Tenant"},
+			wantErr: "table must contain at least one route",
+		},
+		{
+			name: "missing value",
+			cfg: &Config{
+				FromMetadata: "X-Tenant",
+				Table:        []RoutingTableItem{{Exporters: []string{"otlp"}}},
+			},
+			wantErr: "table routes must have a value",
+		},
+		{
+			name: "duplicate value",
+			cfg: &Config{
+				FromMetadata: "X-Tenant",
+				Table: []RoutingTableItem{
+					{Value: "acme", Exporters: []string{"otlp"}},
+					{Value: "acme", Exporters: []string{"jaeger"}},
+				},
+			},
+			wantErr: `duplicate route for value "acme"`,
+		},
+		{
+			name: "missing exporters",
+			cfg: &Config{
+				FromMetadata: "X-Tenant",
+				Table:        []RoutingTableItem{{Value: "acme"}},
+			},
+			wantErr: `route for value "acme" must have at least one exporter`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.cfg.Validate()
+			if tt.wantErr == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.EqualError(t, err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,146 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package routingprocessor
+
+import (
+	"context"
+	"fmt"
+
+	"go.uber.org/zap"
+	"google.golang.org/grpc/metadata"
+
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/config"
+	"go.opentelemetry.io/collector/consumer"
+	"go.opentelemetry.io/collector/consumer/consumererror"
+	"go.opentelemetry.io/collector/consumer/pdata"
+)
+
+// routingProcessor sends the data to the exporters of the route matching the value
+// of the configured metadata key, or to the default exporters if no route matches.
+type routingProcessor struct {
+	logger   *zap.Logger
+	config   *Config
+	dataType config.DataType
+
+	// The exporters are resolved when the processor is started.
+	defaultExporters []component.Exporter
+	routes           map[string][]component.Exporter
+}
+
+var (
+	_ component.TracesProcessor  = (*routingProcessor)(nil)
+	_ component.MetricsProcessor = (*routingProcessor)(nil)
+	_ component.LogsProcessor    = (*routingProcessor)(nil)
+)
+
+func newRoutingProcessor(logger *zap.Logger, cfg *Config, dataType config.DataType) *routingProcessor {
+	return &routingProcessor{
+		logger:   logger,
+		config:   cfg,
+		dataType: dataType,
+	}
+}
+
+// Start resolves the exporters of the routes, which must be part of a pipeline
+// of the same data type to be created.
+func (rp *routingProcessor) Start(_ context.Context, host component.Host) error {
+	available := host.GetExporters()[rp.dataType]
+
+	var err error
+	if rp.defaultExporters, err = rp.resolveExporters(available, rp.config.DefaultExporters); err != nil {
+		return err
+	}
+	rp.routes = make(map[string][]component.Exporter, len(rp.config.Table))
+	for _, item := range rp.config.Table {
+		if rp.routes[item.Value], err = rp.resolveExporters(available, item.Exporters); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (rp *routingProcessor) resolveExporters(available map[config.ComponentID]component.Exporter, names []string) ([]component.Exporter, error) {
+	exporters := make([]component.Exporter, 0, len(names))
+	for _, name := range names {
+		id, err := config.NewIDFromString(name)
+		if err != nil {
+			return nil, err
+		}
+		exp, ok := available[id]
+		if !ok {
+			return nil, fmt.Errorf("exporter %q not found in the %s pipelines", name, rp.dataType)
+		}
+		exporters = append(exporters, exp)
+	}
+	return exporters, nil
+}
+
+// Shutdown is invoked during service shutdown.
+func (rp *routingProcessor) Shutdown(context.Context) error {
+	return nil
+}
+
+func (rp *routingProcessor) Capabilities() consumer.Capabilities {
+	return consumer.Capabilities{MutatesData: false}
+}
+
+// exportersFor returns the exporters of the route matching the metadata of the context.
+func (rp *routingProcessor) exportersFor(ctx context.Context) []component.Exporter {
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		if values := md.Get(rp.config.FromMetadata); len(values) > 0 {
+			if exporters, ok := rp.routes[values[0]]; ok {
+				return exporters
+			}
+		}
+	}
+	if len(rp.defaultExporters) == 0 {
+		rp.logger.Debug("Dropping data not matching any route", zap.String("data_type", string(rp.dataType)))
+	}
+	return rp.defaultExporters
+}
+
+// ConsumeTraces sends the pdata.Traces to the exporters of the matching route.
+func (rp *routingProcessor) ConsumeTraces(ctx context.Context, td pdata.Traces) error {
+	var errs []error
+	for _, exp := range rp.exportersFor(ctx) {
+		if err := exp.(consumer.Traces).ConsumeTraces(ctx, td); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return consumererror.Combine(errs)
+}
+
+// ConsumeMetrics sends the pdata.Metrics to the exporters of the matching route.
+func (rp *routingProcessor) ConsumeMetrics(ctx context.Context, md pdata.Metrics) error {
+	var errs []error
+	for _, exp := range rp.exportersFor(ctx) {
+		if err := exp.(consumer.Metrics).ConsumeMetrics(ctx, md); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return consumererror.Combine(errs)
+}
+
+// ConsumeLogs sends the pdata.Logs to the exporters of the matching route.
+func (rp *routingProcessor) ConsumeLogs(ctx context.Context, ld pdata.Logs) error {
+	var errs []error
+	for _, exp := range rp.exportersFor(ctx) {
+		if err := exp.(consumer.Logs).ConsumeLogs(ctx, ld); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return consumererror.Combine(errs)
+}
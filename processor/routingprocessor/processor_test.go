@@ -0,0 +1,229 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package routingprocessor
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"google.golang.org/grpc/metadata"
+
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/component/componenthelper"
+	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/config"
+	"go.opentelemetry.io/collector/consumer/consumertest"
+	"go.opentelemetry.io/collector/internal/testdata"
+)
+
+type mockHost struct {
+	component.Host
+	exporters map[config.DataType]map[config.ComponentID]component.Exporter
+}
+
+func (m *mockHost) GetExporters() map[config.DataType]map[config.ComponentID]component.Exporter {
+	return m.exporters
+}
+
+type mockTracesExporter struct {
+	component.Component
+	consumertest.TracesSink
+}
+
+type mockMetricsExporter struct {
+	component.Component
+	consumertest.MetricsSink
+}
+
+type mockLogsExporter struct {
+	component.Component
+	consumertest.LogsSink
+}
+
+func newTestConfig() *Config {
+	cfg := createDefaultConfig().(*Config)
+	cfg.FromMetadata = "X-Tenant"
+	cfg.DefaultExporters = []string{"otlp"}
+	cfg.Table = []RoutingTableItem{
+		{Value: "acme", Exporters: []string{"otlp/acme"}},
+		{Value: "globex", Exporters: []string{"otlp/globex", "otlp"}},
+	}
+	return cfg
+}
+
+func tenantContext(tenant string) context.Context {
+	return metadata.NewIncomingContext(context.Background(), metadata.Pairs("x-tenant", tenant))
+}
+
+func TestTracesRouting(t *testing.T) {
+	defaultExp := &mockTracesExporter{Component: componenthelper.New()}
+	acmeExp := &mockTracesExporter{Component: componenthelper.New()}
+	globexExp := &mockTracesExporter{Component: componenthelper.New()}
+	host := &mockHost{
+		Host: componenttest.NewNopHost(),
+		exporters: map[config.DataType]map[config.ComponentID]component.Exporter{
+			config.TracesDataType: {
+				config.NewID("otlp"):                   defaultExp,
+				config.NewIDWithName("otlp", "acme"):   acmeExp,
+				config.NewIDWithName("otlp", "globex"): globexExp,
+			},
+		},
+	}
+
+	rp := newRoutingProcessor(zap.NewNop(), newTestConfig(), config.TracesDataType)
+	require.NoError(t, rp.Start(context.Background(), host))
+
+	td := testdata.GenerateTracesOneSpan()
+	assert.NoError(t, rp.ConsumeTraces(tenantContext("acme"), td))
+	assert.Equal(t, 1, acmeExp.SpansCount())
+	assert.Equal(t, 0, globexExp.SpansCount())
+	assert.Equal(t, 0, defaultExp.SpansCount())
+
+	assert.NoError(t, rp.ConsumeTraces(tenantContext("globex"), td))
+	assert.Equal(t, 1, acmeExp.SpansCount())
+	assert.Equal(t, 1, globexExp.SpansCount())
+	assert.Equal(t, 1, defaultExp.SpansCount())
+
+	// Unknown tenants and requests without metadata go to the default exporters.
+	assert.NoError(t, rp.ConsumeTraces(tenantContext("initech"), td))
+	assert.NoError(t, rp.ConsumeTraces(context.Background(), td))
+	assert.Equal(t, 1, acmeExp.SpansCount())
+	assert.Equal(t, 1, globexExp.SpansCount())
+	assert.Equal(t, 3, defaultExp.SpansCount())
+
+	assert.NoError(t, rp.Shutdown(context.Background()))
+}
+
+func TestMetricsRouting(t *testing.T) {
+	defaultExp := &mockMetricsExporter{Component: componenthelper.New()}
+	acmeExp := &mockMetricsExporter{Component: componenthelper.New()}
+	globexExp := &mockMetricsExporter{Component: componenthelper.New()}
+	host := &mockHost{
+		Host: componenttest.NewNopHost(),
+		exporters: map[config.DataType]map[config.ComponentID]component.Exporter{
+			config.MetricsDataType: {
+				config.NewID("otlp"):                   defaultExp,
+				config.NewIDWithName("otlp", "acme"):   acmeExp,
+				config.NewIDWithName("otlp", "globex"): globexExp,
+			},
+		},
+	}
+
+	rp := newRoutingProcessor(zap.NewNop(), newTestConfig(), config.MetricsDataType)
+	require.NoError(t, rp.Start(context.Background(), host))
+
+	md := testdata.GenerateMetricsOneMetric()
+	assert.NoError(t, rp.ConsumeMetrics(tenantContext("acme"), md))
+	assert.NoError(t, rp.ConsumeMetrics(tenantContext("globex"), md))
+	assert.NoError(t, rp.ConsumeMetrics(tenantContext("initech"), md))
+	assert.Len(t, acmeExp.AllMetrics(), 1)
+	assert.Len(t, globexExp.AllMetrics(), 1)
+	assert.Len(t, defaultExp.AllMetrics(), 2)
+}
+
+func TestLogsRouting(t *testing.T) {
+	defaultExp := &mockLogsExporter{Component: componenthelper.New()}
+	acmeExp := &mockLogsExporter{Component: componenthelper.New()}
+	globexExp := &mockLogsExporter{Component: componenthelper.New()}
+	host := &mockHost{
+		Host: componenttest.NewNopHost(),
+		exporters: map[config.DataType]map[config.ComponentID]component.Exporter{
+			config.LogsDataType: {
+				config.NewID("otlp"):                   defaultExp,
+				config.NewIDWithName("otlp", "acme"):   acmeExp,
+				config.NewIDWithName("otlp", "globex"): globexExp,
+			},
+		},
+	}
+
+	rp := newRoutingProcessor(zap.NewNop(), newTestConfig(), config.LogsDataType)
+	require.NoError(t, rp.Start(context.Background(), host))
+
+	ld := testdata.GenerateLogsOneLogRecord()
+	assert.NoError(t, rp.ConsumeLogs(tenantContext("acme"), ld))
+	assert.NoError(t, rp.ConsumeLogs(tenantContext("globex"), ld))
+	assert.NoError(t, rp.ConsumeLogs(tenantContext("initech"), ld))
+	assert.Equal(t, 1, acmeExp.LogRecordsCount())
+	assert.Equal(t, 1, globexExp.LogRecordsCount())
+	assert.Equal(t, 2, defaultExp.LogRecordsCount())
+}
+
+func TestRoutingDropsUnmatched(t *testing.T) {
+	acmeExp := &mockTracesExporter{Component: componenthelper.New()}
+	host := &mockHost{
+		Host: componenttest.NewNopHost(),
+		exporters: map[config.DataType]map[config.ComponentID]component.Exporter{
+			config.TracesDataType: {
+				config.NewIDWithName("otlp", "acme"): acmeExp,
+			},
+		},
+	}
+	cfg := newTestConfig()
+	cfg.DefaultExporters = nil
+	cfg.Table = cfg.Table[:1]
+
+	rp := newRoutingProcessor(zap.NewNop(), cfg, config.TracesDataType)
+	require.NoError(t, rp.Start(context.Background(), host))
+
+	td := testdata.GenerateTracesOneSpan()
+	assert.NoError(t, rp.ConsumeTraces(tenantContext("initech"), td))
+	assert.NoError(t, rp.ConsumeTraces(context.Background(), td))
+	assert.Equal(t, 0, acmeExp.SpansCount())
+
+	assert.NoError(t, rp.ConsumeTraces(tenantContext("acme"), td))
+	assert.Equal(t, 1, acmeExp.SpansCount())
+}
+
+func TestRoutingStartMissingExporter(t *testing.T) {
+	host := &mockHost{
+		Host: componenttest.NewNopHost(),
+		exporters: map[config.DataType]map[config.ComponentID]component.Exporter{
+			config.TracesDataType: {
+				config.NewIDWithName("otlp", "acme"): &mockTracesExporter{Component: componenthelper.New()},
+			},
+		},
+	}
+
+	rp := newRoutingProcessor(zap.NewNop(), newTestConfig(), config.TracesDataType)
+	assert.EqualError(t, rp.Start(context.Background(), host), `exporter "otlp" not found in the traces pipelines`)
+}
+
+func TestRoutingExporterError(t *testing.T) {
+	defaultExp := &mockTracesExporter{Component: componenthelper.New()}
+	host := &mockHost{
+		Host: componenttest.NewNopHost(),
+		exporters: map[config.DataType]map[config.ComponentID]component.Exporter{
+			config.TracesDataType: {
+				config.NewID("otlp"):                 defaultExp,
+				config.NewIDWithName("otlp", "acme"): defaultExp,
+				config.NewIDWithName("otlp", "globex"): &struct {
+					component.Component
+					consumertest.Consumer
+				}{componenthelper.New(), consumertest.NewErr(errors.New("my error"))},
+			},
+		},
+	}
+
+	rp := newRoutingProcessor(zap.NewNop(), newTestConfig(), config.TracesDataType)
+	require.NoError(t, rp.Start(context.Background(), host))
+
+	// The error of an exporter does not prevent the other exporters of the route from receiving the data.
+	assert.EqualError(t, rp.ConsumeTraces(tenantContext("globex"), testdata.GenerateTracesOneSpan()), "my error")
+	assert.Equal(t, 1, defaultExp.SpansCount())
+}
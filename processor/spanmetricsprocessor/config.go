@@ -0,0 +1,67 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package spanmetricsprocessor
+
+import (
+	"errors"
+	"time"
+
+	"go.opentelemetry.io/collector/config"
+)
+
+// defaultLatencyHistogramBuckets are the latency histogram buckets used when
+// none are configured.
+var defaultLatencyHistogramBuckets = []time.Duration{
+	2 * time.Millisecond, 4 * time.Millisecond, 6 * time.Millisecond, 8 * time.Millisecond,
+	10 * time.Millisecond, 50 * time.Millisecond, 100 * time.Millisecond, 200 * time.Millisecond,
+	400 * time.Millisecond, 800 * time.Millisecond, time.Second, 1400 * time.Millisecond,
+	2 * time.Second, 5 * time.Second, 10 * time.Second, 15 * time.Second,
+}
+
+// Config defines the configuration for the span metrics processor.
+type Config struct {
+	config.ProcessorSettings `mapstructure:",squash"` // squash ensures fields are correctly decoded in embedded struct
+
+	// MetricsExporter is the name of the exporter receiving the generated metrics,
+	// which must be listed in a metrics pipeline.
+	MetricsExporter string `mapstructure:"metrics_exporter"`
+
+	// LatencyHistogramBuckets are the upper bounds of the buckets of the latency
+	// histogram, in increasing order. If not set, defaultLatencyHistogramBuckets
+	// is used.
+	LatencyHistogramBuckets []time.Duration `mapstructure:"latency_histogram_buckets"`
+
+	// MaxStaleness is the time after which a series without spans is forgotten,
+	// its metrics restarting from zero with a new start time. Zero means the
+	// series are never forgotten.
+	MaxStaleness time.Duration `mapstructure:"max_staleness"`
+}
+
+var _ config.Processor = (*Config)(nil)
+
+// Validate checks if the processor configuration is valid.
+func (cfg *Config) Validate() error {
+	if cfg.MetricsExporter == "" {
+		return errors.New("metrics_exporter must be set")
+	}
+	if cfg.MaxStaleness < 0 {
+		return errors.New("max_staleness must be non-negative")
+	}
+	for i, bucket := range cfg.LatencyHistogramBuckets {
+		if bucket <= 0 || (i > 0 && bucket <= cfg.LatencyHistogramBuckets[i-1]) {
+			return errors.New("latency_histogram_buckets must be positive and in increasing order")
+		}
+	}
+	return nil
+}
@@ -0,0 +1,108 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package spanmetricsprocessor
+
+import (
+	"path"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/config"
+	"go.opentelemetry.io/collector/config/configtest"
+)
+
+func TestLoadConfig(t *testing.T) {
+	factories, err := componenttest.NopFactories()
+	assert.NoError(t, err)
+
+	factory := NewFactory()
+	factories.Processors[typeStr] = factory
+
+	cfg, err := configtest.LoadConfigFile(t, path.Join(".", "testdata", "config.yaml"), factories)
+	require.NoError(t, err)
+	require.NotNil(t, cfg)
+
+	assert.Equal(t, &Config{
+		ProcessorSettings: config.NewProcessorSettings(config.NewID(typeStr)),
+		MetricsExporter:   "nop/metrics",
+		MaxStaleness:      defaultMaxStaleness,
+	}, cfg.Processors[config.NewID(typeStr)])
+
+	assert.Equal(t, &Config{
+		ProcessorSettings:       config.NewProcessorSettings(config.NewIDWithName(typeStr, "buckets")),
+		MetricsExporter:         "nop/metrics",
+		LatencyHistogramBuckets: []time.Duration{10 * time.Millisecond, 100 * time.Millisecond, time.Second},
+		MaxStaleness:            10 * time.Minute,
+	}, cfg.Processors[config.NewIDWithName(typeStr, "buckets")])
+}
+
+func TestConfigValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		cfg     *Config
+		wantErr string
+	}{
+		{
+			name: "valid",
+			cfg:  &Config{MetricsExporter: "otlp"},
+		},
+		{
+			name: "valid buckets",
+			cfg: &Config{
+				MetricsExporter:         "otlp",
+				LatencyHistogramBuckets: []time.Duration{time.Millisecond, time.Second},
+			},
+		},
+		{
+			name:    "missing metrics_exporter",
+			cfg:     &Config{},
+			wantErr: "metrics_exporter must be set",
+		},
+		{
+			name:    "negative max_staleness",
+			cfg:     &Config{MetricsExporter: "otlp", MaxStaleness: -time.Second},
+			wantErr: "max_staleness must be non-negative",
+		},
+		{
+			name: "non positive bucket",
+			cfg: &Config{
+				MetricsExporter:         "otlp",
+				LatencyHistogramBuckets: []time.Duration{0, time.Second},
+			},
+			wantErr: "latency_histogram_buckets must be positive and in increasing order",
+		},
+		{
+			name: "unsorted buckets",
+			cfg: &Config{
+				MetricsExporter:         "otlp",
+				LatencyHistogramBuckets: []time.Duration{time.Second, time.Millisecond},
+			},
+			wantErr: "latency_histogram_buckets must be positive and in increasing order",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.cfg.Validate()
+			if tt.wantErr == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.EqualError(t, err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,256 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package spanmetricsprocessor
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/config"
+	"go.opentelemetry.io/collector/consumer"
+	"go.opentelemetry.io/collector/consumer/pdata"
+	"go.opentelemetry.io/collector/translator/conventions"
+)
+
+const (
+	callsMetricName   = "calls_total"
+	errorsMetricName  = "errors_total"
+	latencyMetricName = "latency"
+
+	serviceNameLabel = conventions.AttributeServiceName
+	operationLabel   = "operation"
+)
+
+// seriesKey identifies the metrics of an operation of a service.
+type seriesKey struct {
+	service   string
+	operation string
+}
+
+// series holds the cumulative values of the metrics of a seriesKey.
+type series struct {
+	// start is the time the series was first seen, or seen again after being
+	// forgotten.
+	start pdata.Timestamp
+	// lastSeen is the time of the last batch holding spans of the series.
+	lastSeen     pdata.Timestamp
+	calls        int64
+	errors       int64
+	latencySum   float64
+	bucketCounts []uint64
+}
+
+type spanMetricsProcessor struct {
+	logger       *zap.Logger
+	config       *Config
+	nextConsumer consumer.Traces
+
+	// latencyBounds are the bounds of the latency histogram in milliseconds.
+	latencyBounds []float64
+
+	// The metrics exporter is resolved when the processor is started.
+	metricsExporter consumer.Metrics
+
+	mu     sync.Mutex
+	series map[seriesKey]*series
+	// lastSweep is the last time the stale series were removed.
+	lastSweep pdata.Timestamp
+}
+
+var _ component.TracesProcessor = (*spanMetricsProcessor)(nil)
+
+func newSpanMetricsProcessor(logger *zap.Logger, cfg *Config, nextConsumer consumer.Traces) *spanMetricsProcessor {
+	buckets := cfg.LatencyHistogramBuckets
+	if len(buckets) == 0 {
+		buckets = defaultLatencyHistogramBuckets
+	}
+	bounds := make([]float64, len(buckets))
+	for i, bucket := range buckets {
+		bounds[i] = durationToMillis(bucket)
+	}
+	return &spanMetricsProcessor{
+		logger:        logger,
+		config:        cfg,
+		nextConsumer:  nextConsumer,
+		latencyBounds: bounds,
+		series:        make(map[seriesKey]*series),
+	}
+}
+
+func (p *spanMetricsProcessor) Start(_ context.Context, host component.Host) error {
+	id, err := config.NewIDFromString(p.config.MetricsExporter)
+	if err != nil {
+		return err
+	}
+	exp, ok := host.GetExporters()[config.MetricsDataType][id]
+	if !ok {
+		return fmt.Errorf("exporter %q not found in the metrics pipelines", p.config.MetricsExporter)
+	}
+	p.metricsExporter = exp.(consumer.Metrics)
+	return nil
+}
+
+func (p *spanMetricsProcessor) Shutdown(context.Context) error {
+	return nil
+}
+
+func (p *spanMetricsProcessor) Capabilities() consumer.Capabilities {
+	return consumer.Capabilities{MutatesData: false}
+}
+
+// ConsumeTraces updates the metrics with the spans of td and sends them to the
+// metrics exporter before passing td to the next consumer. A failure to export
+// the metrics does not prevent td from being passed on.
+func (p *spanMetricsProcessor) ConsumeTraces(ctx context.Context, td pdata.Traces) error {
+	md := p.aggregate(td, pdata.TimestampFromTime(time.Now()))
+	if err := p.metricsExporter.ConsumeMetrics(ctx, md); err != nil {
+		p.logger.Warn("Failed to export the span metrics", zap.Error(err))
+	}
+	return p.nextConsumer.ConsumeTraces(ctx, td)
+}
+
+// aggregate adds the spans of td to the series and returns the cumulative
+// metrics of the series having spans in td, timestamped with now.
+func (p *spanMetricsProcessor) aggregate(td pdata.Traces, now pdata.Timestamp) pdata.Metrics {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.removeStale(now)
+	// keys holds the keys of the series of td in the order of the spans, so that
+	// the metrics are always generated in the same order.
+	var keys []seriesKey
+	seen := make(map[seriesKey]bool)
+
+	rss := td.ResourceSpans()
+	for i := 0; i < rss.Len(); i++ {
+		rs := rss.At(i)
+		var service string
+		if v, ok := rs.Resource().Attributes().Get(conventions.AttributeServiceName); ok {
+			service = v.StringVal()
+		}
+		ilss := rs.InstrumentationLibrarySpans()
+		for j := 0; j < ilss.Len(); j++ {
+			spans := ilss.At(j).Spans()
+			for k := 0; k < spans.Len(); k++ {
+				key := seriesKey{service: service, operation: spans.At(k).Name()}
+				if !seen[key] {
+					seen[key] = true
+					keys = append(keys, key)
+				}
+				p.addSpan(key, spans.At(k), now)
+			}
+		}
+	}
+	return p.buildMetrics(keys, now)
+}
+
+// removeStale forgets the series without spans for MaxStaleness. The series are
+// swept at most once per MaxStaleness, so that a stale series is kept at most
+// twice as long.
+func (p *spanMetricsProcessor) removeStale(now pdata.Timestamp) {
+	maxStaleness := pdata.Timestamp(p.config.MaxStaleness)
+	if maxStaleness == 0 || now < p.lastSweep+maxStaleness {
+		return
+	}
+	p.lastSweep = now
+	for key, s := range p.series {
+		if s.lastSeen+maxStaleness <= now {
+			delete(p.series, key)
+		}
+	}
+}
+
+func (p *spanMetricsProcessor) addSpan(key seriesKey, span pdata.Span, now pdata.Timestamp) {
+	s, ok := p.series[key]
+	if !ok {
+		s = &series{start: now, bucketCounts: make([]uint64, len(p.latencyBounds)+1)}
+		p.series[key] = s
+	}
+	s.lastSeen = now
+
+	s.calls++
+	if span.Status().Code() == pdata.StatusCodeError {
+		s.errors++
+	}
+	var latency float64
+	if span.EndTimestamp() > span.StartTimestamp() {
+		latency = durationToMillis(time.Duration(span.EndTimestamp() - span.StartTimestamp()))
+	}
+	s.latencySum += latency
+	// The buckets include their upper bound.
+	s.bucketCounts[sort.SearchFloat64s(p.latencyBounds, latency)]++
+}
+
+func (p *spanMetricsProcessor) buildMetrics(keys []seriesKey, now pdata.Timestamp) pdata.Metrics {
+	md := pdata.NewMetrics()
+	metrics := md.ResourceMetrics().AppendEmpty().InstrumentationLibraryMetrics().AppendEmpty().Metrics()
+
+	callsSum := newCumulativeIntSum(metrics.AppendEmpty(), callsMetricName, "Number of spans per service and operation")
+	errorsSum := newCumulativeIntSum(metrics.AppendEmpty(), errorsMetricName, "Number of spans with an error status per service and operation")
+	latency := metrics.AppendEmpty()
+	latency.SetName(latencyMetricName)
+	latency.SetDescription("Duration of the spans per service and operation")
+	latency.SetUnit("ms")
+	latency.SetDataType(pdata.MetricDataTypeHistogram)
+	latency.Histogram().SetAggregationTemporality(pdata.AggregationTemporalityCumulative)
+
+	for _, key := range keys {
+		s := p.series[key]
+		initDataPoint(callsSum.DataPoints().AppendEmpty(), key, s, now).SetValue(s.calls)
+		initDataPoint(errorsSum.DataPoints().AppendEmpty(), key, s, now).SetValue(s.errors)
+
+		dp := latency.Histogram().DataPoints().AppendEmpty()
+		dp.SetStartTimestamp(s.start)
+		dp.SetTimestamp(now)
+		setLabels(dp.LabelsMap(), key)
+		dp.SetCount(uint64(s.calls))
+		dp.SetSum(s.latencySum)
+		dp.SetExplicitBounds(append([]float64(nil), p.latencyBounds...))
+		dp.SetBucketCounts(append([]uint64(nil), s.bucketCounts...))
+	}
+	return md
+}
+
+func initDataPoint(dp pdata.IntDataPoint, key seriesKey, s *series, now pdata.Timestamp) pdata.IntDataPoint {
+	dp.SetStartTimestamp(s.start)
+	dp.SetTimestamp(now)
+	setLabels(dp.LabelsMap(), key)
+	return dp
+}
+
+func newCumulativeIntSum(m pdata.Metric, name, description string) pdata.IntSum {
+	m.SetName(name)
+	m.SetDescription(description)
+	m.SetUnit("1")
+	m.SetDataType(pdata.MetricDataTypeIntSum)
+	sum := m.IntSum()
+	sum.SetIsMonotonic(true)
+	sum.SetAggregationTemporality(pdata.AggregationTemporalityCumulative)
+	return sum
+}
+
+func setLabels(labels pdata.StringMap, key seriesKey) {
+	labels.Insert(serviceNameLabel, key.service)
+	labels.Insert(operationLabel, key.operation)
+}
+
+func durationToMillis(d time.Duration) float64 {
+	return float64(d) / float64(time.Millisecond)
+}
@@ -0,0 +1,207 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package spanmetricsprocessor
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/component/componenthelper"
+	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/config"
+	"go.opentelemetry.io/collector/consumer/consumertest"
+	"go.opentelemetry.io/collector/consumer/pdata"
+	"go.opentelemetry.io/collector/consumer/pdatabuilder"
+)
+
+type mockHost struct {
+	component.Host
+	exporters map[config.DataType]map[config.ComponentID]component.Exporter
+}
+
+func (m *mockHost) GetExporters() map[config.DataType]map[config.ComponentID]component.Exporter {
+	return m.exporters
+}
+
+type mockMetricsExporter struct {
+	component.Component
+	consumertest.MetricsSink
+}
+
+func newTestProcessor(t *testing.T, next *consumertest.TracesSink) (*spanMetricsProcessor, *mockMetricsExporter) {
+	cfg := createDefaultConfig().(*Config)
+	cfg.MetricsExporter = "otlp/metrics"
+	cfg.LatencyHistogramBuckets = []time.Duration{10 * time.Millisecond, 100 * time.Millisecond}
+
+	exp := &mockMetricsExporter{Component: componenthelper.New()}
+	host := &mockHost{
+		Host: componenttest.NewNopHost(),
+		exporters: map[config.DataType]map[config.ComponentID]component.Exporter{
+			config.MetricsDataType: {config.NewIDWithName("otlp", "metrics"): exp},
+		},
+	}
+	p := newSpanMetricsProcessor(zap.NewNop(), cfg, next)
+	require.NoError(t, p.Start(context.Background(), host))
+	return p, exp
+}
+
+func TestStart_ExporterNotFound(t *testing.T) {
+	cfg := createDefaultConfig().(*Config)
+	cfg.MetricsExporter = "otlp/metrics"
+	p := newSpanMetricsProcessor(zap.NewNop(), cfg, consumertest.NewNop())
+	assert.EqualError(t, p.Start(context.Background(), componenttest.NewNopHost()),
+		`exporter "otlp/metrics" not found in the metrics pipelines`)
+}
+
+type expectedSeries struct {
+	calls        int64
+	errors       int64
+	latencySum   float64
+	bucketCounts []uint64
+}
+
+func assertMetrics(t *testing.T, md pdata.Metrics, want map[seriesKey]expectedSeries) {
+	metrics := md.ResourceMetrics().At(0).InstrumentationLibraryMetrics().At(0).Metrics()
+	require.Equal(t, 3, metrics.Len())
+
+	calls := metrics.At(0)
+	assert.Equal(t, callsMetricName, calls.Name())
+	assert.True(t, calls.IntSum().IsMonotonic())
+	assert.Equal(t, pdata.AggregationTemporalityCumulative, calls.IntSum().AggregationTemporality())
+	errs := metrics.At(1)
+	assert.Equal(t, errorsMetricName, errs.Name())
+	latency := metrics.At(2)
+	assert.Equal(t, latencyMetricName, latency.Name())
+	assert.Equal(t, "ms", latency.Unit())
+
+	require.Equal(t, len(want), calls.IntSum().DataPoints().Len())
+	require.Equal(t, len(want), errs.IntSum().DataPoints().Len())
+	require.Equal(t, len(want), latency.Histogram().DataPoints().Len())
+	for i := 0; i < len(want); i++ {
+		dp := calls.IntSum().DataPoints().At(i)
+		service, _ := dp.LabelsMap().Get(serviceNameLabel)
+		operation, _ := dp.LabelsMap().Get(operationLabel)
+		w, ok := want[seriesKey{service: service, operation: operation}]
+		require.True(t, ok, "unexpected series %s %s", service, operation)
+
+		assert.Equal(t, w.calls, dp.Value())
+		assert.Equal(t, dp.LabelsMap(), errs.IntSum().DataPoints().At(i).LabelsMap())
+		assert.Equal(t, w.errors, errs.IntSum().DataPoints().At(i).Value())
+		hdp := latency.Histogram().DataPoints().At(i)
+		assert.Equal(t, dp.LabelsMap(), hdp.LabelsMap())
+		assert.Equal(t, uint64(w.calls), hdp.Count())
+		assert.InDelta(t, w.latencySum, hdp.Sum(), 1e-9)
+		assert.Equal(t, []float64{10, 100}, hdp.ExplicitBounds())
+		assert.Equal(t, w.bucketCounts, hdp.BucketCounts())
+	}
+}
+
+func TestConsumeTraces(t *testing.T) {
+	next := new(consumertest.TracesSink)
+	p, exp := newTestProcessor(t, next)
+
+	start := time.Now()
+	td := pdatabuilder.NewTraces().
+		Resource(pdatabuilder.Attrs{"service.name": "svc-a"}).
+		Span("GET /").WithTimestamps(start, start.Add(5*time.Millisecond)).
+		Span("GET /").WithTimestamps(start, start.Add(150*time.Millisecond)).WithStatus(pdata.StatusCodeError, "").
+		Span("GET /").WithTimestamps(start, start.Add(10*time.Millisecond)).WithStatus(pdata.StatusCodeOk, "").
+		Resource(pdatabuilder.Attrs{"service.name": "svc-b"}).
+		Span("POST /").WithTimestamps(start, start.Add(50*time.Millisecond)).WithStatus(pdata.StatusCodeError, "").
+		Build()
+	require.NoError(t, p.ConsumeTraces(context.Background(), td))
+
+	// The spans are passed on unchanged.
+	require.Len(t, next.AllTraces(), 1)
+	assert.Equal(t, td, next.AllTraces()[0])
+
+	require.Len(t, exp.AllMetrics(), 1)
+	assertMetrics(t, exp.AllMetrics()[0], map[seriesKey]expectedSeries{
+		{service: "svc-a", operation: "GET /"}:  {calls: 3, errors: 1, latencySum: 165, bucketCounts: []uint64{2, 0, 1}},
+		{service: "svc-b", operation: "POST /"}: {calls: 1, errors: 1, latencySum: 50, bucketCounts: []uint64{0, 1, 0}},
+	})
+
+	// The metrics are cumulative across batches, only the series of the batch
+	// are sent.
+	td = pdatabuilder.NewTraces().
+		Resource(pdatabuilder.Attrs{"service.name": "svc-a"}).
+		Span("GET /").WithTimestamps(start, start.Add(time.Millisecond)).
+		Resource(nil).
+		Span("GET /").WithTimestamps(start, start.Add(time.Millisecond)).
+		Build()
+	require.NoError(t, p.ConsumeTraces(context.Background(), td))
+
+	require.Len(t, exp.AllMetrics(), 2)
+	assertMetrics(t, exp.AllMetrics()[1], map[seriesKey]expectedSeries{
+		{service: "svc-a", operation: "GET /"}: {calls: 4, errors: 1, latencySum: 166, bucketCounts: []uint64{3, 0, 1}},
+		{service: "", operation: "GET /"}:      {calls: 1, errors: 0, latencySum: 1, bucketCounts: []uint64{1, 0, 0}},
+	})
+
+	assert.NoError(t, p.Shutdown(context.Background()))
+}
+
+func TestAggregate_MaxStaleness(t *testing.T) {
+	p, _ := newTestProcessor(t, new(consumertest.TracesSink))
+	p.config.MaxStaleness = time.Minute
+
+	start := time.Unix(1600000000, 0)
+	spans := func(names ...string) pdata.Traces {
+		b := pdatabuilder.NewTraces().Resource(pdatabuilder.Attrs{"service.name": "svc"})
+		for _, name := range names {
+			b = b.Span(name).WithTimestamps(start, start.Add(time.Millisecond))
+		}
+		return b.Build()
+	}
+	at := func(d time.Duration) pdata.Timestamp {
+		return pdata.TimestampFromTime(start.Add(d))
+	}
+
+	p.aggregate(spans("a", "b"), at(0))
+	p.aggregate(spans("a"), at(50*time.Second))
+	require.Len(t, p.series, 2)
+
+	// b has no spans for a minute and is forgotten, a is kept.
+	md := p.aggregate(spans("a"), at(70*time.Second))
+	assert.Len(t, p.series, 1)
+	assertMetrics(t, md, map[seriesKey]expectedSeries{
+		{service: "svc", operation: "a"}: {calls: 3, latencySum: 3, bucketCounts: []uint64{3, 0, 0}},
+	})
+
+	// A forgotten series restarts from zero with a new start time.
+	md = p.aggregate(spans("b"), at(80*time.Second))
+	assertMetrics(t, md, map[seriesKey]expectedSeries{
+		{service: "svc", operation: "b"}: {calls: 1, latencySum: 1, bucketCounts: []uint64{1, 0, 0}},
+	})
+	dp := md.ResourceMetrics().At(0).InstrumentationLibraryMetrics().At(0).Metrics().At(0).IntSum().DataPoints().At(0)
+	assert.Equal(t, at(80*time.Second), dp.StartTimestamp())
+}
+
+func TestAggregate_ExplicitBoundsAreCopied(t *testing.T) {
+	p, _ := newTestProcessor(t, new(consumertest.TracesSink))
+
+	start := time.Now()
+	td := pdatabuilder.NewTraces().
+		Span("GET /").WithTimestamps(start, start.Add(time.Millisecond)).
+		Build()
+	md := p.aggregate(td, pdata.TimestampFromTime(start))
+	bounds := md.ResourceMetrics().At(0).InstrumentationLibraryMetrics().At(0).Metrics().At(2).Histogram().DataPoints().At(0).ExplicitBounds()
+	bounds[0] = 0
+	assert.Equal(t, []float64{10, 100}, p.latencyBounds)
+}
@@ -0,0 +1,132 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package stalenessprocessor
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/consumer"
+	"go.opentelemetry.io/collector/consumer/pdata"
+	"go.opentelemetry.io/collector/internal/metricskey"
+)
+
+type stalenessProcessor struct {
+	logger   *zap.Logger
+	next     consumer.Metrics
+	interval time.Duration
+	tracker  *seriesTracker
+	now      func() time.Time
+
+	shutdownC  chan struct{}
+	goroutines sync.WaitGroup
+}
+
+func newStalenessProcessor(logger *zap.Logger, cfg *Config, next consumer.Metrics) *stalenessProcessor {
+	return &stalenessProcessor{
+		logger:    logger,
+		next:      next,
+		interval:  cfg.StalenessInterval,
+		tracker:   newSeriesTracker(),
+		now:       time.Now,
+		shutdownC: make(chan struct{}),
+	}
+}
+
+func (sp *stalenessProcessor) start(context.Context, component.Host) error {
+	sp.goroutines.Add(1)
+	go sp.startLoop()
+	return nil
+}
+
+func (sp *stalenessProcessor) shutdown(context.Context) error {
+	close(sp.shutdownC)
+	sp.goroutines.Wait()
+	return nil
+}
+
+// startLoop checks for stale series twice per staleness interval, so a marker is
+// emitted at most one and a half interval after the last data point of its series.
+func (sp *stalenessProcessor) startLoop() {
+	defer sp.goroutines.Done()
+	ticker := time.NewTicker(sp.interval / 2)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-sp.shutdownC:
+			return
+		case <-ticker.C:
+			sp.emitMarkers(context.Background())
+		}
+	}
+}
+
+// emitMarkers sends a staleness marker for every series that was not reported
+// during the staleness interval, the series are forgotten afterwards.
+func (sp *stalenessProcessor) emitMarkers(ctx context.Context) {
+	now := sp.now()
+	md := sp.tracker.expire(now.Add(-sp.interval), pdata.TimestampFromTime(now))
+	if md.ResourceMetrics().Len() == 0 {
+		return
+	}
+	if err := sp.next.ConsumeMetrics(ctx, md); err != nil {
+		sp.logger.Warn("Failed to send the staleness markers", zap.Error(err))
+	}
+}
+
+// ProcessMetrics records the series of the double gauges and sums and passes the
+// metrics through unchanged.
+func (sp *stalenessProcessor) ProcessMetrics(_ context.Context, md pdata.Metrics) (pdata.Metrics, error) {
+	now := sp.now()
+	rms := md.ResourceMetrics()
+	for i := 0; i < rms.Len(); i++ {
+		rm := rms.At(i)
+		resKey := metricskey.Resource(rm.Resource())
+		ilms := rm.InstrumentationLibraryMetrics()
+		for j := 0; j < ilms.Len(); j++ {
+			il := ilms.At(j).InstrumentationLibrary()
+			metrics := ilms.At(j).Metrics()
+			for k := 0; k < metrics.Len(); k++ {
+				m := metrics.At(k)
+				dps, ok := doubleDataPoints(m)
+				if !ok {
+					continue
+				}
+				metKey := metricskey.Metric(resKey, il, m)
+				for l := 0; l < dps.Len(); l++ {
+					dp := dps.At(l)
+					sp.tracker.observe(metricskey.Series(metKey, dp.LabelsMap()), now, rm.Resource(), il, m, dp)
+				}
+			}
+		}
+	}
+	return md, nil
+}
+
+// doubleDataPoints returns the data points of the metric if it is a double gauge
+// or sum, the only types whose value can hold a staleness marker.
+func doubleDataPoints(m pdata.Metric) (pdata.DoubleDataPointSlice, bool) {
+	switch m.DataType() {
+	case pdata.MetricDataTypeDoubleGauge:
+		return m.DoubleGauge().DataPoints(), true
+	case pdata.MetricDataTypeDoubleSum:
+		return m.DoubleSum().DataPoints(), true
+	}
+	return pdata.DoubleDataPointSlice{}, false
+}
@@ -0,0 +1,162 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package stalenessprocessor
+
+import (
+	"context"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/prometheus/prometheus/pkg/value"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"go.opentelemetry.io/collector/consumer/consumertest"
+	"go.opentelemetry.io/collector/consumer/pdata"
+	"go.opentelemetry.io/collector/consumer/pdatabuilder"
+)
+
+// newTestProcessor returns a processor using a clock advanced by the tests.
+func newTestProcessor(sink *consumertest.MetricsSink) (*stalenessProcessor, *time.Time) {
+	cfg := createDefaultConfig().(*Config)
+	cfg.StalenessInterval = time.Minute
+	sp := newStalenessProcessor(zap.NewNop(), cfg, sink)
+	now := time.Unix(1000, 0)
+	sp.now = func() time.Time { return now }
+	return sp, &now
+}
+
+func doubleSum(value float64, labels map[string]string) pdata.Metrics {
+	return pdatabuilder.NewMetrics().
+		Resource(pdatabuilder.Attrs{"host.name": "host"}).
+		DoubleSum("requests", true).WithUnit("1").
+		DoubleDataPoint(value, labels).
+		Build()
+}
+
+func TestProcessMetricsDisappearance(t *testing.T) {
+	sink := new(consumertest.MetricsSink)
+	sp, now := newTestProcessor(sink)
+
+	md := doubleSum(10, map[string]string{"code": "200"})
+	out, err := sp.ProcessMetrics(context.Background(), md)
+	require.NoError(t, err)
+	assert.Equal(t, md, out)
+	assert.Equal(t, 1, sp.tracker.len())
+
+	// The series is not stale before the staleness interval.
+	*now = now.Add(30 * time.Second)
+	sp.emitMarkers(context.Background())
+	assert.Empty(t, sink.AllMetrics())
+
+	*now = now.Add(time.Minute)
+	sp.emitMarkers(context.Background())
+	require.Len(t, sink.AllMetrics(), 1)
+	marker := sink.AllMetrics()[0]
+	rm := marker.ResourceMetrics().At(0)
+	name, _ := rm.Resource().Attributes().Get("host.name")
+	assert.Equal(t, "host", name.StringVal())
+	m := rm.InstrumentationLibraryMetrics().At(0).Metrics().At(0)
+	assert.Equal(t, "requests", m.Name())
+	assert.Equal(t, "1", m.Unit())
+	require.Equal(t, pdata.MetricDataTypeDoubleSum, m.DataType())
+	assert.True(t, m.DoubleSum().IsMonotonic())
+	dp := m.DoubleSum().DataPoints().At(0)
+	assert.True(t, value.IsStaleNaN(dp.Value()))
+	assert.Equal(t, pdata.TimestampFromTime(*now), dp.Timestamp())
+	code, _ := dp.LabelsMap().Get("code")
+	assert.Equal(t, "200", code)
+
+	// The stale series is forgotten and gets a single marker.
+	assert.Equal(t, 0, sp.tracker.len())
+	*now = now.Add(time.Hour)
+	sp.emitMarkers(context.Background())
+	assert.Len(t, sink.AllMetrics(), 1)
+}
+
+func TestProcessMetricsAppearance(t *testing.T) {
+	sink := new(consumertest.MetricsSink)
+	sp, now := newTestProcessor(sink)
+
+	_, err := sp.ProcessMetrics(context.Background(), doubleSum(10, map[string]string{"code": "200"}))
+	require.NoError(t, err)
+
+	// A series reported again stays fresh while the other one becomes stale.
+	*now = now.Add(45 * time.Second)
+	_, err = sp.ProcessMetrics(context.Background(), doubleSum(20, map[string]string{"code": "200"}))
+	require.NoError(t, err)
+	_, err = sp.ProcessMetrics(context.Background(), doubleSum(1, map[string]string{"code": "500"}))
+	require.NoError(t, err)
+	assert.Equal(t, 2, sp.tracker.len())
+
+	*now = now.Add(45 * time.Second)
+	sp.emitMarkers(context.Background())
+	assert.Empty(t, sink.AllMetrics())
+
+	*now = now.Add(30 * time.Second)
+	sp.emitMarkers(context.Background())
+	require.Equal(t, 2, sink.MetricsCount())
+
+	// A series coming back after its marker is tracked again.
+	_, err = sp.ProcessMetrics(context.Background(), doubleSum(30, map[string]string{"code": "200"}))
+	require.NoError(t, err)
+	assert.Equal(t, 1, sp.tracker.len())
+}
+
+func TestProcessMetricsUpstreamMarker(t *testing.T) {
+	sink := new(consumertest.MetricsSink)
+	sp, now := newTestProcessor(sink)
+
+	_, err := sp.ProcessMetrics(context.Background(), doubleSum(10, nil))
+	require.NoError(t, err)
+	_, err = sp.ProcessMetrics(context.Background(), doubleSum(math.Float64frombits(value.StaleNaN), nil))
+	require.NoError(t, err)
+	assert.Equal(t, 0, sp.tracker.len())
+
+	*now = now.Add(time.Hour)
+	sp.emitMarkers(context.Background())
+	assert.Empty(t, sink.AllMetrics())
+}
+
+func TestProcessMetricsIgnoredTypes(t *testing.T) {
+	sink := new(consumertest.MetricsSink)
+	sp, _ := newTestProcessor(sink)
+
+	md := pdatabuilder.NewMetrics().
+		IntGauge("threads").IntDataPoint(3, nil).
+		IntSum("requests", true).IntDataPoint(10, nil).
+		DoubleGauge("temperature").DoubleDataPoint(21.5, nil).
+		Build()
+	_, err := sp.ProcessMetrics(context.Background(), md)
+	require.NoError(t, err)
+	assert.Equal(t, 1, sp.tracker.len())
+}
+
+func TestStartShutdown(t *testing.T) {
+	sink := new(consumertest.MetricsSink)
+	cfg := createDefaultConfig().(*Config)
+	cfg.StalenessInterval = 20 * time.Millisecond
+	sp := newStalenessProcessor(zap.NewNop(), cfg, sink)
+	require.NoError(t, sp.start(context.Background(), nil))
+
+	_, err := sp.ProcessMetrics(context.Background(), doubleSum(10, nil))
+	require.NoError(t, err)
+	assert.Eventually(t, func() bool {
+		return sink.MetricsCount() == 1
+	}, 5*time.Second, 5*time.Millisecond)
+	assert.NoError(t, sp.shutdown(context.Background()))
+}
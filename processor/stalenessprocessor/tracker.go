@@ -0,0 +1,121 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package stalenessprocessor
+
+import (
+	"math"
+	"sync"
+	"time"
+
+	"github.com/prometheus/prometheus/pkg/value"
+
+	"go.opentelemetry.io/collector/consumer/pdata"
+)
+
+// seriesTracker keeps the series seen recently to emit a staleness marker for
+// each of them once they are not reported anymore.
+type seriesTracker struct {
+	mu     sync.Mutex
+	series map[string]*trackedSeries
+}
+
+// trackedSeries is a series seen recently. The marker holds a copy of its resource,
+// instrumentation library and metric with the single data point sent as the
+// staleness marker of the series.
+type trackedSeries struct {
+	lastSeen time.Time
+	marker   pdata.Metrics
+	point    pdata.DoubleDataPoint
+}
+
+func newSeriesTracker() *seriesTracker {
+	return &seriesTracker{series: make(map[string]*trackedSeries)}
+}
+
+// observe records that the series identified by key was reported at now. A data
+// point that is already a staleness marker ends the series.
+func (t *seriesTracker) observe(key string, now time.Time, resource pdata.Resource, il pdata.InstrumentationLibrary, metric pdata.Metric, dp pdata.DoubleDataPoint) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if value.IsStaleNaN(dp.Value()) {
+		delete(t.series, key)
+		return
+	}
+	s, found := t.series[key]
+	if !found {
+		s = &trackedSeries{}
+		s.marker, s.point = newMarker(resource, il, metric, dp)
+		t.series[key] = s
+	}
+	s.lastSeen = now
+	s.point.SetStartTimestamp(dp.StartTimestamp())
+}
+
+// expire removes the series last reported before deadline and returns their
+// staleness markers timestamped with ts.
+func (t *seriesTracker) expire(deadline time.Time, ts pdata.Timestamp) pdata.Metrics {
+	md := pdata.NewMetrics()
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	for key, s := range t.series {
+		if !s.lastSeen.Before(deadline) {
+			continue
+		}
+		s.point.SetTimestamp(ts)
+		s.marker.ResourceMetrics().MoveAndAppendTo(md.ResourceMetrics())
+		delete(t.series, key)
+	}
+	return md
+}
+
+// len returns the number of tracked series.
+func (t *seriesTracker) len() int {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return len(t.series)
+}
+
+// newMarker returns the staleness marker of the series of dp, with the Prometheus
+// staleness NaN as value, and the data point of the marker.
+func newMarker(resource pdata.Resource, il pdata.InstrumentationLibrary, metric pdata.Metric, dp pdata.DoubleDataPoint) (pdata.Metrics, pdata.DoubleDataPoint) {
+	md := pdata.NewMetrics()
+	rm := md.ResourceMetrics().AppendEmpty()
+	resource.CopyTo(rm.Resource())
+	ilm := rm.InstrumentationLibraryMetrics().AppendEmpty()
+	il.CopyTo(ilm.InstrumentationLibrary())
+	m := ilm.Metrics().AppendEmpty()
+	m.SetName(metric.Name())
+	m.SetDescription(metric.Description())
+	m.SetUnit(metric.Unit())
+	m.SetDataType(metric.DataType())
+
+	var dps pdata.DoubleDataPointSlice
+	switch metric.DataType() {
+	case pdata.MetricDataTypeDoubleGauge:
+		dps = m.DoubleGauge().DataPoints()
+	case pdata.MetricDataTypeDoubleSum:
+		sum := m.DoubleSum()
+		sum.SetIsMonotonic(metric.DoubleSum().IsMonotonic())
+		sum.SetAggregationTemporality(metric.DoubleSum().AggregationTemporality())
+		dps = sum.DataPoints()
+	}
+	point := dps.AppendEmpty()
+	dp.LabelsMap().CopyTo(point.LabelsMap())
+	point.SetValue(math.Float64frombits(value.StaleNaN))
+	return md, point
+}
@@ -0,0 +1,144 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package unitnormalizerprocessor
+
+import (
+	"context"
+	"strings"
+
+	"go.uber.org/zap"
+
+	"go.opentelemetry.io/collector/consumer/pdata"
+)
+
+// defaultUnits maps the common spellings of the units, in lower case, to their UCUM
+// canonical forms.
+var defaultUnits = map[string]string{
+	"nanosecond":   "ns",
+	"nanoseconds":  "ns",
+	"nsec":         "ns",
+	"microsecond":  "us",
+	"microseconds": "us",
+	"usec":         "us",
+	"μs":           "us",
+	"millisecond":  "ms",
+	"milliseconds": "ms",
+	"msec":         "ms",
+	"second":       "s",
+	"seconds":      "s",
+	"sec":          "s",
+	"secs":         "s",
+	"minute":       "min",
+	"minutes":      "min",
+	"hour":         "h",
+	"hours":        "h",
+	"hr":           "h",
+	"day":          "d",
+	"days":         "d",
+	"byte":         "By",
+	"bytes":        "By",
+	"kilobyte":     "kBy",
+	"kilobytes":    "kBy",
+	"megabyte":     "MBy",
+	"megabytes":    "MBy",
+	"gigabyte":     "GBy",
+	"gigabytes":    "GBy",
+	"kibibyte":     "KiBy",
+	"kibibytes":    "KiBy",
+	"mebibyte":     "MiBy",
+	"mebibytes":    "MiBy",
+	"gibibyte":     "GiBy",
+	"gibibytes":    "GiBy",
+	"bit":          "bit",
+	"bits":         "bit",
+	"percent":      "%",
+	"celsius":      "Cel",
+	"hertz":        "Hz",
+}
+
+// normalizer maps the units to their canonical forms.
+type normalizer struct {
+	units map[string]string
+	// canonical is the set of the canonical units, kept unchanged.
+	canonical map[string]struct{}
+}
+
+func newNormalizer(units map[string]string) *normalizer {
+	n := &normalizer{
+		units:     make(map[string]string, len(defaultUnits)+len(units)),
+		canonical: make(map[string]struct{}, len(defaultUnits)+len(units)),
+	}
+	for unit, canonical := range defaultUnits {
+		n.add(unit, canonical)
+	}
+	for unit, canonical := range units {
+		n.add(unit, canonical)
+	}
+	return n
+}
+
+func (n *normalizer) add(unit, canonical string) {
+	n.units[strings.ToLower(unit)] = canonical
+	n.canonical[canonical] = struct{}{}
+}
+
+// normalize returns the canonical form of unit, and whether the unit is known.
+// The empty unit, the dimensionless "1" and the canonical units are kept unchanged.
+func (n *normalizer) normalize(unit string) (string, bool) {
+	if unit == "" || unit == "1" {
+		return unit, true
+	}
+	if _, ok := n.canonical[unit]; ok {
+		return unit, true
+	}
+	if canonical, ok := n.units[strings.ToLower(unit)]; ok {
+		return canonical, true
+	}
+	return unit, false
+}
+
+type unitNormalizerProcessor struct {
+	logger     *zap.Logger
+	normalizer *normalizer
+}
+
+func newUnitNormalizerProcessor(cfg *Config, logger *zap.Logger) *unitNormalizerProcessor {
+	return &unitNormalizerProcessor{
+		logger:     logger,
+		normalizer: newNormalizer(cfg.Units),
+	}
+}
+
+// ProcessMetrics normalizes the units of the metrics, the unknown units are kept.
+func (unp *unitNormalizerProcessor) ProcessMetrics(_ context.Context, md pdata.Metrics) (pdata.Metrics, error) {
+	rms := md.ResourceMetrics()
+	for i := 0; i < rms.Len(); i++ {
+		ilms := rms.At(i).InstrumentationLibraryMetrics()
+		for j := 0; j < ilms.Len(); j++ {
+			metrics := ilms.At(j).Metrics()
+			for k := 0; k < metrics.Len(); k++ {
+				m := metrics.At(k)
+				unit, ok := unp.normalizer.normalize(m.Unit())
+				if !ok {
+					unp.logger.Debug("Unknown unit kept unchanged",
+						zap.String("metric", m.Name()),
+						zap.String("unit", m.Unit()))
+					continue
+				}
+				m.SetUnit(unit)
+			}
+		}
+	}
+	return md, nil
+}
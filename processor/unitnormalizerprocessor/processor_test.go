@@ -0,0 +1,117 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package unitnormalizerprocessor
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+
+	"go.opentelemetry.io/collector/consumer/pdata"
+	"go.opentelemetry.io/collector/consumer/pdatabuilder"
+)
+
+func newTestProcessor(cfg *Config) (*unitNormalizerProcessor, *observer.ObservedLogs) {
+	core, logs := observer.New(zapcore.DebugLevel)
+	return newUnitNormalizerProcessor(cfg, zap.New(core)), logs
+}
+
+func metricUnits(md pdata.Metrics) []string {
+	var units []string
+	metrics := md.ResourceMetrics().At(0).InstrumentationLibraryMetrics().At(0).Metrics()
+	for i := 0; i < metrics.Len(); i++ {
+		units = append(units, metrics.At(i).Unit())
+	}
+	return units
+}
+
+func TestNormalizeCommonUnits(t *testing.T) {
+	unp, logs := newTestProcessor(&Config{})
+
+	md := pdatabuilder.NewMetrics().
+		DoubleGauge("a").WithUnit("milliseconds").
+		DoubleGauge("b").WithUnit("Seconds").
+		DoubleGauge("c").WithUnit("usec").
+		DoubleGauge("d").WithUnit("bytes").
+		DoubleGauge("e").WithUnit("MegaBytes").
+		DoubleGauge("f").WithUnit("percent").
+		DoubleGauge("g").WithUnit("hours").
+		Build()
+	md, err := unp.ProcessMetrics(context.Background(), md)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"ms", "s", "us", "By", "MBy", "%", "h"}, metricUnits(md))
+	assert.Equal(t, 0, logs.Len())
+}
+
+func TestNormalizeCanonicalUnits(t *testing.T) {
+	unp, logs := newTestProcessor(&Config{})
+
+	// The canonical units are case sensitive, e.g. MBy, and are kept unchanged,
+	// as well as the empty and the dimensionless units.
+	md := pdatabuilder.NewMetrics().
+		DoubleGauge("a").WithUnit("ms").
+		DoubleGauge("b").WithUnit("MBy").
+		DoubleGauge("c").WithUnit("1").
+		DoubleGauge("d").
+		Build()
+	md, err := unp.ProcessMetrics(context.Background(), md)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"ms", "MBy", "1", ""}, metricUnits(md))
+	assert.Equal(t, 0, logs.Len())
+}
+
+func TestNormalizeUnknownUnits(t *testing.T) {
+	unp, logs := newTestProcessor(&Config{})
+
+	md := pdatabuilder.NewMetrics().
+		IntSum("requests", true).WithUnit("{requests}").
+		DoubleGauge("latency").WithUnit("ms").
+		Build()
+	md, err := unp.ProcessMetrics(context.Background(), md)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"{requests}", "ms"}, metricUnits(md))
+
+	// The unknown units pass through with a debug log.
+	require.Equal(t, 1, logs.Len())
+	entry := logs.All()[0]
+	assert.Equal(t, zapcore.DebugLevel, entry.Level)
+	assert.Equal(t, "Unknown unit kept unchanged", entry.Message)
+	assert.Equal(t, "requests", entry.ContextMap()["metric"])
+	assert.Equal(t, "{requests}", entry.ContextMap()["unit"])
+}
+
+func TestNormalizeConfiguredUnits(t *testing.T) {
+	unp, logs := newTestProcessor(&Config{
+		Units: map[string]string{
+			"requests": "{requests}",
+			// Overrides the default mapping.
+			"sec": "ms",
+		},
+	})
+
+	md := pdatabuilder.NewMetrics().
+		IntSum("requests", true).WithUnit("Requests").
+		DoubleGauge("latency").WithUnit("sec").
+		DoubleGauge("duration").WithUnit("seconds").
+		Build()
+	md, err := unp.ProcessMetrics(context.Background(), md)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"{requests}", "ms", "s"}, metricUnits(md))
+	assert.Equal(t, 0, logs.Len())
+}
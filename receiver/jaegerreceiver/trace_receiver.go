@@ -184,8 +184,8 @@ func (jr *jReceiver) collectorHTTPEnabled() bool {
 	return jr.config != nil && jr.config.CollectorHTTPPort > 0
 }
 
-func (jr *jReceiver) Start(_ context.Context, host component.Host) error {
-	if err := jr.startAgent(host); err != nil {
+func (jr *jReceiver) Start(ctx context.Context, host component.Host) error {
+	if err := jr.startAgent(ctx, host); err != nil {
 		return err
 	}
 
@@ -286,7 +286,7 @@ func (jr *jReceiver) PostSpans(ctx context.Context, r *api_v2.PostSpansRequest)
 	return &api_v2.PostSpansResponse{}, nil
 }
 
-func (jr *jReceiver) startAgent(host component.Host) error {
+func (jr *jReceiver) startAgent(ctx context.Context, host component.Host) error {
 	if !jr.agentBinaryThriftEnabled() && !jr.agentCompactThriftEnabled() && !jr.agentHTTPEnabled() {
 		return nil
 	}
@@ -334,7 +334,7 @@ func (jr *jReceiver) startAgent(host component.Host) error {
 			jr.logger.Error("Error creating grpc dial options for remote sampling endpoint", zap.Error(err))
 			return err
 		}
-		conn, err := grpc.Dial(jr.config.RemoteSamplingClientSettings.Endpoint, grpcOpts...)
+		conn, err := jr.config.RemoteSamplingClientSettings.DialContext(ctx, jr.config.RemoteSamplingClientSettings.Endpoint, grpcOpts...)
 		if err != nil {
 			jr.logger.Error("Error creating grpc connection to jaeger remote sampling endpoint", zap.String("endpoint", jr.config.RemoteSamplingClientSettings.Endpoint))
 			return err
@@ -472,6 +472,7 @@ func (jr *jReceiver) startCollector(host component.Host) error {
 		}
 
 		jr.grpc = grpc.NewServer(opts...)
+		jr.config.CollectorGRPCServerSettings.RegisterServices(jr.grpc)
 		gaddr := jr.collectorGRPCAddr()
 		gln, gerr := net.Listen("tcp", gaddr)
 		if gerr != nil {
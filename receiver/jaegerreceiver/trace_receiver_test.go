@@ -45,6 +45,7 @@ import (
 	"go.opentelemetry.io/collector/component/componenttest"
 	"go.opentelemetry.io/collector/config"
 	"go.opentelemetry.io/collector/config/configgrpc"
+	_ "go.opentelemetry.io/collector/config/configgrpc/channelz"
 	"go.opentelemetry.io/collector/config/confighttp"
 	"go.opentelemetry.io/collector/config/configtls"
 	"go.opentelemetry.io/collector/consumer"
@@ -239,6 +240,22 @@ func TestGRPCReception(t *testing.T) {
 	assert.EqualValues(t, want, gotTraces[0])
 }
 
+func TestGRPCEnableChannelz(t *testing.T) {
+	config := &configuration{
+		CollectorGRPCPort: int(testutil.GetAvailablePort(t)),
+		CollectorGRPCServerSettings: configgrpc.GRPCServerSettings{
+			EnableChannelz: true,
+		},
+	}
+	params := component.ReceiverCreateParams{Logger: zap.NewNop()}
+	jr := newJaegerReceiver(jaegerReceiver, config, consumertest.NewNop(), params)
+
+	require.NoError(t, jr.Start(context.Background(), componenttest.NewNopHost()))
+	t.Cleanup(func() { require.NoError(t, jr.Shutdown(context.Background())) })
+
+	assert.Contains(t, jr.grpc.GetServiceInfo(), "grpc.channelz.v1.Channelz")
+}
+
 func TestGRPCReceptionWithTLS(t *testing.T) {
 	// prepare
 	tlsCreds := &configtls.TLSServerSetting{
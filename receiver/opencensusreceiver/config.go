@@ -15,6 +15,8 @@
 package opencensusreceiver
 
 import (
+	"errors"
+
 	"go.opentelemetry.io/collector/config"
 	"go.opentelemetry.io/collector/config/configgrpc"
 )
@@ -47,5 +49,9 @@ var _ config.Receiver = (*Config)(nil)
 
 // Validate checks the receiver configuration is valid
 func (cfg *Config) Validate() error {
+	if cfg.VerifyChecksum {
+		// The checksum is only verified for the unary RPCs, the OpenCensus protocol is streaming.
+		return errors.New("verify_checksum is not supported by the OpenCensus streaming RPCs")
+	}
 	return nil
 }
@@ -154,3 +154,11 @@ func TestLoadConfig(t *testing.T) {
 			},
 		})
 }
+
+func TestConfigValidate(t *testing.T) {
+	cfg := createDefaultConfig().(*Config)
+	assert.NoError(t, cfg.Validate())
+
+	cfg.VerifyChecksum = true
+	assert.EqualError(t, cfg.Validate(), "verify_checksum is not supported by the OpenCensus streaming RPCs")
+}
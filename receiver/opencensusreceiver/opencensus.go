@@ -178,6 +178,7 @@ func (ocr *ocReceiver) grpcServer(host component.Host) (*grpc.Server, error) {
 			return nil, err
 		}
 		ocr.serverGRPC = obsreport.GRPCServerWithObservabilityEnabled(opts...)
+		ocr.grpcServerSettings.RegisterServices(ocr.serverGRPC)
 	}
 
 	return ocr.serverGRPC, nil
@@ -46,6 +46,7 @@ import (
 	"go.opentelemetry.io/collector/component/componenttest"
 	"go.opentelemetry.io/collector/config"
 	"go.opentelemetry.io/collector/config/configgrpc"
+	_ "go.opentelemetry.io/collector/config/configgrpc/channelz"
 	"go.opentelemetry.io/collector/config/configtls"
 	"go.opentelemetry.io/collector/consumer/consumertest"
 	"go.opentelemetry.io/collector/internal/internalconsumertest"
@@ -268,6 +269,17 @@ func TestMultipleStopReceptionShouldNotError(t *testing.T) {
 	require.NoError(t, r.Shutdown(context.Background()))
 }
 
+func TestEnableChannelz(t *testing.T) {
+	addr := testutil.GetAvailableLocalAddress(t)
+	r, err := newOpenCensusReceiver(ocReceiverID, "tcp", addr, consumertest.NewNop(), nil,
+		withGRPCServerSettings(configgrpc.GRPCServerSettings{EnableChannelz: true}))
+	require.NoError(t, err)
+	require.NoError(t, r.Start(context.Background(), componenttest.NewNopHost()))
+	t.Cleanup(func() { require.NoError(t, r.Shutdown(context.Background())) })
+
+	assert.Contains(t, r.serverGRPC.GetServiceInfo(), "grpc.channelz.v1.Channelz")
+}
+
 func TestStartWithoutConsumersShouldFail(t *testing.T) {
 	addr := testutil.GetAvailableLocalAddress(t)
 	r, err := newOpenCensusReceiver(ocReceiverID, "tcp", addr, nil, nil)
@@ -124,6 +124,7 @@ func (r *otlpReceiver) startProtocolServers(host component.Host) error {
 			return err
 		}
 		r.serverGRPC = grpc.NewServer(opts...)
+		r.cfg.GRPC.RegisterServices(r.serverGRPC)
 
 		if r.traceReceiver != nil {
 			collectortrace.RegisterTraceServiceServer(r.serverGRPC, r.traceReceiver)
@@ -41,6 +41,7 @@ import (
 	"go.opentelemetry.io/collector/component/componenttest"
 	"go.opentelemetry.io/collector/config"
 	"go.opentelemetry.io/collector/config/configgrpc"
+	_ "go.opentelemetry.io/collector/config/configgrpc/channelz"
 	"go.opentelemetry.io/collector/config/confighttp"
 	"go.opentelemetry.io/collector/config/confignet"
 	"go.opentelemetry.io/collector/config/configtls"
@@ -55,6 +56,7 @@ import (
 	otlptrace "go.opentelemetry.io/collector/internal/data/protogen/trace/v1"
 	"go.opentelemetry.io/collector/internal/internalconsumertest"
 	"go.opentelemetry.io/collector/internal/pdatagrpc"
+	"go.opentelemetry.io/collector/internal/sharedcomponent"
 	"go.opentelemetry.io/collector/internal/testdata"
 	"go.opentelemetry.io/collector/obsreport/obsreporttest"
 	"go.opentelemetry.io/collector/testutil"
@@ -667,6 +669,20 @@ func TestGRPCInvalidTLSCredentials(t *testing.T) {
 		`failed to load TLS config: for auth via TLS, either both certificate and key must be supplied, or neither`)
 }
 
+func TestGRPCEnableChannelz(t *testing.T) {
+	factory := NewFactory()
+	cfg := factory.CreateDefaultConfig().(*Config)
+	cfg.GRPC.NetAddr.Endpoint = testutil.GetAvailableLocalAddress(t)
+	cfg.GRPC.EnableChannelz = true
+	cfg.HTTP = nil
+	r := newReceiver(t, factory, cfg, consumertest.NewNop(), nil)
+	require.NoError(t, r.Start(context.Background(), componenttest.NewNopHost()))
+	t.Cleanup(func() { require.NoError(t, r.Shutdown(context.Background())) })
+
+	ocr := r.(*sharedcomponent.SharedComponent).Unwrap().(*otlpReceiver)
+	assert.Contains(t, ocr.serverGRPC.GetServiceInfo(), "grpc.channelz.v1.Channelz")
+}
+
 func TestHTTPInvalidTLSCredentials(t *testing.T) {
 	cfg := &Config{
 		ReceiverSettings: config.NewReceiverSettings(config.NewID(typeStr)),
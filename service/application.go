@@ -114,6 +114,9 @@ func New(params Parameters) (*Application, error) {
 			if app.logger, err = newLogger(params.LoggingOptions); err != nil {
 				return fmt.Errorf("failed to get logger: %w", err)
 			}
+			// The packages without a component logger, e.g. configtls, log with the
+			// global logger.
+			zap.ReplaceGlobals(app.logger)
 
 			return app.execute(context.Background())
 		},
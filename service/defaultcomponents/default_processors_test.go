@@ -32,6 +32,7 @@ import (
 	"go.opentelemetry.io/collector/processor/memorylimiter"
 	"go.opentelemetry.io/collector/processor/processorhelper"
 	"go.opentelemetry.io/collector/processor/resourceprocessor"
+	"go.opentelemetry.io/collector/processor/routingprocessor"
 	"go.opentelemetry.io/collector/processor/spanprocessor"
 )
 
@@ -42,8 +43,9 @@ func TestDefaultProcessors(t *testing.T) {
 	procFactories := allFactories.Processors
 
 	tests := []struct {
-		processor   config.Type
-		getConfigFn getProcessorConfigFn
+		processor     config.Type
+		getConfigFn   getProcessorConfigFn
+		skipLifecycle bool
 	}{
 		{
 			processor: "attributes",
@@ -58,9 +60,15 @@ func TestDefaultProcessors(t *testing.T) {
 		{
 			processor: "batch",
 		},
+		{
+			processor: "cumulativetodelta",
+		},
 		{
 			processor: "filter",
 		},
+		{
+			processor: "histogramtosummary",
+		},
 		{
 			processor: "memory_limiter",
 			getConfigFn: func() config.Processor {
@@ -70,6 +78,9 @@ func TestDefaultProcessors(t *testing.T) {
 				return cfg
 			},
 		},
+		{
+			processor: "metricsrelabel",
+		},
 		{
 			processor: "probabilistic_sampler",
 		},
@@ -83,6 +94,18 @@ func TestDefaultProcessors(t *testing.T) {
 				return cfg
 			},
 		},
+		{
+			processor: "routing",
+			getConfigFn: func() config.Processor {
+				cfg := procFactories["routing"].CreateDefaultConfig().(*routingprocessor.Config)
+				cfg.FromMetadata = "X-Tenant"
+				return cfg
+			},
+		},
+		{
+			processor:     "spanmetrics",
+			skipLifecycle: true, // Requires a metrics exporter to start.
+		},
 		{
 			processor: "span",
 			getConfigFn: func() config.Processor {
@@ -91,6 +114,12 @@ func TestDefaultProcessors(t *testing.T) {
 				return cfg
 			},
 		},
+		{
+			processor: "staleness",
+		},
+		{
+			processor: "unitnormalizer",
+		},
 	}
 
 	assert.Equal(t, len(tests), len(procFactories))
@@ -101,6 +130,11 @@ func TestDefaultProcessors(t *testing.T) {
 			assert.Equal(t, tt.processor, factory.Type())
 			assert.EqualValues(t, config.NewID(tt.processor), factory.CreateDefaultConfig().ID())
 
+			if tt.skipLifecycle {
+				t.Log("Skipping lifecycle test", tt.processor)
+				return
+			}
+
 			verifyProcessorLifecycle(t, factory, tt.getConfigFn)
 		})
 	}
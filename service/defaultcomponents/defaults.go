@@ -35,11 +35,18 @@ import (
 	"go.opentelemetry.io/collector/extension/zpagesextension"
 	"go.opentelemetry.io/collector/processor/attributesprocessor"
 	"go.opentelemetry.io/collector/processor/batchprocessor"
+	"go.opentelemetry.io/collector/processor/cumulativetodeltaprocessor"
 	"go.opentelemetry.io/collector/processor/filterprocessor"
+	"go.opentelemetry.io/collector/processor/histogramtosummaryprocessor"
 	"go.opentelemetry.io/collector/processor/memorylimiter"
+	"go.opentelemetry.io/collector/processor/metricsrelabelprocessor"
 	"go.opentelemetry.io/collector/processor/probabilisticsamplerprocessor"
 	"go.opentelemetry.io/collector/processor/resourceprocessor"
+	"go.opentelemetry.io/collector/processor/routingprocessor"
+	"go.opentelemetry.io/collector/processor/spanmetricsprocessor"
 	"go.opentelemetry.io/collector/processor/spanprocessor"
+	"go.opentelemetry.io/collector/processor/stalenessprocessor"
+	"go.opentelemetry.io/collector/processor/unitnormalizerprocessor"
 	"go.opentelemetry.io/collector/receiver/hostmetricsreceiver"
 	"go.opentelemetry.io/collector/receiver/jaegerreceiver"
 	"go.opentelemetry.io/collector/receiver/kafkareceiver"
@@ -105,6 +112,13 @@ func Components() (
 		probabilisticsamplerprocessor.NewFactory(),
 		spanprocessor.NewFactory(),
 		filterprocessor.NewFactory(),
+		cumulativetodeltaprocessor.NewFactory(),
+		routingprocessor.NewFactory(),
+		histogramtosummaryprocessor.NewFactory(),
+		metricsrelabelprocessor.NewFactory(),
+		spanmetricsprocessor.NewFactory(),
+		unitnormalizerprocessor.NewFactory(),
+		stalenessprocessor.NewFactory(),
 	)
 	if err != nil {
 		errs = append(errs, err)
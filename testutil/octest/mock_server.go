@@ -0,0 +1,246 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package octest provides an in-process OpenCensus gRPC server to be used
+// in tests of components that export data using the OpenCensus protocol.
+package octest
+
+import (
+	"context"
+	"io"
+	"net"
+	"sync"
+	"time"
+
+	commonpb "github.com/census-instrumentation/opencensus-proto/gen-go/agent/common/v1"
+	agentmetricspb "github.com/census-instrumentation/opencensus-proto/gen-go/agent/metrics/v1"
+	agenttracepb "github.com/census-instrumentation/opencensus-proto/gen-go/agent/trace/v1"
+	resourcepb "github.com/census-instrumentation/opencensus-proto/gen-go/resource/v1"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+
+	"go.opentelemetry.io/collector/consumer/pdata"
+	"go.opentelemetry.io/collector/translator/internaldata"
+)
+
+// MockServer is an in-process OpenCensus trace and metrics gRPC server that
+// stores all the data it receives and allows querying it for testing.
+type MockServer struct {
+	ln  net.Listener
+	srv *grpc.Server
+
+	mu           sync.Mutex
+	traces       []pdata.Traces
+	metrics      []pdata.Metrics
+	spansCount   int
+	metricsCount int
+	exportErr    error
+	exportDelay  time.Duration
+	lastMetadata metadata.MD
+}
+
+// NewMockServer starts a MockServer listening on an available local port.
+// Callers must call Stop when the server is no longer needed.
+func NewMockServer() (*MockServer, error) {
+	ln, err := net.Listen("tcp", "localhost:0")
+	if err != nil {
+		return nil, err
+	}
+
+	ms := &MockServer{
+		ln:  ln,
+		srv: grpc.NewServer(),
+	}
+	agenttracepb.RegisterTraceServiceServer(ms.srv, &traceService{ms: ms})
+	agentmetricspb.RegisterMetricsServiceServer(ms.srv, &metricsService{ms: ms})
+	go func() {
+		_ = ms.srv.Serve(ln)
+	}()
+	return ms, nil
+}
+
+// Endpoint returns the host:port on which the server is listening.
+func (ms *MockServer) Endpoint() string {
+	return ms.ln.Addr().String()
+}
+
+// Stop stops the server and closes all the open connections.
+func (ms *MockServer) Stop() {
+	ms.srv.Stop()
+}
+
+// SetExportError sets the error returned to the client for every received
+// message. A nil error restores the default behavior of accepting the data.
+func (ms *MockServer) SetExportError(err error) {
+	ms.mu.Lock()
+	defer ms.mu.Unlock()
+	ms.exportErr = err
+}
+
+// SetExportDelay sets the time the server waits before processing every
+// received message, to simulate a slow backend.
+func (ms *MockServer) SetExportDelay(d time.Duration) {
+	ms.mu.Lock()
+	defer ms.mu.Unlock()
+	ms.exportDelay = d
+}
+
+// AllTraces returns the traces received by this server since last Reset.
+func (ms *MockServer) AllTraces() []pdata.Traces {
+	ms.mu.Lock()
+	defer ms.mu.Unlock()
+
+	copyTraces := make([]pdata.Traces, len(ms.traces))
+	copy(copyTraces, ms.traces)
+	return copyTraces
+}
+
+// SpansCount returns the number of spans received by this server since last Reset.
+func (ms *MockServer) SpansCount() int {
+	ms.mu.Lock()
+	defer ms.mu.Unlock()
+	return ms.spansCount
+}
+
+// AllMetrics returns the metrics received by this server since last Reset.
+func (ms *MockServer) AllMetrics() []pdata.Metrics {
+	ms.mu.Lock()
+	defer ms.mu.Unlock()
+
+	copyMetrics := make([]pdata.Metrics, len(ms.metrics))
+	copy(copyMetrics, ms.metrics)
+	return copyMetrics
+}
+
+// MetricsCount returns the number of metrics received by this server since last Reset.
+func (ms *MockServer) MetricsCount() int {
+	ms.mu.Lock()
+	defer ms.mu.Unlock()
+	return ms.metricsCount
+}
+
+// LastMetadata returns the metadata received with the last opened stream.
+func (ms *MockServer) LastMetadata() metadata.MD {
+	ms.mu.Lock()
+	defer ms.mu.Unlock()
+	return ms.lastMetadata.Copy()
+}
+
+// Reset deletes any stored data.
+func (ms *MockServer) Reset() {
+	ms.mu.Lock()
+	defer ms.mu.Unlock()
+
+	ms.traces = nil
+	ms.metrics = nil
+	ms.spansCount = 0
+	ms.metricsCount = 0
+	ms.lastMetadata = nil
+}
+
+// recordMetadata stores the metadata received with a new stream.
+func (ms *MockServer) recordMetadata(ctx context.Context) {
+	md, _ := metadata.FromIncomingContext(ctx)
+	ms.mu.Lock()
+	defer ms.mu.Unlock()
+	ms.lastMetadata = md
+}
+
+// beforeExport applies the configured delay and returns the configured error.
+func (ms *MockServer) beforeExport() error {
+	ms.mu.Lock()
+	delay, err := ms.exportDelay, ms.exportErr
+	ms.mu.Unlock()
+
+	if delay > 0 {
+		time.Sleep(delay)
+	}
+	return err
+}
+
+type traceService struct {
+	agenttracepb.UnimplementedTraceServiceServer
+	ms *MockServer
+}
+
+func (ts *traceService) Export(tes agenttracepb.TraceService_ExportServer) error {
+	ts.ms.recordMetadata(tes.Context())
+	// Node and Resource are only required in the first message of the stream,
+	// subsequent messages that do not set them reuse the last received values.
+	var lastNode *commonpb.Node
+	var lastResource *resourcepb.Resource
+	for {
+		req, err := tes.Recv()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if err = ts.ms.beforeExport(); err != nil {
+			return err
+		}
+
+		if req.Node != nil {
+			lastNode = req.Node
+		}
+		if req.Resource != nil {
+			lastResource = req.Resource
+		}
+		td := internaldata.OCToTraces(lastNode, lastResource, req.Spans)
+
+		ts.ms.mu.Lock()
+		ts.ms.traces = append(ts.ms.traces, td)
+		ts.ms.spansCount += td.SpanCount()
+		ts.ms.mu.Unlock()
+	}
+}
+
+type metricsService struct {
+	agentmetricspb.UnimplementedMetricsServiceServer
+	ms *MockServer
+}
+
+func (mss *metricsService) Export(mes agentmetricspb.MetricsService_ExportServer) error {
+	mss.ms.recordMetadata(mes.Context())
+	// Node and Resource are only required in the first message of the stream,
+	// subsequent messages that do not set them reuse the last received values.
+	var lastNode *commonpb.Node
+	var lastResource *resourcepb.Resource
+	for {
+		req, err := mes.Recv()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if err = mss.ms.beforeExport(); err != nil {
+			return err
+		}
+
+		if req.Node != nil {
+			lastNode = req.Node
+		}
+		if req.Resource != nil {
+			lastResource = req.Resource
+		}
+		md := internaldata.OCToMetrics(lastNode, lastResource, req.Metrics)
+
+		mss.ms.mu.Lock()
+		mss.ms.metrics = append(mss.ms.metrics, md)
+		mss.ms.metricsCount += md.MetricCount()
+		mss.ms.mu.Unlock()
+	}
+}
@@ -0,0 +1,138 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package octest
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	commonpb "github.com/census-instrumentation/opencensus-proto/gen-go/agent/common/v1"
+	agentmetricspb "github.com/census-instrumentation/opencensus-proto/gen-go/agent/metrics/v1"
+	agenttracepb "github.com/census-instrumentation/opencensus-proto/gen-go/agent/trace/v1"
+	metricspb "github.com/census-instrumentation/opencensus-proto/gen-go/metrics/v1"
+	tracepb "github.com/census-instrumentation/opencensus-proto/gen-go/trace/v1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+func newClientConn(t *testing.T, ms *MockServer) *grpc.ClientConn {
+	cc, err := grpc.Dial(ms.Endpoint(), grpc.WithInsecure(), grpc.WithBlock())
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		assert.NoError(t, cc.Close())
+	})
+	return cc
+}
+
+func TestMockServer_Traces(t *testing.T) {
+	ms, err := NewMockServer()
+	require.NoError(t, err)
+	defer ms.Stop()
+
+	ctx := metadata.AppendToOutgoingContext(context.Background(), "header", "value")
+	tsec, err := agenttracepb.NewTraceServiceClient(newClientConn(t, ms)).Export(ctx)
+	require.NoError(t, err)
+	require.NoError(t, tsec.Send(&agenttracepb.ExportTraceServiceRequest{
+		Node:  &commonpb.Node{ServiceInfo: &commonpb.ServiceInfo{Name: "test"}},
+		Spans: []*tracepb.Span{{Name: &tracepb.TruncatableString{Value: "span1"}}},
+	}))
+	// No Node in the second message, the one from the first message is reused.
+	require.NoError(t, tsec.Send(&agenttracepb.ExportTraceServiceRequest{
+		Spans: []*tracepb.Span{{Name: &tracepb.TruncatableString{Value: "span2"}}, {Name: &tracepb.TruncatableString{Value: "span3"}}},
+	}))
+
+	assert.Eventually(t, func() bool {
+		return ms.SpansCount() == 3
+	}, 10*time.Second, 5*time.Millisecond)
+	traces := ms.AllTraces()
+	require.Len(t, traces, 2)
+	for _, td := range traces {
+		name, ok := td.ResourceSpans().At(0).Resource().Attributes().Get("service.name")
+		require.True(t, ok)
+		assert.Equal(t, "test", name.StringVal())
+	}
+	assert.Empty(t, ms.AllMetrics())
+	assert.Equal(t, []string{"value"}, ms.LastMetadata().Get("header"))
+
+	ms.Reset()
+	assert.Empty(t, ms.AllTraces())
+	assert.Equal(t, 0, ms.SpansCount())
+	assert.Empty(t, ms.LastMetadata())
+}
+
+func TestMockServer_Metrics(t *testing.T) {
+	ms, err := NewMockServer()
+	require.NoError(t, err)
+	defer ms.Stop()
+
+	msec, err := agentmetricspb.NewMetricsServiceClient(newClientConn(t, ms)).Export(context.Background())
+	require.NoError(t, err)
+	require.NoError(t, msec.Send(&agentmetricspb.ExportMetricsServiceRequest{
+		Node: &commonpb.Node{},
+		Metrics: []*metricspb.Metric{{
+			MetricDescriptor: &metricspb.MetricDescriptor{Name: "metric1", Type: metricspb.MetricDescriptor_GAUGE_INT64},
+		}},
+	}))
+
+	assert.Eventually(t, func() bool {
+		return ms.MetricsCount() == 1
+	}, 10*time.Second, 5*time.Millisecond)
+	require.Len(t, ms.AllMetrics(), 1)
+	assert.Empty(t, ms.AllTraces())
+
+	ms.Reset()
+	assert.Empty(t, ms.AllMetrics())
+	assert.Equal(t, 0, ms.MetricsCount())
+}
+
+func TestMockServer_ExportError(t *testing.T) {
+	ms, err := NewMockServer()
+	require.NoError(t, err)
+	defer ms.Stop()
+	ms.SetExportError(errors.New("my error"))
+
+	tsec, err := agenttracepb.NewTraceServiceClient(newClientConn(t, ms)).Export(context.Background())
+	require.NoError(t, err)
+	require.NoError(t, tsec.Send(&agenttracepb.ExportTraceServiceRequest{
+		Node:  &commonpb.Node{},
+		Spans: []*tracepb.Span{{}},
+	}))
+	_, err = tsec.Recv()
+	assert.Error(t, err)
+	assert.Equal(t, 0, ms.SpansCount())
+}
+
+func TestMockServer_ExportDelay(t *testing.T) {
+	ms, err := NewMockServer()
+	require.NoError(t, err)
+	defer ms.Stop()
+	ms.SetExportDelay(100 * time.Millisecond)
+
+	tsec, err := agenttracepb.NewTraceServiceClient(newClientConn(t, ms)).Export(context.Background())
+	require.NoError(t, err)
+	start := time.Now()
+	require.NoError(t, tsec.Send(&agenttracepb.ExportTraceServiceRequest{
+		Node:  &commonpb.Node{},
+		Spans: []*tracepb.Span{{}},
+	}))
+	assert.Eventually(t, func() bool {
+		return ms.SpansCount() == 1
+	}, 10*time.Second, 5*time.Millisecond)
+	assert.GreaterOrEqual(t, int64(time.Since(start)), int64(100*time.Millisecond))
+}
@@ -15,10 +15,6 @@
 package tracetranslator
 
 import (
-	"encoding/json"
-	"fmt"
-	"strconv"
-
 	"go.opentelemetry.io/collector/consumer/pdata"
 )
 
@@ -57,35 +53,10 @@ const (
 	OpenTracingSpanKindInternal    OpenTracingSpanKind = "internal"
 )
 
-// AttributeValueToString converts an OTLP AttributeValue object to its equivalent string representation
+// AttributeValueToString converts an OTLP AttributeValue object to its equivalent string representation,
+// see pdata.AttributeValue.AsString.
 func AttributeValueToString(attr pdata.AttributeValue) string {
-	switch attr.Type() {
-	case pdata.AttributeValueTypeNull:
-		return ""
-
-	case pdata.AttributeValueTypeString:
-		return attr.StringVal()
-
-	case pdata.AttributeValueTypeBool:
-		return strconv.FormatBool(attr.BoolVal())
-
-	case pdata.AttributeValueTypeDouble:
-		return strconv.FormatFloat(attr.DoubleVal(), 'f', -1, 64)
-
-	case pdata.AttributeValueTypeInt:
-		return strconv.FormatInt(attr.IntVal(), 10)
-
-	case pdata.AttributeValueTypeMap:
-		jsonStr, _ := json.Marshal(AttributeMapToMap(attr.MapVal()))
-		return string(jsonStr)
-
-	case pdata.AttributeValueTypeArray:
-		jsonStr, _ := json.Marshal(attributeArrayToSlice(attr.ArrayVal()))
-		return string(jsonStr)
-
-	default:
-		return fmt.Sprintf("<Unknown OpenTelemetry attribute value type %q>", attr.Type())
-	}
+	return attr.AsString()
 }
 
 // AttributeMapToMap converts an OTLP AttributeMap to a standard go map